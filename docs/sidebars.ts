@@ -7,7 +7,7 @@ const sidebars: SidebarsConfig = {
 			type: "category",
 			label: "Backend",
 			link: { type: "doc", id: "backend/index" },
-			items: ["backend/worlds", "backend/ingest"],
+			items: ["backend/worlds", "backend/ingest", "backend/auth", "backend/monitoring"],
 		},
 		"frontend/index",
 		"contributing/index",
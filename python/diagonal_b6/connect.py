@@ -1,3 +1,4 @@
+import collections
 import grpc
 
 from diagonal_b6 import expression
@@ -7,6 +8,9 @@ from diagonal_b6 import VERSION
 from diagonal_b6 import api_pb2
 from diagonal_b6 import api_pb2_grpc
 
+ProfileEntry = collections.namedtuple("ProfileEntry", ["function", "calls", "seconds", "items"])
+Completion = collections.namedtuple("Completion", ["text", "begin", "end", "description"])
+
 class Connection:
 
     def __init__(self, stub, root):
@@ -14,23 +18,96 @@ class Connection:
         self.root = root
 
     def __call__(self, e):
+        return expression.from_node_proto(self.stub.Evaluate(self._request(e)).result)
+
+    def profile(self, e):
+        """Evaluate e, returning its result together with the time spent in
+        each function call made, ordered from most to least expensive."""
+        request = self._request(e)
+        request.profile = True
+        response = self.stub.Evaluate(request)
+        entries = [ProfileEntry(e.function, e.calls, e.nanoseconds / 1e9, e.items) for e in response.profile.entries]
+        return expression.from_node_proto(response.result), entries
+
+    def complete(self, e, cursor=None):
+        """Return suggested completions for the partial shell expression e,
+        at the given cursor position, or the end of e if omitted."""
+        request = api_pb2.CompleteRequestProto()
+        request.expression = e
+        request.cursor = len(e) if cursor is None else cursor
+        if self.root:
+            request.root.CopyFrom(self.root.to_proto())
+        response = self.stub.Complete(request)
+        return [Completion(c.text, c.begin, c.end, c.description) for c in response.completions]
+
+    def _request(self, e):
         request = api_pb2.EvaluateRequestProto()
         request.version = VERSION
         node = expression.to_node(e)
         request.request.CopyFrom(node.to_node_proto())
         if self.root:
             request.root.CopyFrom(self.root.to_proto())
-        return expression.from_node_proto(self.stub.Evaluate(request).result)
+        return request
 
     def list_worlds(self):
         response = self.stub.ListWorlds(api_pb2.ListWorldsRequestProto())
         return [features.from_id_proto(id) for id in response.ids]
 
+    def world_info(self, id=None):
+        """Return descriptions of the compact indices underlying the world
+        with the given id, or the root world if omitted, including the
+        files they were built from and their replication timestamps."""
+        request = api_pb2.WorldInfoRequestProto()
+        if id:
+            request.id.CopyFrom(id.to_proto())
+        elif self.root:
+            request.id.CopyFrom(self.root.to_proto())
+        return list(self.stub.WorldInfo(request).indices)
+
     def delete_world(self, id):
         request = api_pb2.DeleteWorldRequestProto()
         request.id.CopyFrom(id.to_proto())
         self.stub.DeleteWorld(request)
 
+    def clone_world(self, from_id, to_id, read_only=False, name="", author=""):
+        """Create a world with id to_id, containing the modifications made
+        to the world from_id. If read_only is set, the clone can't be
+        modified, preserving it as a snapshot."""
+        request = api_pb2.CloneWorldRequestProto()
+        request.to.CopyFrom(to_id.to_proto())
+        getattr(request, "from").CopyFrom(from_id.to_proto())
+        request.read_only = read_only
+        request.name = name
+        request.author = author
+        return self.stub.CloneWorld(request).metadata
+
+    def freeze_world(self, id):
+        """Prevent further modification of the world with the given id."""
+        request = api_pb2.FreezeWorldRequestProto()
+        request.id.CopyFrom(id.to_proto())
+        self.stub.FreezeWorld(request)
+
+    def rename_world(self, from_id, to_id):
+        request = api_pb2.RenameWorldRequestProto()
+        request.to.CopyFrom(to_id.to_proto())
+        getattr(request, "from").CopyFrom(from_id.to_proto())
+        self.stub.RenameWorld(request)
+
+    def set_world_metadata(self, id, name="", author=""):
+        request = api_pb2.SetWorldMetadataRequestProto()
+        request.id.CopyFrom(id.to_proto())
+        request.name = name
+        request.author = author
+        return self.stub.SetWorldMetadata(request).metadata
+
+    def diff_worlds(self, from_id, to_id):
+        """Return descriptions of the features that differ between the
+        worlds from_id and to_id, ordered by feature id."""
+        request = api_pb2.DiffWorldsRequestProto()
+        request.to.CopyFrom(to_id.to_proto())
+        getattr(request, "from").CopyFrom(from_id.to_proto())
+        return list(self.stub.DiffWorlds(request).features)
+
 def connect_insecure(address, root=None, channel_arguments=None):
     channel = grpc.insecure_channel(address, options=channel_arguments)
     return Connection(api_pb2_grpc.B6Stub(channel), root)
@@ -0,0 +1,354 @@
+package api
+
+import (
+	"container/list"
+	"reflect"
+	"sync"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/ingest"
+	pb "diagonal.works/b6/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+type CacheOptions struct {
+	// The approximate maximum memory used by cached results, in bytes.
+	MaxBytes int
+	// The names of the functions whose results can be cached. These
+	// functions must be deterministic, given their arguments and the
+	// state of the world.
+	Functions []string
+}
+
+type CacheStats struct {
+	Hits      int
+	Misses    int
+	Evictions int
+	// Results that weren't cached, because they were too large, or
+	// couldn't be safely reused.
+	Rejected int
+	Entries  int
+	Bytes    int
+}
+
+type CacheKey struct {
+	World      b6.FeatureID
+	Version    uint64
+	Expression string
+}
+
+type cacheEntry struct {
+	key   CacheKey
+	value reflect.Value
+	bytes int
+}
+
+// Cache is an LRU cache for the results of function calls made by the VM,
+// keyed on the call's expression, and the ID and version of the world in
+// which it was evaluated. Lazily evaluated collections are recorded as
+// they're iterated over by the caller, and cached once complete. The memory used by cached results is estimated,
+// rather than measured exactly.
+type Cache struct {
+	maxBytes  int
+	functions map[b6.SymbolExpression]struct{}
+
+	lock    sync.Mutex
+	entries map[CacheKey]*list.Element
+	lru     *list.List
+	stats   CacheStats
+}
+
+// Individual results are limited to a fraction of the size of the cache,
+// to prevent a single large result evicting everything else.
+const maxCacheEntryFraction = 8
+
+// Expressions with large literals, like GeoJSON, aren't worth caching.
+const maxCacheKeyBytes = 64 * 1024
+
+const cacheEntryOverheadBytes = 128
+
+func NewCache(options CacheOptions) *Cache {
+	c := &Cache{
+		maxBytes:  options.MaxBytes,
+		functions: make(map[b6.SymbolExpression]struct{}),
+		entries:   make(map[CacheKey]*list.Element),
+		lru:       list.New(),
+	}
+	for _, f := range options.Functions {
+		c.functions[b6.SymbolExpression(f)] = struct{}{}
+	}
+	return c
+}
+
+// IsCacheable returns true if the results of calls to the given
+// function can be cached.
+func (c *Cache) IsCacheable(function b6.SymbolExpression) bool {
+	_, ok := c.functions[function]
+	return ok
+}
+
+func (c *Cache) Get(key CacheKey) (reflect.Value, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if e, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(e)
+		c.stats.Hits++
+		return e.Value.(*cacheEntry).value, true
+	}
+	c.stats.Misses++
+	return reflect.Value{}, false
+}
+
+// Add caches the given result, returning the value that should be used
+// by the caller in its place. Lazily evaluated collections aren't read
+// here, but are instead recorded as the caller iterates over them, and
+// cached once the caller reaches the end, provided they're not too large.
+// Other results that are too large are returned unchanged, and not cached.
+func (c *Cache) Add(key CacheKey, value reflect.Value, context *Context) reflect.Value {
+	limit := c.maxBytes/maxCacheEntryFraction - len(key.Expression) - cacheEntryOverheadBytes
+	if collection, ok := isLazyCollection(value); ok {
+		if adaptor, ok := context.Adaptors.Collections[value.Type()]; ok && limit > 0 {
+			return adaptor(&cachingCollection{c: collection, t: value.Type(), key: key, limit: limit, cache: c, context: context})
+		}
+		c.reject()
+		return value
+	}
+	bytes, ok := estimateBytes(value, limit)
+	if !ok {
+		c.reject()
+		return value
+	}
+	return c.add(key, value, bytes)
+}
+
+func (c *Cache) add(key CacheKey, value reflect.Value, bytes int) reflect.Value {
+	bytes += len(key.Expression) + cacheEntryOverheadBytes
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if e, ok := c.entries[key]; ok {
+		// Another goroutine evaluated the same call concurrently
+		c.lru.MoveToFront(e)
+		return e.Value.(*cacheEntry).value
+	}
+	c.entries[key] = c.lru.PushFront(&cacheEntry{key: key, value: value, bytes: bytes})
+	c.stats.Entries++
+	c.stats.Bytes += bytes
+	for c.stats.Bytes > c.maxBytes && c.lru.Len() > 0 {
+		c.remove(c.lru.Back())
+		c.stats.Evictions++
+	}
+	return value
+}
+
+func (c *Cache) reject() {
+	c.lock.Lock()
+	c.stats.Rejected++
+	c.lock.Unlock()
+}
+
+func (c *Cache) remove(e *list.Element) {
+	entry := c.lru.Remove(e).(*cacheEntry)
+	delete(c.entries, entry.key)
+	c.stats.Entries--
+	c.stats.Bytes -= entry.bytes
+}
+
+func (c *Cache) Clear() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for c.lru.Len() > 0 {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *Cache) Stats() CacheStats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.stats
+}
+
+// NewCacheKey returns a key for the result of evaluating the given
+// expression in the context's world, or false if the result can't be
+// cached, for example because the expression references the arguments
+// of an enclosing lambda.
+func NewCacheKey(e b6.Expression, context *Context) (CacheKey, bool) {
+	if !isClosed(e, nil, context.FunctionSymbols) {
+		return CacheKey{}, false
+	}
+	node, err := cacheKeyProto(e)
+	if err != nil {
+		return CacheKey{}, false
+	}
+	marshalled, err := proto.MarshalOptions{Deterministic: true}.Marshal(node)
+	if err != nil || len(marshalled) > maxCacheKeyBytes {
+		return CacheKey{}, false
+	}
+	// Worlds that aren't versioned can't be safely cached, as we can't
+	// detect changes to them.
+	m, ok := context.World.(ingest.MutableWorld)
+	if !ok {
+		return CacheKey{}, false
+	}
+	return CacheKey{World: context.WorldID, Version: m.Version(), Expression: string(marshalled)}, true
+}
+
+// isClosed returns true if the only symbols referenced by an expression
+// are global functions, or the arguments of lambdas within the
+// expression itself.
+func isClosed(e b6.Expression, bound []string, fs FunctionSymbols) bool {
+	switch e := e.AnyExpression.(type) {
+	case b6.SymbolExpression:
+		for _, b := range bound {
+			if b == string(e) {
+				return true
+			}
+		}
+		_, ok := fs.Function(e)
+		return ok
+	case b6.CallExpression:
+		if !isClosed(e.Function, bound, fs) {
+			return false
+		}
+		for _, arg := range e.Args {
+			if !isClosed(arg, bound, fs) {
+				return false
+			}
+		}
+		return true
+	case b6.LambdaExpression:
+		return isClosed(e.Expression, append(bound[0:len(bound):len(bound)], e.Args...), fs)
+	case b6.AnyLiteral:
+		return true
+	}
+	return false
+}
+
+// cacheKeyProto returns a proto representation of the given expression
+// without source positions, and with features replaced by their IDs, as
+// the world is already part of the key.
+func cacheKeyProto(e b6.Expression) (*pb.NodeProto, error) {
+	switch ee := e.AnyExpression.(type) {
+	case b6.CallExpression:
+		f, err := cacheKeyProto(ee.Function)
+		if err != nil {
+			return nil, err
+		}
+		call := &pb.CallNodeProto{Function: f, Args: make([]*pb.NodeProto, len(ee.Args))}
+		for i, arg := range ee.Args {
+			if call.Args[i], err = cacheKeyProto(arg); err != nil {
+				return nil, err
+			}
+		}
+		return &pb.NodeProto{Node: &pb.NodeProto_Call{Call: call}}, nil
+	case b6.LambdaExpression:
+		node, err := cacheKeyProto(ee.Expression)
+		if err != nil {
+			return nil, err
+		}
+		lambda := &pb.LambdaNodeProto{Args: ee.Args, Node: node}
+		return &pb.NodeProto{Node: &pb.NodeProto_Lambda_{Lambda_: lambda}}, nil
+	case b6.FeatureExpression:
+		return b6.NewFeatureIDExpression(ee.FeatureID()).AnyExpression.ToProto()
+	}
+	return e.AnyExpression.ToProto()
+}
+
+// isLazyCollection returns the given value as a collection if it's one
+// whose items are only evaluated when iterated over.
+func isLazyCollection(v reflect.Value) (b6.UntypedCollection, bool) {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil, false
+	}
+	if c, ok := v.Interface().(b6.UntypedCollection); ok {
+		// Collection features are already stored in the world
+		if _, ok := c.(b6.Feature); !ok {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// estimateBytes returns an estimate of the memory used by the given
+// value, or false if the value can't be safely cached and reused, or
+// would use more than the given number of bytes.
+func estimateBytes(v reflect.Value, limit int) (int, bool) {
+	if !v.IsValid() || v.Kind() == reflect.Func || limit <= 0 {
+		return 0, false
+	}
+	switch vv := v.Interface().(type) {
+	case Callable, ingest.Change, ingest.WorldsChange:
+		return 0, false
+	case string:
+		return len(vv), len(vv) <= limit
+	case b6.Area:
+		n := 0
+		for i := 0; i < vv.Len(); i++ {
+			for _, loop := range vv.Polygon(i).Loops() {
+				n += loop.NumVertices()
+			}
+		}
+		bytes := n * pointBytes
+		return bytes, bytes <= limit
+	case b6.Geometry:
+		bytes := vv.GeometryLen() * pointBytes
+		return bytes, bytes <= limit
+	}
+	return valueBytes, true
+}
+
+const valueBytes = 32
+const pointBytes = 24
+
+// cachingCollection wraps a lazily evaluated collection, recording its
+// items as they're iterated over, and adding them to the cache once the
+// end of the collection is reached. Recording is abandoned if the items
+// exceed the limit, so large results are never read more than once.
+type cachingCollection struct {
+	c       b6.UntypedCollection
+	t       reflect.Type
+	key     CacheKey
+	limit   int
+	cache   *Cache
+	context *Context
+}
+
+func (c *cachingCollection) BeginUntyped() b6.Iterator[any, any] {
+	return &cachingIterator{Iterator: c.c.BeginUntyped(), c: c, recording: true}
+}
+
+func (c *cachingCollection) Count() (int, bool) {
+	return c.c.Count()
+}
+
+type cachingIterator struct {
+	b6.Iterator[any, any]
+	c         *cachingCollection
+	recorded  b6.ArrayCollection[any, any]
+	bytes     int
+	recording bool
+}
+
+func (i *cachingIterator) Next() (bool, error) {
+	ok, err := i.Iterator.Next()
+	if !i.recording {
+		return ok, err
+	} else if err != nil {
+		i.recording = false
+	} else if ok {
+		i.bytes += 2 * valueBytes
+		if i.bytes > i.c.limit {
+			i.recording = false
+			i.recorded = b6.ArrayCollection[any, any]{}
+			i.c.cache.reject()
+		} else {
+			i.recorded.Keys = append(i.recorded.Keys, i.Key())
+			i.recorded.Values = append(i.recorded.Values, i.Value())
+		}
+	} else {
+		i.recording = false
+		if adaptor, ok := i.c.context.Adaptors.Collections[i.c.t]; ok {
+			i.c.cache.add(i.c.key, adaptor(i.recorded.Collection()), i.bytes)
+		}
+	}
+	return ok, err
+}
@@ -0,0 +1,423 @@
+package api
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/ingest"
+	pb "diagonal.works/b6/proto"
+	"diagonal.works/b6/search"
+)
+
+type CompletionType int
+
+const (
+	CompletionTypeFunction CompletionType = iota
+	CompletionTypeArgument
+	CompletionTypeTag
+	CompletionTypeFeatureID
+)
+
+func (c CompletionType) ToProto() pb.CompletionType {
+	switch c {
+	case CompletionTypeFunction:
+		return pb.CompletionType_CompletionTypeFunction
+	case CompletionTypeArgument:
+		return pb.CompletionType_CompletionTypeArgument
+	case CompletionTypeTag:
+		return pb.CompletionType_CompletionTypeTag
+	case CompletionTypeFeatureID:
+		return pb.CompletionType_CompletionTypeFeatureID
+	}
+	panic(fmt.Sprintf("bad completion type %d", c))
+}
+
+// Completion is a suggestion for the text that should replace the
+// expression between Begin and End. Argument completions are placeholders
+// describing the argument expected at the cursor, rather than text to be
+// inserted.
+type Completion struct {
+	Type        CompletionType
+	Text        string
+	Begin       int
+	End         int
+	Description string
+}
+
+func (c Completion) ToProto() *pb.CompletionProto {
+	return &pb.CompletionProto{
+		Type:        c.Type.ToProto(),
+		Text:        c.Text,
+		Begin:       int32(c.Begin),
+		End:         int32(c.End),
+		Description: c.Description,
+	}
+}
+
+const MaxCompletions = 50
+
+// Completer suggests completions for partially typed shell expressions,
+// using the functions available, and the tags and features in the world.
+type Completer struct {
+	FunctionSymbols FunctionSymbols
+	Adaptors        Adaptors
+	// The names of each function's arguments, keyed by function name,
+	// used to describe functions and suggest arguments.
+	ArgNames map[string][]string
+
+	lock sync.Mutex
+	tags map[b6.World]*worldTags
+}
+
+// worldTags holds the tags known to a world's search index, sorted to
+// allow them to be searched by prefix.
+type worldTags struct {
+	version uint64
+	keys    []string // eg #building, @name
+	tags    []string // eg #building=yes
+}
+
+// The number of worlds for which tags are cached before the cache is
+// discarded.
+const maxCachedWorlds = 64
+
+type completionToken struct {
+	token int
+	begin int
+	end   int
+	text  string
+}
+
+// completionFrame tracks a call whose arguments are being typed.
+type completionFrame struct {
+	function  string
+	args      int
+	pipelined bool
+}
+
+// Complete returns suggested completions for the expression being typed
+// at the cursor, a byte offset into the expression.
+func (c *Completer) Complete(expression string, cursor int, w b6.World) []Completion {
+	if cursor < 0 || cursor > len(expression) {
+		cursor = len(expression)
+	}
+	tokens, partial := lexForCompletion(expression[0:cursor])
+	var word completionToken
+	if partial >= 0 {
+		word = completionToken{begin: partial, end: cursor, text: expression[partial:cursor]}
+	} else if len(tokens) > 0 && tokens[len(tokens)-1].end == cursor && isWordToken(tokens[len(tokens)-1].token) {
+		word = tokens[len(tokens)-1]
+		tokens = tokens[0 : len(tokens)-1]
+	} else {
+		word = completionToken{begin: cursor, end: cursor}
+	}
+
+	// Tag values are lexed as a TAG_KEY, followed by =, followed by the
+	// value itself.
+	if n := len(tokens); n >= 2 && tokens[n-1].token == '=' && tokens[n-2].token == TAG_KEY && tokens[n-1].end == word.begin {
+		key := tokens[n-2]
+		prefix := expression[key.begin:cursor]
+		return c.completeTags(prefix, key.begin, cursor, w)
+	}
+
+	switch {
+	case strings.HasPrefix(word.text, "#") || strings.HasPrefix(word.text, "@"):
+		return c.completeTags(word.text, word.begin, word.end, w)
+	case strings.HasPrefix(word.text, "/"):
+		return completeFeatureIDs(word.text, word.begin, word.end, w)
+	case word.text == "" || word.token == SYMBOL:
+		frame := currentFrame(tokens)
+		completions := make([]Completion, 0)
+		want := c.argType(frame)
+		if word.text == "" {
+			if a, ok := c.argument(frame, cursor); ok {
+				completions = append(completions, a)
+			}
+			if want == nil {
+				// Without an argument to complete, suggesting every
+				// function isn't useful.
+				return completions
+			}
+		}
+		return append(completions, c.completeFunctions(word, want)...)
+	}
+	return []Completion{}
+}
+
+func isWordToken(token int) bool {
+	switch token {
+	case SYMBOL, TAG_KEY, FEATURE_ID:
+		return true
+	}
+	return false
+}
+
+// lexForCompletion returns the tokens in the given expression, and the
+// position of a partially typed token that couldn't be lexed, or -1 if
+// the whole expression was lexed.
+func lexForCompletion(expression string) ([]completionToken, int) {
+	l := lexer{Expression: expression}
+	tokens := make([]completionToken, 0)
+	for {
+		var yylval yySymType
+		begin := l.Index
+		token := l.Lex(&yylval)
+		if l.Err != nil {
+			// Skip whitespace preceding the partial token
+			for begin < len(expression) && strings.ContainsRune(" \t\n", rune(expression[begin])) {
+				begin++
+			}
+			return tokens, begin
+		} else if token == eof {
+			return tokens, -1
+		}
+		end := l.Index
+		if yylval.e.AnyExpression != nil {
+			begin = yylval.e.Begin
+		} else {
+			begin = end - 1
+			if token == ARROW {
+				begin = end - 2
+			}
+		}
+		tokens = append(tokens, completionToken{token: token, begin: begin, end: end, text: expression[begin:end]})
+	}
+}
+
+// currentFrame returns the innermost call being typed at the end of the
+// given tokens.
+func currentFrame(tokens []completionToken) completionFrame {
+	frames := []completionFrame{{}}
+	arg := func() {
+		if top := &frames[len(frames)-1]; top.function != "" {
+			top.args++
+		}
+	}
+	skip := false
+	for _, t := range tokens {
+		if skip {
+			skip = false
+			continue
+		}
+		top := &frames[len(frames)-1]
+		switch t.token {
+		case '(', '[', '{':
+			frames = append(frames, completionFrame{})
+		case ')', ']', '}':
+			if len(frames) > 1 {
+				frames = frames[0 : len(frames)-1]
+				arg()
+			}
+		case ARROW:
+			*top = completionFrame{}
+		case '|':
+			*top = completionFrame{pipelined: true}
+		case '=':
+			// Skip the value of a tag, which was counted with its key.
+			skip = true
+		case ',':
+			// Skip the longitude of a lat, lng pair.
+			skip = true
+		case SYMBOL:
+			if top.function == "" {
+				top.function = t.text
+			} else {
+				top.args++
+			}
+		case INT, FLOAT, STRING, FEATURE_ID, TAG_KEY:
+			arg()
+		}
+	}
+	return frames[len(frames)-1]
+}
+
+// argIndex returns the index of the argument being typed in the given
+// frame. Arguments to a pipelined call fill its parameters after the first,
+// which takes the result of the pipeline.
+func argIndex(frame completionFrame) int {
+	if frame.pipelined {
+		return frame.args + 1
+	}
+	return frame.args
+}
+
+// argType returns the type of the argument being typed in the given frame,
+// or nil if it's unknown.
+func (c *Completer) argType(frame completionFrame) reflect.Type {
+	if frame.function == "" {
+		return nil
+	}
+	f, ok := c.FunctionSymbols.Function(b6.SymbolExpression(frame.function))
+	if !ok {
+		return nil
+	}
+	t := f.Type()
+	i := argIndex(frame)
+	if i < t.NumIn()-1 {
+		return t.In(i + 1)
+	} else if t.IsVariadic() {
+		return t.In(t.NumIn() - 1).Elem()
+	}
+	return nil
+}
+
+func (c *Completer) argument(frame completionFrame, cursor int) (Completion, bool) {
+	names, ok := c.ArgNames[frame.function]
+	i := argIndex(frame)
+	if !ok || i >= len(names) {
+		return Completion{}, false
+	}
+	completion := Completion{Type: CompletionTypeArgument, Text: names[i], Begin: cursor, End: cursor}
+	if t := c.argType(frame); t != nil {
+		completion.Description = typeName(t)
+	}
+	return completion, true
+}
+
+// completeFunctions returns the functions beginning with the given word.
+// Functions whose results will certainly convert to the type of the
+// argument being typed are listed first, followed by those whose results
+// may convert at runtime.
+func (c *Completer) completeFunctions(word completionToken, want reflect.Type) []Completion {
+	checker := typeChecker{fs: c.FunctionSymbols, adaptors: &c.Adaptors}
+	var certain, possible, others []Completion
+	for name, f := range c.FunctionSymbols {
+		if !strings.HasPrefix(name, word.text) {
+			continue
+		}
+		t := reflect.TypeOf(f)
+		completion := Completion{
+			Type:        CompletionTypeFunction,
+			Text:        name,
+			Begin:       word.begin,
+			End:         word.end,
+			Description: c.describe(name, t),
+		}
+		if want == nil || (t.Out(0).Kind() != reflect.Interface && checker.canConvert(t.Out(0), want)) {
+			certain = append(certain, completion)
+		} else if checker.canConvert(t.Out(0), want) {
+			possible = append(possible, completion)
+		} else {
+			others = append(others, completion)
+		}
+	}
+	completions := make([]Completion, 0, len(certain)+len(possible)+len(others))
+	for _, cs := range [][]Completion{certain, possible, others} {
+		sort.Slice(cs, func(i, j int) bool { return cs[i].Text < cs[j].Text })
+		completions = append(completions, cs...)
+	}
+	if len(completions) > MaxCompletions {
+		completions = completions[0:MaxCompletions]
+	}
+	return completions
+}
+
+// describe returns a signature for the given function, eg
+// "reachable origin options distance query".
+func (c *Completer) describe(name string, t reflect.Type) string {
+	if names, ok := c.ArgNames[name]; ok {
+		return strings.Join(append([]string{name}, names...), " ")
+	}
+	args := make([]string, 0, t.NumIn()-1)
+	for i := 1; i < t.NumIn(); i++ {
+		args = append(args, t.In(i).String())
+	}
+	return strings.Join(append([]string{name}, args...), " ")
+}
+
+func (c *Completer) completeTags(prefix string, begin int, end int, w b6.World) []Completion {
+	tags := c.worldTags(w)
+	candidates := tags.keys
+	if strings.Contains(prefix, "=") {
+		candidates = tags.tags
+	}
+	completions := make([]Completion, 0)
+	for i := sort.SearchStrings(candidates, prefix); i < len(candidates) && strings.HasPrefix(candidates[i], prefix); i++ {
+		if candidates[i] == prefix {
+			continue
+		}
+		completions = append(completions, Completion{Type: CompletionTypeTag, Text: candidates[i], Begin: begin, End: end})
+		if len(completions) == MaxCompletions {
+			break
+		}
+	}
+	return completions
+}
+
+// worldTags returns the tags in the given world's search index, reusing
+// those from previous calls if the world hasn't changed.
+func (c *Completer) worldTags(w b6.World) *worldTags {
+	var version uint64
+	if m, ok := w.(ingest.MutableWorld); ok {
+		version = m.Version()
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if tags, ok := c.tags[w]; ok && tags.version == version {
+		return tags
+	}
+	tags := &worldTags{version: version}
+	keys := make(map[string]struct{})
+	for _, token := range w.Tokens() {
+		if token == search.AllToken || search.IsSpatialToken(token) {
+			continue
+		}
+		if i := strings.Index(token, "="); i > 0 {
+			keys["#"+token[0:i]] = struct{}{}
+			tags.tags = append(tags.tags, "#"+token)
+		} else {
+			keys["@"+token] = struct{}{}
+		}
+	}
+	for key := range keys {
+		tags.keys = append(tags.keys, key)
+	}
+	sort.Strings(tags.keys)
+	sort.Strings(tags.tags)
+	if c.tags == nil || len(c.tags) >= maxCachedWorlds {
+		c.tags = make(map[b6.World]*worldTags)
+	}
+	c.tags[w] = tags
+	return tags
+}
+
+var featureTypes = []b6.FeatureType{b6.FeatureTypePoint, b6.FeatureTypePath, b6.FeatureTypeArea, b6.FeatureTypeRelation, b6.FeatureTypeCollection}
+
+// completeFeatureIDs suggests the prefixes of feature IDs matching the
+// given one, or the ID itself, if it identifies a feature in the world.
+func completeFeatureIDs(prefix string, begin int, end int, w b6.World) []Completion {
+	completions := make([]Completion, 0)
+	if id, err := ParseFeatureIDToken(prefix); err == nil && id.IsValid() {
+		if f := w.FindFeatureByID(id); f != nil {
+			completion := Completion{Type: CompletionTypeFeatureID, Text: prefix, Begin: begin, End: end}
+			if name := f.Get("name"); name.IsValid() {
+				completion.Description = name.Value.String()
+			}
+			completions = append(completions, completion)
+		}
+	}
+	seen := make(map[string]struct{})
+	for _, alias := range aliases {
+		candidates := []string{alias.Prefix}
+		for _, t := range featureTypes {
+			if alias.Type == b6.FeatureTypeInvalid || alias.Type == t {
+				id := UnparseFeatureID(b6.FeatureID{Type: t, Namespace: alias.Namespace}, false)
+				candidates = append(candidates, id[0:strings.LastIndex(id, "/")+1])
+			}
+		}
+		for _, candidate := range candidates {
+			if _, ok := seen[candidate]; !ok && candidate != prefix && strings.HasPrefix(candidate, prefix) {
+				seen[candidate] = struct{}{}
+				completions = append(completions, Completion{Type: CompletionTypeFeatureID, Text: candidate, Begin: begin, End: end})
+			}
+		}
+	}
+	sort.SliceStable(completions, func(i, j int) bool {
+		return len(completions[i].Text) < len(completions[j].Text)
+	})
+	return completions
+}
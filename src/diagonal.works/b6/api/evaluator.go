@@ -2,9 +2,10 @@ package api
 
 import (
 	"context"
-	"sync"
+	"time"
 
 	"diagonal.works/b6"
+	"diagonal.works/b6/auth"
 	"diagonal.works/b6/ingest"
 	pb "diagonal.works/b6/proto"
 )
@@ -19,60 +20,85 @@ type Evaluator struct {
 	FunctionSymbols FunctionSymbols
 	Adaptors        Adaptors
 	Options         Options
-	Lock            *sync.RWMutex
+	Locks           *ingest.WorldLocks
 }
 
-func (e *Evaluator) EvaluateProto(request *pb.EvaluateRequestProto) (interface{}, error) {
+// EvaluateProto, EvaluateString and EvaluateExpression evaluate with the
+// permissions carried by the given context, as set by auth.Handler. Callers
+// must hold a read lock on the root world from Locks, which is upgraded to
+// a write lock while applying changes.
+func (e *Evaluator) EvaluateProto(ctx context.Context, request *pb.EvaluateRequestProto) (interface{}, error) {
 	expression, err := b6.ExpressionFromProto(request.Request)
 	if err != nil {
 		return nil, err
 	}
 	root := b6.NewFeatureIDFromProto(request.Root)
-	return e.EvaluateExpression(expression, root)
+	return e.EvaluateExpression(ctx, expression, root)
 }
 
-func (e *Evaluator) EvaluateString(expression string, root b6.FeatureID) (interface{}, error) {
+func (e *Evaluator) EvaluateString(ctx context.Context, expression string, root b6.FeatureID) (interface{}, error) {
 	parsed, err := ParseExpression(expression)
 	if err != nil {
 		return nil, err
 	}
-	v, err := e.EvaluateExpression(parsed, root)
+	v, err := e.EvaluateExpression(ctx, parsed, root)
 	if err != nil {
 		return nil, err
 	}
 	return v, nil
 }
 
-func (e *Evaluator) EvaluateExpression(expression b6.Expression, root b6.FeatureID) (interface{}, error) {
-	var world ingest.MutableWorld
-	if root.IsValid() {
-		world = e.Worlds.FindOrCreateWorld(root)
-	} else {
-		world = e.Worlds.FindOrCreateWorld(ingest.DefaultWorldFeatureID)
+func (e *Evaluator) EvaluateExpression(ctx context.Context, expression b6.Expression, root b6.FeatureID) (interface{}, error) {
+	if !root.IsValid() {
+		root = ingest.DefaultWorldFeatureID
 	}
+	permissions := auth.FromContext(ctx)
+	worlds := permissions.RestrictWorlds(e.Worlds)
+	world := worlds.FindOrCreateWorld(root)
 	vmContext := Context{
 		World:           world,
-		Worlds:          e.Worlds,
+		WorldID:         root,
+		Worlds:          worlds,
+		Locks:           e.Locks,
 		FunctionSymbols: e.FunctionSymbols,
 		Adaptors:        e.Adaptors,
-		Context:         context.Background(),
+		Context:         ctx,
 	}
 	vmContext.FillFromOptions(&e.Options)
+	vmContext.FileIOAllowed = vmContext.FileIOAllowed && permissions.FileIO
 
+	start := time.Now()
 	simplified := Simplify(expression, e.FunctionSymbols)
+	vmContext.StartTimer()
 	v, err := Evaluate(simplified, &vmContext)
+	e.Options.Metrics.ObserveEvaluation(expression, start, err)
 	if err != nil {
 		return b6.Literal{AnyLiteral: nil}, err
 	}
 
 	if change, ok := v.(ingest.Change); ok {
-		e.Lock.RUnlock()
-		e.Lock.Lock()
+		if err := permissions.CheckModify(root); err != nil {
+			return nil, err
+		}
+		downgrade := e.Locks.Upgrade(root)
 		var modified b6.Collection[b6.FeatureID, b6.FeatureID]
 		modified, err = change.Apply(world)
-		e.Lock.Unlock()
-		e.Lock.RLock()
+		downgrade()
+		e.Options.Metrics.ObserveChange(err)
+		if err != nil {
+			return nil, err
+		}
 		return &AppliedChange{Change: change, Modified: modified}, nil
+	} else if change, ok := v.(ingest.WorldsChange); ok {
+		downgrade := e.Locks.Upgrade(root)
+		var id b6.FeatureID
+		id, err = change.Apply(worlds, root)
+		downgrade()
+		e.Options.Metrics.ObserveChange(err)
+		if err != nil {
+			return nil, err
+		}
+		return id, nil
 	}
 	return v, err
 }
@@ -25,7 +25,9 @@ func buildingAccess(c *api.Context, origins b6.Collection[interface{}, b6.Featur
 	weights := graph.SimpleHighwayWeights{}
 	for _, f := range o {
 		s := graph.NewShortestPathSearchFromFeature(f, weights, c.World)
-		s.ExpandSearch(limit, weights, graph.PointsAndAreas, c.World)
+		if err := expandSearch(c, s, limit, weights, graph.PointsAndAreas); err != nil {
+			return b6.Collection[b6.FeatureID, b6.FeatureID]{}, err
+		}
 		for id := range s.AreaDistances() {
 			if reached := b6.FindAreaByID(id, c.World); reached != nil {
 				if reached.Get("#building").IsValid() {
@@ -0,0 +1,207 @@
+package functions
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/api"
+	"diagonal.works/b6/ingest"
+	"diagonal.works/b6/test/camden"
+)
+
+type countedCalls struct {
+	calls int
+}
+
+func (c *countedCalls) double(context *api.Context, i int) (int, error) {
+	c.calls++
+	return i * 2, nil
+}
+
+func newCachingContext(w b6.World, counted *countedCalls, options api.CacheOptions) *api.Context {
+	fs := make(api.FunctionSymbols)
+	for name, f := range Functions() {
+		fs[name] = f
+	}
+	fs["double"] = counted.double
+	return &api.Context{
+		World:           w,
+		WorldID:         ingest.DefaultWorldFeatureID,
+		FunctionSymbols: fs,
+		Adaptors:        Adaptors(),
+		Context:         context.Background(),
+		Cache:           api.NewCache(options),
+	}
+}
+
+func TestCacheReusesResults(t *testing.T) {
+	w := ingest.NewMutableOverlayWorld(camden.BuildGranarySquareForTests(t))
+	var counted countedCalls
+	c := newCachingContext(w, &counted, api.CacheOptions{MaxBytes: 1024 * 1024, Functions: []string{"double"}})
+
+	for i := 0; i < 2; i++ {
+		if v, err := api.EvaluateString("add-ints (double 21) (double 21)", c); err != nil {
+			t.Fatal(err)
+		} else if v != 84 {
+			t.Errorf("Expected 84, found %v", v)
+		}
+	}
+	if counted.calls != 1 {
+		t.Errorf("Expected 1 call, found %d", counted.calls)
+	}
+	if stats := c.Cache.Stats(); stats.Hits != 3 || stats.Misses != 1 || stats.Entries != 1 {
+		t.Errorf("Unexpected cache stats: %+v", stats)
+	}
+}
+
+func TestCacheReusesResultsForLambdaArguments(t *testing.T) {
+	w := ingest.NewMutableOverlayWorld(camden.BuildGranarySquareForTests(t))
+	var counted countedCalls
+	c := newCachingContext(w, &counted, api.CacheOptions{MaxBytes: 1024 * 1024, Functions: []string{"double"}})
+
+	for i := 0; i < 2; i++ {
+		v, err := api.EvaluateString("map {1: 10, 2: 10, 3: 20} {v -> double v}", c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		values := []int{}
+		if err := api.FillSliceFromValues(v.(b6.UntypedCollection), &values); err != nil {
+			t.Fatal(err)
+		}
+		if expected := []int{20, 20, 40}; !reflect.DeepEqual(expected, values) {
+			t.Errorf("Expected %v, found %v", expected, values)
+		}
+	}
+	if counted.calls != 3 {
+		t.Errorf("Expected 3 calls, found %d", counted.calls)
+	}
+}
+
+func TestCacheIsInvalidatedByWorldModifications(t *testing.T) {
+	w := ingest.NewMutableOverlayWorld(camden.BuildGranarySquareForTests(t))
+	var counted countedCalls
+	c := newCachingContext(w, &counted, api.CacheOptions{MaxBytes: 1024 * 1024, Functions: []string{"find"}})
+
+	const e = `find [#amenity=restaurant]`
+	count := func() int {
+		v, err := api.EvaluateString(e, c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n := 0
+		i := v.(b6.UntypedCollection).BeginUntyped()
+		for {
+			ok, err := i.Next()
+			if err != nil {
+				t.Fatal(err)
+			} else if !ok {
+				break
+			}
+			n++
+		}
+		return n
+	}
+
+	before := count()
+	if after := count(); after != before {
+		t.Errorf("Expected %d results from cache, found %d", before, after)
+	}
+	if stats := c.Cache.Stats(); stats.Hits != 1 {
+		t.Errorf("Expected 1 cache hit, found %d", stats.Hits)
+	}
+
+	id := b6.FeatureIDFromString("/area/openstreetmap.org/way/222021576")
+	if err := w.AddTag(id, b6.Tag{Key: "#amenity", Value: b6.NewStringExpression("restaurant")}); err != nil {
+		t.Fatal(err)
+	}
+	if after := count(); after != before+1 {
+		t.Errorf("Expected %d results after modification, found %d", before+1, after)
+	}
+}
+
+func TestCacheEvictsLeastRecentlyUsedResults(t *testing.T) {
+	w := ingest.NewMutableOverlayWorld(camden.BuildGranarySquareForTests(t))
+	var counted countedCalls
+	const maxBytes = 4096
+	c := newCachingContext(w, &counted, api.CacheOptions{MaxBytes: maxBytes, Functions: []string{"double"}})
+
+	for i := 0; i < 100; i++ {
+		if _, err := api.EvaluateString(fmt.Sprintf("double %d", i), c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stats := c.Cache.Stats()
+	if stats.Evictions == 0 || stats.Bytes > maxBytes {
+		t.Errorf("Expected evictions keeping the cache below %d bytes, found %+v", maxBytes, stats)
+	}
+
+	// The most recently used result should still be cached, while the
+	// first should have been evicted.
+	calls := counted.calls
+	if _, err := api.EvaluateString("double 99", c); err != nil {
+		t.Fatal(err)
+	} else if counted.calls != calls {
+		t.Error("Expected most recent result to be cached")
+	}
+	if _, err := api.EvaluateString("double 0", c); err != nil {
+		t.Fatal(err)
+	} else if counted.calls != calls+1 {
+		t.Error("Expected least recent result to be evicted")
+	}
+}
+
+func TestCacheOnlyAddsCollectionsOnceIterated(t *testing.T) {
+	w := ingest.NewMutableOverlayWorld(camden.BuildGranarySquareForTests(t))
+	var counted countedCalls
+	c := newCachingContext(w, &counted, api.CacheOptions{MaxBytes: 1024 * 1024, Functions: []string{"find"}})
+
+	v, err := api.EvaluateString(`find [#amenity=restaurant]`, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats := c.Cache.Stats(); stats.Entries != 0 {
+		t.Errorf("Expected no entries before iteration, found %+v", stats)
+	}
+	n := 0
+	i := v.(b6.UntypedCollection).BeginUntyped()
+	for {
+		ok, err := i.Next()
+		if err != nil {
+			t.Fatal(err)
+		} else if !ok {
+			break
+		}
+		n++
+	}
+	if n == 0 {
+		t.Fatal("Expected at least one restaurant")
+	}
+	if stats := c.Cache.Stats(); stats.Entries != 1 {
+		t.Errorf("Expected 1 entry after iteration, found %+v", stats)
+	}
+}
+
+func TestCacheRejectsLargeCollectionsWhileIterating(t *testing.T) {
+	w := ingest.NewMutableOverlayWorld(camden.BuildGranarySquareForTests(t))
+	var counted countedCalls
+	// Large enough for the key, but not the result
+	c := newCachingContext(w, &counted, api.CacheOptions{MaxBytes: 4096, Functions: []string{"find"}})
+
+	v, err := api.EvaluateString(`find [#building]`, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := []b6.Feature{}
+	if err := api.FillSliceFromValues(v.(b6.UntypedCollection), &values); err != nil {
+		t.Fatal(err)
+	}
+	if len(values) < 10 {
+		t.Fatalf("Expected at least 10 buildings, found %d", len(values))
+	}
+	if stats := c.Cache.Stats(); stats.Entries != 0 || stats.Rejected != 1 {
+		t.Errorf("Expected result to be rejected, found %+v", stats)
+	}
+}
@@ -0,0 +1,132 @@
+package functions
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/api"
+	"diagonal.works/b6/graph"
+)
+
+// centralityOptionsFromTags returns the options controlling centrality
+// measures, from the options passed to graph functions.
+func centralityOptionsFromTags(opts b6.Tags) (graph.CentralityOptions, bool, error) {
+	var options graph.CentralityOptions
+	if t := opts.Get("centrality:samples"); t.IsValid() {
+		if i, err := strconv.Atoi(t.Value.String()); err == nil && i > 0 {
+			options.Samples = i
+		} else {
+			return options, false, fmt.Errorf("expected a positive integer for centrality:samples, found %q", t.Value.String())
+		}
+	}
+	if t := opts.Get("centrality:seed"); t.IsValid() {
+		if i, err := strconv.ParseInt(t.Value.String(), 10, 64); err == nil {
+			options.Seed = i
+		} else {
+			return options, false, fmt.Errorf("expected an integer for centrality:seed, found %q", t.Value.String())
+		}
+	}
+	bySegment := false
+	if t := opts.Get("centrality:by"); t.IsValid() {
+		switch t.Value.String() {
+		case "path":
+		case "segment":
+			bySegment = true
+		default:
+			return options, false, fmt.Errorf("expected path or segment for centrality:by, found %q", t.Value.String())
+		}
+	}
+	return options, bySegment, nil
+}
+
+// computeCentrality returns either the betweenness or closeness scores
+// requested by the given options, keyed by path ID, or by segment
+// geometry.
+func computeCentrality(context *api.Context, options b6.UntypedCollection, radius []float64, betweenness bool) (b6.Collection[any, float64], error) {
+	opts, err := api.CollectionToTags(options)
+	if err != nil {
+		return b6.Collection[any, float64]{}, err
+	}
+	co, bySegment, err := centralityOptionsFromTags(opts)
+	if err != nil {
+		return b6.Collection[any, float64]{}, err
+	}
+	if len(radius) > 0 {
+		co.Radius = radius[0]
+	}
+	co.Cores = context.Cores
+	weights, err := weightsFromContext(context, options)
+	if err != nil {
+		return b6.Collection[any, float64]{}, err
+	}
+	centrality, err := graph.ComputeCentrality(context.Context, weights, co, context.World)
+	if err != nil {
+		return b6.Collection[any, float64]{}, err
+	}
+
+	c := b6.ArrayCollection[any, float64]{}
+	if bySegment {
+		scores := centrality.Closeness
+		if betweenness {
+			scores = centrality.Betweenness
+		}
+		keys := make([]b6.SegmentKey, 0, len(scores))
+		for key := range scores {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i].Less(keys[j]) })
+		for _, key := range keys {
+			if path, ok := context.World.FindFeatureByID(key.ID).(b6.PhysicalFeature); ok {
+				c.Keys = append(c.Keys, b6.GeometryFromPoints(*key.ToPathSegment(path).Polyline()))
+				c.Values = append(c.Values, scores[key])
+			}
+		}
+	} else {
+		b, cl := centrality.ByPath()
+		scores := cl
+		if betweenness {
+			scores = b
+		}
+		ids := make([]b6.FeatureID, 0, len(scores))
+		for id := range scores {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i].Less(ids[j]) })
+		for _, id := range ids {
+			c.Keys = append(c.Keys, id)
+			c.Values = append(c.Values, scores[id])
+		}
+	}
+	return c.Collection(), nil
+}
+
+// Return the betweenness centrality of the street network, being the
+// number of shortest paths between pairs of points that pass along each
+// path, with paths of equal cost sharing the count.
+// Keys are the IDs of paths, with the highest score of any of their
+// segments, or the geometry of each segment with centrality:by=segment.
+// Only paths shorter than the optional radius, in the units of the
+// weights, typically seconds, are considered, measuring local rather than
+// global centrality.
+// In addition to the options described in accessible-all, computation can
+// be controlled with:
+// centrality:samples: the number of randomly chosen origins to search
+// from, with scores scaled to estimate those from every origin
+// centrality:seed: seeds the choice of sampled origins
+// centrality:by: either path or segment
+// Scores can be added as tags for rendering, for example:
+// add-tags (map (betweenness {"mode": "walk"} 600.0) {b -> float-tag "betweenness" b})
+func betweenness(context *api.Context, options b6.UntypedCollection, radius ...float64) (b6.Collection[any, float64], error) {
+	return computeCentrality(context, options, radius, true)
+}
+
+// Return the harmonic closeness centrality of the street network, being
+// the sum of the reciprocals of the costs of the shortest paths to each
+// point from every other. The closeness of a segment is the mean of that
+// of its ends.
+// Keys, the optional radius, and options are as described in betweenness.
+func closeness(context *api.Context, options b6.UntypedCollection, radius ...float64) (b6.Collection[any, float64], error) {
+	return computeCentrality(context, options, radius, false)
+}
@@ -0,0 +1,79 @@
+package functions
+
+import (
+	"context"
+	"testing"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/api"
+	"diagonal.works/b6/ingest"
+	"diagonal.works/b6/test/camden"
+)
+
+func TestCentrality(t *testing.T) {
+	w := camden.BuildGranarySquareForTests(t)
+	if w == nil {
+		return
+	}
+	c := &api.Context{
+		World:           w,
+		Cores:           2,
+		FunctionSymbols: Functions(),
+		Adaptors:        Adaptors(),
+		Context:         context.Background(),
+	}
+
+	v, err := api.EvaluateString("betweenness {\"mode\": \"walk\", \"centrality:samples\": \"50\"} 300.0", c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scores := make(map[any]float64)
+	if err := api.FillMap(v.(b6.Collection[any, float64]), scores); err != nil {
+		t.Fatal(err)
+	}
+	if len(scores) == 0 {
+		t.Fatal("Expected betweenness scores, found none")
+	}
+	positive := 0
+	for key, score := range scores {
+		if _, ok := key.(b6.FeatureID); !ok {
+			t.Fatalf("Expected path IDs as keys, found %T", key)
+		}
+		if score > 0.0 {
+			positive++
+		}
+	}
+	if positive == 0 {
+		t.Error("Expected at least one path with positive betweenness")
+	}
+
+	v, err = api.EvaluateString("closeness {\"mode\": \"walk\", \"centrality:samples\": \"50\", \"centrality:by\": \"segment\"} 300.0", c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := v.(b6.Collection[any, float64]).Begin()
+	if ok, err := i.Next(); err != nil || !ok {
+		t.Fatalf("Expected closeness scores, found %v, %s", ok, err)
+	} else if _, ok := i.Key().(b6.Geometry); !ok {
+		t.Errorf("Expected segment geometries as keys, found %T", i.Key())
+	}
+
+	change, err := api.EvaluateString("add-tags (map (betweenness {\"mode\": \"walk\", \"centrality:samples\": \"50\"} 300.0) {b -> float-tag \"betweenness\" b})", c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mutable := ingest.NewMutableOverlayWorld(w)
+	if _, err := change.(ingest.Change).Apply(mutable); err != nil {
+		t.Fatalf("Expected no error applying change, found: %s", err)
+	}
+	for key := range scores {
+		if f := mutable.FindFeatureByID(key.(b6.FeatureID)); f == nil || !f.Get("betweenness").IsValid() {
+			t.Errorf("Expected betweenness tag on %s", key)
+			break
+		}
+	}
+
+	if _, err := api.EvaluateString("betweenness {\"mode\": \"walk\", \"centrality:by\": \"area\"}", c); err == nil {
+		t.Error("Expected an error for an invalid centrality:by")
+	}
+}
@@ -0,0 +1,52 @@
+package functions
+
+import (
+	"testing"
+
+	"diagonal.works/b6/api"
+	"diagonal.works/b6/test/camden"
+)
+
+func TestComplete(t *testing.T) {
+	granarySquare := camden.BuildGranarySquareForTests(t)
+	completer := NewCompleter()
+
+	tests := []struct {
+		name       string
+		expression string
+		// The first completion expected, and the text it replaces
+		expected api.Completion
+	}{
+		{"Function", "find-fea", api.Completion{Type: api.CompletionTypeFunction, Text: "find-feature", Begin: 0, End: 8, Description: "find-feature id"}},
+		{"Argument", "find-feature ", api.Completion{Type: api.CompletionTypeArgument, Text: "id", Begin: 13, End: 13, Description: "b6.FeatureID"}},
+		{"PipelinedArgument", "find [#building] | map ", api.Completion{Type: api.CompletionTypeArgument, Text: "function", Begin: 23, End: 23, Description: "api.Callable"}},
+		{"NestedArgument", "add-ints 1 (count ", api.Completion{Type: api.CompletionTypeArgument, Text: "collection", Begin: 18, End: 18, Description: "b6.Collection[interface {},interface {}]"}},
+		{"FunctionMatchingArgument", "add-ints 1 (cou", api.Completion{Type: api.CompletionTypeFunction, Text: "count", Begin: 12, End: 15, Description: "count collection"}},
+		{"TagKey", "find [#build", api.Completion{Type: api.CompletionTypeTag, Text: "#building", Begin: 6, End: 12}},
+		{"SearchableTagKey", "find [@wiki", api.Completion{Type: api.CompletionTypeTag, Text: "@wikidata", Begin: 6, End: 11}},
+		{"TagValue", "find [#building=y", api.Completion{Type: api.CompletionTypeTag, Text: "#building=yes", Begin: 6, End: 17}},
+		{"FeatureIDPrefix", "find-feature /g", api.Completion{Type: api.CompletionTypeFeatureID, Text: "/gb/uprn/", Begin: 13, End: 15}},
+		{"FeatureID", "find-feature /n/6082053666", api.Completion{Type: api.CompletionTypeFeatureID, Text: "/n/6082053666", Begin: 13, End: 26, Description: "Vermuteria"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			completions := completer.Complete(test.expression, len(test.expression), granarySquare)
+			if len(completions) == 0 {
+				t.Fatal("Expected at least one completion")
+			}
+			if completions[0] != test.expected {
+				t.Errorf("Expected %+v, found %+v", test.expected, completions[0])
+			}
+		})
+	}
+}
+
+func TestCompleteUsesCursor(t *testing.T) {
+	completer := NewCompleter()
+	const e = "find [#build] | count"
+	completions := completer.Complete(e, len("find [#build"), camden.BuildGranarySquareForTests(t))
+	if len(completions) != 1 || completions[0].Text != "#building" {
+		t.Errorf("Expected a completion for #building, found %+v", completions)
+	}
+}
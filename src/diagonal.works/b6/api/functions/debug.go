@@ -22,3 +22,29 @@ func debugTokens(c *api.Context, id b6.Identifiable) (b6.Collection[int, string]
 func debugAllQuery(c *api.Context, token string) (search.Query, error) {
 	return search.All{Token: token}, nil
 }
+
+// Return statistics for the cache of function results, keyed by name.
+// Intended for debugging use only.
+func debugCacheStats(c *api.Context) (b6.Collection[string, int], error) {
+	if c.Cache == nil {
+		return b6.Collection[string, int]{}, fmt.Errorf("Caching is disabled")
+	}
+	stats := c.Cache.Stats()
+	return b6.ArrayCollection[string, int]{
+		Keys:   []string{"hits", "misses", "evictions", "rejected", "entries", "bytes"},
+		Values: []int{stats.Hits, stats.Misses, stats.Evictions, stats.Rejected, stats.Entries, stats.Bytes},
+	}.Collection(), nil
+}
+
+// Return the result of calling the given function, together with a
+// profile of the time spent in the function calls it makes.
+// Intended for debugging use only.
+func profile(c *api.Context, f func(c *api.Context) (interface{}, error)) (interface{}, error) {
+	profiled := *c
+	profiled.Profile = api.NewProfile()
+	v, err := f(&profiled)
+	if err != nil {
+		return nil, err
+	}
+	return &api.ProfiledResult{Value: v, Profile: profiled.Profile}, nil
+}
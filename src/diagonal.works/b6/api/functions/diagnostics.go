@@ -0,0 +1,99 @@
+package functions
+
+import (
+	"sort"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/api"
+	"diagonal.works/b6/graph"
+	"diagonal.works/b6/ingest"
+)
+
+// Return the connected component of each path of the street network.
+// Components are numbered from 0 in decreasing order of the number of
+// paths they contain, so paths outside component 0 typically form islands
+// that can't be reached from most of the network. Paths are connected if
+// they share a point, regardless of the direction in which they can be
+// traversed.
+// See accessible-all for options values.
+func networkComponents(context *api.Context, options b6.UntypedCollection) (b6.Collection[b6.FeatureID, int], error) {
+	weights, err := weightsFromContext(context, options)
+	if err != nil {
+		return b6.Collection[b6.FeatureID, int]{}, err
+	}
+	components, _, err := graph.NetworkComponents(weights, context.World)
+	if err != nil {
+		return b6.Collection[b6.FeatureID, int]{}, err
+	}
+	c := b6.ArrayCollection[b6.FeatureID, int]{
+		Keys:   make([]b6.FeatureID, 0, len(components)),
+		Values: make([]int, 0, len(components)),
+	}
+	for id := range components {
+		c.Keys = append(c.Keys, id)
+	}
+	sort.Slice(c.Keys, func(i, j int) bool { return c.Keys[i].Less(c.Keys[j]) })
+	for _, id := range c.Keys {
+		c.Values = append(c.Values, components[id])
+	}
+	return c.Collection(), nil
+}
+
+// Return points that indicate problems with the street network, commonly
+// leading to routing failures.
+// Values describe the problem with each point:
+// dead-end: the end of a path that isn't connected to any other
+// gap: a dead end within the given threshold distance in meters of a path
+// it isn't connected to, and that can't be reached within ten times the
+// threshold along the network
+// unconnected-entrance: a point tagged with entrance that isn't part of
+// any path of the network
+// See accessible-all for options values.
+func networkProblems(context *api.Context, options b6.UntypedCollection, threshold float64) (b6.Collection[b6.FeatureID, string], error) {
+	d, err := diagnoseNetwork(context, options, threshold)
+	if err != nil {
+		return b6.Collection[b6.FeatureID, string]{}, err
+	}
+	gaps := make(map[b6.FeatureID]struct{}, len(d.Gaps))
+	for _, gap := range d.Gaps {
+		gaps[gap.DeadEnd] = struct{}{}
+	}
+	c := b6.ArrayCollection[b6.FeatureID, string]{
+		Keys:   make([]b6.FeatureID, 0, len(d.DeadEnds)+len(d.UnconnectedEntrances)),
+		Values: make([]string, 0, len(d.DeadEnds)+len(d.UnconnectedEntrances)),
+	}
+	for _, id := range d.DeadEnds {
+		c.Keys = append(c.Keys, id)
+		if _, ok := gaps[id]; ok {
+			c.Values = append(c.Values, "gap")
+		} else {
+			c.Values = append(c.Values, "dead-end")
+		}
+	}
+	for _, id := range d.UnconnectedEntrances {
+		c.Keys = append(c.Keys, id)
+		c.Values = append(c.Values, "unconnected-entrance")
+	}
+	return c.Collection(), nil
+}
+
+// Add paths and points to close the gaps in the street network reported
+// by network-problems, connecting each dead end to the closest point on
+// the path across the gap. Points within 4m of an existing point on that
+// path are replaced by it.
+// See accessible-all for options values.
+func connectNetworkGaps(context *api.Context, options b6.UntypedCollection, threshold float64) (ingest.Change, error) {
+	d, err := diagnoseNetwork(context, options, threshold)
+	if err != nil {
+		return nil, err
+	}
+	return d.FixGaps(b6.MetersToAngle(4.0), context.World).Change(context.World), nil
+}
+
+func diagnoseNetwork(context *api.Context, options b6.UntypedCollection, threshold float64) (*graph.NetworkDiagnostics, error) {
+	weights, err := weightsFromContext(context, options)
+	if err != nil {
+		return nil, err
+	}
+	return graph.DiagnoseNetwork(weights, graph.NetworkDiagnosticsOptions{GapThreshold: b6.MetersToAngle(threshold)}, context.World)
+}
@@ -0,0 +1,94 @@
+package functions
+
+import (
+	"context"
+	"testing"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/api"
+	"diagonal.works/b6/ingest"
+	"diagonal.works/b6/test/camden"
+)
+
+func TestNetworkDiagnostics(t *testing.T) {
+	w := camden.BuildGranarySquareForTests(t)
+	if w == nil {
+		return
+	}
+	c := &api.Context{
+		World:           w,
+		Cores:           2,
+		FunctionSymbols: Functions(),
+		Adaptors:        Adaptors(),
+		Context:         context.Background(),
+	}
+
+	v, err := api.EvaluateString("network-components {\"mode\": \"walk\"}", c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	components := make(map[b6.FeatureID]int)
+	if err := api.FillMap(v.(b6.Collection[b6.FeatureID, int]), components); err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[int]int)
+	for _, component := range components {
+		counts[component]++
+	}
+	if len(components) == 0 || counts[0] == 0 {
+		t.Fatalf("Expected paths in component 0, found %v", counts)
+	}
+	for component, n := range counts {
+		if component > 0 && n > counts[0] {
+			t.Errorf("Expected component 0 to be the largest, found %d with %d paths", component, n)
+		}
+	}
+
+	v, err = api.EvaluateString("network-problems {\"mode\": \"walk\"} 5.0", c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	problems := make(map[b6.FeatureID]string)
+	if err := api.FillMap(v.(b6.Collection[b6.FeatureID, string]), problems); err != nil {
+		t.Fatal(err)
+	}
+	gaps := 0
+	for id, problem := range problems {
+		switch problem {
+		case "gap":
+			gaps++
+		case "dead-end", "unconnected-entrance":
+		default:
+			t.Errorf("Unexpected problem %q for %s", problem, id)
+		}
+	}
+	if len(problems) == 0 {
+		t.Error("Expected at least one dead end")
+	}
+
+	change, err := api.EvaluateString("connect-network-gaps {\"mode\": \"walk\"} 5.0", c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := ingest.NewMutableOverlayWorld(w)
+	if _, err := change.(ingest.Change).Apply(m); err != nil {
+		t.Fatalf("Expected no error applying change, found: %s", err)
+	}
+	c.World = m
+	if v, err = api.EvaluateString("network-problems {\"mode\": \"walk\"} 5.0", c); err != nil {
+		t.Fatal(err)
+	}
+	problems = make(map[b6.FeatureID]string)
+	if err := api.FillMap(v.(b6.Collection[b6.FeatureID, string]), problems); err != nil {
+		t.Fatal(err)
+	}
+	remaining := 0
+	for _, problem := range problems {
+		if problem == "gap" {
+			remaining++
+		}
+	}
+	if gaps > 0 && remaining >= gaps {
+		t.Errorf("Expected fewer gaps after connecting them, found %d, then %d", gaps, remaining)
+	}
+}
@@ -3,7 +3,7 @@ package functions
 // Code generated by b6-api. DO NOT EDIT.
 
 var functionDocs = map[string]Doc{
-	"accessible-all": Doc{Doc: "Return the a collection of the features reachable from the given origins, within the given duration in seconds, that match the given query.\nKeys of the collection are origins, values are reachable destinations.\nOptions are passed as tags containing the mode, and mode specific values. Examples include:\nWalking, with the default speed of 4.5km/h:\nmode=walk\nWalking, a speed of 3km/h:\nmode=walk, walk:speed=3.0\nTransit at peak times:\nmode=transit\nTransit at off-peak times:\nmode=transit, peak=no\nWalking, accounting for elevation:\nelevation=true (optional: elevation:uphill=2.0 elevation:downhill=1.2)\nWalking, accounting for elevation, adding double the penalty for uphill:\nelevation=true, elevation:uphill=2.0\nWalking, with the resulting collection flipped such that keys are\ndestinations and values are origins. Useful for efficiency if you assume\nsymmetry, and the number of destinations is considerably smaller than the\nnumber of origins:\nmode=walk, flip=yes\n", ArgNames: []string{"origins","destinations","duration","options"}},
+	"accessible-all": Doc{Doc: "Return the a collection of the features reachable from the given origins, within the given duration in seconds, that match the given query.\nKeys of the collection are origins, values are reachable destinations.\nOptions are passed as tags containing the mode, and mode specific values. Examples include:\nWalking, with the default speed of 4.5km/h:\nmode=walk\nWalking, a speed of 3km/h:\nmode=walk, walk:speed=3.0\nTransit at peak times:\nmode=transit\nTransit at off-peak times:\nmode=transit, peak=no\nCycling, with the default speed of 15km/h, preferring paths with lower\nlevels of traffic stress:\nmode=cycle\nCycling at 20km/h, avoiding paths with a level of traffic stress above 2:\nmode=cycle, cycle:speed=20, cycle:max-stress=2\nDriving, or travelling by bus, at speed limits:\nmode=car or mode=bus\nA routing profile, loaded from the file given by b6's --routing-profiles\nflag:\nprofile=wheelchair\nA routing profile, given inline, see RoutingProfileYAML:\nprofile:speed=4, profile:exclude=#highway=steps, profile:penalties:[#barrier=kerb]=30\nWalking, accounting for elevation:\nelevation=true (optional: elevation:uphill=2.0 elevation:downhill=1.2)\nWalking, accounting for elevation, adding double the penalty for uphill:\nelevation=true, elevation:uphill=2.0\nWalking, with the resulting collection flipped such that keys are\ndestinations and values are origins. Useful for efficiency if you assume\nsymmetry, and the number of destinations is considerably smaller than the\nnumber of origins:\nmode=walk, flip=yes\n", ArgNames: []string{"origins","destinations","duration","options"}},
 	"accessible-routes": Doc{Doc: "", ArgNames: []string{"origin","destinations","duration","options"}},
 	"add": Doc{Doc: "Return a added to b.\n", ArgNames: []string{"a","b"}},
 	"add-collection": Doc{Doc: "Add a collection feature with the given id, tags and items.\n", ArgNames: []string{"id","tags","collection"}},
@@ -21,6 +21,7 @@ var functionDocs = map[string]Doc{
 	"apply-to-path": Doc{Doc: "Wrap the given function such that it will only be called when passed a path.\n", ArgNames: []string{"f"}},
 	"apply-to-point": Doc{Doc: "Wrap the given function such that it will only be called when passed a point.\n", ArgNames: []string{"f"}},
 	"area": Doc{Doc: "Return the area of the given polygon in m².\n", ArgNames: []string{"area"}},
+	"betweenness": Doc{Doc: "Return the betweenness centrality of the street network, being the\nnumber of shortest paths between pairs of points that pass along each\npath, with paths of equal cost sharing the count.\nKeys are the IDs of paths, with the highest score of any of their\nsegments, or the geometry of each segment with centrality:by=segment.\nOnly paths shorter than the optional radius, in the units of the\nweights, typically seconds, are considered, measuring local rather than\nglobal centrality.\nIn addition to the options described in accessible-all, computation can\nbe controlled with:\ncentrality:samples: the number of randomly chosen origins to search\nfrom, with scores scaled to estimate those from every origin\ncentrality:seed: seeds the choice of sampled origins\ncentrality:by: either path or segment\nScores can be added as tags for rendering, for example:\nadd-tags (map (betweenness {\"mode\": \"walk\"} 600.0) {b -> float-tag \"betweenness\" b})\n", ArgNames: []string{"options","radius"}},
 	"building-access": Doc{Doc: "Deprecated. Use accessible.\n", ArgNames: []string{"origins","limit","mode"}},
 	"call": Doc{Doc: "", ArgNames: []string{"f","args"}},
 	"cap-polygon": Doc{Doc: "Return a polygon approximating a spherical cap with the given center and radius in meters.\n", ArgNames: []string{"center","radius"}},
@@ -28,11 +29,14 @@ var functionDocs = map[string]Doc{
 	"changes-from-file": Doc{Doc: "Return the changes contained in the given file.\nAs the file is read by the b6 server process, the filename it relative\nto the filesystems it sees. Reading from files on cloud storage is\nsupported.\n", ArgNames: []string{"filename"}},
 	"changes-to-file": Doc{Doc: "Export the changes that have been applied to the world to the given filename as yaml.\nAs the file is written by the b6 server process, the filename it relative\nto the filesystems it sees. Writing files to cloud storage is\nsupported.\n", ArgNames: []string{"filename"}},
 	"clamp": Doc{Doc: "Return the given value, unless it falls outside the given inclusive bounds, in which case return the boundary.\n", ArgNames: []string{"v","low","high"}},
+	"clone-world": Doc{Doc: "Return a change that creates a world with the given ID, containing the\nmodifications made to the current world.\nChanges to either world don't affect the other, allowing scenarios to\nbranch from each other, rather than only from the base world.\n", ArgNames: []string{"id"}},
+	"closeness": Doc{Doc: "Return the harmonic closeness centrality of the street network, being\nthe sum of the reciprocals of the costs of the shortest paths to each\npoint from every other. The closeness of a segment is the mean of that\nof its ends.\nKeys, the optional radius, and options are as described in betweenness.\n", ArgNames: []string{"options","radius"}},
 	"closest": Doc{Doc: "Return the closest feature from the given origin via the given mode, within the given distance in meters, matching the given query.\nSee accessible-all for options values.\n", ArgNames: []string{"origin","options","distance","query"}},
 	"closest-distance": Doc{Doc: "Return the distance through the graph of the closest feature from the given origin via the given mode, within the given distance in meters, matching the given query.\nSee accessible-all for options values.\n", ArgNames: []string{"origin","options","distance","query"}},
 	"collect-areas": Doc{Doc: "Return a single area containing all areas from the given collection.\nIf areas in the collection overlap, loops within the returned area\nwill overlap, which will likely cause undefined behaviour in many\nfunctions.\n", ArgNames: []string{"areas"}},
 	"collection": Doc{Doc: "Return a collection of the given key value pairs.\n", ArgNames: []string{"pairs"}},
 	"connect": Doc{Doc: "Add a path that connects the two given points, if they're not already directly connected.\n", ArgNames: []string{"a","b"}},
+	"connect-network-gaps": Doc{Doc: "Add paths and points to close the gaps in the street network reported\nby network-problems, connecting each dead end to the closest point on\nthe path across the gap. Points within 4m of an existing point on that\npath are replaced by it.\nSee accessible-all for options values.\n", ArgNames: []string{"options","threshold"}},
 	"connect-to-network": Doc{Doc: "Add a path and point to connect given feature to the street network.\nThe street network is defined at the set of paths tagged #highway that\nallow traversal of more than 500m. A point is added to the closest\nnetwork path at the projection of the origin point on that path, unless\nthat point is within 4m of an existing path point.\n", ArgNames: []string{"feature"}},
 	"connect-to-network-all": Doc{Doc: "Add paths and points to connect the given collection of features to the\nnetwork. See connect-to-network for connection details.\nMore efficient than using map with connect-to-network, as the street\nnetwork is only computed once.\n", ArgNames: []string{"features"}},
 	"containing-areas": Doc{Doc: "", ArgNames: []string{"points","q"}},
@@ -44,8 +48,10 @@ var functionDocs = map[string]Doc{
 	"count-valid-keys": Doc{Doc: "Return a collection of the number of occurances of each valid value in the given collection.\nInvalid values are not counted, but case the key to appear in the output.\n", ArgNames: []string{"collection"}},
 	"count-values": Doc{Doc: "Return a collection of the number of occurances of each value in the given collection.\n", ArgNames: []string{"collection"}},
 	"debug-all-query": Doc{Doc: "Deprecated.\n", ArgNames: []string{"token"}},
+	"debug-cache-stats": Doc{Doc: "Return statistics for the cache of function results, keyed by name.\nIntended for debugging use only.\n", ArgNames: []string{}},
 	"debug-tokens": Doc{Doc: "Return the search index tokens generated for the given feature.\nIntended for debugging use only.\n", ArgNames: []string{"id"}},
 	"degree": Doc{Doc: "Return the number of paths connected to the given point.\nA single path will be counted twice if the point isn't at one of its\ntwo ends - once in one direction, and once in the other.\n", ArgNames: []string{"point"}},
+	"diff-world": Doc{Doc: "Return the features that differ between the given world and the\ncurrent one.\nValues are added, for features only present in the current world,\nremoved, for those only present in the given world, or modified, for\nthose with different tags or members. Only features modified in at\nleast one of the worlds are compared.\n", ArgNames: []string{"id"}},
 	"distance-meters": Doc{Doc: "Return the distance in meters between the given points.\n", ArgNames: []string{"a","b"}},
 	"distance-to-point-meters": Doc{Doc: "Return the distance in meters between the given path, and the project of the give point onto it.\n", ArgNames: []string{"path","point"}},
 	"divide": Doc{Doc: "Return a divided by b.\n", ArgNames: []string{"a","b"}},
@@ -64,7 +70,9 @@ var functionDocs = map[string]Doc{
 	"find-relations": Doc{Doc: "Return a collection of the relation features present in the world that match the given query.\nKeys are IDs, and values are features.\n", ArgNames: []string{"query"}},
 	"first": Doc{Doc: "Return the first value of the given pair.\n", ArgNames: []string{"pair"}},
 	"flatten": Doc{Doc: "Return a collection with keys and values taken from the collections that form the values of the given collection.\n", ArgNames: []string{"collection"}},
+	"float-tag": Doc{Doc: "Return a tag with the given key, and the given float as its value.\nThe value can be read with float-value or get-float.\n", ArgNames: []string{"key","value"}},
 	"float-value": Doc{Doc: "Return the value of the given tag as a float.\nPropagates error if the value isn't a valid float.\n", ArgNames: []string{"tag"}},
+	"freeze-world": Doc{Doc: "Return a change that prevents further modification of the current\nworld.\nThe world can still be cloned, to continue modifying a copy.\n", ArgNames: []string{}},
 	"geojson-areas": Doc{Doc: "Return the areas present in the given geojson.\n", ArgNames: []string{"g"}},
 	"get": Doc{Doc: "Return the tag with the given key on the given feature.\nReturns a tag. To return the string value of a tag, use get-string.\n", ArgNames: []string{"id","key"}},
 	"get-centroid": Doc{Doc: "Return the centroid of the given feature.\nReturns either the centroid of an invalid geometry.\n", ArgNames: []string{"id"}},
@@ -98,6 +106,10 @@ var functionDocs = map[string]Doc{
 	"materialise": Doc{Doc: "Return a change that adds a collection feature to the world with the given ID, containing the result of calling the given function.\nThe given function isn't passed any arguments.\nAlso adds an expression feature (with the same namespace and value)\nrepresenting the given function.\n", ArgNames: []string{"id","function"}},
 	"materialise-map": Doc{Doc: "", ArgNames: []string{"collection","id","function"}},
 	"merge-changes": Doc{Doc: "Return a change that will apply all the changes in the given collection.\nChanges are applied transactionally. If the application of one change\nfails (for example, because it includes a path that references a missing\npoint), then no changes will be applied.\n", ArgNames: []string{"collection"}},
+	"nearest": Doc{Doc: "Return a collection of the k features matching the given query that are\nclosest to the given point, by straight line distance, in order of\nincreasing distance.\nIf a distance in meters is given, only features within that distance\nare returned.\nKeys are IDs, and values are features.\n", ArgNames: []string{"origin","query","k","distance"}},
+	"nearest-distances": Doc{Doc: "Return a collection of the k features matching the given query that are\nclosest to the given point, by straight line distance, in order of\nincreasing distance.\nIf a distance in meters is given, only features within that distance\nare returned.\nKeys are IDs, and values are distances in meters.\n", ArgNames: []string{"origin","query","k","distance"}},
+	"network-components": Doc{Doc: "Return the connected component of each path of the street network.\nComponents are numbered from 0 in decreasing order of the number of\npaths they contain, so paths outside component 0 typically form islands\nthat can't be reached from most of the network. Paths are connected if\nthey share a point, regardless of the direction in which they can be\ntraversed.\nSee accessible-all for options values.\n", ArgNames: []string{"options"}},
+	"network-problems": Doc{Doc: "Return points that indicate problems with the street network, commonly\nleading to routing failures.\nValues describe the problem with each point:\ndead-end: the end of a path that isn't connected to any other\ngap: a dead end within the given threshold distance in meters of a path\nit isn't connected to, and that can't be reached within ten times the\nthreshold along the network\nunconnected-entrance: a point tagged with entrance that isn't part of\nany path of the network\nSee accessible-all for options values.\n", ArgNames: []string{"options","threshold"}},
 	"or": Doc{Doc: "Return a query that will match features that match either of the given queries.\n", ArgNames: []string{"a","b"}},
 	"ordered-join": Doc{Doc: "Returns a path formed by joining the two given paths.\nIf necessary to maintain consistency, the order of points is reversed,\ndetermined by which points are shared between the paths. Returns an error\nif no endpoints are shared.\n", ArgNames: []string{"pathA","pathB"}},
 	"pair": Doc{Doc: "Return a pair containing the given values.\n", ArgNames: []string{"first","second"}},
@@ -108,11 +120,16 @@ var functionDocs = map[string]Doc{
 	"point-features": Doc{Doc: "Return a collection of the point features referenced by the given feature.\nKeys are ids of the respective value, values are point features. Area\nfeatures return the points referenced by their path features.\n", ArgNames: []string{"f"}},
 	"point-paths": Doc{Doc: "Return a collection of the path features referencing the given point.\nKeys are the ids of the respective paths.\n", ArgNames: []string{"id"}},
 	"points": Doc{Doc: "Return a collection of the points of the given geometry.\nKeys are ordered integers from 0, values are points.\n", ArgNames: []string{"geometry"}},
+	"profile": Doc{Doc: "Return the result of calling the given function, together with a\nprofile of the time spent in the function calls it makes.\nIntended for debugging use only.\n", ArgNames: []string{"f"}},
 	"reachable": Doc{Doc: "Return the a collection of the features reachable from the given origin via the given mode, within the given distance in meters, that match the given query.\nSee accessible-all for options values.\nDeprecated. Use accessible-all.\n", ArgNames: []string{"origin","options","distance","query"}},
 	"reachable-area": Doc{Doc: "Return the area formed by the convex hull of the features matching the given query reachable from the given origin via the given mode specified in options, within the given distance in meters.\nSee accessible-all for options values.\n", ArgNames: []string{"origin","options","distance"}},
 	"rectangle-polygon": Doc{Doc: "Return a rectangle polygon with the given top left and bottom right points.\n", ArgNames: []string{"a","b"}},
 	"remove-tag": Doc{Doc: "Remove the tag with the given key from the given feature.\n", ArgNames: []string{"id","key"}},
 	"remove-tags": Doc{Doc: "Remove the given tags from the given features.\nThe keys of the given collection specify the features to change, the\nvalues provide the key of the tag to be removed.\n", ArgNames: []string{"collection"}},
+	"rename-world": Doc{Doc: "Return a change that moves the current world to the given ID, which\nmustn't already be in use.\n", ArgNames: []string{"id"}},
+	"render-map": Doc{Doc: "Render the given geometries into a PNG image, written to the given\nfilename, with the map's bounds fitted to those of the geometries,\nunless given explicitly.\nFeatures with a b6:colour tag giving a hex colour are drawn in that\ncolour.\nOptions are given as a collection of keys and values:\nbounds: the bounds of the map, as lat,lng,lat,lng\nwidth, height: the size of the image in pixels, default 1024x768\npadding: the space around the bounds in pixels, default 16\nbackground, fill, stroke: colours as #rrggbb or #rrggbbaa, or none\nstroke-width, point-radius: sizes in pixels\nAs the file is written by the b6 server process, the filename is\nrelative to the filesystems it sees.\n", ArgNames: []string{"geometries","filename","options"}},
+	"route-summary": Doc{Doc: "Return a summary of the given route, for comparison with alternatives.\nKeys of the collection are:\nlength: the length of the route, in meters\ncost: the cost of the route, typically the time taken in seconds\nelevation-gain: the total climb in meters, from ele tags\ncrossings: the number of crossings passed through\nhighway:<value>: the proportion of the route's length along paths\ntagged with each value of #highway.\n", ArgNames: []string{"route"}},
+	"routes": Doc{Doc: "Return the shortest route from the given origin to the given\ndestination, within the given duration in seconds, followed by\nalternative routes that differ significantly from it.\nKeys of the collection are the rank of the route, starting at 0 for the\nshortest.\nAlternatives are found by repeatedly penalising the paths used by\nprevious routes. In addition to the options described in accessible-all,\nthey can be controlled with:\nalternatives: the maximum number of routes returned, default 3\nalternatives:penalty: the factor by which the cost of paths used by\nprevious routes is multiplied, default 1.4\nalternatives:max-overlap: the maximum proportion of a route's length\nshared with previous routes, default 0.75\nalternatives:max-stretch: the maximum ratio between the cost of a\nroute and that of the shortest, default 1.5\n", ArgNames: []string{"origin","destination","duration","options"}},
 	"s2-center": Doc{Doc: "Return a collection the center of the s2 cell with the given token.\n", ArgNames: []string{"token"}},
 	"s2-covering": Doc{Doc: "Return a collection of of s2 cells tokens that cover the given area at the given level.\n", ArgNames: []string{"area","minLevel","maxLevel"}},
 	"s2-grid": Doc{Doc: "Return a collection of points representing the centroids of s2 cells that cover the given area at the given level.\n", ArgNames: []string{"area","level"}},
@@ -121,8 +138,14 @@ var functionDocs = map[string]Doc{
 	"sample-points": Doc{Doc: "Return a collection of points along the given path, with the given distance in meters between them.\nKeys are ordered integers from 0, values are points.\n", ArgNames: []string{"path","distanceMeters"}},
 	"sample-points-along-paths": Doc{Doc: "Return a collection of points along the given paths, with the given distance in meters between them.\nKeys are the id of the respective path, values are points.\n", ArgNames: []string{"paths","distanceMeters"}},
 	"second": Doc{Doc: "Return the second value of the given pair.\n", ArgNames: []string{"pair"}},
+	"set-world-metadata": Doc{Doc: "Return a change that sets the metadata of the current world with the\ngiven key, either name or author, to the given value.\n", ArgNames: []string{"key","value"}},
+	"shortest-route": Doc{Doc: "Return the shortest route from the given origin to the given\ndestination, within the given duration in seconds.\nIf the origin or destination are areas, the route starts or ends at the\nclosest point on their boundary connected to the street network.\nReturns an empty route if the destination can't be reached.\nUses a contraction hierarchy, given by b6's --hierarchies flag, if one\nwas built with the same options, and the world hasn't been modified\nalong the paths it includes.\nSee accessible-all for options values.\n", ArgNames: []string{"origin","destination","duration","options"}},
 	"sightline": Doc{Doc: "", ArgNames: []string{"from","radius"}},
 	"snap-area-edges": Doc{Doc: "Return an area formed by projecting the edges of the given polygon onto the paths present in the world matching the given query.\nPaths beyond the given threshold in meters are ignored.\n", ArgNames: []string{"area","query","threshold"}},
+	"snapshot-world": Doc{Doc: "Return a change that creates a read-only world with the given ID,\ncontaining the modifications made to the current world.\nThe snapshot preserves the current state of the world, to be compared\nagainst, or cloned, as it's modified further.\n", ArgNames: []string{"id"}},
+	"spatial-join": Doc{Doc: "Return the pairs of features for which the given predicate holds,\nwith keys from the first collection, and values the IDs of features\nmatching the given query.\nThe predicate is one of intersects, contains, within, or\nwithin-distance, which also needs a distance in meters. contains means\nthe first feature contains the second, and within the reverse.\nFeatures from the query are found via the spatial index, and the join\nis evaluated in parallel. Features aren't joined with themselves.\n", ArgNames: []string{"left","right","predicate","distance"}},
+	"spatial-join-add-tags": Doc{Doc: "Return a change that adds a tag with the given key to each feature in\nthe given collection, with the ID of the feature matching the given\nquery for which the predicate holds as its value. If there are\nmultiple matching features, the one with the smallest ID is used.\nPredicates are as for spatial-join.\n", ArgNames: []string{"left","right","predicate","key","distance"}},
+	"spatial-join-count": Doc{Doc: "Return the number of features matching the given query for which the\ngiven predicate holds with each feature in the given collection,\nincluding those for which there are none.\nPredicates are as for spatial-join.\n", ArgNames: []string{"left","right","predicate","distance"}},
 	"sum": Doc{Doc: "Return the sum of all values in a given collection.\n", ArgNames: []string{"collection"}},
 	"sum-by-key": Doc{Doc: "Return a collection of the result of summing the values of each item with the same key.\nRequires values to be integers.\n", ArgNames: []string{"c"}},
 	"tag": Doc{Doc: "Return a tag with the given key and value.\n", ArgNames: []string{"key","value"}},
@@ -135,6 +158,7 @@ var functionDocs = map[string]Doc{
 	"to-geojson-collection": Doc{Doc: "", ArgNames: []string{"renderables"}},
 	"to-str": Doc{Doc: "", ArgNames: []string{"a"}},
 	"top": Doc{Doc: "Return a collection with the n entries from the given collection with the greatest values.\nRequires the values of the given collection to be integers or floats.\n", ArgNames: []string{"collection","n"}},
+	"travel-times": Doc{Doc: "Return the travel time in seconds from each of the given origins to each\nof the given destinations reachable within the given duration.\nKeys of the collection are origins, values are collections keyed by\ndestination, with the travel time as the value.\nUses a contraction hierarchy, given by b6's --hierarchies flag, if one\nwas built with the same options, and the world hasn't been modified\nalong the paths it includes, which is considerably faster than a\nsearch from each origin when there are many origins and destinations.\nSee accessible-all for options values.\n", ArgNames: []string{"origins","destinations","duration","options"}},
 	"type-area": Doc{Doc: "Return a query that will match area features.\n", ArgNames: []string{}},
 	"type-path": Doc{Doc: "Return a query that will match path features.\n", ArgNames: []string{}},
 	"type-point": Doc{Doc: "Return a query that will match point features.\n", ArgNames: []string{}},
@@ -143,4 +167,6 @@ var functionDocs = map[string]Doc{
 	"with-change": Doc{Doc: "Return the result of calling the given function in a world in which the given change has been applied.\nThe underlying world used by the server is not modified.\n", ArgNames: []string{"change","function"}},
 	"within": Doc{Doc: "Return a query that will match features that intersect the given area.\nDeprecated. Use intersecting.\n", ArgNames: []string{"a"}},
 	"within-cap": Doc{Doc: "Return a query that will match features that intersect a spherical cap centred on the given point, with the given radius in meters.\nDeprecated. Use intersecting-cap.\n", ArgNames: []string{"point","radius"}},
+	"world-info": Doc{Doc: "Return information about the compact indices underlying the current\nworld, to identify the data from which results were derived. Includes\nthe files each index was built from, with their SHA-256 hashes, the OSM\nreplication timestamp, build options and tag mapping, feature counts,\nbounds and block checksums. Keys are prefixed with the position of the\nindex, for example 0/replication, with later indices taking precedence.\n", ArgNames: []string{}},
+	"world-metadata": Doc{Doc: "Return the metadata of the current world.\nKeys are name, author, created, the world it was cloned from as\nparent, and read-only. Keys without a value are omitted.\n", ArgNames: []string{}},
 }
@@ -2,7 +2,11 @@ package functions
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
+	"time"
 
+	"diagonal.works/b6"
 	"diagonal.works/b6/api"
 	"diagonal.works/b6/ingest"
 	"diagonal.works/b6/ingest/compact"
@@ -27,3 +31,69 @@ func exportWorld(c *api.Context, filename string) (int, error) {
 	}
 	return 0, compact.Build(source, &options)
 }
+
+// Return information about the compact indices underlying the current
+// world, to identify the data from which results were derived. Includes
+// the files each index was built from, with their SHA-256 hashes, the OSM
+// replication timestamp, build options and tag mapping, feature counts,
+// bounds and block checksums. Keys are prefixed with the position of the
+// index, for example 0/replication, with later indices taking precedence.
+func worldInfo(c *api.Context) (b6.Collection[string, string], error) {
+	info := b6.ArrayCollection[string, string]{}
+	add := func(key string, value string) {
+		info.Keys = append(info.Keys, key)
+		info.Values = append(info.Values, value)
+	}
+	for i, m := range compact.FindIndexMetadata(c.World) {
+		prefix := fmt.Sprintf("%d/", i)
+		add(prefix+"version", m.Version)
+		add(prefix+"builder", m.Header.Builder)
+		if m.Header.BuildTimestamp != 0 {
+			add(prefix+"built", time.Unix(m.Header.BuildTimestamp, 0).UTC().Format(time.RFC3339))
+		}
+		if m.Header.ReplicationTimestamp != 0 {
+			add(prefix+"replication", time.Unix(m.Header.ReplicationTimestamp, 0).UTC().Format(time.RFC3339))
+		}
+		for j, source := range m.Header.Sources {
+			add(fmt.Sprintf("%ssource/%d/filename", prefix, j), source.Filename)
+			if source.Size > 0 {
+				add(fmt.Sprintf("%ssource/%d/size", prefix, j), strconv.FormatInt(source.Size, 10))
+			}
+			if source.Sha256 != "" {
+				add(fmt.Sprintf("%ssource/%d/sha256", prefix, j), source.Sha256)
+			}
+		}
+		for _, key := range sortedKeys(m.Header.Options) {
+			add(prefix+"option/"+key, m.Header.Options[key])
+		}
+		for _, key := range sortedKeys(m.Header.TagMapping) {
+			add(prefix+"tag-mapping/"+key, m.Header.TagMapping[key])
+		}
+		for _, counts := range m.Header.Counts {
+			add(prefix+"count/"+counts.Namespace+"/points", strconv.FormatUint(counts.Points, 10))
+			add(prefix+"count/"+counts.Namespace+"/paths", strconv.FormatUint(counts.Paths, 10))
+			add(prefix+"count/"+counts.Namespace+"/areas", strconv.FormatUint(counts.Areas, 10))
+			add(prefix+"count/"+counts.Namespace+"/relations", strconv.FormatUint(counts.Relations, 10))
+		}
+		if m.Metadata != nil {
+			if m.Metadata.BoundsLo != nil {
+				add(prefix+"bounds/lo", b6.PointProtoToS2LatLng(m.Metadata.BoundsLo).String())
+				add(prefix+"bounds/hi", b6.PointProtoToS2LatLng(m.Metadata.BoundsHi).String())
+			}
+			add(prefix+"checksum/header", fmt.Sprintf("%08x", m.Metadata.HeaderCrc32C))
+			for _, block := range m.Metadata.Blocks {
+				add(fmt.Sprintf("%schecksum/%d", prefix, block.Offset), fmt.Sprintf("%08x", block.Crc32C))
+			}
+		}
+	}
+	return info.Collection(), nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,60 @@
+package functions
+
+import (
+	"context"
+	"testing"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/api"
+	"diagonal.works/b6/ingest"
+	"diagonal.works/b6/ingest/compact"
+	pb "diagonal.works/b6/proto"
+	"diagonal.works/b6/test"
+)
+
+func TestWorldInfo(t *testing.T) {
+	pbf := ingest.PBFFilesOSMSource{Glob: test.Data(test.GranarySquarePBF), FailWhenNoFiles: true}
+	source, err := ingest.NewFeatureSourceFromPBF(&pbf, &ingest.BuildOptions{Cores: 2}, context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := compact.Options{
+		Goroutines: 2,
+		Header: &pb.CompactHeaderProto{
+			ReplicationTimestamp: 1700000000,
+			Sources:              []*pb.CompactSourceProto{{Filename: "granary-square.osm.pbf"}},
+		},
+	}
+	index, err := compact.BuildInMemory(source, &options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := compact.NewWorldFromData(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &api.Context{World: ingest.NewMutableOverlayWorld(w)}
+	info, err := worldInfo(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := make(map[string]string)
+	if err := b6.FillMap(info, m); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"0/version":                             compact.Version,
+		"0/replication":                         "2023-11-14T22:13:20Z",
+		"0/source/0/filename":                   "granary-square.osm.pbf",
+		"0/count/openstreetmap.org/node/points": "1550",
+	}
+	for key, value := range expected {
+		if m[key] != value {
+			t.Errorf("Expected %q for %s, found %q", value, key, m[key])
+		}
+	}
+	if m["0/checksum/header"] == "" || m["0/bounds/lo"] == "" {
+		t.Errorf("Expected checksums and bounds, found %v", m)
+	}
+}
@@ -109,6 +109,12 @@ func tag(context *api.Context, key string, value string) (b6.Tag, error) {
 	return b6.Tag{Key: key, Value: b6.NewStringExpression(value)}, nil
 }
 
+// Return a tag with the given key, and the given float as its value.
+// The value can be read with float-value or get-float.
+func floatTag(context *api.Context, key string, value float64) (b6.Tag, error) {
+	return b6.Tag{Key: key, Value: b6.NewStringExpression(strconv.FormatFloat(value, 'f', -1, 64))}, nil
+}
+
 // Return the value of the given tag as a string.
 func value(context *api.Context, tag b6.Tag) (string, error) {
 	return tag.Value.String(), nil
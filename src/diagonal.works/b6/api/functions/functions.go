@@ -44,30 +44,36 @@ var functions = api.FunctionSymbols{
 	"join-missing":             joinMissing,
 	"list-feature":             listFeature,
 	// search
-	"find-feature":     findFeature,
-	"find-area":        findAreaFeature,
-	"find-relation":    findRelationFeature,
-	"find-collection":  findCollectionFeature,
-	"find":             find,
-	"find-areas":       findAreaFeatures,
-	"find-relations":   findRelationFeatures,
-	"containing-areas": findAreasContainingPoints,
-	"intersecting":     intersecting,
-	"intersecting-cap": intersectingCap,
-	"tagged":           tagged,
-	"keyed":            keyed,
-	"typed":            typed,
-	"and":              and,
-	"or":               or,
-	"all":              all,
-	"is-valid":         isValid,
-	"type-point":       typePoint,
-	"type-path":        typePath,
-	"type-area":        typeArea,
-	"within":           within,
-	"within-cap":       withinCap,
+	"find-feature":          findFeature,
+	"find-area":             findAreaFeature,
+	"find-relation":         findRelationFeature,
+	"find-collection":       findCollectionFeature,
+	"find":                  find,
+	"find-areas":            findAreaFeatures,
+	"find-relations":        findRelationFeatures,
+	"containing-areas":      findAreasContainingPoints,
+	"intersecting":          intersecting,
+	"intersecting-cap":      intersectingCap,
+	"nearest":               nearest,
+	"nearest-distances":     nearestDistances,
+	"spatial-join":          spatialJoinFeatures,
+	"spatial-join-count":    spatialJoinCount,
+	"spatial-join-add-tags": spatialJoinAddTags,
+	"tagged":                tagged,
+	"keyed":                 keyed,
+	"typed":                 typed,
+	"and":                   and,
+	"or":                    or,
+	"all":                   all,
+	"is-valid":              isValid,
+	"type-point":            typePoint,
+	"type-path":             typePath,
+	"type-area":             typeArea,
+	"within":                within,
+	"within-cap":            withinCap,
 	// features
 	"tag":                       tag,
+	"float-tag":                 floatTag,
 	"value":                     value,
 	"int-value":                 intValue,
 	"float-value":               floatValue,
@@ -113,6 +119,12 @@ var functions = api.FunctionSymbols{
 	"reachable":              reachable,
 	"accessible-all":         accessibleAll,
 	"accessible-routes":      accessibleRoutes,
+	"shortest-route":         shortestRoute,
+	"travel-times":           travelTimes,
+	"routes":                 routes,
+	"route-summary":          routeSummary,
+	"betweenness":            betweenness,
+	"closeness":              closeness,
 	"filter-accessible":      filterAccessible,
 	"closest":                closestFeature,
 	"closest-distance":       closestFeatureDistance,
@@ -120,6 +132,9 @@ var functions = api.FunctionSymbols{
 	"connect":                connect,
 	"connect-to-network":     connectToNetwork,
 	"connect-to-network-all": connectToNetworkAll,
+	"network-components":     networkComponents,
+	"network-problems":       networkProblems,
+	"connect-network-gaps":   connectNetworkGaps,
 	// access
 	"building-access": buildingAccess,
 	// geometry
@@ -140,6 +155,8 @@ var functions = api.FunctionSymbols{
 	"tile-ids":     tileIDs,
 	"tile-ids-hex": tileIDsHex,
 	"tile-paths":   tilePaths,
+	// raster
+	"render-map": renderMap,
 	// geojson
 	"parse-geojson":         parseGeoJSON,
 	"parse-geojson-file":    parseGeoJSONFile,
@@ -171,16 +188,51 @@ var functions = api.FunctionSymbols{
 	"materialise":     materialise,
 	"materialise-map": materialiseMap,
 	// debug
-	"debug-tokens":    debugTokens,
-	"debug-all-query": debugAllQuery,
+	"debug-tokens":      debugTokens,
+	"debug-all-query":   debugAllQuery,
+	"debug-cache-stats": debugCacheStats,
+	"profile":           profile,
 	// export
 	"export-world": exportWorld,
+	"world-info":   worldInfo,
+	// worlds
+	"clone-world":        cloneWorld,
+	"snapshot-world":     snapshotWorld,
+	"freeze-world":       freezeWorld,
+	"rename-world":       renameWorld,
+	"set-world-metadata": setWorldMetadata,
+	"world-metadata":     worldMetadata,
+	"diff-world":         diffWorld,
 }
 
 func Functions() api.FunctionSymbols {
 	return functions // Validated in init()
 }
 
+// CacheableFunctions returns the names of functions that are both
+// deterministic, and expensive enough to be worth caching.
+func CacheableFunctions() []string {
+	return []string{
+		"accessible-all",
+		"accessible-routes",
+		"betweenness",
+		"closeness",
+		"closest",
+		"closest-distance",
+		"containing-areas",
+		"find",
+		"find-areas",
+		"network-components",
+		"network-problems",
+		"paths-to-reach",
+		"reachable",
+		"reachable-area",
+		"routes",
+		"shortest-route",
+		"travel-times",
+	}
+}
+
 type Doc struct {
 	Doc      string
 	ArgNames []string
@@ -346,7 +398,9 @@ var collectionAdaptors = []interface{}{
 	b6.AdaptCollection[b6.FeatureID, b6.Identifiable],
 	b6.AdaptCollection[b6.FeatureID, b6.Geometry],
 	b6.AdaptCollection[b6.FeatureID, b6.PhysicalFeature],
+	b6.AdaptCollection[b6.FeatureID, b6.Route],
 	b6.AdaptCollection[b6.FeatureID, b6.Tag],
+	b6.AdaptCollection[b6.FeatureID, int],
 	b6.AdaptCollection[b6.FeatureID, string],
 	b6.AdaptCollection[b6.Identifiable, string],
 	b6.AdaptCollection[int, b6.Area],
@@ -404,6 +458,18 @@ func NewContext(w b6.World) *api.Context {
 	}
 }
 
+func NewCompleter() *api.Completer {
+	argNames := make(map[string][]string, len(functionDocs))
+	for name, doc := range functionDocs {
+		argNames[name] = doc.ArgNames
+	}
+	return &api.Completer{
+		FunctionSymbols: Functions(),
+		Adaptors:        Adaptors(),
+		ArgNames:        argNames,
+	}
+}
+
 func makeFunctionAdaptor(adaptor interface{}) func(c api.Callable) reflect.Value {
 	return func(c api.Callable) reflect.Value {
 		w := reflect.ValueOf(adaptor)
@@ -16,25 +16,30 @@ import (
 	"github.com/golang/geo/s2"
 )
 
-func newShortestPathSearch(origin b6.Feature, options b6.UntypedCollection, distance float64, features graph.ShortestPathFeatures, w b6.World) (*graph.ShortestPathSearch, error) {
-	weights, err := WeightsFromOptions(options, w)
+func newShortestPathSearch(context *api.Context, origin b6.Feature, options b6.UntypedCollection, distance float64, features graph.ShortestPathFeatures) (*graph.ShortestPathSearch, error) {
+	weights, err := weightsFromContext(context, options)
 	if err != nil {
 		return nil, err
 	}
 
 	var s *graph.ShortestPathSearch
 	if origin, ok := origin.(b6.PhysicalFeature); ok {
-		s = graph.NewShortestPathSearchFromFeature(origin, weights, w)
-		s.ExpandSearch(distance, weights, features, w)
-		return s, nil
+		s = graph.NewShortestPathSearchFromFeature(origin, weights, context.World)
+		return s, expandSearch(context, s, distance, weights, features)
 	}
 
 	return nil, fmt.Errorf("Can't find paths from feature type %s", origin.FeatureID().Type)
 }
 
+// expandSearch expands the given search within the limits of the context,
+// counting each point expanded as a feature read.
+func expandSearch(context *api.Context, s *graph.ShortestPathSearch, distance float64, weights graph.Weights, features graph.ShortestPathFeatures) error {
+	return s.ExpandSearchWithCheck(distance, weights, features, context.World, context.CountFeatures)
+}
+
 func FindReachableFeaturesWithPathStates(context *api.Context, origin b6.Feature, options b6.UntypedCollection, distance float64, query b6.Query, pathStates *geojson.FeatureCollection) (b6.Collection[b6.FeatureID, b6.Feature], error) {
 	features := b6.ArrayFeatureCollection[b6.Feature](make([]b6.Feature, 0))
-	s, err := newShortestPathSearch(origin, options, distance, graph.PointsAndAreas, context.World)
+	s, err := newShortestPathSearch(context, origin, options, distance, graph.PointsAndAreas)
 	if err == nil {
 		for id := range s.PointDistances() {
 			if point := context.World.FindFeatureByID(id); point != nil {
@@ -173,6 +178,18 @@ func (o *odCollection) Less(i, j int) bool {
 // mode=transit
 // Transit at off-peak times:
 // mode=transit, peak=no
+// Cycling, with the default speed of 15km/h, preferring paths with lower
+// levels of traffic stress:
+// mode=cycle
+// Cycling at 20km/h, avoiding paths with a level of traffic stress above 2:
+// mode=cycle, cycle:speed=20, cycle:max-stress=2
+// Driving, or travelling by bus, at speed limits:
+// mode=car or mode=bus
+// A routing profile, loaded from the file given by b6's --routing-profiles
+// flag:
+// profile=wheelchair
+// A routing profile, given inline, see RoutingProfileYAML:
+// profile:speed=4, profile:exclude=#highway=steps, profile:penalties:[#barrier=kerb]=30
 // Walking, accounting for elevation:
 // elevation=true (optional: elevation:uphill=2.0 elevation:downhill=1.2)
 // Walking, accounting for elevation, adding double the penalty for uphill:
@@ -188,7 +205,7 @@ func accessibleAll(context *api.Context, origins b6.Collection[any, b6.Identifia
 		return b6.Collection[b6.FeatureID, b6.FeatureID]{}, err
 	}
 
-	weights, err := WeightsFromOptions(options, context.World)
+	weights, err := weightsFromContext(context, options)
 	if err != nil {
 		return b6.Collection[b6.FeatureID, b6.FeatureID]{}, err
 	}
@@ -212,7 +229,10 @@ func accessibleAll(context *api.Context, origins b6.Collection[any, b6.Identifia
 		g.Go(func() error {
 			for j := range c {
 				if origin := context.World.FindFeatureByID(os[j]); origin != nil {
-					ds[j] = accessibleFromOrigin(ds[j], origin, destinations, weights, duration, context.World)
+					var err error
+					if ds[j], err = accessibleFromOrigin(context, ds[j], origin, destinations, weights, duration); err != nil {
+						return err
+					}
 				}
 			}
 			return nil
@@ -252,8 +272,31 @@ func WeightsFromOptions(options b6.UntypedCollection, w b6.World) (graph.Weights
 	return WeightsFromTags(opts, w)
 }
 
+// weightsFromContext returns the weights given by the options, which can
+// include routing profiles defined in the context.
+func weightsFromContext(context *api.Context, options b6.UntypedCollection) (graph.Weights, error) {
+	opts, err := api.CollectionToTags(options)
+	if err != nil {
+		return nil, err
+	}
+	return weightsFromTags(opts, context.RoutingProfiles, context)
+}
+
 func WeightsFromTags(opts b6.Tags, w b6.World) (graph.Weights, error) {
+	context := newRoutingProfileContext()
+	context.World = w
+	return weightsFromTags(opts, nil, context)
+}
+
+func weightsFromTags(opts b6.Tags, profiles map[string]*graph.Profile, context *api.Context) (graph.Weights, error) {
 	var weights graph.Weights
+	w := context.World
+
+	if profile, ok, err := routingProfileFromTags(opts, profiles, context); err != nil {
+		return nil, err
+	} else if ok {
+		return graph.ProfileWeights{Profile: profile, W: w}, nil
+	}
 
 	switch m := opts.Get("mode").Value.String(); m {
 	case "", "walk":
@@ -293,7 +336,7 @@ func WeightsFromTags(opts b6.Tags, w b6.World) (graph.Weights, error) {
 		}
 	case "transit":
 		opts.ModifyOrAddTag(b6.Tag{Key: "mode", Value: b6.NewStringExpression("walk")})
-		walking, err := WeightsFromTags(opts, w)
+		walking, err := weightsFromTags(opts, profiles, context)
 		if err != nil {
 			return nil, err
 		}
@@ -302,8 +345,29 @@ func WeightsFromTags(opts b6.Tags, w b6.World) (graph.Weights, error) {
 		} else {
 			weights = graph.TransitTimeWeights{PeakTraffic: true, Weights: walking}
 		}
+	case "cycle":
+		cycling := graph.CyclingWeights{Speed: graph.CyclingMetersPerSecond}
+		if speed := opts.Get("cycle:speed"); speed.IsValid() {
+			if f, err := strconv.ParseFloat(speed.Value.String(), 64); err == nil && f > 0.0 {
+				cycling.Speed = f * graph.KilometersPerHourToMetersPerSecond
+			} else {
+				return nil, fmt.Errorf("expected a positive float string for cycle:speed, found %q", speed.Value.String())
+			}
+		}
+		if stress := opts.Get("cycle:max-stress"); stress.IsValid() {
+			if i, err := strconv.Atoi(stress.Value.String()); err == nil && i >= int(graph.TrafficStressLowest) && i <= int(graph.TrafficStressHighest) {
+				cycling.MaxStress = graph.TrafficStress(i)
+			} else {
+				return nil, fmt.Errorf("expected an integer between %d and %d for cycle:max-stress, found %q", graph.TrafficStressLowest, graph.TrafficStressHighest, stress.Value.String())
+			}
+		}
+		weights = cycling
+	case "car":
+		weights = graph.CarTimeWeights{}
+	case "bus":
+		weights = graph.BusTimeWeights{}
 	default:
-		return nil, fmt.Errorf("expected mode=walk or mode=transit, found %s", m)
+		return nil, fmt.Errorf("expected mode=walk, mode=transit, mode=cycle, mode=car or mode=bus, found %s", m)
 	}
 
 	return weights, nil
@@ -315,13 +379,15 @@ func accessibleRoutes(context *api.Context, origin b6.Identifiable, destinations
 		return b6.Collection[b6.FeatureID, b6.Route]{}, nil
 	}
 
-	weights, err := WeightsFromOptions(options, context.World)
+	weights, err := weightsFromContext(context, options)
 	if err != nil {
 		return b6.Collection[b6.FeatureID, b6.Route]{}, err
 	}
 
 	s := graph.NewShortestPathSearchFromFeature(f, weights, context.World)
-	s.ExpandSearch(duration, weights, graph.PointsAndAreas, context.World)
+	if err := expandSearch(context, s, duration, weights, graph.PointsAndAreas); err != nil {
+		return b6.Collection[b6.FeatureID, b6.Route]{}, err
+	}
 	routes := s.AllRoutes()
 	c := b6.ArrayCollection[b6.FeatureID, b6.Route]{}
 	for id, route := range routes {
@@ -371,9 +437,12 @@ func filterAccessible(context *api.Context, collection b6.Collection[b6.FeatureI
 	}
 }
 
-func accessibleFromOrigin(ds []b6.FeatureID, origin b6.Feature, destinations b6.Query, weights graph.Weights, distance float64, w b6.World) []b6.FeatureID {
+func accessibleFromOrigin(context *api.Context, ds []b6.FeatureID, origin b6.Feature, destinations b6.Query, weights graph.Weights, distance float64) ([]b6.FeatureID, error) {
+	w := context.World
 	s := graph.NewShortestPathSearchFromFeature(origin, weights, w)
-	s.ExpandSearch(distance, weights, graph.PointsAndAreas, w)
+	if err := expandSearch(context, s, distance, weights, graph.PointsAndAreas); err != nil {
+		return ds, err
+	}
 	for id := range s.AreaDistances() {
 		if id.FeatureID() == origin.FeatureID() {
 			continue
@@ -394,7 +463,7 @@ func accessibleFromOrigin(ds []b6.FeatureID, origin b6.Feature, destinations b6.
 			}
 		}
 	}
-	return ds
+	return ds, nil
 }
 
 // Return the closest feature from the given origin via the given mode, within the given distance in meters, matching the given query.
@@ -412,7 +481,7 @@ func closestFeatureDistance(context *api.Context, origin b6.Feature, options b6.
 }
 
 func findClosest(context *api.Context, origin b6.Feature, options b6.UntypedCollection, distance float64, query b6.Query) (b6.Feature, float64, error) {
-	s, err := newShortestPathSearch(origin, options, distance, graph.PointsAndAreas, context.World)
+	s, err := newShortestPathSearch(context, origin, options, distance, graph.PointsAndAreas)
 	if err == nil {
 		// TODO: This expands the search everywhere up to the maximum distance, and we
 		// can actually stop early.
@@ -453,7 +522,7 @@ func pathsToReachFeatures(context *api.Context, origin b6.Feature, options b6.Un
 		Keys:   make([]b6.FeatureID, 0),
 		Values: make([]int, 0),
 	}
-	s, err := newShortestPathSearch(origin, options, distance, graph.PointsAndAreas, context.World)
+	s, err := newShortestPathSearch(context, origin, options, distance, graph.PointsAndAreas)
 	if err == nil {
 		points := 0
 		counts := make(map[b6.FeatureID]int)
@@ -502,7 +571,7 @@ func pathsToReachFeatures(context *api.Context, origin b6.Feature, options b6.Un
 // See accessible-all for options values.
 func reachableArea(context *api.Context, origin b6.Feature, options b6.UntypedCollection, distance float64) (float64, error) {
 	area := 0.0
-	s, err := newShortestPathSearch(origin, options, distance, graph.Points, context.World)
+	s, err := newShortestPathSearch(context, origin, options, distance, graph.Points)
 	if err == nil {
 		distances := s.PointDistances()
 		query := s2.NewConvexHullQuery()
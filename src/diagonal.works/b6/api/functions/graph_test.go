@@ -129,6 +129,34 @@ func TestWeightsFromOptions(t *testing.T) {
 	if weights != expected {
 		t.Errorf("expected %+v, found %+v", expected, weights)
 	}
+
+	options = []b6.Tag{
+		{Key: "mode", Value: b6.NewStringExpression("cycle")},
+		{Key: "cycle:speed", Value: b6.NewStringExpression("18")},
+		{Key: "cycle:max-stress", Value: b6.NewStringExpression("2")},
+	}
+	weights, err = WeightsFromOptions(b6.ArrayValuesCollection[b6.Tag](options).Collection(), w)
+	if err != nil {
+		t.Errorf("expected no error, found: %s", err)
+	}
+	cycling := graph.CyclingWeights{Speed: 18.0 * graph.KilometersPerHourToMetersPerSecond, MaxStress: graph.TrafficStressLow}
+	if weights != cycling {
+		t.Errorf("expected %+v, found %+v", cycling, weights)
+	}
+
+	options = []b6.Tag{{Key: "mode", Value: b6.NewStringExpression("cycle")}, {Key: "cycle:max-stress", Value: b6.NewStringExpression("5")}}
+	if _, err := WeightsFromOptions(b6.ArrayValuesCollection[b6.Tag](options).Collection(), w); err == nil {
+		t.Error("expected an error for an invalid cycle:max-stress")
+	}
+
+	for mode, expected := range map[string]graph.Weights{"car": graph.CarTimeWeights{}, "bus": graph.BusTimeWeights{}} {
+		options = []b6.Tag{{Key: "mode", Value: b6.NewStringExpression(mode)}}
+		if weights, err := WeightsFromOptions(b6.ArrayValuesCollection[b6.Tag](options).Collection(), w); err != nil {
+			t.Errorf("expected no error, found: %s", err)
+		} else if weights != expected {
+			t.Errorf("expected %+v, found %+v", expected, weights)
+		}
+	}
 }
 
 func accessibilityForGranarySquare(options []b6.Tag, w b6.World) (b6.Collection[b6.FeatureID, b6.FeatureID], error) {
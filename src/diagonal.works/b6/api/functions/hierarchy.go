@@ -0,0 +1,310 @@
+package functions
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/api"
+	"diagonal.works/b6/graph"
+)
+
+// HierarchyKey returns a canonical description of the weights given by
+// the options passed to graph functions, used to match contraction
+// hierarchies with the queries they can answer. Options that don't affect
+// weights, like flip, are ignored.
+func HierarchyKey(opts b6.Tags) string {
+	keys := make([]string, 0, len(opts)+1)
+	mode := false
+	for _, t := range opts {
+		if t.Key == "flip" {
+			continue
+		}
+		if t.Key == "mode" || t.Key == "profile" || strings.HasPrefix(t.Key, routingProfilePrefix) {
+			mode = true
+		}
+		keys = append(keys, t.Key+"="+t.Value.String())
+	}
+	if !mode {
+		keys = append(keys, "mode=walk")
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+// BuildContractionHierarchy returns a contraction hierarchy for the given
+// world, using the weights given by the options passed to graph functions,
+// with routing profiles taken from profiles.
+func BuildContractionHierarchy(w b6.World, opts b6.Tags, profiles map[string]*graph.Profile) (*graph.ContractionHierarchy, error) {
+	context := newRoutingProfileContext()
+	context.World = w
+	weights, err := weightsFromTags(opts, profiles, context)
+	if err != nil {
+		return nil, err
+	}
+	return graph.BuildContractionHierarchy(weights, HierarchyKey(opts), w)
+}
+
+// ReadContractionHierarchies returns the hierarchies in the given files,
+// written by b6-build-hierarchy, attached to the world they were built for.
+func ReadContractionHierarchies(filenames []string, w b6.World) ([]*graph.ContractionHierarchy, error) {
+	hs := make([]*graph.ContractionHierarchy, 0, len(filenames))
+	for _, filename := range filenames {
+		f, err := os.Open(filename)
+		if err != nil {
+			return nil, err
+		}
+		h, err := graph.ReadContractionHierarchy(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+		if err := h.Attach(w); err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+		hs = append(hs, h)
+	}
+	return hs, nil
+}
+
+// hierarchyFromContext returns a contraction hierarchy built with the
+// weights given by options, if one exists and remains valid for the
+// context's world.
+func hierarchyFromContext(context *api.Context, opts b6.Tags) *graph.ContractionHierarchy {
+	if len(context.Hierarchies) == 0 {
+		return nil
+	}
+	key := HierarchyKey(opts)
+	for _, h := range context.Hierarchies {
+		if h.Weights == key && h.IsValidFor(context.World) {
+			return h
+		}
+	}
+	return nil
+}
+
+// hierarchyNodes returns the points in the hierarchy from which routes to
+// or from the given feature start: the feature itself for points, or
+// points on the boundary of areas. Returns false if the feature is a point
+// outside the hierarchy, or an area with no points in it, which may still
+// be reachable via paths outside it, meaning the hierarchy can't be used.
+func hierarchyNodes(f b6.Feature, h *graph.ContractionHierarchy) ([]b6.FeatureID, bool) {
+	switch f := f.(type) {
+	case b6.AreaFeature:
+		nodes := make([]b6.FeatureID, 0)
+		for i := 0; i < f.Len(); i++ {
+			for _, path := range f.Feature(i) {
+				for _, r := range path.References() {
+					if id := r.Source(); h.Contains(id) {
+						nodes = append(nodes, id)
+					}
+				}
+			}
+		}
+		return nodes, len(nodes) > 0
+	case b6.PhysicalFeature:
+		if f.GeometryType() == b6.GeometryTypePoint && h.Contains(f.FeatureID()) {
+			return []b6.FeatureID{f.FeatureID()}, true
+		}
+	}
+	return nil, false
+}
+
+// Return the shortest route from the given origin to the given
+// destination, within the given duration in seconds.
+// If the origin or destination are areas, the route starts or ends at the
+// closest point on their boundary connected to the street network.
+// Returns an empty route if the destination can't be reached.
+// Uses a contraction hierarchy, given by b6's --hierarchies flag, if one
+// was built with the same options, and the world hasn't been modified
+// along the paths it includes.
+// See accessible-all for options values.
+func shortestRoute(context *api.Context, origin b6.Identifiable, destination b6.Identifiable, duration float64, options b6.UntypedCollection) (b6.Route, error) {
+	from, to := api.Resolve(origin, context.World), api.Resolve(destination, context.World)
+	if from == nil || to == nil {
+		return b6.Route{}, nil
+	}
+	opts, err := api.CollectionToTags(options)
+	if err != nil {
+		return b6.Route{}, err
+	}
+
+	if h := hierarchyFromContext(context, opts); h != nil {
+		fromNodes, fromOK := hierarchyNodes(from, h)
+		toNodes, toOK := hierarchyNodes(to, h)
+		if fromOK && toOK {
+			best, bestFrom, bestTo := math.Inf(1), -1, -1
+			distances := h.Distances(fromNodes, toNodes, duration)
+			for i := range distances {
+				for j, d := range distances[i] {
+					if d < best {
+						best, bestFrom, bestTo = d, i, j
+					}
+				}
+			}
+			if bestFrom >= 0 {
+				route, _ := h.ShortestRoute(fromNodes[bestFrom], toNodes[bestTo], duration)
+				return route, nil
+			}
+			return b6.Route{}, nil
+		}
+	}
+
+	weights, err := weightsFromContext(context, options)
+	if err != nil {
+		return b6.Route{}, err
+	}
+	s := graph.NewShortestPathSearchFromFeature(from, weights, context.World)
+	if err := expandSearch(context, s, duration, weights, graph.PointsAndAreas); err != nil {
+		return b6.Route{}, err
+	}
+	if area, ok := to.(b6.AreaFeature); ok {
+		if entrance, ok := s.AreaEntrances()[area.AreaID()]; ok {
+			return s.BuildRoute(entrance), nil
+		}
+	} else if _, ok := s.PointDistances()[to.FeatureID()]; ok {
+		return s.BuildRoute(to.FeatureID()), nil
+	}
+	return b6.Route{}, nil
+}
+
+// Return the travel time in seconds from each of the given origins to each
+// of the given destinations reachable within the given duration.
+// Keys of the collection are origins, values are collections keyed by
+// destination, with the travel time as the value.
+// Uses a contraction hierarchy, given by b6's --hierarchies flag, if one
+// was built with the same options, and the world hasn't been modified
+// along the paths it includes, which is considerably faster than a
+// search from each origin when there are many origins and destinations.
+// See accessible-all for options values.
+func travelTimes(context *api.Context, origins b6.Collection[any, b6.Identifiable], destinations b6.Collection[any, b6.Identifiable], duration float64, options b6.UntypedCollection) (b6.Collection[b6.FeatureID, b6.UntypedCollection], error) {
+	opts, err := api.CollectionToTags(options)
+	if err != nil {
+		return b6.Collection[b6.FeatureID, b6.UntypedCollection]{}, err
+	}
+	originFeatures, err := resolveAll(context, origins)
+	if err != nil {
+		return b6.Collection[b6.FeatureID, b6.UntypedCollection]{}, err
+	}
+	destinationFeatures, err := resolveAll(context, destinations)
+	if err != nil {
+		return b6.Collection[b6.FeatureID, b6.UntypedCollection]{}, err
+	}
+
+	var times [][]float64
+	if h := hierarchyFromContext(context, opts); h != nil {
+		times = travelTimesFromHierarchy(h, originFeatures, destinationFeatures, duration)
+	}
+	if times == nil {
+		weights, err := weightsFromContext(context, options)
+		if err != nil {
+			return b6.Collection[b6.FeatureID, b6.UntypedCollection]{}, err
+		}
+		times = make([][]float64, len(originFeatures))
+		for i, origin := range originFeatures {
+			s := graph.NewShortestPathSearchFromFeature(origin, weights, context.World)
+			if err := expandSearch(context, s, duration, weights, graph.PointsAndAreas); err != nil {
+				return b6.Collection[b6.FeatureID, b6.UntypedCollection]{}, err
+			}
+			points, areas := s.PointDistances(), s.AreaDistances()
+			times[i] = make([]float64, len(destinationFeatures))
+			for j, destination := range destinationFeatures {
+				var ok bool
+				if area, isArea := destination.(b6.AreaFeature); isArea {
+					times[i][j], ok = areas[area.AreaID()]
+				} else {
+					times[i][j], ok = points[destination.FeatureID()]
+				}
+				if !ok {
+					times[i][j] = math.Inf(1)
+				}
+			}
+		}
+	}
+
+	c := b6.ArrayCollection[b6.FeatureID, b6.UntypedCollection]{
+		Keys:   make([]b6.FeatureID, 0, len(originFeatures)),
+		Values: make([]b6.UntypedCollection, 0, len(originFeatures)),
+	}
+	for i, origin := range originFeatures {
+		reachable := b6.ArrayCollection[b6.FeatureID, float64]{}
+		for j, destination := range destinationFeatures {
+			if !math.IsInf(times[i][j], 1) {
+				reachable.Keys = append(reachable.Keys, destination.FeatureID())
+				reachable.Values = append(reachable.Values, times[i][j])
+			}
+		}
+		c.Keys = append(c.Keys, origin.FeatureID())
+		c.Values = append(c.Values, reachable.Collection())
+	}
+	return c.Collection(), nil
+}
+
+// travelTimesFromHierarchy returns the travel times from each origin to
+// each destination using the given hierarchy, or nil if it can't be used
+// for the given features.
+func travelTimesFromHierarchy(h *graph.ContractionHierarchy, origins []b6.Feature, destinations []b6.Feature, duration float64) [][]float64 {
+	index := func(features []b6.Feature) ([]b6.FeatureID, [][]int, bool) {
+		nodes := make([]b6.FeatureID, 0, len(features))
+		indices := make([][]int, len(features))
+		seen := make(map[b6.FeatureID]int)
+		for i, f := range features {
+			ns, ok := hierarchyNodes(f, h)
+			if !ok {
+				return nil, nil, false
+			}
+			for _, n := range ns {
+				j, ok := seen[n]
+				if !ok {
+					j = len(nodes)
+					nodes = append(nodes, n)
+					seen[n] = j
+				}
+				indices[i] = append(indices[i], j)
+			}
+		}
+		return nodes, indices, true
+	}
+	fromNodes, fromIndices, ok := index(origins)
+	if !ok {
+		return nil
+	}
+	toNodes, toIndices, ok := index(destinations)
+	if !ok {
+		return nil
+	}
+	distances := h.Distances(fromNodes, toNodes, duration)
+	times := make([][]float64, len(origins))
+	for i := range origins {
+		times[i] = make([]float64, len(destinations))
+		for j := range destinations {
+			times[i][j] = math.Inf(1)
+			for _, from := range fromIndices[i] {
+				for _, to := range toIndices[j] {
+					times[i][j] = math.Min(times[i][j], distances[from][to])
+				}
+			}
+		}
+	}
+	return times
+}
+
+func resolveAll(context *api.Context, c b6.Collection[any, b6.Identifiable]) ([]b6.Feature, error) {
+	features := make([]b6.Feature, 0)
+	i := c.Begin()
+	for {
+		ok, err := i.Next()
+		if err != nil {
+			return nil, err
+		} else if !ok {
+			break
+		}
+		if f := api.Resolve(i.Value(), context.World); f != nil {
+			features = append(features, f)
+		}
+	}
+	return features, nil
+}
@@ -0,0 +1,213 @@
+package functions
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/api"
+	"diagonal.works/b6/graph"
+	"diagonal.works/b6/ingest"
+	"diagonal.works/b6/osm"
+	"diagonal.works/b6/test/camden"
+)
+
+func TestHierarchyKey(t *testing.T) {
+	tags := func(kvs ...string) b6.Tags {
+		ts := make(b6.Tags, 0, len(kvs)/2)
+		for i := 0; i < len(kvs); i += 2 {
+			ts = append(ts, b6.Tag{Key: kvs[i], Value: b6.NewStringExpression(kvs[i+1])})
+		}
+		return ts
+	}
+	tests := []struct {
+		a, b  b6.Tags
+		equal bool
+	}{
+		{tags(), tags("mode", "walk"), true},
+		{tags("flip", "yes"), tags("mode", "walk"), true},
+		{tags("walk:speed", "3", "mode", "walk"), tags("mode", "walk", "walk:speed", "3"), true},
+		{tags("mode", "walk"), tags("mode", "cycle"), false},
+		{tags("profile", "wheelchair"), tags("mode", "walk"), false},
+	}
+	for _, test := range tests {
+		if equal := HierarchyKey(test.a) == HierarchyKey(test.b); equal != test.equal {
+			t.Errorf("Expected keys for %v and %v to be equal: %v, found %q and %q", test.a, test.b, test.equal, HierarchyKey(test.a), HierarchyKey(test.b))
+		}
+	}
+}
+
+func newHierarchyTestContext(w b6.World, hierarchies []*graph.ContractionHierarchy) *api.Context {
+	return &api.Context{
+		World:           w,
+		Cores:           2,
+		FunctionSymbols: Functions(),
+		Adaptors:        Adaptors(),
+		Context:         context.Background(),
+		Hierarchies:     hierarchies,
+	}
+}
+
+func TestTravelTimesAndShortestRouteWithHierarchy(t *testing.T) {
+	base := camden.BuildGranarySquareForTests(t)
+	if base == nil {
+		return
+	}
+	options := b6.Tags{{Key: "mode", Value: b6.NewStringExpression("walk")}}
+	h, err := BuildContractionHierarchy(base, options, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []b6.FeatureID{camden.StableStreetBridgeNorthEndID, camden.StableStreetBridgeSouthEndID, camden.SomersTownBridgeEastGateID} {
+		if !h.Contains(id) {
+			t.Fatalf("Expected %s to be part of the hierarchy", id)
+		}
+	}
+	w := ingest.NewMutableOverlayWorld(base)
+
+	origins := b6.ArrayValuesCollection[b6.Identifiable]{camden.StableStreetBridgeNorthEndID, camden.LightermanID.FeatureID()}
+	destinations := b6.ArrayValuesCollection[b6.Identifiable]{camden.StableStreetBridgeSouthEndID, camden.SomersTownBridgeEastGateID, ingest.AreaIDFromOSMWayID(camden.CoalDropsYardWestBuildingWay).FeatureID()}
+	collection := b6.ArrayValuesCollection[b6.Tag](options).Collection()
+
+	travelTimesWith := func(c *api.Context) map[b6.FeatureID]map[b6.FeatureID]float64 {
+		tts, err := travelTimes(c, b6.AdaptCollection[any, b6.Identifiable](origins.Collection()), b6.AdaptCollection[any, b6.Identifiable](destinations.Collection()), 1000.0, collection)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := make(map[b6.FeatureID]map[b6.FeatureID]float64)
+		i := tts.Begin()
+		for {
+			ok, err := i.Next()
+			if err != nil {
+				t.Fatal(err)
+			} else if !ok {
+				break
+			}
+			result[i.Key()] = make(map[b6.FeatureID]float64)
+			j := i.Value().BeginUntyped()
+			for {
+				ok, err := j.Next()
+				if err != nil {
+					t.Fatal(err)
+				} else if !ok {
+					break
+				}
+				result[i.Key()][j.Key().(b6.FeatureID)] = j.Value().(float64)
+			}
+		}
+		return result
+	}
+
+	compare := func(name string) {
+		with, without := newHierarchyTestContext(w, []*graph.ContractionHierarchy{h}), newHierarchyTestContext(w, nil)
+		expected, found := travelTimesWith(without), travelTimesWith(with)
+		n := 0
+		for origin, ds := range expected {
+			for destination, e := range ds {
+				n++
+				if f, ok := found[origin][destination]; !ok || math.Abs(f-e) > 1e-6 {
+					t.Errorf("%s: expected travel time %f from %s to %s, found %f", name, e, origin, destination, f)
+				}
+			}
+			if len(found[origin]) != len(ds) {
+				t.Errorf("%s: expected %d destinations from %s, found %d", name, len(ds), origin, len(found[origin]))
+			}
+		}
+		if n == 0 {
+			t.Errorf("%s: expected at least one reachable destination", name)
+		}
+
+		expectedRoute, err := shortestRoute(without, origins[0], destinations[0], 1000.0, collection)
+		if err != nil {
+			t.Fatal(err)
+		}
+		route, err := shortestRoute(with, origins[0], destinations[0], 1000.0, collection)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(route.Steps) == 0 || len(expectedRoute.Steps) == 0 {
+			t.Errorf("%s: expected a route", name)
+		} else if e, f := expectedRoute.Steps[len(expectedRoute.Steps)-1], route.Steps[len(route.Steps)-1]; e.Destination != f.Destination || math.Abs(e.Cost-f.Cost) > 1e-6 {
+			t.Errorf("%s: expected route ending %+v, found %+v", name, e, f)
+		}
+	}
+
+	compare("unmodified")
+	if !h.IsValidFor(w) {
+		t.Error("Expected hierarchy to be valid for unmodified world")
+	}
+
+	// Closing the bridge must fall back to searching the modified world.
+	if err := w.AddTag(camden.StableStreetBridgeID, b6.Tag{Key: "access", Value: b6.NewStringExpression("no")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.AddTag(camden.StableStreetBridgeID, b6.Tag{Key: "#highway", Value: b6.NewStringExpression("construction")}); err != nil {
+		t.Fatal(err)
+	}
+	if h.IsValidFor(w) {
+		t.Error("Expected hierarchy to be invalid after modifying a path it includes")
+	}
+	compare("modified")
+}
+
+func TestShortestRouteBetweenAreasOutsideHierarchy(t *testing.T) {
+	base := camden.BuildGranarySquareForTests(t)
+	if base == nil {
+		return
+	}
+	options := b6.Tags{{Key: "mode", Value: b6.NewStringExpression("walk")}}
+	h, err := BuildContractionHierarchy(base, options, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Two new buildings, joined by a new footway that isn't connected to
+	// the rest of the network, and so doesn't invalidate the hierarchy.
+	w := ingest.NewMutableOverlayWorld(base)
+	square := func(id osm.WayID, first osm.NodeID, lat float64, lng float64) *ingest.AreaFeature {
+		way := osm.Way{ID: id, Tags: osm.Tags{{Key: "building", Value: "yes"}}}
+		for i, ll := range [][2]float64{{lat, lng}, {lat, lng + 0.0001}, {lat + 0.0001, lng + 0.0001}, {lat + 0.0001, lng}} {
+			node := osm.Node{ID: first + osm.NodeID(i), Location: osm.LatLng{Lat: ll[0], Lng: ll[1]}}
+			var point ingest.GenericFeature
+			point.FillFromOSM(ingest.OSMFeature{Node: &node})
+			if err := w.AddFeature(&point); err != nil {
+				t.Fatal(err)
+			}
+			way.Nodes = append(way.Nodes, node.ID)
+		}
+		way.Nodes = append(way.Nodes, first)
+		var path ingest.GenericFeature
+		path.FillFromOSM(ingest.OSMFeature{Way: &way})
+		if err := w.AddFeature(&path); err != nil {
+			t.Fatal(err)
+		}
+		var area ingest.AreaFeature
+		area.FillFromOSMWay(&way)
+		if err := w.AddFeature(&area); err != nil {
+			t.Fatal(err)
+		}
+		return &area
+	}
+	from := square(9000000001, 9000000001, 51.5330, -0.1290)
+	to := square(9000000002, 9000000011, 51.5330, -0.1285)
+	footway := osm.Way{ID: 9000000003, Nodes: []osm.NodeID{9000000001, 9000000011}, Tags: osm.Tags{{Key: "highway", Value: "footway"}}}
+	var path ingest.GenericFeature
+	path.FillFromOSM(ingest.OSMFeature{Way: &footway})
+	if err := w.AddFeature(&path); err != nil {
+		t.Fatal(err)
+	}
+	if !h.IsValidFor(w) {
+		t.Fatal("Expected hierarchy to remain valid after adding disconnected features")
+	}
+
+	c := newHierarchyTestContext(w, []*graph.ContractionHierarchy{h})
+	collection := b6.ArrayValuesCollection[b6.Tag](options).Collection()
+	route, err := shortestRoute(c, from.FeatureID(), to.FeatureID(), 1000.0, collection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(route.Steps) == 0 {
+		t.Error("Expected a route between areas outside the hierarchy")
+	}
+}
@@ -0,0 +1,364 @@
+package functions
+
+import (
+	"fmt"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/api"
+	"diagonal.works/b6/ingest"
+	"diagonal.works/b6/search"
+
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+	"golang.org/x/sync/errgroup"
+)
+
+type spatialPredicate int
+
+const (
+	spatialPredicateIntersects spatialPredicate = iota
+	spatialPredicateContains
+	spatialPredicateWithin
+	spatialPredicateWithinDistance
+)
+
+func spatialPredicateFromString(s string) (spatialPredicate, bool) {
+	switch s {
+	case "intersects":
+		return spatialPredicateIntersects, true
+	case "contains":
+		return spatialPredicateContains, true
+	case "within":
+		return spatialPredicateWithin, true
+	case "within-distance":
+		return spatialPredicateWithinDistance, true
+	}
+	return spatialPredicateIntersects, false
+}
+
+// spatialJoin finds the features matching a query that satisfy a
+// predicate with each of a set of features. Candidates are found via the
+// spatial index, using the geometry of each feature, or a covering
+// expanded by the distance, before the predicate is tested exactly.
+type spatialJoin struct {
+	right     b6.Query
+	predicate spatialPredicate
+	distance  s1.Angle
+}
+
+func newSpatialJoin(right b6.Query, predicate string, distance []float64) (*spatialJoin, error) {
+	p, ok := spatialPredicateFromString(predicate)
+	if !ok {
+		return nil, fmt.Errorf("expected intersects, contains, within or within-distance, found %q", predicate)
+	}
+	s := &spatialJoin{right: right, predicate: p}
+	if p == spatialPredicateWithinDistance {
+		if len(distance) != 1 {
+			return nil, fmt.Errorf("within-distance needs a distance")
+		}
+		s.distance = b6.MetersToAngle(distance[0])
+	} else if len(distance) > 0 {
+		return nil, fmt.Errorf("only within-distance takes a distance")
+	}
+	return s, nil
+}
+
+func (s *spatialJoin) candidates(left b6.PhysicalFeature) (b6.Query, error) {
+	if s.predicate == spatialPredicateWithinDistance {
+		coverer := s2.RegionCoverer{MaxLevel: search.MaxIndexedCellLevel, MaxCells: 8}
+		var covering s2.CellUnion
+		switch left.GeometryType() {
+		case b6.GeometryTypePoint:
+			covering = coverer.Covering(s2.CapFromCenterAngle(left.Point(), s.distance))
+		case b6.GeometryTypePath:
+			covering = coverer.Covering(left.Polyline())
+			covering.ExpandByRadius(s.distance, 2)
+		case b6.GeometryTypeArea:
+			for i := 0; i < left.(b6.Area).Len(); i++ {
+				covering = append(covering, coverer.Covering(left.(b6.Area).Polygon(i))...)
+			}
+			covering.Normalize()
+			covering.ExpandByRadius(s.distance, 2)
+		}
+		return b6.Intersection{s.right, b6.MightIntersect{Region: &covering}}, nil
+	}
+	intersecting, err := intersecting(nil, left)
+	if err != nil {
+		return nil, err
+	}
+	return b6.Intersection{s.right, intersecting}, nil
+}
+
+func (s *spatialJoin) holds(left b6.PhysicalFeature, right b6.Feature) bool {
+	r, ok := right.(b6.PhysicalFeature)
+	if !ok || left.FeatureID() == right.FeatureID() {
+		return false
+	}
+	switch s.predicate {
+	case spatialPredicateContains:
+		return geometryContains(left, r)
+	case spatialPredicateWithin:
+		return geometryContains(r, left)
+	case spatialPredicateWithinDistance:
+		return geometryDistance(left, r) <= s.distance
+	}
+	// Candidates are found with an exact intersection query
+	return true
+}
+
+// findMatches returns the IDs of the features matching each of the given
+// features, with the same indices.
+func (s *spatialJoin) findMatches(c *api.Context, left b6.Collection[any, b6.Feature]) ([]b6.FeatureID, [][]b6.FeatureID, error) {
+	features := make([]b6.PhysicalFeature, 0)
+	i := left.Begin()
+	for {
+		ok, err := i.Next()
+		if err != nil {
+			return nil, nil, err
+		} else if !ok {
+			break
+		}
+		if f, ok := i.Value().(b6.PhysicalFeature); ok {
+			features = append(features, f)
+		}
+	}
+
+	matches := make([][]b6.FeatureID, len(features))
+	cores := c.Cores
+	if cores < 1 {
+		cores = 1
+	}
+	indices := make(chan int)
+	g, gc := errgroup.WithContext(c.Context)
+	for i := 0; i < cores; i++ {
+		g.Go(func() error {
+			for j := range indices {
+				q, err := s.candidates(features[j])
+				if err != nil {
+					return err
+				}
+				rights := c.World.FindFeatures(q)
+				for rights.Next() {
+					if s.holds(features[j], rights.Feature()) {
+						matches[j] = append(matches[j], rights.FeatureID())
+					}
+				}
+			}
+			return nil
+		})
+	}
+done:
+	for j := range features {
+		select {
+		case <-gc.Done():
+			break done
+		case indices <- j:
+		}
+	}
+	close(indices)
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	ids := make([]b6.FeatureID, len(features))
+	for j, f := range features {
+		ids[j] = f.FeatureID()
+	}
+	return ids, matches, nil
+}
+
+// geometryContains returns true if every point of b lies within a, which
+// must be an area.
+func geometryContains(a b6.PhysicalFeature, b b6.PhysicalFeature) bool {
+	area, ok := a.(b6.AreaFeature)
+	if !ok {
+		return false
+	}
+	for i := 0; i < area.Len(); i++ {
+		polygon := area.Polygon(i)
+		switch b.GeometryType() {
+		case b6.GeometryTypePoint:
+			if polygon.ContainsPoint(b.Point()) {
+				return true
+			}
+		case b6.GeometryTypePath:
+			if polygonContainsPolyline(polygon, b.Polyline()) {
+				return true
+			}
+		case b6.GeometryTypeArea:
+			contained := true
+			for j := 0; j < b.(b6.AreaFeature).Len(); j++ {
+				if !polygon.Contains(b.(b6.AreaFeature).Polygon(j)) {
+					contained = false
+					break
+				}
+			}
+			if contained {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// polygonContainsPolyline returns true if all the vertices of the
+// polyline lie within the polygon, and none of its edges cross the
+// polygon's boundary.
+func polygonContainsPolyline(polygon *s2.Polygon, polyline *s2.Polyline) bool {
+	for _, p := range *polyline {
+		if !polygon.ContainsPoint(p) {
+			return false
+		}
+	}
+	for i := 0; i+1 < len(*polyline); i++ {
+		crosser := s2.NewChainEdgeCrosser((*polyline)[i], (*polyline)[i+1], (*polyline)[i])
+		for _, loop := range polygon.Loops() {
+			crosser.RestartAt(loop.Vertex(0))
+			for j := 1; j <= loop.NumVertices(); j++ {
+				if crosser.ChainCrossingSign(loop.Vertex(j)) == s2.Cross {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// geometryEdges returns the vertices and edges of the given geometry.
+func geometryEdges(g b6.PhysicalFeature) ([]s2.Point, []s2.Edge) {
+	switch g.GeometryType() {
+	case b6.GeometryTypePoint:
+		return []s2.Point{g.Point()}, nil
+	case b6.GeometryTypePath:
+		polyline := *g.Polyline()
+		edges := make([]s2.Edge, 0, len(polyline))
+		for i := 0; i+1 < len(polyline); i++ {
+			edges = append(edges, s2.Edge{V0: polyline[i], V1: polyline[i+1]})
+		}
+		return polyline, edges
+	case b6.GeometryTypeArea:
+		var points []s2.Point
+		var edges []s2.Edge
+		for i := 0; i < g.(b6.AreaFeature).Len(); i++ {
+			for _, loop := range g.(b6.AreaFeature).Polygon(i).Loops() {
+				for j := 0; j < loop.NumVertices(); j++ {
+					points = append(points, loop.Vertex(j))
+					edges = append(edges, s2.Edge{V0: loop.Vertex(j), V1: loop.Vertex(j + 1)})
+				}
+			}
+		}
+		return points, edges
+	}
+	return nil, nil
+}
+
+// geometryDistance returns the minimum distance between two geometries,
+// which is zero if they intersect.
+func geometryDistance(a b6.PhysicalFeature, b b6.PhysicalFeature) s1.Angle {
+	if q, err := intersecting(nil, a); err == nil && q.Matches(b, nil) {
+		return 0
+	}
+	pa, ea := geometryEdges(a)
+	pb, eb := geometryEdges(b)
+	distance := s1.InfAngle()
+	// The geometries don't intersect, so the closest points lie on a
+	// vertex of one of them.
+	for _, vertices := range []struct {
+		points []s2.Point
+		edges  []s2.Edge
+		other  []s2.Point
+	}{{pa, eb, pb}, {pb, ea, pa}} {
+		for _, p := range vertices.points {
+			if len(vertices.edges) == 0 {
+				for _, o := range vertices.other {
+					if d := p.Distance(o); d < distance {
+						distance = d
+					}
+				}
+			}
+			for _, e := range vertices.edges {
+				if d := s2.DistanceFromSegment(p, e.V0, e.V1); d < distance {
+					distance = d
+				}
+			}
+		}
+	}
+	return distance
+}
+
+// Return the pairs of features for which the given predicate holds,
+// with keys from the first collection, and values the IDs of features
+// matching the given query.
+// The predicate is one of intersects, contains, within, or
+// within-distance, which also needs a distance in meters. contains means
+// the first feature contains the second, and within the reverse.
+// Features from the query are found via the spatial index, and the join
+// is evaluated in parallel. Features aren't joined with themselves.
+func spatialJoinFeatures(c *api.Context, left b6.Collection[any, b6.Feature], right b6.Query, predicate string, distance ...float64) (b6.Collection[b6.FeatureID, b6.FeatureID], error) {
+	s, err := newSpatialJoin(right, predicate, distance)
+	if err != nil {
+		return b6.Collection[b6.FeatureID, b6.FeatureID]{}, err
+	}
+	ids, matches, err := s.findMatches(c, left)
+	if err != nil {
+		return b6.Collection[b6.FeatureID, b6.FeatureID]{}, err
+	}
+	pairs := b6.ArrayCollection[b6.FeatureID, b6.FeatureID]{}
+	for i, id := range ids {
+		for _, match := range matches[i] {
+			pairs.Keys = append(pairs.Keys, id)
+			pairs.Values = append(pairs.Values, match)
+		}
+	}
+	return pairs.Collection(), nil
+}
+
+// Return the number of features matching the given query for which the
+// given predicate holds with each feature in the given collection,
+// including those for which there are none.
+// Predicates are as for spatial-join.
+func spatialJoinCount(c *api.Context, left b6.Collection[any, b6.Feature], right b6.Query, predicate string, distance ...float64) (b6.Collection[b6.FeatureID, int], error) {
+	s, err := newSpatialJoin(right, predicate, distance)
+	if err != nil {
+		return b6.Collection[b6.FeatureID, int]{}, err
+	}
+	ids, matches, err := s.findMatches(c, left)
+	if err != nil {
+		return b6.Collection[b6.FeatureID, int]{}, err
+	}
+	counts := b6.ArrayCollection[b6.FeatureID, int]{Keys: ids, Values: make([]int, len(ids))}
+	for i := range ids {
+		counts.Values[i] = len(matches[i])
+	}
+	return counts.Collection(), nil
+}
+
+// Return a change that adds a tag with the given key to each feature in
+// the given collection, with the ID of the feature matching the given
+// query for which the predicate holds as its value. If there are
+// multiple matching features, the one with the smallest ID is used.
+// Predicates are as for spatial-join.
+func spatialJoinAddTags(c *api.Context, left b6.Collection[any, b6.Feature], right b6.Query, predicate string, key string, distance ...float64) (ingest.Change, error) {
+	s, err := newSpatialJoin(right, predicate, distance)
+	if err != nil {
+		return nil, err
+	}
+	ids, matches, err := s.findMatches(c, left)
+	if err != nil {
+		return nil, err
+	}
+	tags := make(ingest.AddTags, 0, len(ids))
+	for i, id := range ids {
+		if len(matches[i]) == 0 {
+			continue
+		}
+		first := matches[i][0]
+		for _, match := range matches[i][1:] {
+			if match.Less(first) {
+				first = match
+			}
+		}
+		tags = append(tags, ingest.AddTag{ID: id, Tag: b6.Tag{Key: key, Value: b6.NewFeatureIDExpression(first)}})
+	}
+	return tags, nil
+}
@@ -0,0 +1,173 @@
+package functions
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/api"
+	"diagonal.works/b6/ingest"
+	"diagonal.works/b6/test/camden"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func findAll(w b6.World, q b6.Query) []b6.Feature {
+	features := make([]b6.Feature, 0)
+	i := w.FindFeatures(q)
+	for i.Next() {
+		features = append(features, i.Feature())
+	}
+	sort.Slice(features, func(i, j int) bool {
+		return features[i].FeatureID().Less(features[j].FeatureID())
+	})
+	return features
+}
+
+type joinPair struct {
+	Left  b6.FeatureID
+	Right b6.FeatureID
+}
+
+func joinPairs(t *testing.T, c b6.Collection[b6.FeatureID, b6.FeatureID]) []joinPair {
+	t.Helper()
+	pairs := make([]joinPair, 0)
+	i := c.Begin()
+	for {
+		ok, err := i.Next()
+		if err != nil {
+			t.Fatal(err)
+		} else if !ok {
+			break
+		}
+		pairs = append(pairs, joinPair{Left: i.Key(), Right: i.Value()})
+	}
+	sortJoinPairs(pairs)
+	return pairs
+}
+
+func sortJoinPairs(pairs []joinPair) {
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Left != pairs[j].Left {
+			return pairs[i].Left.Less(pairs[j].Left)
+		}
+		return pairs[i].Right.Less(pairs[j].Right)
+	})
+}
+
+func TestSpatialJoinMatchesBruteForce(t *testing.T) {
+	w := camden.BuildGranarySquareForTests(t)
+	c := &api.Context{World: w, Context: context.Background(), Cores: 4}
+
+	buildings := findAll(w, b6.Keyed{Key: "#building"})
+	points := findAll(w, b6.Typed{Type: b6.FeatureTypePoint, Query: b6.All{}})
+	if len(buildings) == 0 || len(points) == 0 {
+		t.Fatal("Expected some buildings and points")
+	}
+
+	contains := make([]joinPair, 0)
+	for _, building := range buildings {
+		for _, point := range points {
+			if geometryContains(building.(b6.PhysicalFeature), point.(b6.PhysicalFeature)) {
+				contains = append(contains, joinPair{Left: building.FeatureID(), Right: point.FeatureID()})
+			}
+		}
+	}
+	if len(contains) == 0 {
+		t.Fatal("Expected some points within buildings")
+	}
+	sortJoinPairs(contains)
+
+	left := b6.AdaptCollection[any, b6.Feature](b6.ArrayFeatureCollection[b6.Feature](buildings).Collection())
+	joined, err := spatialJoinFeatures(c, left, b6.Typed{Type: b6.FeatureTypePoint, Query: b6.All{}}, "contains")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(contains, joinPairs(t, joined)); diff != "" {
+		t.Errorf("Found diff for contains (-want, +got):\n%s", diff)
+	}
+
+	within := make([]joinPair, 0, len(contains))
+	for _, pair := range contains {
+		within = append(within, joinPair{Left: pair.Right, Right: pair.Left})
+	}
+	sortJoinPairs(within)
+	left = b6.AdaptCollection[any, b6.Feature](b6.ArrayFeatureCollection[b6.Feature](points).Collection())
+	joined, err = spatialJoinFeatures(c, left, b6.Keyed{Key: "#building"}, "within")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(within, joinPairs(t, joined)); diff != "" {
+		t.Errorf("Found diff for within (-want, +got):\n%s", diff)
+	}
+
+	const distance = 20.0
+	near := make([]joinPair, 0)
+	for _, point := range points {
+		for _, building := range buildings {
+			if d, ok := b6.DistanceToFeature(point.(b6.PhysicalFeature).Point(), building); ok && b6.AngleToMeters(d) <= distance {
+				near = append(near, joinPair{Left: point.FeatureID(), Right: building.FeatureID()})
+			}
+		}
+	}
+	if len(near) <= len(within) {
+		t.Fatal("Expected more points close to buildings than within them")
+	}
+	sortJoinPairs(near)
+	joined, err = spatialJoinFeatures(c, left, b6.Keyed{Key: "#building"}, "within-distance", distance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(near, joinPairs(t, joined)); diff != "" {
+		t.Errorf("Found diff for within-distance (-want, +got):\n%s", diff)
+	}
+}
+
+func TestSpatialJoinCountAndAddTags(t *testing.T) {
+	w := camden.BuildGranarySquareForTests(t)
+	c := &api.Context{World: w, Context: context.Background(), Cores: 2}
+
+	lighterman := w.FindFeatureByID(camden.LightermanID.FeatureID())
+	left := b6.AdaptCollection[any, b6.Feature](b6.ArrayFeatureCollection[b6.Feature]{lighterman}.Collection())
+	counts, err := spatialJoinCount(c, left, b6.Keyed{Key: "#building"}, "intersects")
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, err := counts.AllValues(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 1 {
+		t.Fatalf("Expected a count for The Lighterman, found %v", values)
+	}
+	if expected := len(findAll(w, b6.Intersection{b6.Keyed{Key: "#building"}, b6.IntersectsMultiPolygon{MultiPolygon: lighterman.(b6.AreaFeature).MultiPolygon()}})) - 1; values[0] != expected {
+		t.Errorf("Expected %d buildings intersecting The Lighterman, found %d", expected, values[0])
+	}
+
+	points := findAll(w, b6.Typed{Type: b6.FeatureTypePoint, Query: b6.All{}})
+	left = b6.AdaptCollection[any, b6.Feature](b6.ArrayFeatureCollection[b6.Feature](points).Collection())
+	change, err := spatialJoinAddTags(c, left, b6.Keyed{Key: "#building"}, "within", "building")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tags, ok := change.(ingest.AddTags)
+	if !ok || len(tags) == 0 {
+		t.Fatalf("Expected tags to be added, found %v", change)
+	}
+	for _, tag := range tags {
+		id := tag.Tag.Value.AnyExpression.(b6.FeatureIDExpression)
+		building := w.FindFeatureByID(b6.FeatureID(id))
+		point := w.FindFeatureByID(tag.ID)
+		if building == nil || !geometryContains(building.(b6.PhysicalFeature), point.(b6.PhysicalFeature)) {
+			t.Errorf("Expected %s to be within %s", tag.ID, b6.FeatureID(id))
+		}
+	}
+
+	if _, err := spatialJoinCount(c, left, b6.Keyed{Key: "#building"}, "within-distance"); err == nil {
+		t.Error("Expected an error for within-distance without a distance")
+	}
+	if _, err := spatialJoinCount(c, left, b6.Keyed{Key: "#building"}, "near"); err == nil {
+		t.Error("Expected an error for an unknown predicate")
+	}
+}
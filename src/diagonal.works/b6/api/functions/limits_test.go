@@ -0,0 +1,116 @@
+package functions
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/api"
+	"diagonal.works/b6/test/camden"
+)
+
+func iterate(c b6.UntypedCollection) (int, error) {
+	n := 0
+	i := c.BeginUntyped()
+	for {
+		ok, err := i.Next()
+		if err != nil || !ok {
+			return n, err
+		}
+		n++
+	}
+}
+
+func TestFeatureLimit(t *testing.T) {
+	granarySquare := camden.BuildGranarySquareForTests(t)
+	c := NewContext(granarySquare)
+	c.FillFromOptions(&api.Options{Limits: api.Limits{MaxFeatures: 10}})
+
+	v, err := api.EvaluateString("find [#building]", c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := iterate(v.(b6.UntypedCollection))
+	var limit *api.LimitExceededError
+	if !errors.As(err, &limit) || limit.Resource != api.ResourceFeatures {
+		t.Fatalf("Expected a feature limit error, found %v", err)
+	}
+	if n != 10 {
+		t.Errorf("Expected to read 10 features before failing, found %d", n)
+	}
+}
+
+func TestTimeLimit(t *testing.T) {
+	granarySquare := camden.BuildGranarySquareForTests(t)
+	c := NewContext(granarySquare)
+	c.FillFromOptions(&api.Options{Limits: api.Limits{Timeout: time.Nanosecond}})
+	time.Sleep(time.Millisecond)
+
+	v, err := api.EvaluateString("find [#building] | map {b -> area b}", c)
+	if err == nil {
+		_, err = iterate(v.(b6.UntypedCollection))
+	}
+	var limit *api.LimitExceededError
+	if !errors.As(err, &limit) || limit.Resource != api.ResourceTime {
+		t.Errorf("Expected a time limit error, found %v", err)
+	}
+}
+
+func TestMemoryLimit(t *testing.T) {
+	c := NewContext(camden.BuildGranarySquareForTests(t))
+	c.FillFromOptions(&api.Options{Limits: api.Limits{MaxHeapBytes: 1}})
+
+	var limit *api.LimitExceededError
+	if err := c.Check(); !errors.As(err, &limit) || limit.Resource != api.ResourceMemory {
+		t.Errorf("Expected a memory limit error, found %v", err)
+	}
+}
+
+func TestCancellation(t *testing.T) {
+	granarySquare := camden.BuildGranarySquareForTests(t)
+	c := NewContext(granarySquare)
+	ctx, cancel := context.WithCancel(context.Background())
+	c.Context = ctx
+	c.FillFromOptions(&api.Options{})
+
+	v, err := api.EvaluateString("find [#building] | map {b -> area b}", c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	if _, err := iterate(v.(b6.UntypedCollection)); err != context.Canceled {
+		t.Errorf("Expected evaluation to be cancelled, found %v", err)
+	}
+}
+
+func TestGraphSearchCountsFeatures(t *testing.T) {
+	granarySquare := camden.BuildGranarySquareForTests(t)
+	c := NewContext(granarySquare)
+	c.FillFromOptions(&api.Options{})
+
+	origin := b6.FindAreaByID(camden.LightermanID, granarySquare)
+	if origin == nil {
+		t.Fatal("Failed to find origin")
+	}
+	options := b6.ArrayValuesCollection[b6.Tag]([]b6.Tag{{Key: "mode", Value: b6.NewStringExpression("walk")}}).Collection()
+	if _, err := reachable(c, origin, options, 1000.0, b6.Keyed{Key: "#building"}); err != nil {
+		t.Fatal(err)
+	}
+	if c.FeaturesRead() == 0 {
+		t.Error("Expected graph search to count features read")
+	}
+}
+
+func TestStartTimerRestartsTimeLimit(t *testing.T) {
+	c := NewContext(camden.BuildGranarySquareForTests(t))
+	c.FillFromOptions(&api.Options{Limits: api.Limits{Timeout: 10 * time.Millisecond}})
+	// Simulate waiting for a lock after filling the context, which
+	// shouldn't count against the timeout.
+	time.Sleep(20 * time.Millisecond)
+	c.StartTimer()
+	if err := c.Check(); err != nil {
+		t.Errorf("Expected no error after restarting the timer, found %v", err)
+	}
+}
@@ -30,7 +30,7 @@ func (v *mapCollection) Next() (bool, error) {
 	var ok bool
 	var err error
 	var frames [1]api.StackFrame
-	if err = v.context.Context.Err(); err == nil {
+	if err = v.context.Check(); err == nil {
 		ok, err = v.i.Next()
 		if ok && err == nil {
 			frames[0].Value = reflect.ValueOf(v.i.Value())
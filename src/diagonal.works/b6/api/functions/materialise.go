@@ -87,6 +87,9 @@ func materialiseMap(context *api.Context, collection b6.Collection[any, b6.Featu
 		context := contexts[i]
 		g.Go(func() error {
 			for f := range in {
+				if err := context.Check(); err != nil {
+					return err
+				}
 				result, err := context.VM.CallWithArgs(context, function, []interface{}{f})
 				if err != nil {
 					return err
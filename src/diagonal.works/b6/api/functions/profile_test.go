@@ -0,0 +1,98 @@
+package functions
+
+import (
+	"testing"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/api"
+	"diagonal.works/b6/test/camden"
+)
+
+func findProfileEntry(entries []api.ProfileEntry, function string) (api.ProfileEntry, bool) {
+	for _, e := range entries {
+		if e.Function == function {
+			return e, true
+		}
+	}
+	return api.ProfileEntry{}, false
+}
+
+func TestProfile(t *testing.T) {
+	granarySquare := camden.BuildGranarySquareForTests(t)
+	c := NewContext(granarySquare)
+	c.FillFromOptions(&api.Options{})
+	c.Profile = api.NewProfile()
+
+	const e = `find [#building] | map {b -> get b "building:levels"}`
+	v, err := api.EvaluateString(e, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := iterate(v.(b6.UntypedCollection))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries := c.Profile.Entries()
+	if find, ok := findProfileEntry(entries, "find"); !ok {
+		t.Error("Expected an entry for find")
+	} else {
+		if find.Calls != 1 || find.Items != n {
+			t.Errorf("Expected 1 call to find returning %d items, found %d calls returning %d", n, find.Calls, find.Items)
+		}
+		if find.Expression.Begin != 0 || find.Expression.End <= find.Expression.Begin {
+			t.Errorf("Expected find's expression to reference its source, found %d-%d", find.Expression.Begin, find.Expression.End)
+		}
+	}
+	if get, ok := findProfileEntry(entries, "get"); !ok || get.Calls != n {
+		t.Errorf("Expected %d calls to get, found %d", n, get.Calls)
+	}
+	if m, ok := findProfileEntry(entries, "map"); !ok || m.Items != n {
+		t.Errorf("Expected %d items read from map, found %d", n, m.Items)
+	}
+}
+
+func TestProfileLambdas(t *testing.T) {
+	c := NewContext(camden.BuildGranarySquareForTests(t))
+	c.FillFromOptions(&api.Options{})
+	c.Profile = api.NewProfile()
+
+	v, err := api.EvaluateString("map {1: 10, 2: 20, 3: 30} {v -> add-ints 1 v}", c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := iterate(v.(b6.UntypedCollection)); err != nil {
+		t.Fatal(err)
+	}
+	entries := c.Profile.Entries()
+	if lambda, ok := findProfileEntry(entries, "lambda"); !ok || lambda.Calls != 3 {
+		t.Errorf("Expected 3 calls to lambda, found %d", lambda.Calls)
+	}
+	if add, ok := findProfileEntry(entries, "add-ints"); !ok || add.Calls != 3 {
+		t.Errorf("Expected 3 calls to add-ints, found %d", add.Calls)
+	}
+}
+
+func TestProfileFunction(t *testing.T) {
+	granarySquare := camden.BuildGranarySquareForTests(t)
+	c := NewContext(granarySquare)
+	c.FillFromOptions(&api.Options{})
+
+	v, err := api.EvaluateString("profile {-> find [#building] | count}", c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	profiled, ok := v.(*api.ProfiledResult)
+	if !ok {
+		t.Fatalf("Expected a profiled result, found %T", v)
+	}
+	if profiled.Value != 13 {
+		t.Errorf("Expected 13 buildings, found %v", profiled.Value)
+	}
+	if _, ok := findProfileEntry(profiled.Profile.Entries(), "count"); !ok {
+		t.Error("Expected an entry for count")
+	}
+	if c.Profile != nil {
+		t.Error("Expected profiling to be limited to the function passed to profile")
+	}
+}
@@ -0,0 +1,204 @@
+package functions
+
+import (
+	"fmt"
+	"image/color"
+	"os"
+	"strconv"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/api"
+	"diagonal.works/b6/ingest"
+	"diagonal.works/b6/raster"
+
+	"github.com/golang/geo/s2"
+)
+
+type renderMapOptions struct {
+	// The bounds of the map, or an empty rectangle to fit the map to the
+	// geometries drawn.
+	Bounds     s2.Rect
+	Width      int
+	Height     int
+	Padding    int
+	Background color.Color
+	Style      raster.Style
+}
+
+var defaultRenderMapOptions = renderMapOptions{
+	Bounds:     s2.EmptyRect(),
+	Width:      1024,
+	Height:     768,
+	Padding:    16,
+	Background: color.RGBA{R: 0xec, G: 0xef, B: 0xf8, A: 0xff},
+	Style: raster.Style{
+		Fill:        color.RGBA{R: 0xb1, G: 0xc5, B: 0xfd, A: 0xff},
+		Stroke:      color.RGBA{R: 0x37, G: 0x58, B: 0x9f, A: 0xff},
+		StrokeWidth: 1,
+		PointRadius: 4,
+	},
+}
+
+func fillRenderMapOptions(options b6.UntypedCollection, o *renderMapOptions) error {
+	i := options.BeginUntyped()
+	for {
+		ok, err := i.Next()
+		if err != nil {
+			return err
+		} else if !ok {
+			return nil
+		}
+		var key, value string
+		if tag, ok := i.Value().(b6.Tag); ok {
+			key, value = tag.Key, tag.Value.String()
+		} else if key, ok = i.Key().(string); ok {
+			switch v := i.Value().(type) {
+			case string:
+				value = v
+			case int:
+				value = strconv.Itoa(v)
+			case float64:
+				value = strconv.FormatFloat(v, 'f', -1, 64)
+			default:
+				return fmt.Errorf("%s: expected a string or number, found %T", key, i.Value())
+			}
+		} else {
+			return fmt.Errorf("expected tag values, or string keys, found %T and %T", i.Key(), i.Value())
+		}
+		var colour *color.Color
+		var integer *int
+		var float *float64
+		var rect *s2.Rect
+		switch key {
+		case "bounds":
+			rect = &o.Bounds
+		case "width":
+			integer = &o.Width
+		case "height":
+			integer = &o.Height
+		case "padding":
+			integer = &o.Padding
+		case "background":
+			colour = &o.Background
+		case "fill":
+			colour = &o.Style.Fill
+		case "stroke":
+			colour = &o.Style.Stroke
+		case "stroke-width":
+			float = &o.Style.StrokeWidth
+		case "point-radius":
+			float = &o.Style.PointRadius
+		default:
+			return fmt.Errorf("unknown option %q", key)
+		}
+		if rect != nil {
+			if *rect, err = ingest.ParseBoundingBox(value); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+		} else if colour != nil {
+			if value == "none" {
+				*colour = nil
+			} else if c, ok := raster.ColourFromHexString(value); ok {
+				*colour = c
+			} else {
+				return fmt.Errorf("%s: expected a colour like #rrggbb, found %q", key, value)
+			}
+		} else if integer != nil {
+			if *integer, err = strconv.Atoi(value); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+		} else if *float, err = strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+}
+
+func geometryBounds(g b6.Geometry) s2.Rect {
+	if a, ok := g.(b6.Area); ok {
+		bounds := s2.EmptyRect()
+		for i := 0; i < a.Len(); i++ {
+			bounds = bounds.Union(a.Polygon(i).RectBound())
+		}
+		return bounds
+	}
+	switch g.GeometryType() {
+	case b6.GeometryTypePoint:
+		return s2.RectFromLatLng(s2.LatLngFromPoint(g.Point()))
+	case b6.GeometryTypePath:
+		return g.Polyline().RectBound()
+	}
+	return s2.EmptyRect()
+}
+
+// Render the given geometries into a PNG image, written to the given
+// filename, with the map's bounds fitted to those of the geometries,
+// unless given explicitly.
+// Features with a b6:colour tag giving a hex colour are drawn in that
+// colour.
+// Options are given as a collection of keys and values:
+// bounds: the bounds of the map, as lat,lng,lat,lng
+// width, height: the size of the image in pixels, default 1024x768
+// padding: the space around the bounds in pixels, default 16
+// background, fill, stroke: colours as #rrggbb or #rrggbbaa, or none
+// stroke-width, point-radius: sizes in pixels
+// As the file is written by the b6 server process, the filename is
+// relative to the filesystems it sees.
+func renderMap(c *api.Context, geometries b6.Collection[any, b6.Geometry], filename string, options b6.UntypedCollection) (string, error) {
+	if !c.FileIOAllowed {
+		return "", fmt.Errorf("File IO is not allowed")
+	}
+
+	o := defaultRenderMapOptions
+	if err := fillRenderMapOptions(options, &o); err != nil {
+		return "", err
+	}
+
+	gs := make([]b6.Geometry, 0)
+	bounds := s2.EmptyRect()
+	i := geometries.Begin()
+	for {
+		ok, err := i.Next()
+		if err != nil {
+			return "", err
+		} else if !ok {
+			break
+		}
+		gs = append(gs, i.Value())
+		bounds = bounds.Union(geometryBounds(i.Value()))
+	}
+	if !o.Bounds.IsEmpty() {
+		bounds = o.Bounds
+	} else if bounds.IsEmpty() {
+		return "", fmt.Errorf("expected at least one geometry, or bounds")
+	}
+
+	canvas, err := raster.NewCanvas(bounds, o.Width, o.Height, o.Padding, o.Background)
+	if err != nil {
+		return "", err
+	}
+	for _, g := range gs {
+		style := o.Style
+		if t, ok := g.(b6.Taggable); ok {
+			if colour := t.Get(b6.ColourTag); colour.IsValid() {
+				if c, ok := raster.ColourFromHexString(colour.Value.String()); ok {
+					if g.GeometryType() == b6.GeometryTypePath {
+						style.Stroke = c
+					} else {
+						style.Fill = c
+					}
+				}
+			}
+		}
+		canvas.DrawGeometry(g, &style)
+	}
+
+	w, err := os.OpenFile(filename, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s for write: %w", filename, err)
+	}
+	if err := canvas.EncodePNG(w); err != nil {
+		w.Close()
+		return "", err
+	}
+	return filename, w.Close()
+}
@@ -0,0 +1,117 @@
+package functions
+
+import (
+	"context"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/api"
+	"diagonal.works/b6/test/camden"
+)
+
+func TestRenderMap(t *testing.T) {
+	w := camden.BuildGranarySquareForTests(t)
+	c := &api.Context{
+		World:         w,
+		FileIOAllowed: true,
+		Context:       context.Background(),
+	}
+	lighterman := w.FindFeatureByID(camden.LightermanID.FeatureID())
+	if lighterman == nil {
+		t.Fatal("Failed to find The Lighterman")
+	}
+	geometries := b6.AdaptCollection[any, b6.Geometry](b6.ArrayFeatureCollection[b6.Feature]{lighterman}.Collection())
+	options := b6.ArrayCollection[string, any]{
+		Keys:   []string{"width", "height", "fill"},
+		Values: []any{200, 100, "#ff0000"},
+	}
+
+	filename := filepath.Join(t.TempDir(), "lighterman.png")
+	if _, err := renderMap(c, geometries, filename, options.Collection()); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	decoded, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := decoded.Bounds().Size(); size.X != 200 || size.Y != 100 {
+		t.Errorf("Expected a 200x100 image, found %dx%d", size.X, size.Y)
+	}
+	// The building is fitted to the image, so covers its center
+	red := color.RGBA{R: 0xff, A: 0xff}
+	if found := color.RGBAModel.Convert(decoded.At(100, 50)); found != red {
+		t.Errorf("Expected building to be drawn at the center of the image, found %v", found)
+	}
+
+	c.FileIOAllowed = false
+	if _, err := renderMap(c, geometries, filename, options.Collection()); err == nil {
+		t.Error("Expected an error when file IO isn't allowed")
+	}
+}
+
+func TestRenderMapWithBounds(t *testing.T) {
+	w := camden.BuildGranarySquareForTests(t)
+	c := &api.Context{World: w, FileIOAllowed: true, Context: context.Background()}
+	lighterman := w.FindFeatureByID(camden.LightermanID.FeatureID())
+	if lighterman == nil {
+		t.Fatal("Failed to find The Lighterman")
+	}
+	geometries := b6.AdaptCollection[any, b6.Geometry](b6.ArrayFeatureCollection[b6.Feature]{lighterman}.Collection())
+
+	// Bounds to the north east of the Lighterman, which shouldn't include it
+	options := b6.ArrayCollection[string, any]{
+		Keys:   []string{"bounds", "width", "height", "fill", "background"},
+		Values: []any{"51.5370,-0.1240,51.5380,-0.1230", 200, 100, "#ff0000", "#ffffff"},
+	}
+	filename := filepath.Join(t.TempDir(), "bounds.png")
+	if _, err := renderMap(c, geometries, filename, options.Collection()); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	decoded, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	red := color.RGBA{R: 0xff, A: 0xff}
+	for x := 0; x < 200; x++ {
+		for y := 0; y < 100; y++ {
+			if color.RGBAModel.Convert(decoded.At(x, y)) == red {
+				t.Fatalf("Expected the building to be outside the bounds, found it at %d,%d", x, y)
+			}
+		}
+	}
+
+	empty := b6.AdaptCollection[any, b6.Geometry](b6.ArrayFeatureCollection[b6.Feature]{}.Collection())
+	if _, err := renderMap(c, empty, filename, options.Collection()); err != nil {
+		t.Errorf("Expected no error when given bounds without geometries, found %s", err)
+	}
+
+	options.Values[0] = "51.5370,-0.1240"
+	if _, err := renderMap(c, geometries, filename, options.Collection()); err == nil {
+		t.Error("Expected an error for invalid bounds")
+	}
+}
+
+func TestRenderMapRejectsUnknownOptions(t *testing.T) {
+	w := camden.BuildGranarySquareForTests(t)
+	c := &api.Context{World: w, FileIOAllowed: true, Context: context.Background()}
+	lighterman := w.FindFeatureByID(camden.LightermanID.FeatureID())
+	geometries := b6.AdaptCollection[any, b6.Geometry](b6.ArrayFeatureCollection[b6.Feature]{lighterman}.Collection())
+	options := b6.ArrayValuesCollection[b6.Tag]{{Key: "colour", Value: b6.NewStringExpression("red")}}
+	if _, err := renderMap(c, geometries, filepath.Join(t.TempDir(), "map.png"), options.Collection()); err == nil {
+		t.Error("Expected an error for an unknown option")
+	}
+}
@@ -0,0 +1,109 @@
+package functions
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/api"
+	"diagonal.works/b6/graph"
+)
+
+// alternativeRouteOptionsFromTags returns the options controlling
+// alternative routes, from the options passed to graph functions.
+func alternativeRouteOptionsFromTags(opts b6.Tags) (graph.AlternativeRouteOptions, error) {
+	var options graph.AlternativeRouteOptions
+	if n := opts.Get("alternatives"); n.IsValid() {
+		if i, err := strconv.Atoi(n.Value.String()); err == nil && i > 0 {
+			options.Routes = i
+		} else {
+			return options, fmt.Errorf("expected a positive integer for alternatives, found %q", n.Value.String())
+		}
+	}
+	floats := []struct {
+		key   string
+		value *float64
+		min   float64
+	}{
+		{"alternatives:penalty", &options.Penalty, 1.0},
+		{"alternatives:max-overlap", &options.MaxOverlap, 0.0},
+		{"alternatives:max-stretch", &options.MaxStretch, 1.0},
+	}
+	for _, f := range floats {
+		if t := opts.Get(f.key); t.IsValid() {
+			if v, err := strconv.ParseFloat(t.Value.String(), 64); err == nil && v > f.min {
+				*f.value = v
+			} else {
+				return options, fmt.Errorf("expected a float string above %g for %s, found %q", f.min, f.key, t.Value.String())
+			}
+		}
+	}
+	return options, nil
+}
+
+// Return the shortest route from the given origin to the given
+// destination, within the given duration in seconds, followed by
+// alternative routes that differ significantly from it.
+// Keys of the collection are the rank of the route, starting at 0 for the
+// shortest.
+// Alternatives are found by repeatedly penalising the paths used by
+// previous routes. In addition to the options described in accessible-all,
+// they can be controlled with:
+// alternatives: the maximum number of routes returned, default 3
+// alternatives:penalty: the factor by which the cost of paths used by
+// previous routes is multiplied, default 1.4
+// alternatives:max-overlap: the maximum proportion of a route's length
+// shared with previous routes, default 0.75
+// alternatives:max-stretch: the maximum ratio between the cost of a
+// route and that of the shortest, default 1.5
+func routes(context *api.Context, origin b6.Identifiable, destination b6.Identifiable, duration float64, options b6.UntypedCollection) (b6.Collection[int, b6.Route], error) {
+	from, to := api.Resolve(origin, context.World), api.Resolve(destination, context.World)
+	if from == nil || to == nil {
+		return b6.Collection[int, b6.Route]{}, nil
+	}
+	opts, err := api.CollectionToTags(options)
+	if err != nil {
+		return b6.Collection[int, b6.Route]{}, err
+	}
+	alternatives, err := alternativeRouteOptionsFromTags(opts)
+	if err != nil {
+		return b6.Collection[int, b6.Route]{}, err
+	}
+	weights, err := weightsFromContext(context, options)
+	if err != nil {
+		return b6.Collection[int, b6.Route]{}, err
+	}
+	rs := graph.AlternativeRoutes(from, to, duration, weights, alternatives, context.World)
+	c := b6.ArrayCollection[int, b6.Route]{Keys: make([]int, len(rs)), Values: rs}
+	for i := range rs {
+		c.Keys[i] = i
+	}
+	return c.Collection(), nil
+}
+
+// Return a summary of the given route, for comparison with alternatives.
+// Keys of the collection are:
+// length: the length of the route, in meters
+// cost: the cost of the route, typically the time taken in seconds
+// elevation-gain: the total climb in meters, from ele tags
+// crossings: the number of crossings passed through
+// highway:<value>: the proportion of the route's length along paths
+// tagged with each value of #highway.
+func routeSummary(context *api.Context, route b6.Route) (b6.Collection[string, float64], error) {
+	summary := graph.SummariseRoute(route, context.World)
+	c := b6.ArrayCollection[string, float64]{
+		Keys:   []string{"length", "cost", "elevation-gain", "crossings"},
+		Values: []float64{summary.Length, summary.Cost, summary.ElevationGain, float64(summary.Crossings)},
+	}
+	highways := make([]string, 0, len(summary.Highways))
+	for highway := range summary.Highways {
+		highways = append(highways, highway)
+	}
+	sort.Strings(highways)
+	for _, highway := range highways {
+		c.Keys = append(c.Keys, "highway:"+highway)
+		c.Values = append(c.Values, summary.Highways[highway])
+	}
+	return c.Collection(), nil
+}
@@ -0,0 +1,88 @@
+package functions
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/api"
+	"diagonal.works/b6/test/camden"
+)
+
+func TestRoutesAndRouteSummary(t *testing.T) {
+	w := camden.BuildGranarySquareForTests(t)
+	if w == nil {
+		return
+	}
+	c := &api.Context{
+		World:           w,
+		Cores:           2,
+		FunctionSymbols: Functions(),
+		Adaptors:        Adaptors(),
+		Context:         context.Background(),
+	}
+
+	e := fmt.Sprintf("routes /%s /%s 1000.0 {\"mode\": \"walk\", \"alternatives\": \"2\"}", camden.StableStreetBridgeNorthEndID, camden.SomersTownBridgeEastGateID)
+	v, err := api.EvaluateString(e, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs, ok := v.(b6.Collection[int, b6.Route])
+	if !ok {
+		t.Fatalf("Expected a collection of routes, found %T", v)
+	}
+	routes, err := rs.AllValues(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) == 0 || len(routes) > 2 {
+		t.Fatalf("Expected 1 or 2 routes, found %d", len(routes))
+	}
+	for i, route := range routes {
+		if len(route.Steps) == 0 || route.Steps[len(route.Steps)-1].Destination != camden.SomersTownBridgeEastGateID {
+			t.Errorf("Expected route %d to end at the destination", i)
+		}
+	}
+
+	summary, err := routeSummary(c, routes[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := make(map[string]float64)
+	i := summary.Begin()
+	for {
+		ok, err := i.Next()
+		if err != nil {
+			t.Fatal(err)
+		} else if !ok {
+			break
+		}
+		values[i.Key()] = i.Value()
+	}
+	if values["length"] <= 0.0 || values["cost"] <= 0.0 {
+		t.Errorf("Expected positive length and cost, found %v", values)
+	}
+	shares := 0.0
+	for key, value := range values {
+		if strings.HasPrefix(key, "highway:") {
+			shares += value
+		}
+	}
+	if math.Abs(shares-1.0) > 1e-6 {
+		t.Errorf("Expected highway shares to sum to 1, found %f", shares)
+	}
+
+	e = fmt.Sprintf("route-summary (shortest-route /%s /%s 1000.0 {\"mode\": \"walk\"})", camden.StableStreetBridgeNorthEndID, camden.SomersTownBridgeEastGateID)
+	if v, err := api.EvaluateString(e, c); err != nil {
+		t.Errorf("Expected no error summarising a route, found %s", err)
+	} else if _, ok := v.(b6.Collection[string, float64]); !ok {
+		t.Errorf("Expected a collection, found %T", v)
+	}
+
+	if _, err := api.EvaluateString(fmt.Sprintf("routes /%s /%s 1000.0 {\"alternatives\": \"none\"}", camden.StableStreetBridgeNorthEndID, camden.SomersTownBridgeEastGateID), c); err == nil {
+		t.Error("Expected an error for an invalid number of alternatives")
+	}
+}
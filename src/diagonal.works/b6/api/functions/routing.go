@@ -0,0 +1,226 @@
+package functions
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/api"
+	"diagonal.works/b6/graph"
+	"diagonal.works/b6/ingest"
+
+	"gopkg.in/yaml.v2"
+)
+
+// RoutingProfileYAML is the representation of a routing profile in a
+// profiles file, or in the options passed to graph functions. Queries are
+// b6 query expressions, like [#highway=footway | #highway=path], or tags,
+// like surface=gravel, and speeds are in km/h.
+//
+// For example:
+// wheelchair:
+//
+//	speed: 3.5
+//	exclude: "#highway=steps"
+//	speeds:
+//	  "[#highway=primary | #highway=trunk]": 2.5
+//	factors:
+//	  surface=gravel: 3.0
+//	penalties:
+//	  "[#barrier=kerb]": 30
+type RoutingProfileYAML struct {
+	Useable          string             `yaml:",omitempty"`
+	Exclude          string             `yaml:",omitempty"`
+	Speed            float64            `yaml:",omitempty"`
+	Speeds           map[string]float64 `yaml:",omitempty"`
+	Factors          map[string]float64 `yaml:",omitempty"`
+	Penalties        map[string]float64 `yaml:",omitempty"`
+	Oneway           bool               `yaml:",omitempty"`
+	OnewayExceptions []string           `yaml:"oneway-exceptions,omitempty"`
+}
+
+// Compile returns the profile described, evaluating its queries with the
+// given context.
+func (r *RoutingProfileYAML) Compile(name string, context *api.Context) (*graph.Profile, error) {
+	p := &graph.Profile{
+		Name:             name,
+		Speed:            r.Speed * graph.KilometersPerHourToMetersPerSecond,
+		Oneway:           r.Oneway,
+		OnewayExceptions: r.OnewayExceptions,
+	}
+	var err error
+	if r.Useable != "" {
+		if p.Useable, err = compileRoutingQuery(r.Useable, context); err != nil {
+			return nil, err
+		}
+	}
+	if r.Exclude != "" {
+		if p.Exclude, err = compileRoutingQuery(r.Exclude, context); err != nil {
+			return nil, err
+		}
+	}
+	if p.Speeds, err = compileRoutingRules(r.Speeds, context, graph.KilometersPerHourToMetersPerSecond); err != nil {
+		return nil, err
+	}
+	if p.Factors, err = compileRoutingRules(r.Factors, context, 1.0); err != nil {
+		return nil, err
+	}
+	if p.Penalties, err = compileRoutingRules(r.Penalties, context, 1.0); err != nil {
+		return nil, err
+	}
+	if p.Speed <= 0.0 && len(p.Speeds) == 0 {
+		return nil, fmt.Errorf("routing profile %q needs a speed", name)
+	}
+	return p, nil
+}
+
+func compileRoutingRules(rules map[string]float64, context *api.Context, scale float64) ([]graph.ProfileRule, error) {
+	// Sort rules for a deterministic order, though the result doesn't
+	// depend on it.
+	expressions := make([]string, 0, len(rules))
+	for e := range rules {
+		expressions = append(expressions, e)
+	}
+	sort.Strings(expressions)
+	compiled := make([]graph.ProfileRule, 0, len(rules))
+	for _, e := range expressions {
+		if rules[e] < 0.0 {
+			return nil, fmt.Errorf("%q: expected a value of at least 0, found %f", e, rules[e])
+		}
+		q, err := compileRoutingQuery(e, context)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, graph.ProfileRule{Query: q, Value: rules[e] * scale})
+	}
+	return compiled, nil
+}
+
+func compileRoutingQuery(expression string, context *api.Context) (b6.Query, error) {
+	v, err := api.EvaluateString(expression, context)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", expression, err)
+	}
+	switch v := v.(type) {
+	case b6.Query:
+		return v, nil
+	case b6.Tag:
+		return b6.Tagged(v), nil
+	}
+	return nil, fmt.Errorf("%q: expected a query or tag, found %T", expression, v)
+}
+
+// ReadRoutingProfiles returns the profiles in the given YAML file, which
+// maps profile names to RoutingProfileYAML.
+func ReadRoutingProfiles(filename string) (map[string]*graph.Profile, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var ys map[string]*RoutingProfileYAML
+	if err := yaml.UnmarshalStrict(data, &ys); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	profiles := make(map[string]*graph.Profile, len(ys))
+	context := newRoutingProfileContext()
+	for name, y := range ys {
+		if profiles[name], err = y.Compile(name, context); err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", filename, name, err)
+		}
+	}
+	return profiles, nil
+}
+
+// newRoutingProfileContext returns a context in which to evaluate the
+// queries of routing profiles outside of the VM.
+func newRoutingProfileContext() *api.Context {
+	return &api.Context{
+		World:           ingest.NewBasicMutableWorld(),
+		FunctionSymbols: Functions(),
+		Adaptors:        Adaptors(),
+		Context:         context.Background(),
+	}
+}
+
+const routingProfilePrefix = "profile:"
+
+// routingProfileFromTags returns the profile given by the profile option,
+// either by name, from the given profiles, or defined with options
+// prefixed with profile:, mirroring the fields of RoutingProfileYAML. For
+// example:
+// profile:speed=5, profile:speeds:#highway=primary=3,
+// profile:penalties:[#barrier=gate]=20
+// Returns false if no profile is given. Queries are evaluated with the
+// given context.
+func routingProfileFromTags(opts b6.Tags, profiles map[string]*graph.Profile, context *api.Context) (*graph.Profile, bool, error) {
+	var y RoutingProfileYAML
+	inline := false
+	for _, tag := range opts {
+		key, ok := strings.CutPrefix(tag.Key, routingProfilePrefix)
+		if !ok {
+			continue
+		}
+		inline = true
+		value := tag.Value.String()
+		field, expression, _ := strings.Cut(key, ":")
+		var err error
+		switch field {
+		case "useable":
+			y.Useable = value
+		case "exclude":
+			y.Exclude = value
+		case "speed":
+			if y.Speed, err = strconv.ParseFloat(value, 64); err != nil {
+				err = fmt.Errorf("expected a float string, found %q", value)
+			}
+		case "oneway":
+			y.Oneway = value == "yes"
+		case "oneway-exceptions":
+			y.OnewayExceptions = strings.Split(value, ",")
+		case "speeds":
+			err = addRoutingRule(&y.Speeds, expression, value)
+		case "factors":
+			err = addRoutingRule(&y.Factors, expression, value)
+		case "penalties":
+			err = addRoutingRule(&y.Penalties, expression, value)
+		default:
+			return nil, false, fmt.Errorf("unknown routing profile option %s", tag.Key)
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("%s: %w", tag.Key, err)
+		}
+	}
+
+	if name := opts.Get("profile"); name.IsValid() {
+		if inline {
+			return nil, false, fmt.Errorf("can't use profile=%s together with options defining a profile", name.Value.String())
+		}
+		if p, ok := profiles[name.Value.String()]; ok {
+			return p, true, nil
+		}
+		return nil, false, fmt.Errorf("no routing profile named %q", name.Value.String())
+	} else if inline {
+		p, err := y.Compile("", context)
+		return p, err == nil, err
+	}
+	return nil, false, nil
+}
+
+func addRoutingRule(rules *map[string]float64, expression string, value string) error {
+	if expression == "" {
+		return fmt.Errorf("expected a query following the option name")
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("expected a float string, found %q", value)
+	}
+	if *rules == nil {
+		*rules = make(map[string]float64)
+	}
+	(*rules)[expression] = f
+	return nil
+}
@@ -0,0 +1,128 @@
+package functions
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/api"
+	"diagonal.works/b6/graph"
+	"diagonal.works/b6/test/camden"
+)
+
+const testRoutingProfiles = `
+wheelchair:
+  speed: 3.5
+  exclude: "#highway=steps"
+  speeds:
+    "[#highway=primary | #highway=trunk]": 2.5
+  factors:
+    surface=gravel: 3.0
+  penalties:
+    "[#barrier=kerb]": 30
+  oneway: true
+  oneway-exceptions: [oneway:foot]
+`
+
+func TestReadRoutingProfiles(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "profiles.yaml")
+	if err := os.WriteFile(filename, []byte(testRoutingProfiles), 0644); err != nil {
+		t.Fatal(err)
+	}
+	profiles, err := ReadRoutingProfiles(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, ok := profiles["wheelchair"]
+	if !ok {
+		t.Fatal("Expected a wheelchair profile")
+	}
+	if math.Abs(p.Speed-3.5*graph.KilometersPerHourToMetersPerSecond) > 1e-9 || !p.Oneway || len(p.OnewayExceptions) != 1 {
+		t.Errorf("Expected speed and oneway options from the file, found %+v", p)
+	}
+	if p.Exclude == nil || !p.Exclude.Equal(b6.Tagged{Key: "#highway", Value: b6.NewStringExpression("steps")}) {
+		t.Errorf("Expected exclude query, found %v", p.Exclude)
+	}
+	if len(p.Speeds) != 1 || len(p.Factors) != 1 || len(p.Penalties) != 1 {
+		t.Errorf("Expected one rule of each type, found %+v", p)
+	} else if _, ok := p.Speeds[0].Query.(b6.Union); !ok {
+		t.Errorf("Expected a union query for speeds, found %T", p.Speeds[0].Query)
+	}
+
+	bad := []string{
+		"wheelchair:\n  exclude: \"#highway=steps\"\n",     // No speed
+		"wheelchair:\n  speed: 3\n  exclude: 42\n",         // Not a query
+		"wheelchair:\n  speed: 3\n  unknown: true\n",       // Unknown field
+		"wheelchair:\n  speeds:\n    \"[#highway\": 3.0\n", // Bad query
+	}
+	for _, b := range bad {
+		if err := os.WriteFile(filename, []byte(b), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ReadRoutingProfiles(filename); err == nil {
+			t.Errorf("Expected an error for %q", b)
+		}
+	}
+}
+
+func TestWeightsFromRoutingProfiles(t *testing.T) {
+	w := camden.BuildGranarySquareForTests(t)
+	if w == nil {
+		return
+	}
+	profile := &graph.Profile{Name: "test", Speed: 1.0}
+	c := &api.Context{
+		World:           w,
+		Cores:           2,
+		FunctionSymbols: Functions(),
+		Adaptors:        Adaptors(),
+		Context:         context.Background(),
+		RoutingProfiles: map[string]*graph.Profile{"test": profile},
+	}
+
+	options := []b6.Tag{{Key: "profile", Value: b6.NewStringExpression("test")}}
+	weights, err := weightsFromContext(c, b6.ArrayValuesCollection[b6.Tag](options).Collection())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pw, ok := weights.(graph.ProfileWeights); !ok || pw.Profile != profile {
+		t.Errorf("Expected weights from the named profile, found %+v", weights)
+	}
+
+	options = []b6.Tag{
+		{Key: "profile:speed", Value: b6.NewStringExpression("6")},
+		{Key: "profile:speeds:#highway=footway", Value: b6.NewStringExpression("4")},
+		{Key: "profile:penalties:[#highway=crossing]", Value: b6.NewStringExpression("20")},
+		{Key: "profile:oneway", Value: b6.NewStringExpression("yes")},
+	}
+	weights, err = weightsFromContext(c, b6.ArrayValuesCollection[b6.Tag](options).Collection())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pw, ok := weights.(graph.ProfileWeights); !ok {
+		t.Errorf("Expected profile weights, found %T", weights)
+	} else if math.Abs(pw.Profile.Speed-6.0*graph.KilometersPerHourToMetersPerSecond) > 1e-9 || len(pw.Profile.Speeds) != 1 || len(pw.Profile.Penalties) != 1 || !pw.Profile.Oneway {
+		t.Errorf("Expected profile from inline options, found %+v", pw.Profile)
+	}
+
+	origins := b6.ArrayFeatureCollection[b6.Feature]{w.FindFeatureByID(camden.StableStreetBridgeNorthEndID)}
+	ids := b6.AdaptCollection[any, b6.Identifiable](origins.Collection())
+	if _, err := accessibleAll(c, ids, b6.Keyed{Key: "entrance"}, 500, b6.ArrayValuesCollection[b6.Tag](options).Collection()); err != nil {
+		t.Errorf("Expected no error from accessible-all with an inline profile, found %s", err)
+	}
+
+	bad := [][]b6.Tag{
+		{{Key: "profile", Value: b6.NewStringExpression("missing")}},
+		{{Key: "profile", Value: b6.NewStringExpression("test")}, {Key: "profile:speed", Value: b6.NewStringExpression("4")}},
+		{{Key: "profile:speed", Value: b6.NewStringExpression("fast")}},
+		{{Key: "profile:unknown", Value: b6.NewStringExpression("4")}},
+	}
+	for _, options := range bad {
+		if _, err := weightsFromContext(c, b6.ArrayValuesCollection[b6.Tag](options).Collection()); err == nil {
+			t.Errorf("Expected an error for %v", options)
+		}
+	}
+}
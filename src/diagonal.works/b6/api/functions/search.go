@@ -1,23 +1,28 @@
 package functions
 
 import (
+	"fmt"
+
 	"diagonal.works/b6"
 	"diagonal.works/b6/api"
 	pb "diagonal.works/b6/proto"
 
+	"github.com/golang/geo/s1"
 	"github.com/golang/geo/s2"
 )
 
 type searchFeatureCollection struct {
-	query b6.Query
-	w     b6.World
-	i     b6.Features
+	query   b6.Query
+	w       b6.World
+	i       b6.Features
+	context *api.Context
 }
 
 func (s *searchFeatureCollection) Begin() b6.Iterator[b6.FeatureID, b6.Feature] {
 	return &searchFeatureCollection{
-		query: s.query,
-		w:     s.w,
+		query:   s.query,
+		w:       s.w,
+		context: s.context,
 	}
 }
 
@@ -33,7 +38,10 @@ func (s *searchFeatureCollection) Next() (bool, error) {
 	if s.i == nil {
 		s.i = s.w.FindFeatures(s.query)
 	}
-	return s.i.Next(), nil
+	if !s.i.Next() {
+		return false, nil
+	}
+	return true, s.context.CountFeatures(1)
 }
 
 func (s *searchFeatureCollection) KeyExpression() b6.Expression {
@@ -64,7 +72,7 @@ var _ b6.AnyCollection[b6.FeatureID, b6.Feature] = &searchFeatureCollection{}
 // Keys are IDs, and values are features.
 func find(context *api.Context, query b6.Query) (b6.Collection[b6.FeatureID, b6.Feature], error) {
 	return b6.Collection[b6.FeatureID, b6.Feature]{
-		AnyCollection: &searchFeatureCollection{query: query, w: context.World},
+		AnyCollection: &searchFeatureCollection{query: query, w: context.World, context: context},
 	}, nil
 }
 
@@ -73,7 +81,7 @@ func find(context *api.Context, query b6.Query) (b6.Collection[b6.FeatureID, b6.
 func findAreaFeatures(context *api.Context, query b6.Query) (b6.Collection[b6.FeatureID, b6.AreaFeature], error) {
 	tq := b6.Typed{Type: b6.FeatureTypeArea, Query: query}
 	c := b6.Collection[b6.FeatureID, b6.Feature]{
-		AnyCollection: &searchFeatureCollection{query: tq, w: context.World},
+		AnyCollection: &searchFeatureCollection{query: tq, w: context.World, context: context},
 	}
 	return b6.AdaptCollection[b6.FeatureID, b6.AreaFeature](c), nil
 }
@@ -83,7 +91,7 @@ func findAreaFeatures(context *api.Context, query b6.Query) (b6.Collection[b6.Fe
 func findRelationFeatures(context *api.Context, query b6.Query) (b6.Collection[b6.FeatureID, b6.RelationFeature], error) {
 	tq := b6.Typed{Type: b6.FeatureTypeRelation, Query: query}
 	c := b6.Collection[b6.FeatureID, b6.Feature]{
-		AnyCollection: &searchFeatureCollection{query: tq, w: context.World},
+		AnyCollection: &searchFeatureCollection{query: tq, w: context.World, context: context},
 	}
 	return b6.AdaptCollection[b6.FeatureID, b6.RelationFeature](c), nil
 }
@@ -108,6 +116,72 @@ func intersectingCap(context *api.Context, center b6.Geometry, radius float64) (
 	return b6.NewIntersectsCap(s2.CapFromCenterAngle(center.Point(), b6.MetersToAngle(radius))), nil
 }
 
+const maxNearestPreallocated = 64
+
+// findNearest returns the features matching the query closest to the
+// origin, in order of increasing distance.
+func findNearest(context *api.Context, origin b6.Geometry, query b6.Query, k int, distance []float64) ([]b6.FeatureID, []b6.Feature, []float64, error) {
+	if origin == nil || origin.GeometryType() != b6.GeometryTypePoint {
+		return nil, nil, nil, fmt.Errorf("expected a point as the origin")
+	}
+	maxDistance := s1.InfAngle()
+	if len(distance) == 1 {
+		maxDistance = b6.MetersToAngle(distance[0])
+	} else if len(distance) > 1 {
+		return nil, nil, nil, fmt.Errorf("expected at most one distance, found %d", len(distance))
+	}
+	if k < 1 {
+		return nil, nil, nil, fmt.Errorf("expected at least 1 feature, found %d", k)
+	}
+	// k is chosen by the caller, so don't preallocate for more features
+	// than a typical query returns.
+	n := k
+	if n > maxNearestPreallocated {
+		n = maxNearestPreallocated
+	}
+	ids := make([]b6.FeatureID, 0, n)
+	features := make([]b6.Feature, 0, n)
+	distances := make([]float64, 0, n)
+	nearest := b6.NewNearestFeatures(context.World, origin.Point(), query, maxDistance)
+	for len(ids) < k && nearest.Next() {
+		if err := context.CountFeatures(1); err != nil {
+			return nil, nil, nil, err
+		}
+		ids = append(ids, nearest.FeatureID())
+		features = append(features, nearest.Feature())
+		distances = append(distances, b6.AngleToMeters(nearest.Distance()))
+	}
+	return ids, features, distances, nil
+}
+
+// Return a collection of the k features matching the given query that are
+// closest to the given point, by straight line distance, in order of
+// increasing distance.
+// If a distance in meters is given, only features within that distance
+// are returned.
+// Keys are IDs, and values are features.
+func nearest(context *api.Context, origin b6.Geometry, query b6.Query, k int, distance ...float64) (b6.Collection[b6.FeatureID, b6.Feature], error) {
+	ids, features, _, err := findNearest(context, origin, query, k, distance)
+	if err != nil {
+		return b6.Collection[b6.FeatureID, b6.Feature]{}, err
+	}
+	return b6.ArrayCollection[b6.FeatureID, b6.Feature]{Keys: ids, Values: features}.Collection(), nil
+}
+
+// Return a collection of the k features matching the given query that are
+// closest to the given point, by straight line distance, in order of
+// increasing distance.
+// If a distance in meters is given, only features within that distance
+// are returned.
+// Keys are IDs, and values are distances in meters.
+func nearestDistances(context *api.Context, origin b6.Geometry, query b6.Query, k int, distance ...float64) (b6.Collection[b6.FeatureID, float64], error) {
+	ids, _, distances, err := findNearest(context, origin, query, k, distance)
+	if err != nil {
+		return b6.Collection[b6.FeatureID, float64]{}, err
+	}
+	return b6.ArrayCollection[b6.FeatureID, float64]{Keys: ids, Values: distances}.Collection(), nil
+}
+
 // Keep only those features that are valid.
 func isValid(context *api.Context) (b6.Query, error) {
 	return b6.IsValid{}, nil
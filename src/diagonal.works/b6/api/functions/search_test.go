@@ -0,0 +1,84 @@
+package functions
+
+import (
+	"context"
+	"testing"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/api"
+	"diagonal.works/b6/test/camden"
+
+	"github.com/golang/geo/s2"
+)
+
+func TestNearest(t *testing.T) {
+	w := camden.BuildGranarySquareForTests(t)
+	c := &api.Context{World: w, Context: context.Background()}
+	// A point inside The Lighterman
+	origin := b6.GeometryFromLatLng(s2.LatLngFromDegrees(51.53531, -0.12434))
+
+	features, err := nearest(c, origin, b6.Keyed{Key: "#building"}, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids, err := features.AllKeys(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 3 || ids[0] != camden.LightermanID.FeatureID() {
+		t.Errorf("Expected 3 buildings, starting with The Lighterman, found %v", ids)
+	}
+
+	distances, err := nearestDistances(c, origin, b6.Keyed{Key: "#building"}, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, err := distances.AllValues(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 3 || values[0] != 0.0 {
+		t.Errorf("Expected the first building to be at 0m, found %v", values)
+	}
+	for i := 1; i < len(values); i++ {
+		if values[i] < values[i-1] {
+			t.Errorf("Expected increasing distances, found %v", values)
+		}
+	}
+
+	within, err := nearestDistances(c, origin, b6.Keyed{Key: "#building"}, 3, values[1]/2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, ok := within.Count(); !ok || n != 1 {
+		t.Errorf("Expected only 1 building within %fm, found %d", values[1]/2, n)
+	}
+
+	lighterman := w.FindFeatureByID(camden.LightermanID.FeatureID()).(b6.AreaFeature)
+	if _, err := nearest(c, lighterman, b6.Keyed{Key: "#building"}, 3); err == nil {
+		t.Error("Expected an error for an area as the origin")
+	}
+	if _, err := nearest(c, origin, b6.Keyed{Key: "#building"}, -1); err == nil {
+		t.Error("Expected an error for a negative number of features")
+	}
+}
+
+func TestNearestFromShell(t *testing.T) {
+	w := camden.BuildGranarySquareForTests(t)
+	for _, expression := range []string{
+		`nearest-distances (ll 51.53531 -0.12434) (keyed "#building") 3`,
+		`nearest-distances (ll 51.53531 -0.12434) (keyed "#building") 3 1000.0`,
+	} {
+		v, err := api.EvaluateString(expression, NewContext(w))
+		if err != nil {
+			t.Fatalf("%s: %s", expression, err)
+		}
+		c, ok := v.(b6.UntypedCollection)
+		if !ok {
+			t.Fatalf("%s: expected a collection, found %T", expression, v)
+		}
+		if n, ok := c.Count(); !ok || n != 3 {
+			t.Errorf("%s: expected 3 features, found %d", expression, n)
+		}
+	}
+}
@@ -20,18 +20,37 @@ const verboseGeoJSON = false
 
 func sightline(context *api.Context, from b6.Geometry, radius float64) (b6.Area, error) {
 	if centroid, ok := b6.Centroid(from); ok {
-		return b6.AreaFromS2Polygon(Sightline(centroid, b6.MetersToAngle(radius), context.World)), nil
+		check := func() error {
+			return context.CountFeatures(sightlineCheckInterval)
+		}
+		if polygon, err := sightlineWithCheck(centroid, b6.MetersToAngle(radius), context.World, check); err == nil {
+			return b6.AreaFromS2Polygon(polygon), nil
+		} else {
+			return b6.InvalidArea{}, err
+		}
 	}
 	return b6.InvalidArea{}, nil
 }
 
 func Sightline(center s2.Point, radius s1.Angle, w b6.World) *s2.Polygon {
+	polygon, _ := sightlineWithCheck(center, radius, w, nil)
+	return polygon
+}
+
+// The number of buildings, or sweep events, processed between calls to
+// check when computing a sightline.
+const sightlineCheckInterval = 256
+
+// sightlineWithCheck computes a sightline in the same way as Sightline, but
+// calls check periodically, abandoning the computation if it returns an
+// error. A nil check is never called.
+func sightlineWithCheck(center s2.Point, radius s1.Angle, w b6.World, check func() error) (*s2.Polygon, error) {
 	defer func() {
 		if r := recover(); r != nil {
 			panic(fmt.Sprintf("panic in Sightline %s: %v", s2.LatLngFromPoint(center).String(), r))
 		}
 	}()
-	return SightlineUsingPolarCoordinates2(center, radius, w)
+	return sightlineUsingPolarCoordinates(center, radius, w, check)
 }
 
 func loopIntersects(a s2.Point, b s2.Point, loop *s2.Loop, exceptEdge int) bool {
@@ -579,6 +598,11 @@ func outputSightlineEvents(barriers []s2.Edge, polar []polarEdge, events []polar
 }
 
 func SightlineUsingPolarCoordinates2(center s2.Point, radius s1.Angle, w b6.World) *s2.Polygon {
+	polygon, _ := sightlineUsingPolarCoordinates(center, radius, w, nil)
+	return polygon
+}
+
+func sightlineUsingPolarCoordinates(center s2.Point, radius s1.Angle, w b6.World, check func() error) (*s2.Polygon, error) {
 	boundary := s2.RegularLoop(center, radius, 128)
 	boundaryEvents := make(edgeEvents, 0, 2*boundary.NumVertices())
 	for i := 0; i < boundary.NumVertices(); i++ {
@@ -592,7 +616,12 @@ func SightlineUsingPolarCoordinates2(center s2.Point, radius s1.Angle, w b6.Worl
 	barriers := make([]s2.Edge, 0, 64)
 	cap := s2.CapFromCenterAngle(center, radius)
 	features := b6.FindAreas(b6.Intersection{b6.MightIntersect{Region: cap}, b6.Keyed{Key: "#building"}}, w)
-	for features.Next() {
+	for n := 1; features.Next(); n++ {
+		if check != nil && n%sightlineCheckInterval == 0 {
+			if err := check(); err != nil {
+				return nil, err
+			}
+		}
 		area := features.Feature()
 		for i := 0; i < area.Len(); i++ {
 			loop := area.Polygon(i).Loop(0)
@@ -702,6 +731,11 @@ func SightlineUsingPolarCoordinates2(center s2.Point, radius s1.Angle, w b6.Worl
 	live := make(map[int]int)
 	current := -1
 	for i := 0; i < len(pevs); i++ {
+		if check != nil && (i+1)%sightlineCheckInterval == 0 {
+			if err := check(); err != nil {
+				return nil, err
+			}
+		}
 		if pevs[i].begin {
 			if current < 0 {
 				if verbose {
@@ -795,7 +829,7 @@ func SightlineUsingPolarCoordinates2(center s2.Point, radius s1.Angle, w b6.Worl
 		outputSightlineEvents(barriers, polar, pevs, points, "sightline-panic-events.geojson")
 		panic(fmt.Sprintf("bad loop: %s", err))
 	}
-	return s2.PolygonFromLoops([]*s2.Loop{loop})
+	return s2.PolygonFromLoops([]*s2.Loop{loop}), nil
 }
 
 const entranceApproachDistanceMeters = 4.0
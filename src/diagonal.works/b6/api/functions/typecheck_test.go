@@ -0,0 +1,102 @@
+package functions
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/api"
+	"diagonal.works/b6/test/camden"
+)
+
+func callWithFeature(c *api.Context, f func(*api.Context, b6.Feature) (int, error)) (int, error) {
+	return f(c, nil)
+}
+
+func typeCheckString(e string) error {
+	fs := make(api.FunctionSymbols)
+	for name, f := range Functions() {
+		fs[name] = f
+	}
+	fs["call-with-feature"] = callWithFeature
+	expression, err := api.ParseExpression(e)
+	if err != nil {
+		return err
+	}
+	adaptors := Adaptors()
+	return api.TypeCheck(api.Simplify(expression, fs), fs, &adaptors)
+}
+
+func TestTypeCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		// The source of the expression with a type error, or empty if
+		// the expression is valid.
+		err string
+	}{
+		{"Valid", `find [#building] | map {b -> get b "building:levels"}`, ""},
+		{"ValidPartialApplication", `find [#building] | map (get "name")`, ""},
+		{"ValidNumberConversion", `add-ints 1 (count (find [#building]))`, ""},
+		{"ValidQueryAsFunction", `find [#building] | filter [addr:postcode]`, ""},
+		{"ValidInferredLambdaArgs", `call-with-feature {f -> add-ints 1 (count (all-tags f))}`, ""},
+		{"ValidUnevaluatedLambdaBody", `find [#building] | map {b -> is-valid (get b "name")}`, ""},
+		{"ValidErrorInLambdaBody", `call-with-feature {f -> add-ints 1 f}`, ""},
+		{"WrongArgType", `find 42`, "42"},
+		{"WrongArgTypeInPipeline", `42 | find`, "42"},
+		{"WrongCollectionType", `[#building] | map {b -> b}`, "#building"},
+		{"TooManyArgs", `add-ints 1 2 3`, "add-ints 1 2 3"},
+		{"UndefinedSymbol", `find [#building] | map {b -> undefined b}`, "undefined"},
+		{"WrongLambdaResult", `call-with-feature {f -> "forty-two"}`, `f -> "forty-two"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := typeCheckString(test.expression)
+			if test.err == "" {
+				if err != nil {
+					t.Errorf("Expected no error, found %s", err)
+				}
+				return
+			}
+			var typeErrors api.TypeErrors
+			if !errors.As(err, &typeErrors) || len(typeErrors) != 1 {
+				t.Fatalf("Expected 1 type error, found %v", err)
+			}
+			e := typeErrors[0].Expression
+			if source := test.expression[e.Begin:e.End]; source != test.err {
+				t.Errorf("Expected error at %q, found %q (%s)", test.err, source, typeErrors[0])
+			}
+		})
+	}
+}
+
+func TestTypeErrorsPreventEvaluation(t *testing.T) {
+	var counted countedCalls
+	c := NewContext(camden.BuildGranarySquareForTests(t))
+	c.FunctionSymbols = make(api.FunctionSymbols)
+	for name, f := range Functions() {
+		c.FunctionSymbols[name] = f
+	}
+	c.FunctionSymbols["double"] = counted.double
+
+	var typeErrors api.TypeErrors
+	if _, err := api.EvaluateString(`add-ints (double 21) "21"`, c); !errors.As(err, &typeErrors) {
+		t.Errorf("Expected a type error, found %v", err)
+	}
+	if counted.calls != 0 {
+		t.Error("Expected no functions to be called before type errors are reported")
+	}
+}
+
+func TestInferType(t *testing.T) {
+	expression, err := api.ParseExpression(`find [#building] | count`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	adaptors := Adaptors()
+	if inferred := api.InferType(expression, Functions(), &adaptors); inferred != reflect.TypeOf(0) {
+		t.Errorf("Expected int, found %v", inferred)
+	}
+}
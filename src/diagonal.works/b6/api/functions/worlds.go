@@ -0,0 +1,186 @@
+package functions
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/api"
+	"diagonal.works/b6/ingest"
+)
+
+type cloneWorldChange struct {
+	id       b6.FeatureID
+	readOnly bool
+}
+
+func (c cloneWorldChange) Apply(worlds ingest.Worlds, root b6.FeatureID) (b6.FeatureID, error) {
+	return c.id, worlds.CloneWorld(root, c.id, c.readOnly)
+}
+
+// Return a change that creates a world with the given ID, containing the
+// modifications made to the current world.
+// Changes to either world don't affect the other, allowing scenarios to
+// branch from each other, rather than only from the base world.
+func cloneWorld(c *api.Context, id b6.FeatureID) (ingest.WorldsChange, error) {
+	return cloneWorldChange{id: id}, nil
+}
+
+// Return a change that creates a read-only world with the given ID,
+// containing the modifications made to the current world.
+// The snapshot preserves the current state of the world, to be compared
+// against, or cloned, as it's modified further.
+func snapshotWorld(c *api.Context, id b6.FeatureID) (ingest.WorldsChange, error) {
+	return cloneWorldChange{id: id, readOnly: true}, nil
+}
+
+type freezeWorldChange struct{}
+
+func (freezeWorldChange) Apply(worlds ingest.Worlds, root b6.FeatureID) (b6.FeatureID, error) {
+	return root, worlds.FreezeWorld(root)
+}
+
+// Return a change that prevents further modification of the current
+// world.
+// The world can still be cloned, to continue modifying a copy.
+func freezeWorld(c *api.Context) (ingest.WorldsChange, error) {
+	return freezeWorldChange{}, nil
+}
+
+type renameWorldChange struct {
+	id b6.FeatureID
+}
+
+func (r renameWorldChange) Apply(worlds ingest.Worlds, root b6.FeatureID) (b6.FeatureID, error) {
+	return r.id, worlds.RenameWorld(root, r.id)
+}
+
+// Return a change that moves the current world to the given ID, which
+// mustn't already be in use.
+func renameWorld(c *api.Context, id b6.FeatureID) (ingest.WorldsChange, error) {
+	return renameWorldChange{id: id}, nil
+}
+
+type setWorldMetadataChange struct {
+	key   string
+	value string
+}
+
+func (s setWorldMetadataChange) Apply(worlds ingest.Worlds, root b6.FeatureID) (b6.FeatureID, error) {
+	metadata, err := worlds.WorldMetadata(root)
+	if err != nil {
+		return root, err
+	}
+	switch s.key {
+	case "name":
+		metadata.Name = s.value
+	case "author":
+		metadata.Author = s.value
+	}
+	return root, worlds.SetWorldMetadata(root, metadata)
+}
+
+// Return a change that sets the metadata of the current world with the
+// given key, either name or author, to the given value.
+func setWorldMetadata(c *api.Context, key string, value string) (ingest.WorldsChange, error) {
+	if key != "name" && key != "author" {
+		return nil, fmt.Errorf("can't set world metadata %q, expected name or author", key)
+	}
+	return setWorldMetadataChange{key: key, value: value}, nil
+}
+
+// Return the metadata of the current world.
+// Keys are name, author, created, the world it was cloned from as
+// parent, and read-only. Keys without a value are omitted.
+func worldMetadata(c *api.Context) (b6.Collection[string, string], error) {
+	metadata, err := c.Worlds.WorldMetadata(c.WorldID)
+	if err != nil {
+		return b6.Collection[string, string]{}, err
+	}
+	collection := b6.ArrayCollection[string, string]{}
+	add := func(key string, value string) {
+		if value != "" {
+			collection.Keys = append(collection.Keys, key)
+			collection.Values = append(collection.Values, value)
+		}
+	}
+	add("name", metadata.Name)
+	add("author", metadata.Author)
+	if !metadata.Created.IsZero() {
+		add("created", metadata.Created.UTC().Format(time.RFC3339))
+	}
+	if metadata.Parent.IsValid() {
+		add("parent", metadata.Parent.String())
+	}
+	add("read-only", strconv.FormatBool(metadata.ReadOnly))
+	return collection.Collection(), nil
+}
+
+// Return the features that differ between the given world and the
+// current one.
+// Values are added, for features only present in the current world,
+// removed, for those only present in the given world, or modified, for
+// those with different tags or members. Only features modified in at
+// least one of the worlds are compared.
+func diffWorld(c *api.Context, id b6.FeatureID) (b6.Collection[b6.FeatureID, string], error) {
+	if !id.IsValid() {
+		id = ingest.DefaultWorldFeatureID
+	}
+	current, ok := c.World.(ingest.MutableWorld)
+	if !ok {
+		return b6.Collection[b6.FeatureID, string]{}, fmt.Errorf("can't diff an immutable world")
+	}
+	if _, err := c.Worlds.WorldMetadata(id); err != nil {
+		return b6.Collection[b6.FeatureID, string]{}, err
+	}
+	if id != c.WorldID && c.Locks != nil {
+		unlock, err := rlockWorld(c, id)
+		if err != nil {
+			return b6.Collection[b6.FeatureID, string]{}, err
+		}
+		defer unlock()
+	}
+	diffs, err := ingest.DiffWorlds(c.Worlds.FindOrCreateWorld(id), current)
+	if err != nil {
+		return b6.Collection[b6.FeatureID, string]{}, err
+	}
+	collection := b6.ArrayCollection[b6.FeatureID, string]{
+		Keys:   make([]b6.FeatureID, len(diffs)),
+		Values: make([]string, len(diffs)),
+	}
+	for i, diff := range diffs {
+		collection.Keys[i] = diff.ID
+		switch {
+		case diff.Added:
+			collection.Values[i] = "added"
+		case diff.Removed:
+			collection.Values[i] = "removed"
+		default:
+			collection.Values[i] = "modified"
+		}
+	}
+	return collection.Collection(), nil
+}
+
+// rlockWorld takes a read lock on another world, while the caller already
+// holds one on the current world. Blocking could deadlock against an
+// evaluation on the other world doing the same, if changes to both worlds
+// are waiting for their locks, so we poll instead.
+func rlockWorld(c *api.Context, id b6.FeatureID) (func(), error) {
+	ctx := c.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	for {
+		if unlock, ok := c.Locks.TryRLock(id); ok {
+			return unlock, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package api
+
+import (
+	"fmt"
+	"runtime/metrics"
+	"sync/atomic"
+	"time"
+)
+
+// Limits bound the resources used by a single evaluation, preventing
+// one runaway expression from monopolising a shared server. Zero values
+// impose no limit.
+type Limits struct {
+	// The maximum wall time for an evaluation, including time spent
+	// iterating over lazily evaluated results.
+	Timeout time.Duration
+	// The maximum number of features an evaluation can read from the
+	// world, either via queries, or by expanding graph searches.
+	MaxFeatures int
+	// The heap size, in bytes, beyond which evaluations are aborted. As
+	// Go can't attribute memory to individual goroutines, this is a limit
+	// on the process as a whole.
+	MaxHeapBytes uint64
+}
+
+type Resource int
+
+const (
+	ResourceTime Resource = iota
+	ResourceFeatures
+	ResourceMemory
+)
+
+func (r Resource) String() string {
+	switch r {
+	case ResourceTime:
+		return "time"
+	case ResourceFeatures:
+		return "features"
+	case ResourceMemory:
+		return "memory"
+	}
+	return "invalid"
+}
+
+// LimitExceededError is returned when an evaluation exceeds one of its
+// limits.
+type LimitExceededError struct {
+	Resource Resource
+	// The limit that was exceeded, in units dependent on the resource.
+	Limit uint64
+}
+
+func (l *LimitExceededError) Error() string {
+	switch l.Resource {
+	case ResourceTime:
+		return fmt.Sprintf("evaluation exceeded time limit of %s", time.Duration(l.Limit))
+	case ResourceFeatures:
+		return fmt.Sprintf("evaluation exceeded limit of %d features", l.Limit)
+	case ResourceMemory:
+		return fmt.Sprintf("evaluation aborted, as heap exceeded %dMB", l.Limit/(1024*1024))
+	}
+	return fmt.Sprintf("evaluation exceeded %s limit", l.Resource)
+}
+
+// usage tracks the resources used by an evaluation. It's shared between
+// contexts forked from the same evaluation.
+type usage struct {
+	deadline time.Time
+	features atomic.Int64
+	checks   atomic.Uint64
+}
+
+// The number of features counted between calls to Check.
+const featureCheckInterval = 256
+
+// The number of calls to Check between reads of the heap size, which is
+// comparatively expensive.
+const heapCheckInterval = 64
+
+func newUsage(limits *Limits, now time.Time) *usage {
+	u := &usage{}
+	u.start(limits, now)
+	return u
+}
+
+func (u *usage) start(limits *Limits, now time.Time) {
+	if limits.Timeout > 0 {
+		u.deadline = now.Add(limits.Timeout)
+	}
+}
+
+// StartTimer restarts the clock against which Limits.Timeout is measured,
+// which otherwise starts when the context is filled from options. Callers
+// should call it immediately before evaluation, so that time spent waiting
+// for locks doesn't count against the timeout.
+func (c *Context) StartTimer() {
+	if c.usage != nil {
+		c.usage.start(&c.Limits, time.Now())
+	}
+}
+
+// Check returns an error if the evaluation has exceeded its time or memory
+// limits, or if the context's underlying Context is done, in which case its
+// error is returned unchanged. Long running functions should call it
+// periodically.
+func (c *Context) Check() error {
+	if c.Context != nil {
+		if err := c.Context.Err(); err != nil {
+			return err
+		}
+	}
+	if c.usage == nil {
+		return nil
+	}
+	if !c.usage.deadline.IsZero() && time.Now().After(c.usage.deadline) {
+		return &LimitExceededError{Resource: ResourceTime, Limit: uint64(c.Limits.Timeout)}
+	}
+	if c.Limits.MaxHeapBytes > 0 && (c.usage.checks.Add(1)-1)%heapCheckInterval == 0 {
+		if heapBytes() > c.Limits.MaxHeapBytes {
+			return &LimitExceededError{Resource: ResourceMemory, Limit: c.Limits.MaxHeapBytes}
+		}
+	}
+	return nil
+}
+
+// CountFeatures records that n features have been read from the world,
+// returning an error if the evaluation has exceeded its limits.
+func (c *Context) CountFeatures(n int) error {
+	if c.usage == nil {
+		return nil
+	}
+	total := c.usage.features.Add(int64(n))
+	if c.Limits.MaxFeatures > 0 && total > int64(c.Limits.MaxFeatures) {
+		return &LimitExceededError{Resource: ResourceFeatures, Limit: uint64(c.Limits.MaxFeatures)}
+	}
+	if total/featureCheckInterval != (total-int64(n))/featureCheckInterval {
+		return c.Check()
+	}
+	return nil
+}
+
+// FeaturesRead returns the number of features read from the world by the
+// evaluation so far.
+func (c *Context) FeaturesRead() int {
+	if c.usage == nil {
+		return 0
+	}
+	return int(c.usage.features.Load())
+}
+
+const heapMetric = "/memory/classes/heap/objects:bytes"
+
+func heapBytes() uint64 {
+	sample := []metrics.Sample{{Name: heapMetric}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() == metrics.KindUint64 {
+		return sample[0].Value.Uint64()
+	}
+	return 0
+}
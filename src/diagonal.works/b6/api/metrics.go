@@ -0,0 +1,97 @@
+package api
+
+import (
+	"time"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/monitoring"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// EvaluationMetrics records the duration of evaluations, by their top
+// level function, and the changes applied to worlds. Methods on a nil
+// EvaluationMetrics do nothing.
+type EvaluationMetrics struct {
+	functions FunctionSymbols
+	durations *prometheus.HistogramVec
+	errors    *prometheus.CounterVec
+	changes   *prometheus.CounterVec
+}
+
+// NewEvaluationMetrics returns metrics labelled with the names of the
+// given functions, limiting the number of distinct labels clients can
+// create.
+func NewEvaluationMetrics(r prometheus.Registerer, functions FunctionSymbols) *EvaluationMetrics {
+	f := promauto.With(r)
+	return &EvaluationMetrics{
+		functions: functions,
+		durations: f.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "b6_evaluation_duration_seconds",
+			Help:    "Duration of evaluations, by top level function.",
+			Buckets: monitoring.DurationBuckets,
+		}, []string{"function"}),
+		errors: f.NewCounterVec(prometheus.CounterOpts{
+			Name: "b6_evaluation_errors_total",
+			Help: "Evaluations that failed, by top level function.",
+		}, []string{"function"}),
+		changes: f.NewCounterVec(prometheus.CounterOpts{
+			Name: "b6_world_changes_total",
+			Help: "Changes applied to worlds, by result.",
+		}, []string{"result"}),
+	}
+}
+
+// TopLevelFunction returns the name of the function whose result is
+// returned by the given expression, for example map for
+// find [#building] | map {b -> get b "building:levels"}, unknown if it
+// isn't one of the given functions, or the type of the expression if it
+// isn't a function call.
+func TopLevelFunction(e b6.Expression, functions FunctionSymbols) string {
+	var symbol b6.SymbolExpression
+	switch e := e.AnyExpression.(type) {
+	case b6.CallExpression:
+		var ok bool
+		if symbol, ok = e.Function.AnyExpression.(b6.SymbolExpression); !ok {
+			return "call"
+		}
+	case b6.SymbolExpression:
+		symbol = e
+	case b6.LambdaExpression:
+		return "lambda"
+	default:
+		return "literal"
+	}
+	if _, ok := functions[symbol.String()]; ok {
+		return symbol.String()
+	}
+	return "unknown"
+}
+
+// ObserveEvaluation records the evaluation of the given expression,
+// started at the given time, and which failed if err isn't nil.
+func (m *EvaluationMetrics) ObserveEvaluation(e b6.Expression, start time.Time, err error) {
+	if m == nil {
+		return
+	}
+	function := TopLevelFunction(e, m.functions)
+	m.durations.WithLabelValues(function).Observe(time.Since(start).Seconds())
+	if err != nil {
+		m.errors.WithLabelValues(function).Inc()
+	}
+}
+
+// ObserveChange records the application of a change to a world, which
+// failed if err isn't nil. Changes aren't labelled with the world, since
+// clients choose world IDs, and could otherwise create unlimited series.
+func (m *EvaluationMetrics) ObserveChange(err error) {
+	if m == nil {
+		return
+	}
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	m.changes.WithLabelValues(result).Inc()
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestWorldChangesArentLabelledByWorld(t *testing.T) {
+	r := prometheus.NewRegistry()
+	m := NewEvaluationMetrics(r, FunctionSymbols{})
+	for i := 0; i < 3; i++ {
+		m.ObserveChange(nil)
+	}
+	m.ObserveChange(errors.New("broken"))
+
+	families, err := r.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := make(map[string]float64)
+	for _, family := range families {
+		if family.GetName() != "b6_world_changes_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := metric.GetLabel()
+			if len(labels) != 1 || labels[0].GetName() != "result" {
+				t.Fatalf("Expected only a result label, found %v", labels)
+			}
+			found[labels[0].GetValue()] = metric.GetCounter().GetValue()
+		}
+	}
+	if found["ok"] != 3 || found["error"] != 1 {
+		t.Errorf("Expected 3 successful changes and 1 failure, found %v", found)
+	}
+}
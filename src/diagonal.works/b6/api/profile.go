@@ -0,0 +1,165 @@
+package api
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"diagonal.works/b6"
+	pb "diagonal.works/b6/proto"
+)
+
+// ProfileEntry records the cost of the calls made from a single location
+// in an expression.
+type ProfileEntry struct {
+	// The name of the function called, or "lambda" for calls to lambdas.
+	Function string
+	// The expression making the call, or the lambda itself. If the
+	// expression was parsed, its Begin and End identify its position in
+	// the source.
+	Expression b6.Expression
+	Calls      int
+	// The time spent in calls, including time spent in nested calls.
+	// Time spent iterating over lazily evaluated collections returned by
+	// the call is accounted to the calls made during iteration, rather
+	// than to this entry.
+	Duration time.Duration
+	// The number of items read from collections returned by the calls.
+	Items int
+}
+
+type profileKey struct {
+	pc       int
+	function string
+}
+
+type profileEntry struct {
+	function   string
+	expression b6.Expression
+	calls      atomic.Int64
+	duration   atomic.Int64
+	items      atomic.Int64
+}
+
+// Profile records the time spent in function calls made by the VM, and
+// the size of the collections they return. As the same program counter
+// can be used by more than one VM, a Profile should be used for a single
+// evaluation.
+type Profile struct {
+	lock    sync.Mutex
+	entries map[profileKey]*profileEntry
+}
+
+func NewProfile() *Profile {
+	return &Profile{entries: make(map[profileKey]*profileEntry)}
+}
+
+func (p *Profile) entry(pc int, function string, e b6.Expression) *profileEntry {
+	key := profileKey{pc: pc, function: function}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	entry, ok := p.entries[key]
+	if !ok {
+		entry = &profileEntry{function: function, expression: e}
+		p.entries[key] = entry
+	}
+	return entry
+}
+
+// record accounts a single call, started at the given time, to the
+// profile, returning the result to use in place of the given one, which
+// counts the items read from it if it's a collection.
+func (p *Profile) record(pc int, function string, e b6.Expression, start time.Time, result reflect.Value, context *Context) reflect.Value {
+	entry := p.entry(pc, function, e)
+	entry.calls.Add(1)
+	entry.duration.Add(int64(time.Since(start)))
+	if result.IsValid() && result.CanInterface() {
+		if c, ok := result.Interface().(b6.UntypedCollection); ok {
+			if _, ok := c.(b6.Feature); !ok {
+				if adaptor, ok := context.Adaptors.Collections[result.Type()]; ok {
+					return adaptor(&profiledCollection{c: c, entry: entry})
+				}
+			}
+		}
+	}
+	return result
+}
+
+// Entries returns the entries in the profile, ordered by decreasing
+// duration.
+func (p *Profile) Entries() []ProfileEntry {
+	p.lock.Lock()
+	entries := make([]ProfileEntry, 0, len(p.entries))
+	for _, e := range p.entries {
+		entries = append(entries, ProfileEntry{
+			Function:   e.function,
+			Expression: e.expression,
+			Calls:      int(e.calls.Load()),
+			Duration:   time.Duration(e.duration.Load()),
+			Items:      int(e.items.Load()),
+		})
+	}
+	p.lock.Unlock()
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Duration != entries[j].Duration {
+			return entries[i].Duration > entries[j].Duration
+		}
+		return entries[i].Expression.Begin < entries[j].Expression.Begin
+	})
+	return entries
+}
+
+func (p *Profile) ToProto() (*pb.ProfileProto, error) {
+	entries := p.Entries()
+	profile := &pb.ProfileProto{Entries: make([]*pb.ProfileEntryProto, len(entries))}
+	for i, e := range entries {
+		expression, err := e.Expression.ToProto()
+		if err != nil {
+			return nil, err
+		}
+		profile.Entries[i] = &pb.ProfileEntryProto{
+			Function:    e.Function,
+			Expression:  expression,
+			Calls:       int64(e.Calls),
+			Nanoseconds: e.Duration.Nanoseconds(),
+			Items:       int64(e.Items),
+		}
+	}
+	return profile, nil
+}
+
+type profiledCollection struct {
+	c     b6.UntypedCollection
+	entry *profileEntry
+}
+
+func (p *profiledCollection) BeginUntyped() b6.Iterator[any, any] {
+	return &profiledIterator{Iterator: p.c.BeginUntyped(), entry: p.entry}
+}
+
+func (p *profiledCollection) Count() (int, bool) {
+	return p.c.Count()
+}
+
+type profiledIterator struct {
+	b6.Iterator[any, any]
+	entry *profileEntry
+}
+
+func (p *profiledIterator) Next() (bool, error) {
+	ok, err := p.Iterator.Next()
+	if ok {
+		p.entry.items.Add(1)
+	}
+	return ok, err
+}
+
+// ProfiledResult is the result of an evaluation made with profiling
+// enabled, together with its profile. Costs incurred while iterating over
+// the result are recorded in the profile as they happen.
+type ProfiledResult struct {
+	Value   interface{}
+	Profile *Profile
+}
@@ -0,0 +1,360 @@
+package api
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"diagonal.works/b6"
+)
+
+// TypeError describes part of an expression whose type is incompatible
+// with the way it's used. If the expression was parsed, its Begin and End
+// identify the position of the error in the source.
+type TypeError struct {
+	Expression b6.Expression
+	Message    string
+}
+
+func (t *TypeError) Error() string {
+	return t.Message
+}
+
+// TypeErrors is returned by TypeCheck when an expression has one or more
+// type errors, ordered by their position in the expression.
+type TypeErrors []*TypeError
+
+func (t TypeErrors) Error() string {
+	messages := make([]string, len(t))
+	for i, e := range t {
+		messages[i] = e.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+var anyType = reflect.TypeOf((*interface{})(nil)).Elem()
+var contextPtrType = reflect.TypeOf(&Context{})
+var errorInterface = reflect.TypeOf((*error)(nil)).Elem()
+var identifiableInterface = reflect.TypeOf((*b6.Identifiable)(nil)).Elem()
+var tagType = reflect.TypeOf(b6.Tag{})
+
+// TypeCheck validates an expression before evaluation, using the
+// signatures of the functions it calls, returning TypeErrors if any
+// arguments can't be converted to the types those functions expect.
+// As the VM is dynamically typed, many types are only known at runtime,
+// and the check only reports arguments that will certainly fail. Errors
+// within the bodies of lambdas aren't reported, as the VM only evaluates
+// them if they're called, which, for lazily evaluated collections like
+// those returned by map, may never happen.
+func TypeCheck(e b6.Expression, fs FunctionSymbols, adaptors *Adaptors) error {
+	c := typeChecker{fs: fs, adaptors: adaptors}
+	c.infer(e, nil, nil)
+	if len(c.errors) > 0 {
+		return c.errors
+	}
+	return nil
+}
+
+// InferType returns the static type of the result of an expression, or
+// nil if it can only be determined at runtime.
+func InferType(e b6.Expression, fs FunctionSymbols, adaptors *Adaptors) reflect.Type {
+	c := typeChecker{fs: fs, adaptors: adaptors}
+	return c.infer(e, nil, nil)
+}
+
+type typeBinding struct {
+	symbol string
+	t      reflect.Type
+}
+
+type typeChecker struct {
+	fs       FunctionSymbols
+	adaptors *Adaptors
+	errors   TypeErrors
+	// The number of lambdas whose bodies are being checked.
+	lambdas int
+}
+
+func (c *typeChecker) errorf(e b6.Expression, format string, args ...interface{}) {
+	if c.lambdas == 0 {
+		c.errors = append(c.errors, &TypeError{Expression: e, Message: fmt.Sprintf(format, args...)})
+	}
+}
+
+// infer returns the type of the given expression, or nil if it's unknown.
+// The expected type is used to infer the types of lambda arguments.
+func (c *typeChecker) infer(e b6.Expression, expected reflect.Type, bound []typeBinding) reflect.Type {
+	switch ee := e.AnyExpression.(type) {
+	case b6.SymbolExpression:
+		for i := len(bound) - 1; i >= 0; i-- {
+			if bound[i].symbol == string(ee) {
+				return bound[i].t
+			}
+		}
+		if f, ok := c.fs.Function(ee); ok {
+			return f.Type()
+		}
+		// Undefined symbols are reported even within lambdas, as they
+		// prevent compilation.
+		c.errors = append(c.errors, &TypeError{Expression: e, Message: fmt.Sprintf("undefined symbol %q", ee)})
+	case b6.CallExpression:
+		return c.inferCall(e, ee, bound)
+	case b6.LambdaExpression:
+		return c.inferLambda(ee, expected, bound)
+	case b6.AnyLiteral:
+		if l := ee.Literal(); l != nil {
+			return reflect.TypeOf(l)
+		}
+	}
+	return nil
+}
+
+func (c *typeChecker) inferCall(e b6.Expression, call b6.CallExpression, bound []typeBinding) reflect.Type {
+	f := c.infer(call.Function, nil, bound)
+	if f == nil || f.Kind() != reflect.Func {
+		if f != nil && f != anyType {
+			c.errorf(call.Function, "can't call %s", typeName(f))
+		}
+		for _, arg := range call.Args {
+			c.infer(arg, nil, bound)
+		}
+		return nil
+	}
+	name := functionName(call.Function)
+	params := f.NumIn() - 1
+	expected := params
+	if f.IsVariadic() {
+		expected--
+	} else if len(call.Args) > params {
+		c.errorf(e, "%s: expected %d arguments, found %d", name, params, len(call.Args))
+		return nil
+	}
+	if len(call.Args) < expected {
+		return c.inferPartialCall(call, f, name, bound)
+	}
+	for i, arg := range call.Args {
+		var want reflect.Type
+		if i < expected {
+			want = f.In(i + 1)
+		} else {
+			want = f.In(params).Elem()
+		}
+		c.check(arg, want, name, i, bound)
+	}
+	return f.Out(0)
+}
+
+// inferPartialCall returns the type of a call with fewer arguments than
+// its function expects. The arguments are bound to the function's last
+// parameters, returning a function that expects those remaining.
+func (c *typeChecker) inferPartialCall(call b6.CallExpression, f reflect.Type, name string, bound []typeBinding) reflect.Type {
+	if f.IsVariadic() {
+		// Which parameters the arguments are bound to depends on the
+		// number of arguments eventually passed.
+		for _, arg := range call.Args {
+			c.infer(arg, nil, bound)
+		}
+		return nil
+	}
+	remaining := f.NumIn() - 1 - len(call.Args)
+	for i, arg := range call.Args {
+		c.check(arg, f.In(remaining+i+1), name, remaining+i, bound)
+	}
+	in := []reflect.Type{contextPtrType}
+	for i := 1; i <= remaining; i++ {
+		in = append(in, f.In(i))
+	}
+	out := make([]reflect.Type, f.NumOut())
+	for i := range out {
+		out[i] = f.Out(i)
+	}
+	return reflect.FuncOf(in, out, false)
+}
+
+func (c *typeChecker) check(arg b6.Expression, want reflect.Type, name string, i int, bound []typeBinding) {
+	if have := c.infer(arg, want, bound); !c.canConvert(have, want) {
+		c.errorf(arg, "%s: argument %d: expected %s, found %s", name, i+1, typeName(want), typeName(have))
+	}
+}
+
+func (c *typeChecker) inferLambda(lambda b6.LambdaExpression, expected reflect.Type, bound []typeBinding) reflect.Type {
+	in := []reflect.Type{contextPtrType}
+	bound = bound[0:len(bound):len(bound)]
+	for i, arg := range lambda.Args {
+		t := anyType
+		if expected != nil && expected.Kind() == reflect.Func && expected.NumIn() == len(lambda.Args)+1 {
+			t = expected.In(i + 1)
+		}
+		in = append(in, t)
+		bound = append(bound, typeBinding{symbol: arg, t: t})
+	}
+	c.lambdas++
+	result := c.infer(lambda.Expression, nil, bound)
+	c.lambdas--
+	if result == nil {
+		result = anyType
+	}
+	return reflect.FuncOf(in, []reflect.Type{result, errorInterface}, false)
+}
+
+// canConvert returns false if values of type have can never be converted
+// to type want by ConvertWithContext. Unknown types, and interfaces that
+// may hold a suitable value at runtime, are assumed to be convertible.
+func (c *typeChecker) canConvert(have reflect.Type, want reflect.Type) bool {
+	if have == nil || have == anyType || want == anyType {
+		return true
+	}
+	if want.Kind() == reflect.Func {
+		return c.canConvertFunction(have, want)
+	} else if want.Implements(callableInterface) {
+		return have.Kind() == reflect.Func || have.Implements(queryInterface) || have.Kind() == reflect.Interface
+	} else if want.Implements(untypedCollectionInterface) {
+		return c.canConvertCollection(have, want)
+	}
+	return canConvertValue(have, want)
+}
+
+func (c *typeChecker) canConvertFunction(have reflect.Type, want reflect.Type) bool {
+	if have.Kind() != reflect.Func {
+		if have.Implements(queryInterface) {
+			return want.NumIn() == 2 && want.Out(0).Kind() == reflect.Bool
+		}
+		return have.Kind() == reflect.Interface
+	}
+	if have.NumIn() != want.NumIn() {
+		return false
+	}
+	for i := 1; i < want.NumIn(); i++ {
+		if !c.canConvert(want.In(i), have.In(i)) {
+			return false
+		}
+	}
+	// Function adaptors assert the type of the result, other than for
+	// functions returning bool, which test the result for truthiness.
+	if want.Out(0).Kind() == reflect.Bool {
+		return true
+	}
+	return canAssert(have.Out(0), want.Out(0))
+}
+
+func (c *typeChecker) canConvertCollection(have reflect.Type, want reflect.Type) bool {
+	if have.AssignableTo(want) || have.Kind() == reflect.Interface {
+		return true
+	} else if !have.Implements(untypedCollectionInterface) {
+		return false
+	} else if want == untypedCollectionInterface {
+		return true
+	} else if c.adaptors != nil && c.adaptors.Collections != nil {
+		if _, ok := c.adaptors.Collections[want]; !ok {
+			return false
+		}
+	}
+	// Collection adaptors assert the type of each key and value.
+	haveKey, haveValue := collectionTypes(have)
+	wantKey, wantValue := collectionTypes(want)
+	return canAssert(haveKey, wantKey) && canAssert(haveValue, wantValue)
+}
+
+// collectionTypes returns the types of the keys and values of a collection,
+// or nil if they're unknown.
+func collectionTypes(t reflect.Type) (reflect.Type, reflect.Type) {
+	if t.Kind() == reflect.Interface {
+		return nil, nil
+	}
+	begin, ok := t.MethodByName("Begin")
+	if !ok || begin.Type.NumOut() != 1 {
+		return nil, nil
+	}
+	i := begin.Type.Out(0)
+	key, ok := i.MethodByName("Key")
+	if !ok {
+		return nil, nil
+	}
+	value, ok := i.MethodByName("Value")
+	if !ok {
+		return nil, nil
+	}
+	return key.Type.Out(0), value.Type.Out(0)
+}
+
+// canAssert returns false if a value of type have can never be asserted
+// to have type want.
+func canAssert(have reflect.Type, want reflect.Type) bool {
+	if have == nil || want == nil || have == want {
+		return true
+	} else if want.Kind() == reflect.Interface {
+		return have.Kind() == reflect.Interface || have.Implements(want)
+	} else if have.Kind() == reflect.Interface {
+		return want.Implements(have)
+	}
+	return false
+}
+
+// canConvertValue mirrors the conversions performed by Convert.
+func canConvertValue(have reflect.Type, want reflect.Type) bool {
+	if have == anyType || want == anyType || have.AssignableTo(want) || have.ConvertibleTo(want) {
+		return true
+	}
+	if have.Kind() == reflect.Interface {
+		return want.Kind() == reflect.Interface || want.Implements(have) || isConvertedFromValue(have, want)
+	} else if want.Kind() == reflect.Interface && have.Implements(want) {
+		return true
+	}
+	switch want {
+	case featureIDType, areaIDType, relationIDType, collectionIDType:
+		return have.Implements(identifiableInterface)
+	case numberInterface:
+		switch have.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Float32, reflect.Float64:
+			return true
+		}
+	case expressionInterface:
+		return have.Kind() == reflect.Func
+	case reflect.TypeOf(""), reflect.TypeOf(int(1)), reflect.TypeOf(float64(1.0)):
+		return have == tagType
+	}
+	return false
+}
+
+// The types that Convert converts to strings and numbers, based on their
+// runtime value.
+var convertedFromValue = []reflect.Type{tagType, reflect.TypeOf(b6.IntNumber(0)), reflect.TypeOf(b6.FloatNumber(0))}
+
+// isConvertedFromValue returns true if a value held by an interface of
+// type have may be converted to type want by Convert.
+func isConvertedFromValue(have reflect.Type, want reflect.Type) bool {
+	switch want {
+	case featureIDType, areaIDType, relationIDType, collectionIDType:
+		return true
+	}
+	for _, t := range convertedFromValue {
+		if t.Implements(have) && canConvertValue(t, want) {
+			return true
+		}
+	}
+	return false
+}
+
+func functionName(e b6.Expression) string {
+	if symbol, ok := e.AnyExpression.(b6.SymbolExpression); ok {
+		return string(symbol)
+	} else if _, ok := e.AnyExpression.(b6.LambdaExpression); ok {
+		return "lambda"
+	} else if s, ok := UnparseExpression(e); ok {
+		return s
+	}
+	return "(function)"
+}
+
+func typeName(t reflect.Type) string {
+	if t == nil {
+		return "unknown"
+	} else if t.Kind() == reflect.Func {
+		args := make([]string, 0, t.NumIn())
+		for i := 1; i < t.NumIn(); i++ {
+			args = append(args, t.In(i).String())
+		}
+		return fmt.Sprintf("function(%s) %s", strings.Join(args, ", "), t.Out(0))
+	}
+	return t.String()
+}
@@ -8,17 +8,33 @@ import (
 	"time"
 
 	"diagonal.works/b6"
+	"diagonal.works/b6/graph"
 	"diagonal.works/b6/ingest"
 )
 
 type Options struct {
 	Cores         int
 	FileIOAllowed bool
+	Cache         *Cache
+	Limits        Limits
+	// Routing profiles, selected by name with the profile option of
+	// functions like accessible-all.
+	RoutingProfiles map[string]*graph.Profile
+	// Contraction hierarchies used to speed up shortest path queries with
+	// the weights for which they were built.
+	Hierarchies []*graph.ContractionHierarchy
+	// If set, evaluations by Evaluator and the gRPC service, and the
+	// changes they apply, are recorded.
+	Metrics *EvaluationMetrics
 }
 
 type Context struct {
-	World           b6.World
-	Worlds          ingest.Worlds
+	World   b6.World
+	WorldID b6.FeatureID
+	Worlds  ingest.Worlds
+	// Locks for Worlds, for functions that read worlds other than World,
+	// on which the caller holds a read lock.
+	Locks           *ingest.WorldLocks
 	Cores           int
 	FileIOAllowed   bool
 	Clock           func() time.Time
@@ -26,13 +42,25 @@ type Context struct {
 	FunctionSymbols FunctionSymbols
 	Adaptors        Adaptors
 	Context         context.Context
+	Cache           *Cache
+	Limits          Limits
+	RoutingProfiles map[string]*graph.Profile
+	Hierarchies     []*graph.ContractionHierarchy
+	// If set, the time spent in function calls is recorded in Profile.
+	Profile *Profile
 
-	VM *VM
+	VM    *VM
+	usage *usage
 }
 
 func (c *Context) FillFromOptions(options *Options) {
 	c.Cores = options.Cores
 	c.FileIOAllowed = options.FileIOAllowed
+	c.Cache = options.Cache
+	c.Limits = options.Limits
+	c.RoutingProfiles = options.RoutingProfiles
+	c.Hierarchies = options.Hierarchies
+	c.usage = newUsage(&options.Limits, time.Now())
 }
 
 func (c *Context) Fork(n int) []*Context {
@@ -206,7 +234,7 @@ func (c *compilation) Append(i Instruction) {
 }
 
 func Evaluate(expression b6.Expression, context *Context) (interface{}, error) {
-	vm, err := newVM(expression, context.FunctionSymbols)
+	vm, err := newCheckedVM(expression, context)
 	if err != nil {
 		return nil, err
 	}
@@ -214,7 +242,7 @@ func Evaluate(expression b6.Expression, context *Context) (interface{}, error) {
 }
 
 func EvaluateAndFill(expression b6.Expression, context *Context, toFill interface{}) error {
-	vm, err := newVM(expression, context.FunctionSymbols)
+	vm, err := newCheckedVM(expression, context)
 	if err != nil {
 		return err
 	}
@@ -240,13 +268,22 @@ func EvaluateString(e string, context *Context) (interface{}, error) {
 		return nil, err
 	}
 	expression = Simplify(expression, context.FunctionSymbols)
-	vm, err := newVM(expression, context.FunctionSymbols)
+	vm, err := newCheckedVM(expression, context)
 	if err != nil {
 		return nil, err
 	}
 	return vm.Execute(context)
 }
 
+// newCheckedVM type checks an expression before compiling it, to report
+// errors before evaluation begins.
+func newCheckedVM(expression b6.Expression, context *Context) (*VM, error) {
+	if err := TypeCheck(expression, context.FunctionSymbols, &context.Adaptors); err != nil {
+		return nil, err
+	}
+	return newVM(expression, context.FunctionSymbols)
+}
+
 func newVM(expression b6.Expression, fs FunctionSymbols) (*VM, error) {
 	c := compilation{
 		Globals: fs,
@@ -357,6 +394,10 @@ func compileLiteral(e b6.Expression, c *compilation) error {
 
 const MaxArgs = 32
 
+// The number of instructions executed between checks for cancellation,
+// or exceeded limits.
+const checkInterval = 1024
+
 type StackFrame struct {
 	Value      reflect.Value
 	Expression b6.Expression
@@ -440,7 +481,12 @@ func (v *VM) execute(context *Context) error {
 	var err error
 	args := make([]reflect.Value, 0)
 	done := false
-	for !done {
+	for executed := 1; !done; executed++ {
+		if executed%checkInterval == 0 {
+			if err := context.Check(); err != nil {
+				return err
+			}
+		}
 		switch v.Instructions[v.PC].Op {
 		case OpJump:
 			v.PC = int(v.Instructions[v.PC].Args[ArgsJumpDestination]) - 1 // Incremented below
@@ -613,6 +659,31 @@ func (g goCall) CallFromStack(context *Context, n int, scratch []reflect.Value)
 	argsStart := len(vm.Stack) - n - 1
 	expression := vm.Stack[len(vm.Stack)-1].Expression
 	if n >= expected {
+		var start time.Time
+		if context.Profile != nil {
+			start = time.Now()
+		}
+		var key CacheKey
+		cacheable := false
+		if context.Cache != nil {
+			if symbol, ok := g.expression.AnyExpression.(b6.SymbolExpression); ok && context.Cache.IsCacheable(symbol) {
+				args := make([]b6.Expression, n)
+				for i := range args {
+					args[i] = vm.Stack[argsStart+i].Expression
+				}
+				key, cacheable = NewCacheKey(b6.NewCallExpression(g.expression, args), context)
+			}
+		}
+		if cacheable {
+			if v, ok := context.Cache.Get(key); ok {
+				if context.Profile != nil {
+					v = context.Profile.record(vm.PC, g.name(), expression, start, v, context)
+				}
+				vm.Stack = vm.Stack[0:argsStart]
+				vm.Stack = append(vm.Stack, StackFrame{Value: v, Expression: expression})
+				return scratch, nil
+			}
+		}
 		scratch = append(scratch, reflect.ValueOf(context))
 		for i := 0; i < expected; i++ {
 			arg := argsStart + i
@@ -641,18 +712,31 @@ func (g goCall) CallFromStack(context *Context, n int, scratch []reflect.Value)
 		}
 		if len(result) > 1 {
 			if err, ok := result[1].Interface().(error); ok && err != nil {
+				if context.Profile != nil {
+					context.Profile.record(vm.PC, g.name(), expression, start, reflect.Value{}, context)
+				}
 				vm.Stack = append(vm.Stack, StackFrame{Value: reflect.Value{}, Expression: expression})
 				return nil, err
 			}
 		}
 		if result[0].Kind() == reflect.Func {
+			if context.Profile != nil {
+				context.Profile.record(vm.PC, g.name(), expression, start, reflect.Value{}, context)
+			}
 			vm.Stack = append(vm.Stack, StackFrame{
 				Value:      reflect.ValueOf(&goCall{f: result[0], expression: expression}),
 				Expression: expression,
 			})
 		} else {
+			value := result[0]
+			if cacheable {
+				value = context.Cache.Add(key, value, context)
+			}
+			if context.Profile != nil {
+				value = context.Profile.record(vm.PC, g.name(), expression, start, value, context)
+			}
 			vm.Stack = append(vm.Stack, StackFrame{
-				Value:      result[0],
+				Value:      value,
 				Expression: expression,
 			})
 		}
@@ -671,6 +755,16 @@ func (g goCall) CallFromStack(context *Context, n int, scratch []reflect.Value)
 	return scratch, nil
 }
 
+// name returns the name of the function for use in profiles.
+func (g goCall) name() string {
+	if symbol, ok := g.expression.AnyExpression.(b6.SymbolExpression); ok {
+		return string(symbol)
+	} else if s, ok := UnparseExpression(g.expression); ok {
+		return s
+	}
+	return "(function)"
+}
+
 func (g goCall) ToFunctionValue(t reflect.Type, context *Context) reflect.Value {
 	// If the underlying function matches the Go type we need, we can
 	// return the function itself, otherwise, we need to call it via
@@ -711,10 +805,18 @@ func (l *lambdaCall) CallFromStack(context *Context, n int, scratch []reflect.Va
 	argsStart := len(vm.Stack) - n - 1
 	expression := vm.Stack[len(vm.Stack)-1].Expression
 	if n == l.args {
+		var start time.Time
+		if context.Profile != nil {
+			start = time.Now()
+		}
 		opc := vm.PC
 		vm.PC = l.pc
 		err = vm.execute(context)
 		vm.PC = opc
+		if context.Profile != nil && err == nil {
+			top := &vm.Stack[len(vm.Stack)-1]
+			top.Value = context.Profile.record(l.pc, "lambda", l.expression, start, top.Value, context)
+		}
 	} else if n < l.args {
 		p := &partialCall{c: l, e: expression, vmArgs: vm.Args}
 		p.n = n
@@ -0,0 +1,199 @@
+// Package auth authenticates requests to the b6 gRPC and HTTP servers
+// using bearer tokens, and restricts what they're allowed to do.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/ingest"
+)
+
+var (
+	ErrUnauthenticated  = errors.New("missing or invalid access token")
+	ErrPermissionDenied = errors.New("permission denied")
+)
+
+// Permissions granted to the bearer of an access token.
+type Permissions struct {
+	// Identifies the bearer of the token in logs, either the name given in
+	// a token file, or the subject of a JWT.
+	Subject string
+	// If true, no worlds can be modified or deleted.
+	ReadOnly bool
+	// The worlds that can be modified or deleted, unless ReadOnly is set.
+	// If empty, every world can be.
+	Worlds []b6.FeatureID
+	// Whether functions that read or write files on the server, like
+	// import-geojson-file, can be called. They also need to be enabled on
+	// the server itself.
+	FileIO bool
+}
+
+// Unrestricted is used when authentication is disabled.
+var Unrestricted = &Permissions{FileIO: true}
+
+// CanModify returns true if the world with the given ID can be modified or
+// deleted.
+func (p *Permissions) CanModify(world b6.FeatureID) bool {
+	if p.ReadOnly {
+		return false
+	}
+	if len(p.Worlds) == 0 {
+		return true
+	}
+	for _, id := range p.Worlds {
+		if id == world {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckModify returns ErrPermissionDenied if the world with the given ID
+// can't be modified or deleted.
+func (p *Permissions) CheckModify(world b6.FeatureID) error {
+	if !p.CanModify(world) {
+		return fmt.Errorf("%w: can't modify world %s", ErrPermissionDenied, world)
+	}
+	return nil
+}
+
+// RestrictWorlds returns Worlds that prevent creation, modification or
+// deletion of worlds not allowed by these permissions.
+func (p *Permissions) RestrictWorlds(worlds ingest.Worlds) ingest.Worlds {
+	if !p.ReadOnly && len(p.Worlds) == 0 {
+		return worlds
+	}
+	return &restrictedWorlds{worlds: worlds, permissions: p}
+}
+
+type restrictedWorlds struct {
+	worlds      ingest.Worlds
+	permissions *Permissions
+}
+
+// FindOrCreateWorld only creates worlds that can be modified, since
+// there's no reason to create a world that can only be read.
+func (r *restrictedWorlds) FindOrCreateWorld(id b6.FeatureID) ingest.MutableWorld {
+	if r.permissions.CanModify(id) {
+		return r.worlds.FindOrCreateWorld(id)
+	}
+	return r.FindWorld(id)
+}
+
+func (r *restrictedWorlds) FindWorld(id b6.FeatureID) ingest.MutableWorld {
+	w := r.worlds.FindWorld(id)
+	if r.permissions.CanModify(id) {
+		return w
+	}
+	return readOnlyWorld{MutableWorld: w, id: id}
+}
+
+func (r *restrictedWorlds) ListWorlds() []b6.FeatureID {
+	return r.worlds.ListWorlds()
+}
+
+func (r *restrictedWorlds) DeleteWorld(id b6.FeatureID) error {
+	if err := r.permissions.CheckModify(id); err != nil {
+		return err
+	}
+	return r.worlds.DeleteWorld(id)
+}
+
+// CloneWorld only needs permission to modify the new world, since the
+// original is only read.
+func (r *restrictedWorlds) CloneWorld(from b6.FeatureID, to b6.FeatureID, readOnly bool) error {
+	if err := r.permissions.CheckModify(to); err != nil {
+		return err
+	}
+	return r.worlds.CloneWorld(from, to, readOnly)
+}
+
+func (r *restrictedWorlds) FreezeWorld(id b6.FeatureID) error {
+	if err := r.permissions.CheckModify(id); err != nil {
+		return err
+	}
+	return r.worlds.FreezeWorld(id)
+}
+
+func (r *restrictedWorlds) RenameWorld(from b6.FeatureID, to b6.FeatureID) error {
+	if err := r.permissions.CheckModify(from); err != nil {
+		return err
+	} else if err := r.permissions.CheckModify(to); err != nil {
+		return err
+	}
+	return r.worlds.RenameWorld(from, to)
+}
+
+func (r *restrictedWorlds) WorldMetadata(id b6.FeatureID) (ingest.WorldMetadata, error) {
+	return r.worlds.WorldMetadata(id)
+}
+
+func (r *restrictedWorlds) SetWorldMetadata(id b6.FeatureID, metadata ingest.WorldMetadata) error {
+	if err := r.permissions.CheckModify(id); err != nil {
+		return err
+	}
+	return r.worlds.SetWorldMetadata(id, metadata)
+}
+
+// readOnlyWorld prevents modification of a world, while, unlike
+// ingest.ReadOnlyWorld, continuing to report the version and
+// modifications of the underlying world, so results cached for it remain
+// valid.
+type readOnlyWorld struct {
+	ingest.MutableWorld
+	id b6.FeatureID
+}
+
+func (r readOnlyWorld) AddFeature(f ingest.Feature) error {
+	return fmt.Errorf("%w: can't modify world %s", ErrPermissionDenied, r.id)
+}
+
+func (r readOnlyWorld) AddTag(id b6.FeatureID, tag b6.Tag) error {
+	return fmt.Errorf("%w: can't modify world %s", ErrPermissionDenied, r.id)
+}
+
+func (r readOnlyWorld) RemoveTag(id b6.FeatureID, key string) error {
+	return fmt.Errorf("%w: can't modify world %s", ErrPermissionDenied, r.id)
+}
+
+// Authenticator returns the permissions granted to the bearer of an access
+// token, or an error wrapping ErrUnauthenticated if the token isn't
+// valid.
+type Authenticator interface {
+	Authenticate(token string) (*Permissions, error)
+}
+
+// Authenticators tries each Authenticator in turn, returning the
+// permissions from the first to accept the token.
+type Authenticators []Authenticator
+
+func (as Authenticators) Authenticate(token string) (*Permissions, error) {
+	err := ErrUnauthenticated
+	for _, a := range as {
+		var p *Permissions
+		if p, err = a.Authenticate(token); err == nil {
+			return p, nil
+		}
+	}
+	return nil, err
+}
+
+type permissionsKey struct{}
+
+// NewContext returns a context carrying the given permissions.
+func NewContext(ctx context.Context, p *Permissions) context.Context {
+	return context.WithValue(ctx, permissionsKey{}, p)
+}
+
+// FromContext returns the permissions carried by the given context,
+// or Unrestricted if there are none, as authentication is disabled.
+func FromContext(ctx context.Context) *Permissions {
+	if p, ok := ctx.Value(permissionsKey{}).(*Permissions); ok {
+		return p
+	}
+	return Unrestricted
+}
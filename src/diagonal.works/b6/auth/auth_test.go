@@ -0,0 +1,202 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/ingest"
+	"github.com/golang/geo/s2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	world1 = b6.FeatureID{Type: b6.FeatureTypeCollection, Namespace: ingest.DefaultWorldFeatureID.Namespace, Value: 1}
+	world2 = b6.FeatureID{Type: b6.FeatureTypeCollection, Namespace: ingest.DefaultWorldFeatureID.Namespace, Value: 2}
+)
+
+const tokensYAML = `
+tokens:
+  - name: analyst
+    token: analyst-token
+    read-only: true
+  - name: pipeline
+    token: pipeline-token
+    worlds: [/collection/diagonal.works/world/1]
+    file-io: true
+`
+
+func TestReadTokens(t *testing.T) {
+	tokens, err := ReadTokens(strings.NewReader(tokensYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := tokens.Authenticate("analyst-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Subject != "analyst" || p.CanModify(world1) || p.FileIO {
+		t.Errorf("Expected read-only permissions for analyst, found %+v", p)
+	}
+
+	p, err = tokens.Authenticate("pipeline-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.CanModify(world1) || p.CanModify(world2) || !p.FileIO {
+		t.Errorf("Expected pipeline to modify only world 1, found %+v", p)
+	}
+
+	if _, err := tokens.Authenticate("pipeline"); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("Expected ErrUnauthenticated for an unknown token, found %v", err)
+	}
+}
+
+func TestReadTokensRejectsInvalidFiles(t *testing.T) {
+	invalid := []string{
+		"tokens:\n  - name: missing\n",
+		"tokens:\n  - {name: a, token: t}\n  - {name: b, token: t}\n",
+		"tokens:\n  - {name: a, token: t, worlds: [nonsense]}\n",
+		"tokens:\n  - {name: a, token: t, writable: true}\n",
+	}
+	for _, yaml := range invalid {
+		if _, err := ReadTokens(strings.NewReader(yaml)); err == nil {
+			t.Errorf("Expected an error for %q, found none", yaml)
+		}
+	}
+}
+
+func TestRestrictWorlds(t *testing.T) {
+	worlds := &ingest.MutableWorlds{Base: ingest.NewBasicMutableWorld()}
+	worlds.FindOrCreateWorld(world2)
+	p := &Permissions{Worlds: []b6.FeatureID{world1}}
+	restricted := p.RestrictWorlds(worlds)
+
+	point := &ingest.GenericFeature{
+		ID:   ingest.FromOSMNodeID(42).FeatureID(),
+		Tags: []b6.Tag{{Key: b6.PointTag, Value: b6.NewPointExpressionFromLatLng(s2.LatLngFromDegrees(51.5354, -0.1243))}},
+	}
+	if err := restricted.FindOrCreateWorld(world1).AddFeature(point); err != nil {
+		t.Errorf("Expected no error modifying world 1, found %s", err)
+	}
+	if err := restricted.FindOrCreateWorld(world2).AddFeature(point); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied modifying world 2, found %v", err)
+	}
+	if err := restricted.DeleteWorld(world2); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied deleting world 2, found %v", err)
+	}
+	if len(worlds.ListWorlds()) != 2 {
+		t.Errorf("Expected world 2 to remain, found %v", worlds.ListWorlds())
+	}
+}
+
+func TestReadOnlyPermissionsDontCreateWorlds(t *testing.T) {
+	base := ingest.NewBasicMutableWorld()
+	point := &ingest.GenericFeature{
+		ID:   ingest.FromOSMNodeID(42).FeatureID(),
+		Tags: []b6.Tag{{Key: b6.PointTag, Value: b6.NewPointExpressionFromLatLng(s2.LatLngFromDegrees(51.5354, -0.1243))}},
+	}
+	if err := base.AddFeature(point); err != nil {
+		t.Fatal(err)
+	}
+	worlds := &ingest.MutableWorlds{Base: base}
+	restricted := (&Permissions{ReadOnly: true}).RestrictWorlds(worlds)
+
+	w := restricted.FindOrCreateWorld(world1)
+	if w.FindFeatureByID(point.FeatureID()) == nil {
+		t.Error("Expected a missing world to contain the features of the base world")
+	}
+	if err := w.AddFeature(point); !errors.Is(err, ErrPermissionDenied) {
+		t.Errorf("Expected ErrPermissionDenied modifying a missing world, found %v", err)
+	}
+	if _, err := worlds.WorldMetadata(world1); !errors.Is(err, ingest.ErrWorldNotFound) {
+		t.Errorf("Expected world not to be created, found %v", err)
+	}
+}
+
+func TestGRPCInterceptorRequiresToken(t *testing.T) {
+	tokens := StaticTokens{"secret": &Permissions{Subject: "test", ReadOnly: true}}
+	interceptor := UnaryServerInterceptor(tokens)
+	var found *Permissions
+	handler := func(ctx context.Context, request interface{}) (interface{}, error) {
+		found = FromContext(ctx)
+		return nil, nil
+	}
+
+	tests := []struct {
+		name string
+		md   metadata.MD
+		ok   bool
+	}{
+		{"Valid", metadata.Pairs("authorization", "Bearer secret"), true},
+		{"Invalid", metadata.Pairs("authorization", "Bearer guess"), false},
+		{"Missing", metadata.MD{}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			found = nil
+			ctx := metadata.NewIncomingContext(context.Background(), test.md)
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+			if test.ok {
+				if err != nil || found == nil || found.Subject != "test" {
+					t.Errorf("Expected permissions for test, found %v, %v", found, err)
+				}
+			} else if status.Code(err) != codes.Unauthenticated || found != nil {
+				t.Errorf("Expected Unauthenticated, found %v", err)
+			}
+		})
+	}
+}
+
+func TestHTTPHandlerRequiresToken(t *testing.T) {
+	tokens := StaticTokens{"secret": &Permissions{Subject: "test"}}
+	handler := Handler(tokens, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(FromContext(r.Context()).Subject))
+	}))
+
+	serve := func(r *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	r := httptest.NewRequest("GET", "/stack", nil)
+	if w := serve(r); w.Code != http.StatusUnauthorized || w.Header().Get("WWW-Authenticate") == "" {
+		t.Errorf("Expected 401 without a token, found %d", w.Code)
+	}
+
+	r = httptest.NewRequest("GET", "/stack", nil)
+	r.Header.Set("Authorization", "Bearer secret")
+	if w := serve(r); w.Code != http.StatusOK || w.Body.String() != "test" {
+		t.Errorf("Expected 200 with a bearer token, found %d %q", w.Code, w.Body.String())
+	}
+
+	r = httptest.NewRequest("GET", "/?token=secret&e=1", nil)
+	w := serve(r)
+	if w.Code != http.StatusSeeOther || w.Header().Get("Location") != "/?e=1" {
+		t.Fatalf("Expected a redirect removing the token, found %d to %q", w.Code, w.Header().Get("Location"))
+	}
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != TokenCookie || !cookies[0].HttpOnly {
+		t.Fatalf("Expected an HttpOnly token cookie, found %v", cookies)
+	}
+
+	r = httptest.NewRequest("GET", "/stack", nil)
+	r.AddCookie(cookies[0])
+	if w := serve(r); w.Code != http.StatusOK {
+		t.Errorf("Expected 200 with a token cookie, found %d", w.Code)
+	}
+
+	r = httptest.NewRequest("GET", "/?token=guess", nil)
+	if w := serve(r); w.Code != http.StatusUnauthorized || len(w.Result().Cookies()) != 0 {
+		t.Errorf("Expected 401 with an invalid token, found %d", w.Code)
+	}
+}
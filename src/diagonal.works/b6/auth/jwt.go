@@ -0,0 +1,346 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"diagonal.works/b6"
+)
+
+// The allowance for differences between the clocks of the server and the
+// issuer of tokens when checking expiry.
+const JWTClockSkew = time.Minute
+
+// JWTAuthenticator authenticates requests bearing JSON Web Tokens
+// (RFC 7519), signed with one of a set of public keys, typically read from
+// a JSON Web Key Set with ReadJWKSFile. Tokens must have an expiry time.
+// Permissions are taken from an optional b6 claim, for example:
+//
+//	"b6": {"read-only": false, "worlds": ["/collection/diagonal.works/scenario/1"], "file-io": true}
+//
+// with fields as described in ReadTokens. Tokens without a b6 claim are
+// read-only, and can't use file IO.
+type JWTAuthenticator struct {
+	// Public keys, by key ID. Tokens without a key ID are accepted if
+	// there's only one key.
+	Keys map[string]crypto.PublicKey
+	// If set, tokens must have been issued by this issuer.
+	Issuer string
+	// If set, tokens must have been issued for this audience.
+	Audience string
+	// Defaults to time.Now.
+	Clock func() time.Time
+}
+
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*a = audience{s}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(a))
+}
+
+type b6ClaimJSON struct {
+	ReadOnly bool     `json:"read-only"`
+	Worlds   []string `json:"worlds"`
+	FileIO   bool     `json:"file-io"`
+}
+
+type jwtClaims struct {
+	Issuer    string       `json:"iss"`
+	Subject   string       `json:"sub"`
+	Audience  audience     `json:"aud"`
+	Expires   *float64     `json:"exp"`
+	NotBefore *float64     `json:"nbf"`
+	B6        *b6ClaimJSON `json:"b6"`
+}
+
+func unauthenticated(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrUnauthenticated, fmt.Sprintf(format, args...))
+}
+
+func (j *JWTAuthenticator) Authenticate(token string) (*Permissions, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, unauthenticated("malformed JWT")
+	}
+	var header jwtHeader
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return nil, unauthenticated("bad JWT header: %s", err)
+	}
+	key, ok := j.Keys[header.KeyID]
+	if !ok && header.KeyID == "" && len(j.Keys) == 1 {
+		for _, k := range j.Keys {
+			key, ok = k, true
+		}
+	}
+	if !ok {
+		return nil, unauthenticated("unknown key ID %q", header.KeyID)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, unauthenticated("bad JWT signature encoding")
+	}
+	if err := verifyJWTSignature(header.Algorithm, key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, unauthenticated("%s", err)
+	}
+
+	var claims jwtClaims
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return nil, unauthenticated("bad JWT claims: %s", err)
+	}
+	now := time.Now()
+	if j.Clock != nil {
+		now = j.Clock()
+	}
+	if claims.Expires == nil {
+		return nil, unauthenticated("JWT has no expiry")
+	} else if now.Add(-JWTClockSkew).After(fromNumericDate(*claims.Expires)) {
+		return nil, unauthenticated("JWT expired")
+	}
+	if claims.NotBefore != nil && now.Add(JWTClockSkew).Before(fromNumericDate(*claims.NotBefore)) {
+		return nil, unauthenticated("JWT not yet valid")
+	}
+	if j.Issuer != "" && claims.Issuer != j.Issuer {
+		return nil, unauthenticated("unexpected JWT issuer %q", claims.Issuer)
+	}
+	if j.Audience != "" {
+		found := false
+		for _, a := range claims.Audience {
+			if a == j.Audience {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, unauthenticated("JWT not issued for audience %q", j.Audience)
+		}
+	}
+
+	p := &Permissions{Subject: claims.Subject, ReadOnly: true}
+	if claims.B6 != nil {
+		p.ReadOnly = claims.B6.ReadOnly
+		p.FileIO = claims.B6.FileIO
+		for _, w := range claims.B6.Worlds {
+			id := b6.FeatureIDFromString(w)
+			if !id.IsValid() {
+				return nil, unauthenticated("invalid world ID %q in JWT", w)
+			}
+			p.Worlds = append(p.Worlds, id)
+		}
+	}
+	return p, nil
+}
+
+func decodeJWTPart(part string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func fromNumericDate(seconds float64) time.Time {
+	return time.Unix(0, int64(seconds*float64(time.Second)))
+}
+
+var jwtCurves = map[string]elliptic.Curve{
+	"ES256": elliptic.P256(),
+	"ES384": elliptic.P384(),
+	"ES512": elliptic.P521(),
+}
+
+func verifyJWTSignature(algorithm string, key crypto.PublicKey, signed string, signature []byte) error {
+	var hash crypto.Hash
+	switch algorithm {
+	case "RS256", "PS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "PS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512", "PS512", "ES512":
+		hash = crypto.SHA512
+	case "EdDSA":
+		if k, ok := key.(ed25519.PublicKey); ok {
+			if ed25519.Verify(k, []byte(signed), signature) {
+				return nil
+			}
+			return fmt.Errorf("invalid JWT signature")
+		}
+		return fmt.Errorf("key doesn't match JWT algorithm %s", algorithm)
+	default:
+		// Includes none, and symmetric algorithms like HS256, which
+		// we deliberately don't support.
+		return fmt.Errorf("unsupported JWT algorithm %q", algorithm)
+	}
+	h := hash.New()
+	h.Write([]byte(signed))
+	digest := h.Sum(nil)
+
+	var err error
+	switch algorithm[0] {
+	case 'R':
+		if k, ok := key.(*rsa.PublicKey); ok {
+			err = rsa.VerifyPKCS1v15(k, hash, digest, signature)
+		} else {
+			return fmt.Errorf("key doesn't match JWT algorithm %s", algorithm)
+		}
+	case 'P':
+		if k, ok := key.(*rsa.PublicKey); ok {
+			err = rsa.VerifyPSS(k, hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		} else {
+			return fmt.Errorf("key doesn't match JWT algorithm %s", algorithm)
+		}
+	case 'E':
+		k, ok := key.(*ecdsa.PublicKey)
+		if !ok || k.Curve != jwtCurves[algorithm] {
+			return fmt.Errorf("key doesn't match JWT algorithm %s", algorithm)
+		}
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return fmt.Errorf("invalid JWT signature")
+		}
+		r, s := new(big.Int).SetBytes(signature[:size]), new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(k, digest, r, s) {
+			return fmt.Errorf("invalid JWT signature")
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("invalid JWT signature")
+	}
+	return nil
+}
+
+type jwkJSON struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Use     string `json:"use"`
+	Curve   string `json:"crv"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+}
+
+type jwksJSON struct {
+	Keys []jwkJSON `json:"keys"`
+}
+
+// ReadJWKS returns the public keys from a JSON Web Key Set (RFC 7517), by
+// key ID. RSA, EC (P-256, P-384 and P-521) and Ed25519 keys are
+// supported. Keys for uses other than signatures are ignored.
+func ReadJWKS(r io.Reader) (map[string]crypto.PublicKey, error) {
+	var jwks jwksJSON
+	if err := json.NewDecoder(r).Decode(&jwks); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]crypto.PublicKey, len(jwks.Keys))
+	for i, k := range jwks.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("key %d (%q): %w", i, k.KeyID, err)
+		}
+		keys[k.KeyID] = key
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no signing keys")
+	}
+	return keys, nil
+}
+
+// ReadJWKSFile returns the public keys from the JSON Web Key Set in the
+// given file.
+func ReadJWKSFile(filename string) (map[string]crypto.PublicKey, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	keys, err := ReadJWKS(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return keys, nil
+}
+
+func (k *jwkJSON) publicKey() (crypto.PublicKey, error) {
+	decode := func(s string) (*big.Int, error) {
+		b, err := base64.RawURLEncoding.DecodeString(s)
+		if err != nil || len(b) == 0 {
+			return nil, fmt.Errorf("bad key parameter")
+		}
+		return new(big.Int).SetBytes(b), nil
+	}
+	switch k.KeyType {
+	case "RSA":
+		n, err := decode(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decode(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("bad RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Curve)
+		}
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decode(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("point isn't on curve %s", k.Curve)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Curve != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Curve)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("bad Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.KeyType)
+}
@@ -0,0 +1,183 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func encodeJWTPart(t *testing.T, v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func signJWT(t *testing.T, algorithm string, kid string, key crypto.Signer, claims map[string]interface{}) string {
+	signed := encodeJWTPart(t, map[string]string{"alg": algorithm, "kid": kid, "typ": "JWT"}) + "." + encodeJWTPart(t, claims)
+	var signature []byte
+	var err error
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(k, []byte(signed))
+	case *rsa.PrivateKey:
+		digest := sha256.Sum256([]byte(signed))
+		signature, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+	case *ecdsa.PrivateKey:
+		digest := sha256.Sum256([]byte(signed))
+		var r, s *big.Int
+		if r, s, err = ecdsa.Sign(rand.Reader, k, digest[:]); err == nil {
+			signature = make([]byte, 64)
+			r.FillBytes(signature[:32])
+			s.FillBytes(signature[32:])
+		}
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPublic, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwks := fmt.Sprintf(`{"keys": [
+		{"kty": "RSA", "kid": "rsa", "use": "sig", "n": %q, "e": %q},
+		{"kty": "EC", "kid": "ec", "crv": "P-256", "x": %q, "y": %q},
+		{"kty": "OKP", "kid": "ed", "crv": "Ed25519", "x": %q},
+		{"kty": "RSA", "kid": "encryption", "use": "enc", "n": "AQAB", "e": "AQAB"}
+	]}`,
+		b64(rsaKey.N.Bytes()), b64([]byte{1, 0, 1}),
+		b64(ecKey.X.FillBytes(make([]byte, 32))), b64(ecKey.Y.FillBytes(make([]byte, 32))),
+		b64(edPublic))
+	keys, err := ReadJWKS(strings.NewReader(jwks))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 3 {
+		t.Fatalf("Expected 3 signing keys, found %d", len(keys))
+	}
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	a := &JWTAuthenticator{
+		Keys:     keys,
+		Issuer:   "https://auth.diagonal.works",
+		Audience: "b6",
+		Clock:    func() time.Time { return now },
+	}
+	valid := func() map[string]interface{} {
+		return map[string]interface{}{
+			"iss": "https://auth.diagonal.works",
+			"aud": []string{"b6", "other"},
+			"sub": "pipeline",
+			"exp": now.Add(time.Hour).Unix(),
+			"b6":  map[string]interface{}{"worlds": []string{world1.String()}, "file-io": true},
+		}
+	}
+
+	for _, signer := range []struct {
+		algorithm string
+		kid       string
+		key       crypto.Signer
+	}{{"RS256", "rsa", rsaKey}, {"ES256", "ec", ecKey}, {"EdDSA", "ed", edKey}} {
+		p, err := a.Authenticate(signJWT(t, signer.algorithm, signer.kid, signer.key, valid()))
+		if err != nil {
+			t.Errorf("Expected no error for %s, found %s", signer.algorithm, err)
+		} else if p.Subject != "pipeline" || !p.CanModify(world1) || p.CanModify(world2) || !p.FileIO {
+			t.Errorf("Expected permissions from b6 claim for %s, found %+v", signer.algorithm, p)
+		}
+	}
+
+	claims := valid()
+	delete(claims, "b6")
+	if p, err := a.Authenticate(signJWT(t, "EdDSA", "ed", edKey, claims)); err != nil {
+		t.Error(err)
+	} else if p.CanModify(world1) || p.FileIO {
+		t.Errorf("Expected read-only permissions without a b6 claim, found %+v", p)
+	}
+
+	invalid := []struct {
+		name  string
+		token func() string
+	}{
+		{"Expired", func() string {
+			c := valid()
+			c["exp"] = now.Add(-time.Hour).Unix()
+			return signJWT(t, "EdDSA", "ed", edKey, c)
+		}},
+		{"NoExpiry", func() string {
+			c := valid()
+			delete(c, "exp")
+			return signJWT(t, "EdDSA", "ed", edKey, c)
+		}},
+		{"NotYetValid", func() string {
+			c := valid()
+			c["nbf"] = now.Add(time.Hour).Unix()
+			return signJWT(t, "EdDSA", "ed", edKey, c)
+		}},
+		{"WrongIssuer", func() string {
+			c := valid()
+			c["iss"] = "https://example.com"
+			return signJWT(t, "EdDSA", "ed", edKey, c)
+		}},
+		{"WrongAudience", func() string {
+			c := valid()
+			c["aud"] = "other"
+			return signJWT(t, "EdDSA", "ed", edKey, c)
+		}},
+		{"UnknownKey", func() string {
+			return signJWT(t, "EdDSA", "missing", edKey, valid())
+		}},
+		{"MismatchedAlgorithm", func() string {
+			return signJWT(t, "RS256", "ed", edKey, valid())
+		}},
+		{"TamperedClaims", func() string {
+			token := signJWT(t, "ES256", "ec", ecKey, valid())
+			parts := strings.Split(token, ".")
+			c := valid()
+			c["sub"] = "admin"
+			return parts[0] + "." + encodeJWTPart(t, c) + "." + parts[2]
+		}},
+		{"None", func() string {
+			return encodeJWTPart(t, map[string]string{"alg": "none", "kid": "ed"}) + "." + encodeJWTPart(t, valid()) + "."
+		}},
+		{"Malformed", func() string {
+			return "not-a-jwt"
+		}},
+	}
+	for _, test := range invalid {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := a.Authenticate(test.token()); !errors.Is(err, ErrUnauthenticated) {
+				t.Errorf("Expected ErrUnauthenticated, found %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// The cookie used by browsers to send access tokens over HTTP.
+const TokenCookie = "b6-token"
+
+// The URL query parameter from which browsers can set TokenCookie, in the
+// style of Jupyter, for example http://localhost:8001/?token=8f2b...
+const TokenParameter = "token"
+
+func bearerToken(header string) (string, bool) {
+	if scheme, token, ok := strings.Cut(header, " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token), true
+	}
+	return "", false
+}
+
+func authenticateGRPC(ctx context.Context, a Authenticator) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, header := range md.Get("authorization") {
+		if token, ok := bearerToken(header); ok {
+			p, err := a.Authenticate(token)
+			if err != nil {
+				return nil, status.Error(codes.Unauthenticated, err.Error())
+			}
+			return NewContext(ctx, p), nil
+		}
+	}
+	return nil, status.Error(codes.Unauthenticated, ErrUnauthenticated.Error())
+}
+
+// UnaryServerInterceptor rejects gRPC calls without a valid bearer token,
+// as sent by the Python client's connect(), and passes the permissions it
+// grants to the service via the context.
+func UnaryServerInterceptor(a Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticateGRPC(ctx, a)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, request)
+	}
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (a *authenticatedStream) Context() context.Context {
+	return a.ctx
+}
+
+// StreamServerInterceptor is the equivalent of UnaryServerInterceptor for
+// streaming calls.
+func StreamServerInterceptor(a Authenticator) grpc.StreamServerInterceptor {
+	return func(server interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticateGRPC(stream.Context(), a)
+		if err != nil {
+			return err
+		}
+		return handler(server, &authenticatedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// Handler rejects HTTP requests without a valid access token, and passes
+// the permissions it grants to the next handler via the request's
+// context. Tokens are read from a bearer Authorization header, or
+// TokenCookie. GET requests with a valid token in TokenParameter set the
+// cookie, and are redirected to remove the token from the URL.
+func Handler(a Authenticator, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := bearerToken(r.Header.Get("Authorization"))
+		if !ok {
+			if cookie, err := r.Cookie(TokenCookie); err == nil {
+				token, ok = cookie.Value, true
+			}
+		}
+		fromURL := false
+		if q := r.URL.Query(); !ok && r.Method == http.MethodGet && q.Has(TokenParameter) {
+			token, ok, fromURL = q.Get(TokenParameter), true, true
+		}
+		var p *Permissions
+		var err error
+		if ok {
+			p, err = a.Authenticate(token)
+		}
+		if !ok || err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="b6"`)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		if fromURL {
+			http.SetCookie(w, &http.Cookie{
+				Name:     TokenCookie,
+				Value:    token,
+				Path:     "/",
+				HttpOnly: true,
+				Secure:   r.TLS != nil,
+				SameSite: http.SameSiteStrictMode,
+			})
+			u := *r.URL
+			q := u.Query()
+			q.Del(TokenParameter)
+			u.RawQuery = q.Encode()
+			http.Redirect(w, r, u.String(), http.StatusSeeOther)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), p)))
+	})
+}
@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"os"
+
+	"diagonal.works/b6"
+	"gopkg.in/yaml.v2"
+)
+
+// StaticTokens authenticates requests against a fixed set of tokens, each
+// with their own permissions.
+type StaticTokens map[string]*Permissions
+
+func (s StaticTokens) Authenticate(token string) (*Permissions, error) {
+	// Compare every token in constant time, to avoid leaking their
+	// contents through timing.
+	var found *Permissions
+	for t, p := range s {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			found = p
+		}
+	}
+	if found == nil {
+		return nil, ErrUnauthenticated
+	}
+	return found, nil
+}
+
+type tokenYAML struct {
+	Name     string   `yaml:"name"`
+	Token    string   `yaml:"token"`
+	ReadOnly bool     `yaml:"read-only"`
+	Worlds   []string `yaml:"worlds"`
+	FileIO   bool     `yaml:"file-io"`
+}
+
+type tokenFileYAML struct {
+	Tokens []tokenYAML `yaml:"tokens"`
+}
+
+// ReadTokens returns StaticTokens from YAML, for example:
+//
+//	tokens:
+//	  - name: analyst
+//	    token: 8f2b...
+//	    read-only: true
+//	  - name: pipeline
+//	    token: c41e...
+//	    worlds: [/collection/diagonal.works/scenario/1]
+//	    file-io: true
+//
+// Tokens can modify every world unless read-only, or restricted to the
+// given worlds, and can't use file IO unless allowed.
+func ReadTokens(r io.Reader) (StaticTokens, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var f tokenFileYAML
+	if err := yaml.UnmarshalStrict(data, &f); err != nil {
+		return nil, err
+	}
+	tokens := make(StaticTokens, len(f.Tokens))
+	for i, t := range f.Tokens {
+		if t.Token == "" {
+			return nil, fmt.Errorf("token %d (%q): missing token", i, t.Name)
+		} else if _, ok := tokens[t.Token]; ok {
+			return nil, fmt.Errorf("token %d (%q): duplicate token", i, t.Name)
+		}
+		p := &Permissions{Subject: t.Name, ReadOnly: t.ReadOnly, FileIO: t.FileIO}
+		for _, w := range t.Worlds {
+			id := b6.FeatureIDFromString(w)
+			if !id.IsValid() {
+				return nil, fmt.Errorf("token %d (%q): invalid world ID %q", i, t.Name, w)
+			}
+			p.Worlds = append(p.Worlds, id)
+		}
+		tokens[t.Token] = p
+	}
+	return tokens, nil
+}
+
+// ReadTokenFile returns StaticTokens read from the given YAML file, as
+// described in ReadTokens.
+func ReadTokenFile(filename string) (StaticTokens, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	tokens, err := ReadTokens(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return tokens, nil
+}
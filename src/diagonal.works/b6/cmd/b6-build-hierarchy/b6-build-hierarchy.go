@@ -0,0 +1,87 @@
+package main
+
+// Build a contraction hierarchy for the street network of a world, for the
+// weights given by the options passed to graph functions. b6 uses the
+// hierarchy, given by its --hierarchies flag, to speed up queries like
+// shortest-route and travel-times that use the same options.
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/api/functions"
+	"diagonal.works/b6/graph"
+	"diagonal.works/b6/ingest"
+	"diagonal.works/b6/ingest/compact"
+
+	_ "github.com/apache/beam/sdks/go/pkg/beam/io/filesystem/gcs"
+	_ "github.com/apache/beam/sdks/go/pkg/beam/io/filesystem/local"
+)
+
+func main() {
+	worldFlag := flag.String("world", "", "World for which to build the hierarchy")
+	outputFlag := flag.String("output", "", "Output hierarchy filename")
+	optionsFlag := flag.String("options", "mode=walk", "Options for graph functions, as key=value pairs separated by commas, eg mode=cycle,cycle:speed=20")
+	routingProfilesFlag := flag.String("routing-profiles", "", "YAML file with routing profiles, selected with the profile option")
+	coresFlag := flag.Int("cores", runtime.NumCPU(), "Number of cores available")
+	flag.Parse()
+
+	if err := run(*worldFlag, *outputFlag, *optionsFlag, *routingProfilesFlag, *coresFlag); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+
+func run(world string, output string, options string, routingProfiles string, cores int) error {
+	if world == "" || output == "" {
+		return fmt.Errorf("must specify --world and --output")
+	}
+
+	var opts b6.Tags
+	for _, option := range strings.Split(options, ",") {
+		if option == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(option, "=")
+		if !ok {
+			return fmt.Errorf("expected key=value in --options, found %q", option)
+		}
+		opts = append(opts, b6.Tag{Key: key, Value: b6.NewStringExpression(value)})
+	}
+
+	var profiles map[string]*graph.Profile
+	if routingProfiles != "" {
+		var err error
+		if profiles, err = functions.ReadRoutingProfiles(routingProfiles); err != nil {
+			return err
+		}
+	}
+
+	w, err := compact.ReadWorld(world, &ingest.BuildOptions{Cores: cores})
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	h, err := functions.BuildContractionHierarchy(w, opts, profiles)
+	if err != nil {
+		return err
+	}
+	log.Printf("built hierarchy for %s with %d points and %d shortcuts in %s", h.Weights, h.Len(), h.Shortcuts(), time.Since(start))
+
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if err := h.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,131 @@
+package main
+
+// Write a compact index containing the subset of a world's features in
+// given namespaces, intersecting a polygon, or matching a query, together
+// with the features needed to build their geometry.
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/api"
+	"diagonal.works/b6/api/functions"
+	"diagonal.works/b6/geojson"
+	"diagonal.works/b6/geometry"
+	"diagonal.works/b6/ingest"
+	"diagonal.works/b6/ingest/compact"
+
+	_ "github.com/apache/beam/sdks/go/pkg/beam/io/filesystem/gcs"
+	_ "github.com/apache/beam/sdks/go/pkg/beam/io/filesystem/local"
+)
+
+func readPolygon(filename string) (b6.Query, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	g, err := geojson.Unmarshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	polygons := g.ToS2Polygons()
+	if len(polygons) == 0 {
+		return nil, fmt.Errorf("%s: expected a polygon", filename)
+	}
+	return b6.IntersectsMultiPolygon{MultiPolygon: geometry.MultiPolygon(polygons)}, nil
+}
+
+func evaluateQuery(expression string, w b6.World) (b6.Query, error) {
+	v, err := api.EvaluateString(expression, functions.NewContext(w))
+	if err != nil {
+		return nil, err
+	}
+	if q, ok := v.(b6.Query); ok {
+		return q, nil
+	}
+	return nil, fmt.Errorf("expected a query, found %T", v)
+}
+
+func extract(world string, namespaces string, polygon string, query string, cores int) (ingest.FeatureSource, error) {
+	w, err := compact.ReadWorld(world, &ingest.BuildOptions{Cores: cores})
+	if err != nil {
+		return nil, err
+	}
+	var e ingest.Extract
+	if namespaces != "" {
+		for _, ns := range strings.Split(namespaces, ",") {
+			e.Namespaces = append(e.Namespaces, b6.Namespace(ns))
+		}
+	}
+	queries := make(b6.Intersection, 0, 2)
+	if polygon != "" {
+		q, err := readPolygon(polygon)
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, q)
+	}
+	if query != "" {
+		q, err := evaluateQuery(query, w)
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, q)
+	}
+	if len(queries) == 1 {
+		e.Query = queries[0]
+	} else if len(queries) > 1 {
+		e.Query = queries
+	}
+	return ingest.NewExtractSource(w, &e, cores)
+}
+
+func main() {
+	world := flag.String("world", "", "World to extract from, in the same form as for b6")
+	output := flag.String("output", "", "Output index filename")
+	namespaces := flag.String("namespaces", "", "Namespaces of features to extract, separated by commas")
+	polygon := flag.String("polygon", "", "GeoJSON file with polygons intersecting the features to extract")
+	query := flag.String("query", "", "Query matching the features to extract, for example 'keyed \"#building\"'")
+	cores := flag.Int("cores", runtime.NumCPU(), "Available cores")
+	memory := flag.Bool("memory", true, "Use memory for intermediate data")
+	scratch := flag.String("scratch", ".", "Directory for temporary files, for --memory=false  or writing to cloud")
+	compress := flag.Bool("compress", false, "Compress features, for a smaller index with slower lookups")
+	flag.Parse()
+
+	var err error
+	if *world == "" || *output == "" {
+		err = fmt.Errorf("must specify --world and --output")
+	} else if *namespaces == "" && *polygon == "" && *query == "" {
+		err = fmt.Errorf("must specify at least one of --namespaces, --polygon or --query")
+	} else {
+		var source ingest.FeatureSource
+		if source, err = extract(*world, *namespaces, *polygon, *query, *cores); err == nil {
+			t := compact.OutputTypeMemory
+			if !*memory {
+				t = compact.OutputTypeDisk
+			}
+			options := compact.Options{
+				OutputFilename:            *output,
+				Goroutines:                *cores,
+				ScratchDirectory:          *scratch,
+				PointsScratchOutputType:   t,
+				CompressFeatures:          *compress,
+				FeaturesScratchOutputType: t,
+			}
+			var finish func() error
+			if finish, err = compact.MaybeWriteToCloud(&options); err == nil {
+				if err = compact.Build(source, &options); err == nil {
+					err = finish()
+				}
+			}
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
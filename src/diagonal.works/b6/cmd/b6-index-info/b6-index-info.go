@@ -0,0 +1,218 @@
+package main
+
+// Print the layout, contents and search tokens of a compact index, dump
+// individual features, and validate that the index is consistent and
+// matches its checksums, exiting with a non-zero status if it isn't.
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/api"
+	"diagonal.works/b6/encoding"
+	"diagonal.works/b6/ingest"
+	"diagonal.works/b6/ingest/compact"
+
+	"gopkg.in/yaml.v2"
+)
+
+func printLayout(info *compact.IndexInfo) {
+	fmt.Printf("length: %d\n", info.Length)
+	fmt.Printf("version: %s\n", info.Version)
+	fmt.Printf("strings: %d bytes\n", info.StringsLength)
+	fmt.Printf("namespaces:\n")
+	for i, ns := range info.Namespaces {
+		fmt.Printf("  %d: %s\n", i, ns)
+	}
+	fmt.Printf("blocks:\n")
+	t := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(t, "  offset\ttype\tlength\tcontents\n")
+	for _, b := range info.Blocks {
+		var contents string
+		switch b.Type {
+		case compact.BlockTypeFeatures, compact.BlockTypeCompressedFeatures:
+			contents = fmt.Sprintf("%s/%s, %d buckets", b.FeatureType, b.Namespace, b.Buckets)
+		case compact.BlockTypeSearchIndex:
+			contents = fmt.Sprintf("%d tokens", b.Tokens)
+		}
+		fmt.Fprintf(t, "  %d\t%s\t%d\t%s\n", b.Offset, b.Type, b.Length, contents)
+	}
+	t.Flush()
+}
+
+func formatTimestamp(t int64) string {
+	if t == 0 {
+		return "unknown"
+	}
+	return time.Unix(t, 0).UTC().Format(time.RFC3339)
+}
+
+func printSortedMap(m map[string]string) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Printf("  %s: %s\n", k, m[k])
+	}
+}
+
+func printMetadata(m *compact.IndexMetadata) {
+	fmt.Printf("builder: %s\n", m.Header.Builder)
+	fmt.Printf("built: %s\n", formatTimestamp(m.Header.BuildTimestamp))
+	fmt.Printf("replication: %s\n", formatTimestamp(m.Header.ReplicationTimestamp))
+	if len(m.Header.Sources) > 0 {
+		fmt.Printf("sources:\n")
+		for _, source := range m.Header.Sources {
+			fmt.Printf("  %s: %d bytes, sha256 %s\n", source.Filename, source.Size, source.Sha256)
+		}
+	}
+	if len(m.Header.Options) > 0 {
+		fmt.Printf("options:\n")
+		printSortedMap(m.Header.Options)
+	}
+	if len(m.Header.TagMapping) > 0 {
+		fmt.Printf("tag mapping:\n")
+		printSortedMap(m.Header.TagMapping)
+	}
+	if m.Metadata != nil && m.Metadata.BoundsLo != nil {
+		lo, hi := b6.PointProtoToS2LatLng(m.Metadata.BoundsLo), b6.PointProtoToS2LatLng(m.Metadata.BoundsHi)
+		fmt.Printf("bounds: %s, %s\n", lo, hi)
+	}
+}
+
+func printCounts(counts *compact.NamespacedCounts) {
+	nss := make([]string, 0, len(counts.ByNamespace))
+	for ns := range counts.ByNamespace {
+		nss = append(nss, string(ns))
+	}
+	sort.Strings(nss)
+	fmt.Printf("features:\n")
+	t := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(t, "  namespace\tpoints\tpaths\tareas\trelations\tpath points\tarea paths\trelation members\t\n")
+	for _, ns := range nss {
+		c := counts.ByNamespace[b6.Namespace(ns)]
+		fmt.Fprintf(t, "  %s\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t\n", ns, c.Points, c.Paths, c.Areas, c.Relations, c.PathPoints, c.AreaPaths, c.RelationMembers)
+	}
+	t.Flush()
+}
+
+func printTokens(tokens []compact.TokenInfo) {
+	fmt.Printf("tokens:\n")
+	t := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	for _, token := range tokens {
+		fmt.Fprintf(t, "  %s\t%d\n", token.Token, token.Features)
+	}
+	t.Flush()
+}
+
+func dumpFeatures(w b6.World, ids string) error {
+	for _, token := range strings.Split(ids, ",") {
+		id, err := api.ParseFeatureIDToken(token)
+		if err != nil {
+			return fmt.Errorf("%q: %w", token, err)
+		}
+		f := w.FindFeatureByID(id)
+		if f == nil {
+			return fmt.Errorf("%s: not found", id)
+		}
+		fmt.Println("---")
+		if err := yaml.NewEncoder(os.Stdout).Encode(ingest.NewFeatureFromWorld(f)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func run() (bool, error) {
+	indexFlag := flag.String("index", "", "Compact index to inspect")
+	baseFlag := flag.String("base", "", "Compact index to which --index is applied as an overlay, used to resolve references")
+	tokensFlag := flag.Int("tokens", 20, "Number of search tokens with the longest posting lists to print")
+	featuresFlag := flag.String("features", "", "IDs of features to print as YAML, separated by commas, for example /n/3501612811")
+	validateFlag := flag.Bool("validate", true, "Check that references and posting lists resolve, exiting with a non-zero status if not")
+	coresFlag := flag.Int("cores", runtime.NumCPU(), "Number of cores available")
+	flag.Parse()
+
+	if *indexFlag == "" {
+		return false, fmt.Errorf("must specify --index")
+	}
+	m, err := encoding.Mmap(*indexFlag)
+	if err != nil {
+		return false, err
+	}
+	info, err := compact.ReadIndexInfo(m.Data)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", *indexFlag, err)
+	}
+	printLayout(info)
+	metadata, err := compact.ReadIndexMetadata(m.Data)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", *indexFlag, err)
+	}
+	printMetadata(metadata)
+
+	w := compact.NewWorld()
+	if *baseFlag != "" {
+		base, err := compact.NewWorldFromFile(*baseFlag)
+		if err != nil {
+			return false, fmt.Errorf("%s: %w", *baseFlag, err)
+		}
+		w = compact.NewWorldWithBase(base)
+	}
+	if err := w.Merge(m.Data); err != nil {
+		return false, fmt.Errorf("%s: %w", *indexFlag, err)
+	}
+
+	counts, err := w.CountFeatures(*coresFlag)
+	if err != nil {
+		return false, err
+	}
+	printCounts(counts)
+	if *tokensFlag > 0 {
+		printTokens(w.TopTokens(*tokensFlag))
+	}
+	if *featuresFlag != "" {
+		if err := dumpFeatures(w, *featuresFlag); err != nil {
+			return false, err
+		}
+	}
+
+	if !*validateFlag {
+		return true, nil
+	}
+	if metadata.Metadata == nil {
+		fmt.Printf("checksums: none, index predates version 5.2.0\n")
+	} else if err := compact.VerifyChecksums(m.Data, metadata.Metadata); err != nil {
+		fmt.Printf("checksums: %s\n", err)
+		return false, nil
+	} else {
+		fmt.Printf("checksums: ok, %d blocks\n", len(metadata.Metadata.Blocks))
+	}
+	v := w.Validate(*coresFlag)
+	fmt.Printf("validation: %d errors, %d missing relation members\n", v.NumErrors, v.MissingRelationMembers)
+	for _, err := range v.Errors {
+		fmt.Printf("  %s\n", err)
+	}
+	if v.NumErrors > len(v.Errors) {
+		fmt.Printf("  ... and %d more\n", v.NumErrors-len(v.Errors))
+	}
+	return v.NumErrors == 0, nil
+}
+
+func main() {
+	ok, err := run()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	} else if !ok {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+// Merge several compact indices into one, to avoid the startup time and
+// memory needed to load them separately. When the same feature appears in
+// more than one index, the one from the last takes precedence.
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"diagonal.works/b6/encoding"
+	"diagonal.works/b6/ingest"
+	"diagonal.works/b6/ingest/compact"
+	pb "diagonal.works/b6/proto"
+
+	_ "github.com/apache/beam/sdks/go/pkg/beam/io/filesystem/gcs"
+	_ "github.com/apache/beam/sdks/go/pkg/beam/io/filesystem/local"
+)
+
+// addSource records the given input index as a source of the merged
+// index, which carries forward the latest replication timestamp of the
+// inputs.
+func addSource(header *pb.CompactHeaderProto, filename string, data []byte) error {
+	source, err := compact.NewSourceProto(filename)
+	if err != nil {
+		return err
+	}
+	header.Sources = append(header.Sources, source)
+	metadata, err := compact.ReadIndexMetadata(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+	if t := metadata.Header.ReplicationTimestamp; t > header.ReplicationTimestamp {
+		header.ReplicationTimestamp = t
+	}
+	return nil
+}
+
+func main() {
+	inputs := flag.String("inputs", "", "Compact indices to merge, separated by commas, in increasing order of precedence")
+	output := flag.String("output", "", "Output index filename")
+	cores := flag.Int("cores", runtime.NumCPU(), "Available cores")
+	memory := flag.Bool("memory", true, "Use memory for intermediate data")
+	scratch := flag.String("scratch", ".", "Directory for temporary files, for --memory=false  or writing to cloud")
+	compress := flag.Bool("compress", false, "Compress features, for a smaller index with slower lookups")
+	flag.Parse()
+
+	var err error
+	if *inputs == "" || *output == "" {
+		err = fmt.Errorf("must specify --inputs and --output")
+	} else {
+		indices := make([][]byte, 0)
+		header := &pb.CompactHeaderProto{}
+		for _, input := range strings.Split(*inputs, ",") {
+			var m encoding.Mmapped
+			if m, err = encoding.Mmap(input); err != nil {
+				break
+			}
+			indices = append(indices, m.Data)
+			if err = addSource(header, input, m.Data); err != nil {
+				break
+			}
+		}
+		if err == nil {
+			t := compact.OutputTypeMemory
+			if !*memory {
+				t = compact.OutputTypeDisk
+			}
+			options := compact.Options{
+				OutputFilename:            *output,
+				Goroutines:                *cores,
+				ScratchDirectory:          *scratch,
+				PointsScratchOutputType:   t,
+				CompressFeatures:          *compress,
+				FeaturesScratchOutputType: t,
+				Header:                    header,
+			}
+			var finish func() error
+			finish, err = compact.MaybeWriteToCloud(&options)
+			if err == nil {
+				var source ingest.FeatureSource
+				if source, err = compact.NewMergedIndexSource(indices); err == nil {
+					err = compact.Build(source, &options)
+				}
+				if err == nil {
+					err = finish()
+				}
+			}
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
@@ -11,17 +11,73 @@ import (
 
 	"diagonal.works/b6/ingest"
 	"diagonal.works/b6/ingest/compact"
+	"diagonal.works/b6/osm"
+	pb "diagonal.works/b6/proto"
 
+	"github.com/apache/beam/sdks/go/pkg/beam/io/filesystem"
 	_ "github.com/apache/beam/sdks/go/pkg/beam/io/filesystem/gcs"
 	_ "github.com/apache/beam/sdks/go/pkg/beam/io/filesystem/local"
 )
 
+// newHeader returns a header recording the given input files, and the
+// flags and tag mapping used to ingest them. Flags in inherited, registered
+// by libraries rather than this command, aren't recorded.
+func newHeader(input string, hash bool, inherited map[string]struct{}) (*pb.CompactHeaderProto, error) {
+	header := &pb.CompactHeaderProto{
+		Options:    make(map[string]string),
+		TagMapping: ingest.OSMTagMapping(),
+	}
+	flag.VisitAll(func(f *flag.Flag) {
+		if _, ok := inherited[f.Name]; !ok {
+			header.Options[f.Name] = f.Value.String()
+		}
+	})
+
+	ctx := context.Background()
+	fs, err := filesystem.New(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	defer fs.Close()
+	filenames, err := fs.List(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	for _, filename := range filenames {
+		source := &pb.CompactSourceProto{Filename: filename}
+		if hash {
+			if source, err = compact.NewSourceProto(filename); err != nil {
+				return nil, err
+			}
+		}
+		header.Sources = append(header.Sources, source)
+		r, err := fs.OpenRead(ctx, filename)
+		if err != nil {
+			return nil, err
+		}
+		h, err := osm.ReadHeader(r)
+		r.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+		if t := h.GetOsmosisReplicationTimestamp(); t > header.ReplicationTimestamp {
+			header.ReplicationTimestamp = t
+		}
+	}
+	return header, nil
+}
+
 func main() {
+	inherited := make(map[string]struct{})
+	flag.VisitAll(func(f *flag.Flag) { inherited[f.Name] = struct{}{} })
+
 	input := flag.String("input", "", "Input filename, OSM PBF format")
 	output := flag.String("output", "", "Output index filename")
 	cores := flag.Int("cores", runtime.NumCPU(), "Available cores")
 	memory := flag.Bool("memory", true, "Use memory for intermediate data")
 	scratch := flag.String("scratch", ".", "Directory for temporary files, for --memory=false  or writing to cloud")
+	compress := flag.Bool("compress", false, "Compress features, for a smaller index with slower lookups")
+	hash := flag.Bool("hash", true, "Record a SHA-256 hash of each input file in the index header")
 	flag.Parse()
 
 	var err error
@@ -33,13 +89,18 @@ func main() {
 			t = compact.OutputTypeDisk
 		}
 		options := compact.Options{
-			OutputFilename:          *output,
-			Goroutines:              *cores,
-			ScratchDirectory:        *scratch,
-			PointsScratchOutputType: t,
+			OutputFilename:            *output,
+			Goroutines:                *cores,
+			ScratchDirectory:          *scratch,
+			PointsScratchOutputType:   t,
+			CompressFeatures:          *compress,
+			FeaturesScratchOutputType: t,
 		}
 		var finish func() error
-		finish, err = compact.MaybeWriteToCloud(&options)
+		options.Header, err = newHeader(*input, *hash, inherited)
+		if err == nil {
+			finish, err = compact.MaybeWriteToCloud(&options)
+		}
 		if err == nil {
 			osmSource := ingest.PBFFilesOSMSource{Glob: *input}
 			var source ingest.FeatureSource
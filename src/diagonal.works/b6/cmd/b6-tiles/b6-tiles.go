@@ -0,0 +1,150 @@
+package main
+
+// Render a pyramid of vector tiles for a world's basemap, writing them to
+// a PMTiles or MBTiles archive that can be hosted statically.
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/ingest"
+	"diagonal.works/b6/ingest/compact"
+	"diagonal.works/b6/renderer"
+	"diagonal.works/b6/renderer/mbtiles"
+
+	"github.com/golang/geo/s2"
+
+	_ "github.com/apache/beam/sdks/go/pkg/beam/io/filesystem/gcs"
+	_ "github.com/apache/beam/sdks/go/pkg/beam/io/filesystem/local"
+)
+
+// worldBounds returns a rectangle bounding every feature in the world.
+func worldBounds(w b6.World) s2.Rect {
+	bounds := s2.EmptyRect()
+	features := w.FindFeatures(b6.All{})
+	for features.Next() {
+		f, ok := features.Feature().(b6.PhysicalFeature)
+		if !ok {
+			continue
+		}
+		switch f.GeometryType() {
+		case b6.GeometryTypePoint:
+			bounds = bounds.AddPoint(s2.LatLngFromPoint(f.Point()))
+		case b6.GeometryTypePath:
+			bounds = bounds.Union(f.Polyline().RectBound())
+		case b6.GeometryTypeArea:
+			if area, ok := f.(b6.AreaFeature); ok {
+				for i := 0; i < area.Len(); i++ {
+					bounds = bounds.Union(area.Polygon(i).RectBound())
+				}
+			}
+		}
+	}
+	return bounds
+}
+
+func main() {
+	worldFlag := flag.String("world", "", "World to render")
+	outputFlag := flag.String("output", "", "Archive to write, ending in .pmtiles or .mbtiles")
+	minZoomFlag := flag.Uint("min-zoom", 8, "Minimum zoom level to render")
+	maxZoomFlag := flag.Uint("max-zoom", 16, "Maximum zoom level to render")
+	boundsFlag := flag.String("bounds", "", "Bounds to render, as lat,lng,lat,lng. Defaults to the bounds of the world.")
+	nameFlag := flag.String("name", "b6", "Name of the tileset")
+	descriptionFlag := flag.String("description", "", "Description of the tileset")
+	attributionFlag := flag.String("attribution", "", "Attribution for the tileset's data, as HTML")
+	coresFlag := flag.Int("cores", runtime.NumCPU(), "Number of cores available")
+	basemapRulesFlag := flag.String("basemap-rules", "", "YAML file with rules for rendering the basemap. Defaults to the built in rules.")
+	flag.Parse()
+
+	if *worldFlag == "" || *outputFlag == "" {
+		fmt.Fprintln(os.Stderr, "Must specify --world and --output")
+		os.Exit(1)
+	}
+
+	rules := renderer.BasemapRenderRules
+	if *basemapRulesFlag != "" {
+		var err error
+		if rules, err = renderer.ReadRenderRules(*basemapRulesFlag); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	}
+
+	if *minZoomFlag > *maxZoomFlag {
+		fmt.Fprintf(os.Stderr, "--min-zoom %d is greater than --max-zoom %d\n", *minZoomFlag, *maxZoomFlag)
+		os.Exit(1)
+	}
+
+	format := filepath.Ext(*outputFlag)
+	if format != ".pmtiles" && format != ".mbtiles" {
+		fmt.Fprintf(os.Stderr, "Can't infer archive format from %q, expected .pmtiles or .mbtiles\n", *outputFlag)
+		os.Exit(1)
+	}
+
+	bounds, err := ingest.ParseBoundingBox(*boundsFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	w, err := compact.ReadWorld(*worldFlag, &ingest.BuildOptions{Cores: *coresFlag})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	if bounds.IsFull() {
+		bounds = worldBounds(w)
+	}
+	if bounds.IsEmpty() {
+		fmt.Fprintln(os.Stderr, "World has no features to render")
+		os.Exit(1)
+	}
+
+	// The archive is only created once the inputs are known to be valid,
+	// and removed if rendering fails, to avoid leaving a partial archive.
+	var archive renderer.TileArchive
+	if format == ".pmtiles" {
+		archive, err = renderer.NewPMTilesWriter(*outputFlag)
+	} else {
+		archive, err = mbtiles.NewWriter(*outputFlag)
+	}
+	if err != nil {
+		os.Remove(*outputFlag)
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	options := renderer.PyramidOptions{
+		Bounds:  bounds,
+		MinZoom: *minZoomFlag,
+		MaxZoom: *maxZoomFlag,
+		Args:    renderer.TileArgs{R: ingest.DefaultWorldFeatureID},
+		Cores:   *coresFlag,
+	}
+	log.Printf("Rendering %d tiles", renderer.CountTilesInPyramid(bounds, options.MinZoom, options.MaxZoom))
+	r := &renderer.BasemapRenderer{RenderRules: rules, Worlds: ingest.NewReadOnlyWorlds(w)}
+	err = renderer.RenderPyramid(r, &options, archive)
+	metadata := renderer.TilesetMetadata{
+		Name:        *nameFlag,
+		Description: *descriptionFlag,
+		Attribution: *attributionFlag,
+		MinZoom:     options.MinZoom,
+		MaxZoom:     options.MaxZoom,
+		Bounds:      bounds,
+		Layers:      rules.VectorLayers(options.MinZoom, options.MaxZoom),
+	}
+	if cerr := archive.Close(&metadata); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(*outputFlag)
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	log.Printf("Wrote %s", *outputFlag)
+}
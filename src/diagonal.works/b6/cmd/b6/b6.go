@@ -5,6 +5,7 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"log/slog"
 	"net"
 	"net/http"
 	"net/http/pprof"
@@ -12,17 +13,22 @@ import (
 	"runtime"
 	rpprof "runtime/pprof"
 	"strings"
-	"sync"
 
 	"diagonal.works/b6"
 	"diagonal.works/b6/api"
+	"diagonal.works/b6/api/functions"
+	"diagonal.works/b6/auth"
 	b6grpc "diagonal.works/b6/grpc"
 	"diagonal.works/b6/ingest"
 	"diagonal.works/b6/ingest/compact"
+	"diagonal.works/b6/monitoring"
 	pb "diagonal.works/b6/proto"
+	"diagonal.works/b6/renderer"
 	"diagonal.works/b6/ui"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	_ "github.com/apache/beam/sdks/go/pkg/beam/io/filesystem/gcs"
 	_ "github.com/apache/beam/sdks/go/pkg/beam/io/filesystem/local"
@@ -32,6 +38,15 @@ func main() {
 	httpFlag := flag.String("http", ":8001", "Host and port on which to serve HTTP")
 	grpcFlag := flag.String("grpc", ":8002", "Host and port on which to serve GRPC")
 	grpcSizeFlag := flag.Int("grpc-size", 16*1024*1024, "Maximum size for GRPC messages")
+	grpcTLSCertFlag := flag.String("grpc-tls-cert", "", "PEM certificate with which to serve GRPC over TLS, as required by the Python client's connect()")
+	grpcTLSKeyFlag := flag.String("grpc-tls-key", "", "PEM private key for --grpc-tls-cert")
+	authTokensFlag := flag.String("auth-tokens", "", "YAML file of access tokens and their permissions. Requests without a valid token are rejected.")
+	authJWKSFlag := flag.String("auth-jwks", "", "JSON Web Key Set with which to validate JWT access tokens. Requests without a valid token are rejected.")
+	authJWTIssuerFlag := flag.String("auth-jwt-issuer", "", "If set, JWT access tokens must be issued by this issuer")
+	authJWTAudienceFlag := flag.String("auth-jwt-audience", "", "If set, JWT access tokens must be issued for this audience")
+	metricsFlag := flag.Bool("metrics", true, "Serve Prometheus metrics on /metrics")
+	logFormatFlag := flag.String("log-format", "text", "Format for logs, either text or json")
+	logLevelFlag := flag.String("log-level", "info", "Minimum level of logs, either debug, info, warn or error. Successful requests are logged at debug.")
 	worldFlag := flag.String("world", "", "World to load")
 	readOnlyFlag := flag.Bool("read-only", false, "Prevent changes to the world")
 	staticFlag := flag.String("static", "src/diagonal.works/b6/cmd/b6/js/static", "Path to static content")
@@ -43,6 +58,16 @@ func main() {
 	enableViteFlag := flag.Bool("enable-vite", false, "Serve javascript from a development vite server")
 	coresFlag := flag.Int("cores", runtime.NumCPU(), "Number of cores available")
 	fileIOFlag := flag.Bool("file-io", true, "Is file IO allowed from the API?")
+	cacheSizeFlag := flag.Int("cache-size", 256, "Approximate memory used to cache function results, in MB. 0 disables caching.")
+	tileCacheSizeFlag := flag.Int("tile-cache-size", 256, "Approximate memory used to cache rendered tiles, in MB. 0 disables caching.")
+	tileCacheDirFlag := flag.String("tile-cache-dir", "", "Directory in which to cache tiles evicted from memory. Its contents are removed on startup.")
+	tileCacheDiskSizeFlag := flag.Int("tile-cache-disk-size", 4096, "Maximum size of tiles cached in --tile-cache-dir, in MB.")
+	basemapRulesFlag := flag.String("basemap-rules", "", "YAML file with rules for rendering the basemap. Defaults to the built in rules.")
+	routingProfilesFlag := flag.String("routing-profiles", "", "YAML file with routing profiles, selected with the profile option of graph functions.")
+	hierarchiesFlag := flag.String("hierarchies", "", "Contraction hierarchies for --world, built by b6-build-hierarchy, separated by commas.")
+	timeoutFlag := flag.Duration("timeout", 0, "Maximum time for each evaluation. 0 imposes no limit.")
+	maxFeaturesFlag := flag.Int("max-features", 0, "Maximum number of features read by each evaluation. 0 imposes no limit.")
+	maxHeapFlag := flag.Int("max-heap", 0, "Heap size, in MB, beyond which evaluations are aborted. 0 imposes no limit.")
 
 	additionalWorlds := make(map[b6.FeatureID]string)
 	flag.Func("add-world", "Additional worlds; specify like \"<feature_id> <world-arguments>\"", func(s string) error {
@@ -61,6 +86,19 @@ func main() {
 
 	flag.Parse()
 
+	var logLevel slog.Level
+	if err := logLevel.UnmarshalText([]byte(*logLevelFlag)); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	logger, err := monitoring.NewLogger(os.Stderr, *logFormatFlag, logLevel)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	// Also redirects the log package, used elsewhere.
+	slog.SetDefault(logger)
+
 	if *worldFlag == "" {
 		fmt.Fprintln(os.Stderr, "Must specify --world")
 		os.Exit(1)
@@ -85,7 +123,7 @@ func main() {
 
 	var worlds ingest.Worlds
 	if *readOnlyFlag {
-		worlds = ingest.ReadOnlyWorlds{Base: base}
+		worlds = ingest.NewReadOnlyWorlds(base)
 	} else {
 		worlds = &ingest.MutableWorlds{Base: base, Mutable: additionalMutableWorlds}
 	}
@@ -93,21 +131,97 @@ func main() {
 	apiOptions := api.Options{
 		Cores:         *coresFlag,
 		FileIOAllowed: *fileIOFlag,
+		Limits: api.Limits{
+			Timeout:      *timeoutFlag,
+			MaxFeatures:  *maxFeaturesFlag,
+			MaxHeapBytes: uint64(*maxHeapFlag) * 1024 * 1024,
+		},
+	}
+	if *routingProfilesFlag != "" {
+		if apiOptions.RoutingProfiles, err = functions.ReadRoutingProfiles(*routingProfilesFlag); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	}
+	if *hierarchiesFlag != "" {
+		if apiOptions.Hierarchies, err = functions.ReadContractionHierarchies(strings.Split(*hierarchiesFlag, ","), base); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	}
+	if *cacheSizeFlag > 0 {
+		apiOptions.Cache = api.NewCache(api.CacheOptions{
+			MaxBytes:  *cacheSizeFlag * 1024 * 1024,
+			Functions: functions.CacheableFunctions(),
+		})
+	}
+
+	var authenticators auth.Authenticators
+	if *authTokensFlag != "" {
+		tokens, err := auth.ReadTokenFile(*authTokensFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		authenticators = append(authenticators, tokens)
+	}
+	if *authJWKSFlag != "" {
+		keys, err := auth.ReadJWKSFile(*authJWKSFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		authenticators = append(authenticators, &auth.JWTAuthenticator{
+			Keys:     keys,
+			Issuer:   *authJWTIssuerFlag,
+			Audience: *authJWTAudienceFlag,
+		})
 	}
 
-	var lock sync.RWMutex
+	var tileCache *renderer.TileCache
+	if *tileCacheSizeFlag > 0 {
+		tileCache, err = renderer.NewTileCache(renderer.TileCacheOptions{
+			MaxBytes:     *tileCacheSizeFlag * 1024 * 1024,
+			Directory:    *tileCacheDirFlag,
+			MaxDiskBytes: *tileCacheDiskSizeFlag * 1024 * 1024,
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	}
+
+	var basemapRules renderer.RenderRules
+	if *basemapRulesFlag != "" {
+		if basemapRules, err = renderer.ReadRenderRules(*basemapRulesFlag); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+	}
+
+	var locks ingest.WorldLocks
+
+	registry := monitoring.NewRegistry()
+	apiOptions.Metrics = api.NewEvaluationMetrics(registry, functions.Functions())
+	registerMetrics(registry, worlds, &locks, apiOptions.Cache, tileCache)
+	httpMetrics := monitoring.NewHTTPMetrics(registry)
+	grpcMetrics := monitoring.NewGRPCMetrics(registry)
+	metrics := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
 
 	options := ui.Options{
-		StaticPath:      *staticFlag,
-		JavaScriptPath:  *jsFlag,
-		StaticV2Path:    *staticV2Flag,
-		StorybookPath:   *storybookFlag,
-		EnableV2UI:      *enableV2UIFlag,
-		EnableVite:      *enableViteFlag,
-		EnableStorybook: *enableStorybookFlag,
-		Worlds:          worlds,
-		APIOptions:      apiOptions,
-		Lock:            &lock,
+		StaticPath:        *staticFlag,
+		JavaScriptPath:    *jsFlag,
+		StaticV2Path:      *staticV2Flag,
+		StorybookPath:     *storybookFlag,
+		EnableV2UI:        *enableV2UIFlag,
+		EnableVite:        *enableViteFlag,
+		EnableStorybook:   *enableStorybookFlag,
+		Worlds:            worlds,
+		APIOptions:        apiOptions,
+		BasemapRules:      basemapRules,
+		TileCache:         tileCache,
+		Locks:             &locks,
+		InstrumentHandler: httpMetrics.Instrument,
 	}
 
 	handler := http.NewServeMux()
@@ -117,10 +231,14 @@ func main() {
 	}
 	ui.RegisterTiles(handler, &options)
 
-	handler.HandleFunc("/healthy", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	healthy := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/plain")
 		w.Write([]byte("ok"))
-	}))
+	})
+	handler.HandleFunc("/healthy", healthy)
+	if *metricsFlag {
+		handler.Handle("/metrics", metrics)
+	}
 
 	handler.HandleFunc("/i/pprof/", pprof.Index)
 	handler.HandleFunc("/i/pprof/profile", pprof.Profile)
@@ -131,8 +249,26 @@ func main() {
 	var grpcServer *grpc.Server
 	if *grpcFlag != "" {
 		log.Printf("Listening for GRPC on %s", *grpcFlag)
-		grpcServer = grpc.NewServer(grpc.MaxRecvMsgSize(*grpcSizeFlag), grpc.MaxSendMsgSize(*grpcSizeFlag))
-		pb.RegisterB6Server(grpcServer, b6grpc.NewB6Service(worlds, apiOptions, &lock))
+		grpcOptions := []grpc.ServerOption{grpc.MaxRecvMsgSize(*grpcSizeFlag), grpc.MaxSendMsgSize(*grpcSizeFlag)}
+		if *grpcTLSCertFlag != "" {
+			c, err := credentials.NewServerTLSFromFile(*grpcTLSCertFlag, *grpcTLSKeyFlag)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err.Error())
+				os.Exit(1)
+			}
+			grpcOptions = append(grpcOptions, grpc.Creds(c))
+		} else if len(authenticators) > 0 {
+			log.Printf("Warning: serving GRPC without TLS, so access tokens are sent in the clear")
+		}
+		unary := []grpc.UnaryServerInterceptor{grpcMetrics.UnaryServerInterceptor()}
+		stream := []grpc.StreamServerInterceptor{grpcMetrics.StreamServerInterceptor()}
+		if len(authenticators) > 0 {
+			unary = append(unary, auth.UnaryServerInterceptor(authenticators))
+			stream = append(stream, auth.StreamServerInterceptor(authenticators))
+		}
+		grpcOptions = append(grpcOptions, grpc.ChainUnaryInterceptor(unary...), grpc.ChainStreamInterceptor(stream...))
+		grpcServer = grpc.NewServer(grpcOptions...)
+		pb.RegisterB6Server(grpcServer, b6grpc.NewB6Service(worlds, apiOptions, &locks))
 		go func() {
 			listener, err := net.Listen("tcp", *grpcFlag)
 			if err == nil {
@@ -145,7 +281,18 @@ func main() {
 		}()
 	}
 
-	server := http.Server{Addr: *httpFlag, Handler: handler}
+	var httpHandler http.Handler = handler
+	if len(authenticators) > 0 {
+		authenticated := http.NewServeMux()
+		authenticated.Handle("/", auth.Handler(authenticators, handler))
+		authenticated.HandleFunc("/healthy", healthy)
+		if *metricsFlag {
+			authenticated.Handle("/metrics", metrics)
+		}
+		httpHandler = authenticated
+	}
+
+	server := http.Server{Addr: *httpFlag, Handler: monitoring.Handler(httpHandler)}
 	log.Printf("Listening for HTTP on %s", *httpFlag)
 	if err := server.ListenAndServe(); err != nil {
 		fmt.Fprintln(os.Stderr, err.Error())
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"sync"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/api"
+	"diagonal.works/b6/ingest"
+	"diagonal.works/b6/renderer"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var worldModifiedDesc = prometheus.NewDesc("b6_world_modified", "Features added or modified, and tags modified, in each world.", []string{"world", "kind"}, nil)
+
+// worldSizes counts the features and tags modified in each world. Worlds
+// locked for writing are skipped, reporting their previous sizes instead,
+// so scrapes aren't held up while long changes are applied.
+type worldSizes struct {
+	worlds ingest.Worlds
+	locks  *ingest.WorldLocks
+	last   map[b6.FeatureID][2]int
+	lock   sync.Mutex
+}
+
+func (w *worldSizes) Describe(ch chan<- *prometheus.Desc) {
+	ch <- worldModifiedDesc
+}
+
+func (w *worldSizes) Collect(ch chan<- prometheus.Metric) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	ids := w.worlds.ListWorlds()
+	sizes := make(map[b6.FeatureID][2]int, len(ids))
+	for _, id := range ids {
+		if unlock, ok := w.locks.TryRLock(id); ok {
+			var size [2]int
+			world := w.worlds.FindOrCreateWorld(id)
+			options := &b6.EachFeatureOptions{Goroutines: 1}
+			world.EachModifiedFeature(func(b6.Feature, int) error {
+				size[0]++
+				return nil
+			}, options)
+			world.EachModifiedTag(func(ingest.ModifiedTag, int) error {
+				size[1]++
+				return nil
+			}, options)
+			unlock()
+			sizes[id] = size
+		} else if size, ok := w.last[id]; ok {
+			sizes[id] = size
+		}
+	}
+	w.last = sizes
+	for id, size := range sizes {
+		ch <- prometheus.MustNewConstMetric(worldModifiedDesc, prometheus.GaugeValue, float64(size[0]), id.String(), "features")
+		ch <- prometheus.MustNewConstMetric(worldModifiedDesc, prometheus.GaugeValue, float64(size[1]), id.String(), "tags")
+	}
+}
+
+// counterFunc registers a counter whose value is read from statistics
+// maintained elsewhere whenever metrics are scraped. labels, if not nil,
+// distinguish the counters registered under the same name.
+func counterFunc(f promauto.Factory, name string, help string, labels prometheus.Labels, value func() int) {
+	f.NewCounterFunc(prometheus.CounterOpts{Name: name, Help: help, ConstLabels: labels}, func() float64 {
+		return float64(value())
+	})
+}
+
+// gaugeFunc is the equivalent of counterFunc for gauges.
+func gaugeFunc(f promauto.Factory, name string, help string, labels prometheus.Labels, value func() int) {
+	f.NewGaugeFunc(prometheus.GaugeOpts{Name: name, Help: help, ConstLabels: labels}, func() float64 {
+		return float64(value())
+	})
+}
+
+func registerMetrics(r prometheus.Registerer, worlds ingest.Worlds, locks *ingest.WorldLocks, cache *api.Cache, tileCache *renderer.TileCache) {
+	f := promauto.With(r)
+	gaugeFunc(f, "b6_build_info", "Always 1, labelled with the versions of the backend and gRPC API.", prometheus.Labels{"version": b6.BackendVersion, "api_version": b6.ApiVersion}, func() int {
+		return 1
+	})
+
+	gaugeFunc(f, "b6_worlds", "Worlds, including the default world.", nil, func() int {
+		return len(worlds.ListWorlds())
+	})
+	r.MustRegister(&worldSizes{worlds: worlds, locks: locks})
+
+	if cache != nil {
+		const requests = "Lookups in the function result cache, by result."
+		counterFunc(f, "b6_cache_requests_total", requests, prometheus.Labels{"result": "hit"}, func() int {
+			return cache.Stats().Hits
+		})
+		counterFunc(f, "b6_cache_requests_total", requests, prometheus.Labels{"result": "miss"}, func() int {
+			return cache.Stats().Misses
+		})
+		counterFunc(f, "b6_cache_evictions_total", "Results evicted from the function result cache.", nil, func() int {
+			return cache.Stats().Evictions
+		})
+		counterFunc(f, "b6_cache_rejected_total", "Results too large, or otherwise unsuitable, to cache.", nil, func() int {
+			return cache.Stats().Rejected
+		})
+		gaugeFunc(f, "b6_cache_entries", "Results in the function result cache.", nil, func() int {
+			return cache.Stats().Entries
+		})
+		gaugeFunc(f, "b6_cache_bytes", "Approximate memory used by the function result cache.", nil, func() int {
+			return cache.Stats().Bytes
+		})
+	}
+
+	if tileCache != nil {
+		const requests = "Lookups in the tile cache, by result."
+		counterFunc(f, "b6_tile_cache_requests_total", requests, prometheus.Labels{"result": "hit"}, func() int {
+			return tileCache.Stats().Hits
+		})
+		counterFunc(f, "b6_tile_cache_requests_total", requests, prometheus.Labels{"result": "disk_hit"}, func() int {
+			return tileCache.Stats().DiskHits
+		})
+		counterFunc(f, "b6_tile_cache_requests_total", requests, prometheus.Labels{"result": "miss"}, func() int {
+			return tileCache.Stats().Misses
+		})
+		counterFunc(f, "b6_tile_cache_invalidations_total", "Tiles removed from the tile cache because features within them changed.", nil, func() int {
+			return tileCache.Stats().Invalidations
+		})
+		counterFunc(f, "b6_tile_cache_evictions_total", "Tiles evicted from the tile cache.", nil, func() int {
+			return tileCache.Stats().Evictions
+		})
+		const entries = "Tiles in the tile cache, by storage."
+		gaugeFunc(f, "b6_tile_cache_entries", entries, prometheus.Labels{"storage": "memory"}, func() int {
+			return tileCache.Stats().Entries
+		})
+		gaugeFunc(f, "b6_tile_cache_entries", entries, prometheus.Labels{"storage": "disk"}, func() int {
+			return tileCache.Stats().DiskEntries
+		})
+		const bytes = "Bytes used by the tile cache, by storage."
+		gaugeFunc(f, "b6_tile_cache_bytes", bytes, prometheus.Labels{"storage": "memory"}, func() int {
+			return tileCache.Stats().Bytes
+		})
+		gaugeFunc(f, "b6_tile_cache_bytes", bytes, prometheus.Labels{"storage": "disk"}, func() int {
+			return tileCache.Stats().DiskBytes
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package encoding
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/golang/groupcache/lru"
+	"golang.org/x/sync/errgroup"
+)
+
+// Compressed maps group runs of adjacent buckets into frames, which are
+// compressed with DEFLATE independently. Buckets typically hold only one
+// or two items, too few to compress well alone. Since buckets are chosen
+// by the low bits of the ID, features with nearby IDs, which are often
+// also nearby in space, share a frame.
+const CompressedFrameBucketBits = 6
+
+// CompressedFrameCacheSize is the number of decompressed frames kept for
+// each compressed map.
+const CompressedFrameCacheSize = 512
+
+// frame holds the decompressed buckets of a compressed map.
+type frame struct {
+	offsets []int
+	data    []byte
+}
+
+func (f *frame) Bucket(i int) []byte {
+	return f.data[f.offsets[i]:f.offsets[i+1]]
+}
+
+var flateReaders = sync.Pool{
+	New: func() interface{} {
+		return flate.NewReader(bytes.NewReader(nil))
+	},
+}
+
+func decompressFrame(compressed []byte, buckets int) (*frame, error) {
+	f := &frame{offsets: make([]int, buckets+1)}
+	if len(compressed) == 0 {
+		return f, nil
+	}
+	r := flateReaders.Get().(io.ReadCloser)
+	defer flateReaders.Put(r)
+	if err := r.(flate.Resetter).Reset(bytes.NewReader(compressed), nil); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	i := 0
+	for bucket := 0; bucket < buckets; bucket++ {
+		l, n := binary.Uvarint(data[i:])
+		if n <= 0 {
+			return nil, fmt.Errorf("bad length for bucket %d", bucket)
+		}
+		i += n
+		f.offsets[bucket+1] = f.offsets[bucket] + int(l)
+	}
+	if i+f.offsets[buckets] != len(data) {
+		return nil, fmt.Errorf("expected %d bytes, found %d", i+f.offsets[buckets], len(data))
+	}
+	f.data = data[i:]
+	return f, nil
+}
+
+// frameCache holds recently decompressed frames. Decompressed buffers are
+// never modified once added, so slices of them can be returned to
+// callers, and outlive their eviction.
+type frameCache struct {
+	bits  int // The number of buckets per frame, as a power of 2
+	cache *lru.Cache
+	lock  sync.Mutex
+}
+
+// Bucket returns the items in the given bucket, or an error if the frame
+// containing it is corrupt.
+func (c *frameCache) Bucket(bucket int, frames *ByteArrays) ([]byte, error) {
+	i := bucket >> c.bits
+	c.lock.Lock()
+	hit, ok := c.cache.Get(i)
+	c.lock.Unlock()
+	if !ok {
+		f, err := decompressFrame(frames.Item(i), 1<<c.bits)
+		if err != nil {
+			return nil, fmt.Errorf("corrupt map: frame %d: %w", i, err)
+		}
+		c.lock.Lock()
+		c.cache.Add(i, f)
+		c.lock.Unlock()
+		hit = f
+	}
+	return hit.(*frame).Bucket(bucket & ((1 << c.bits) - 1)), nil
+}
+
+func frameBucketBits(layout *Uint64MapLayout) int {
+	if layout.BucketBits < CompressedFrameBucketBits {
+		return layout.BucketBits
+	}
+	return CompressedFrameBucketBits
+}
+
+// NewCompressedUint64Map returns a map written by WriteCompressedUint64Map,
+// decompressing frames of buckets as they're needed.
+func NewCompressedUint64Map(data []byte) *Uint64Map {
+	m := NewUint64Map(data)
+	m.frames = &frameCache{
+		bits:  frameBucketBits(&m.Layout),
+		cache: lru.New(CompressedFrameCacheSize),
+	}
+	return m
+}
+
+// WriteCompressedUint64Map writes a copy of m with frames of adjacent
+// buckets compressed independently, so a lookup only needs to decompress
+// the frame containing the ID. Frames are compressed twice, once to
+// reserve space, and once to write them, to avoid holding the compressed
+// map in memory. It returns the offset of the end of the map.
+func WriteCompressedUint64Map(m *Uint64Map, w io.WriterAt, offset Offset, goroutines int) (Offset, error) {
+	bits := frameBucketBits(&m.Layout)
+	frames := NewByteArraysBuilder(m.Layout.SentinelBucket() >> bits)
+	err := eachCompressedFrame(m, bits, goroutines, func(i int, compressed []byte) error {
+		if len(compressed) > 0 {
+			frames.Reserve(i, len(compressed))
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var buffer [Uint64MapLayoutLength]byte
+	n := m.Layout.Marshal(buffer[0:])
+	if _, err := w.WriteAt(buffer[0:n], int64(offset)); err != nil {
+		return 0, fmt.Errorf("Failed to write Uint64MapLayout: %s", err)
+	}
+	end, err := frames.WriteHeader(w, offset.Add(n))
+	if err != nil {
+		return 0, err
+	}
+	err = eachCompressedFrame(m, bits, goroutines, func(i int, compressed []byte) error {
+		if len(compressed) > 0 {
+			return frames.WriteItem(w, i, compressed)
+		}
+		return nil
+	})
+	return end, err
+}
+
+func eachCompressedFrame(m *Uint64Map, bits int, goroutines int, f func(i int, compressed []byte) error) error {
+	if goroutines < 1 {
+		goroutines = 1
+	}
+	frames := make(chan int)
+	g, ctx := errgroup.WithContext(context.Background())
+	for i := 0; i < goroutines; i++ {
+		g.Go(func() error {
+			var uncompressed, compressed bytes.Buffer
+			w, err := flate.NewWriter(&compressed, flate.DefaultCompression)
+			if err != nil {
+				return err
+			}
+			var length [binary.MaxVarintLen64]byte
+			for i := range frames {
+				uncompressed.Reset()
+				compressed.Reset()
+				empty := true
+				for bucket := i << bits; bucket < (i+1)<<bits; bucket++ {
+					items, err := m.bucket(bucket)
+					if err != nil {
+						return err
+					}
+					uncompressed.Write(length[0:binary.PutUvarint(length[0:], uint64(len(items)))])
+					empty = empty && len(items) == 0
+				}
+				if !empty {
+					for bucket := i << bits; bucket < (i+1)<<bits; bucket++ {
+						items, err := m.bucket(bucket)
+						if err != nil {
+							return err
+						}
+						uncompressed.Write(items)
+					}
+					w.Reset(&compressed)
+					if _, err := w.Write(uncompressed.Bytes()); err != nil {
+						return err
+					}
+					if err := w.Close(); err != nil {
+						return err
+					}
+				}
+				if err := f(i, compressed.Bytes()); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+done:
+	for i := 0; i < m.Layout.SentinelBucket()>>bits; i++ {
+		select {
+		case frames <- i:
+		case <-ctx.Done():
+			break done
+		}
+	}
+	close(frames)
+	return g.Wait()
+}
@@ -270,9 +270,13 @@ type Uint64MapIterator struct {
 	start  int
 	end    int
 	ids    idsAndTags
+	err    error
 }
 
 func (u *Uint64MapIterator) Next() bool {
+	if u.err != nil {
+		return false
+	}
 	if u.end >= len(u.ids.IDs) {
 		u.end = 0
 		u.start = 0
@@ -281,7 +285,9 @@ func (u *Uint64MapIterator) Next() bool {
 				return false
 			}
 			u.bucket++
-			u.m.fillIDsAndTagged(u.bucket, &u.ids)
+			if u.err = u.m.fillIDsAndTagged(u.bucket, &u.ids); u.err != nil {
+				return false
+			}
 			if len(u.ids.IDs) > 0 {
 				break
 			}
@@ -311,9 +317,15 @@ func (u *Uint64MapIterator) Tag(i int) Tag {
 	return u.ids.Tags[u.start+i].Tag
 }
 
+// Err returns the error that ended iteration early, if the map is corrupt.
+func (u *Uint64MapIterator) Err() error {
+	return u.err
+}
+
 type Uint64Map struct {
 	Layout  Uint64MapLayout
 	buckets *ByteArrays
+	frames  *frameCache // Non-nil if buckets are compressed
 }
 
 func NewUint64Map(data []byte) *Uint64Map {
@@ -331,14 +343,36 @@ func (m *Uint64Map) MaxBucketLength() int {
 	return m.buckets.MaxItemLength()
 }
 
-func (m *Uint64Map) FillTagged(id uint64, tagged []Tagged) []Tagged {
+// IsCompressed returns true if the map's buckets are compressed, see
+// WriteCompressedUint64Map.
+func (m *Uint64Map) IsCompressed() bool {
+	return m.frames != nil
+}
+
+// bucket returns the items in the given bucket, decompressing them if
+// necessary.
+func (m *Uint64Map) bucket(bucket int) ([]byte, error) {
+	if m.frames == nil {
+		return m.buckets.Item(bucket), nil
+	}
+	return m.frames.Bucket(bucket, m.buckets)
+}
+
+func corruptBucketError(bucket int, i int, header *uint64MapBucketHeader, items []byte) error {
+	return fmt.Errorf("corrupt map: bucket %d pos %d header length %d vs bucket length %d", bucket, i, header.Length, len(items))
+}
+
+func (m *Uint64Map) FillTagged(id uint64, tagged []Tagged) ([]Tagged, error) {
 	bucket := m.Layout.BucketForID(id)
-	items := m.buckets.Item(bucket)
+	items, err := m.bucket(bucket)
+	if err != nil {
+		return tagged, err
+	}
 	var header uint64MapBucketHeader
 	for i := 0; i < len(items); {
 		hn := header.Unmarshal(items[i:], bucket, &m.Layout)
 		if i+hn+header.Length > len(items) {
-			panic(fmt.Sprintf("corrupt map: bucket %d pos %d header length %d vs bucket length %d", bucket, i, header.Length, len(items)))
+			return tagged, corruptBucketError(bucket, i, &header, items)
 		}
 		i += hn
 		if header.ID == id {
@@ -346,43 +380,49 @@ func (m *Uint64Map) FillTagged(id uint64, tagged []Tagged) []Tagged {
 		}
 		i += int(header.Length)
 	}
-	return tagged
+	return tagged, nil
 }
 
-func (m *Uint64Map) FindFirstWithTag(id uint64, tag Tag) []byte {
+func (m *Uint64Map) FindFirstWithTag(id uint64, tag Tag) ([]byte, error) {
 	bucket := m.Layout.BucketForID(id)
-	items := m.buckets.Item(bucket)
+	items, err := m.bucket(bucket)
+	if err != nil {
+		return nil, err
+	}
 	var header uint64MapBucketHeader
 	for i := 0; i < len(items); {
 		hn := header.Unmarshal(items[i:], bucket, &m.Layout)
 		if i+hn+header.Length > len(items) {
-			panic(fmt.Sprintf("corrupt map: bucket %d pos %d header length %d vs bucket length %d", bucket, i, header.Length, len(items)))
+			return nil, corruptBucketError(bucket, i, &header, items)
 		}
 		i += hn
 		if header.ID == id && header.Tag == tag {
-			return items[i : i+int(header.Length)]
+			return items[i : i+int(header.Length)], nil
 		}
 		i += int(header.Length)
 	}
-	return nil
+	return nil, nil
 }
 
-func (m *Uint64Map) FindFirst(id uint64) (Tagged, bool) {
+func (m *Uint64Map) FindFirst(id uint64) (Tagged, bool, error) {
 	bucket := m.Layout.BucketForID(id)
-	items := m.buckets.Item(bucket)
+	items, err := m.bucket(bucket)
+	if err != nil {
+		return Tagged{}, false, err
+	}
 	var header uint64MapBucketHeader
 	for i := 0; i < len(items); {
 		hn := header.Unmarshal(items[i:], bucket, &m.Layout)
 		if i+hn+header.Length > len(items) {
-			panic(fmt.Sprintf("corrupt map: bucket %d pos %d header length %d vs bucket length %d", bucket, i, header.Length, len(items)))
+			return Tagged{}, false, corruptBucketError(bucket, i, &header, items)
 		}
 		i += hn
 		if header.ID == id {
-			return Tagged{Tag: header.Tag, Data: items[i : i+int(header.Length)]}, true
+			return Tagged{Tag: header.Tag, Data: items[i : i+int(header.Length)]}, true, nil
 		}
 		i += int(header.Length)
 	}
-	return Tagged{}, false
+	return Tagged{}, false, nil
 }
 
 type idsAndTags struct {
@@ -410,7 +450,9 @@ func (m *Uint64Map) EachItem(f func(id uint64, tagged []Tagged, goroutine int) e
 		}
 		var err error
 		for bucket := range buckets {
-			m.fillIDsAndTagged(bucket, &ids)
+			if err = m.fillIDsAndTagged(bucket, &ids); err != nil {
+				break
+			}
 			if len(ids.IDs) > 0 {
 				start := 0
 				for i := 1; i < len(ids.IDs); i++ {
@@ -439,11 +481,12 @@ func (m *Uint64Map) EachItem(f func(id uint64, tagged []Tagged, goroutine int) e
 	for i := 0; i < goroutines; i++ {
 		go readBuckets(i)
 	}
+done:
 	for bucket := 0; bucket < m.Layout.SentinelBucket(); bucket++ {
 		select {
 		case buckets <- bucket:
 		case <-cancel:
-			break
+			break done
 		}
 	}
 	close(buckets)
@@ -465,15 +508,18 @@ func (m *Uint64Map) Begin() *Uint64MapIterator {
 	}
 }
 
-func (m *Uint64Map) fillIDsAndTagged(bucket int, ids *idsAndTags) {
-	buffer := m.buckets.Item(bucket)
+func (m *Uint64Map) fillIDsAndTagged(bucket int, ids *idsAndTags) error {
 	ids.Tags = ids.Tags[0:0]
 	ids.IDs = ids.IDs[0:0]
+	buffer, err := m.bucket(bucket)
+	if err != nil {
+		return err
+	}
 	var header uint64MapBucketHeader
 	for i := 0; i < len(buffer); {
 		hn := header.Unmarshal(buffer[i:], bucket, &m.Layout)
 		if i+hn+header.Length > len(buffer) {
-			panic(fmt.Sprintf("corrupt map: bucket %d pod %d header length %d vs bucket length %d", bucket, i, header.Length, len(buffer)))
+			return corruptBucketError(bucket, i, &header, buffer)
 		}
 		i += hn
 		ids.IDs = append(ids.IDs, header.ID)
@@ -481,6 +527,7 @@ func (m *Uint64Map) fillIDsAndTagged(bucket int, ids *idsAndTags) {
 		i += int(header.Length)
 	}
 	sort.Sort(ids)
+	return nil
 }
 
 // ComputeHistogram fills histogram with the frequency of items
@@ -492,7 +539,10 @@ func (m *Uint64Map) ComputeHistogram(histogram []int) error {
 		histogram[i] = 0
 	}
 	for bucket := 0; bucket < m.Layout.SentinelBucket(); bucket++ {
-		buffer := m.buckets.Item(bucket)
+		buffer, err := m.bucket(bucket)
+		if err != nil {
+			return err
+		}
 		var header uint64MapBucketHeader
 		items := 0
 		for i := 0; i < len(buffer); {
@@ -68,12 +68,33 @@ func TestUint64Map(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) { test.f(m, ids, t) })
 	}
+
+	var compressed Buffer
+	end, err := WriteCompressedUint64Map(m, &compressed, offset, 2)
+	if err != nil {
+		t.Fatalf("Expected no error from WriteCompressedUint64Map(), found: %s", err)
+	}
+	if _, err := compressed.WriteAt(zeros[0:], int64(end)); err != nil {
+		t.Fatal("Failed to pad output")
+	}
+	c := NewCompressedUint64Map(compressed.Bytes()[offset:])
+	if !c.IsCompressed() || c.Length() != end.Difference(offset) {
+		t.Errorf("Expected compressed map length %d, found %d", end.Difference(offset), c.Length())
+	}
+	if c.Length() >= m.Length() {
+		t.Errorf("Expected compressed map to be smaller, found %d vs %d", c.Length(), m.Length())
+	}
+	for _, test := range tests {
+		t.Run("Compressed"+test.name, func(t *testing.T) { test.f(c, ids, t) })
+	}
 }
 
 func ValidateFillTagged(m *Uint64Map, ids map[uint64]struct{}, t *testing.T) {
 	tagged := make([]Tagged, 0, 1)
-	tagged = m.FillTagged(uint64(camden.VermuteriaNode), tagged)
-	if len(tagged) != 2 {
+	tagged, err := m.FillTagged(uint64(camden.VermuteriaNode), tagged)
+	if err != nil {
+		t.Errorf("Expected no error, found: %s", err)
+	} else if len(tagged) != 2 {
 		t.Errorf("Expected 2 entries, found %d", len(tagged))
 	} else {
 		if tagged[0].Tag > tagged[1].Tag {
@@ -153,6 +174,9 @@ func ValidateIterator(m *Uint64Map, ids map[uint64]struct{}, t *testing.T) {
 	for i.Next() {
 		found[i.ID()] = string(i.Data(0))
 	}
+	if i.Err() != nil {
+		t.Errorf("Expected no error, found: %s", i.Err())
+	}
 
 	if name, ok := found[uint64(camden.VermuteriaNode)]; !ok || name != "Vermuteria" {
 		t.Error("Expected to iterate past Vermuteria")
@@ -160,15 +184,63 @@ func ValidateIterator(m *Uint64Map, ids map[uint64]struct{}, t *testing.T) {
 }
 
 func ValidateFindFirst(m *Uint64Map, ids map[uint64]struct{}, t *testing.T) {
-	v := m.FindFirstWithTag(uint64(camden.VermuteriaNode), TestNameTag)
-	if v == nil || string(v) != "Vermuteria" {
-		t.Errorf("Expected to lookup name for Vermuteria, found %s", v)
+	v, err := m.FindFirstWithTag(uint64(camden.VermuteriaNode), TestNameTag)
+	if err != nil || v == nil || string(v) != "Vermuteria" {
+		t.Errorf("Expected to lookup name for Vermuteria, found %s (error %v)", v, err)
 	}
 
-	tag, ok := m.FindFirst(uint64(camden.VermuteriaNode))
-	if !ok {
+	tag, ok, err := m.FindFirst(uint64(camden.VermuteriaNode))
+	if err != nil {
+		t.Errorf("Expected no error, found: %s", err)
+	} else if !ok {
 		t.Error("Expected to find name or amenity for Vermuteria")
 	} else if (tag.Tag == TestNameTag && string(tag.Data) != "Vermuteria") || (tag.Tag == TestAmenityTag && string(tag.Data) != "cafe") {
 		t.Errorf("Expected to find name or amenity for Vermuteria, found %s", v)
 	}
 }
+
+func TestCompressedUint64MapReturnsErrorsForCorruptFrames(t *testing.T) {
+	names, _ := loadGranarySquareForTests(t)
+
+	builder := NewUint64MapBuilder(10, 1)
+	for id, name := range names {
+		builder.Reserve(uint64(id), TestNameTag, len(name))
+	}
+	var output Buffer
+	builder.WriteHeader(&output, 0)
+	for id, name := range names {
+		if err := builder.WriteItem(uint64(id), TestNameTag, []byte(name), &output); err != nil {
+			t.Fatalf("Expected no error from WriteItem(), found: %s", err)
+		}
+	}
+	var compressed Buffer
+	if _, err := WriteCompressedUint64Map(NewUint64Map(output.Bytes()), &compressed, 0, 2); err != nil {
+		t.Fatalf("Expected no error from WriteCompressedUint64Map(), found: %s", err)
+	}
+
+	c := NewCompressedUint64Map(compressed.Bytes())
+	id := uint64(camden.VermuteriaNode)
+	frame := c.buckets.Item(c.Layout.BucketForID(id) >> c.frames.bits)
+	if len(frame) == 0 {
+		t.Fatal("Expected a non-empty frame")
+	}
+	for i := range frame {
+		frame[i] = 0xff
+	}
+
+	if _, _, err := c.FindFirst(id); err == nil {
+		t.Error("Expected an error from FindFirst()")
+	}
+	if _, err := c.FindFirstWithTag(id, TestNameTag); err == nil {
+		t.Error("Expected an error from FindFirstWithTag()")
+	}
+	if err := c.EachItem(func(uint64, []Tagged, int) error { return nil }, 2); err == nil {
+		t.Error("Expected an error from EachItem()")
+	}
+	i := c.Begin()
+	for i.Next() {
+	}
+	if i.Err() == nil {
+		t.Error("Expected an error from iteration")
+	}
+}
@@ -0,0 +1,243 @@
+package graph
+
+import (
+	"container/heap"
+	"context"
+	"math"
+	"math/rand"
+	"sync"
+
+	"diagonal.works/b6"
+	"golang.org/x/sync/errgroup"
+)
+
+// CentralityOptions controls the computation of centrality measures.
+type CentralityOptions struct {
+	// The maximum weight of the shortest paths considered, or 0 for no
+	// limit. Limiting the radius measures local, rather than global,
+	// centrality, and reduces the time taken.
+	Radius float64
+	// If above 0, the number of points from which shortest paths are
+	// found, chosen at random, with scores scaled to estimate those from
+	// every point. Otherwise, every point is used.
+	Samples int
+	// Seeds the choice of sampled points, making scores reproducible.
+	Seed  int64
+	Cores int
+}
+
+// Centrality holds betweenness and closeness scores for the segments of
+// the street network, as traversed with a Weights.
+type Centrality struct {
+	// Keyed by segments, ignoring direction.
+	Betweenness map[b6.SegmentKey]float64
+	Closeness   map[b6.SegmentKey]float64
+}
+
+// ComputeCentrality returns the betweenness and closeness of every segment
+// of the street network of the given world, using Brandes' algorithm
+// ("A Faster Algorithm for Betweenness Centrality", 2001), extended to
+// count segments rather than points.
+// The betweenness of a segment is the number of shortest paths between
+// ordered pairs of points that pass along it, with paths of equal weight sharing
+// the count. The closeness of a point is the sum of the reciprocals of the
+// weights of the shortest paths to it from every other point (harmonic
+// closeness), and that of a segment the mean of the closeness of its ends.
+func ComputeCentrality(ctx context.Context, weights Weights, options CentralityOptions, w b6.World) (*Centrality, error) {
+	n, err := buildNetwork(weights, w)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make([]int32, len(n.nodes))
+	for i := range sources {
+		sources[i] = int32(i)
+	}
+	scale := 1.0
+	if options.Samples > 0 && options.Samples < len(sources) {
+		r := rand.New(rand.NewSource(options.Seed))
+		r.Shuffle(len(sources), func(i, j int) { sources[i], sources[j] = sources[j], sources[i] })
+		sources = sources[0:options.Samples]
+		scale = float64(len(n.nodes)) / float64(options.Samples)
+	}
+	radius := options.Radius
+	if radius <= 0.0 {
+		radius = math.Inf(1)
+	}
+	cores := options.Cores
+	if cores < 1 {
+		cores = 1
+	}
+
+	var lock sync.Mutex
+	betweenness := make([]float64, len(n.segments))
+	closeness := make([]float64, len(n.nodes))
+	c := make(chan int32)
+	g, gc := errgroup.WithContext(ctx)
+	for i := 0; i < cores; i++ {
+		g.Go(func() error {
+			b := newBrandesSearch(n)
+			for source := range c {
+				b.search(source, radius)
+			}
+			lock.Lock()
+			for i, v := range b.betweenness {
+				betweenness[i] += v
+			}
+			for i, v := range b.closeness {
+				closeness[i] += v
+			}
+			lock.Unlock()
+			return nil
+		})
+	}
+done:
+	for _, source := range sources {
+		select {
+		case <-gc.Done():
+			break done
+		case c <- source:
+		}
+	}
+	close(c)
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	centrality := &Centrality{
+		Betweenness: make(map[b6.SegmentKey]float64, len(n.segments)),
+		Closeness:   make(map[b6.SegmentKey]float64, len(n.segments)),
+	}
+	for from, edges := range n.out {
+		for to, e := range edges {
+			key := n.segments[e.segment]
+			undirected := key
+			if undirected.First > undirected.Last {
+				undirected.First, undirected.Last = undirected.Last, undirected.First
+			}
+			centrality.Betweenness[undirected] += betweenness[e.segment] * scale
+			centrality.Closeness[undirected] = (closeness[from] + closeness[to]) * scale / 2.0
+		}
+	}
+	return centrality, nil
+}
+
+type brandesPredecessor struct {
+	node    int32
+	segment int32
+}
+
+// brandesSearch finds the shortest paths from a point, accumulating their
+// contribution to betweenness and closeness. State is reused between
+// searches to avoid allocation.
+type brandesSearch struct {
+	n            *network
+	distances    []float64
+	sigma        []float64
+	delta        []float64
+	done         []bool
+	predecessors [][]brandesPredecessor
+	settled      []int32
+	touched      []int32
+	queue        nodeQueue
+
+	betweenness []float64
+	closeness   []float64
+}
+
+func newBrandesSearch(n *network) *brandesSearch {
+	b := &brandesSearch{
+		n:            n,
+		distances:    make([]float64, len(n.nodes)),
+		sigma:        make([]float64, len(n.nodes)),
+		delta:        make([]float64, len(n.nodes)),
+		done:         make([]bool, len(n.nodes)),
+		predecessors: make([][]brandesPredecessor, len(n.nodes)),
+		betweenness:  make([]float64, len(n.segments)),
+		closeness:    make([]float64, len(n.nodes)),
+	}
+	for i := range b.distances {
+		b.distances[i] = math.Inf(1)
+	}
+	return b
+}
+
+// The tolerance within which paths are considered to have equal weight.
+const brandesEpsilon = 1e-9
+
+func (b *brandesSearch) search(source int32, radius float64) {
+	for _, v := range b.touched {
+		b.distances[v] = math.Inf(1)
+		b.sigma[v] = 0.0
+		b.delta[v] = 0.0
+		b.done[v] = false
+		b.predecessors[v] = b.predecessors[v][0:0]
+	}
+	b.touched = b.touched[0:0]
+	b.settled = b.settled[0:0]
+	b.queue = b.queue[0:0]
+
+	b.distances[source] = 0.0
+	b.sigma[source] = 1.0
+	b.touched = append(b.touched, source)
+	heap.Push(&b.queue, nodeDistance{node: source, distance: 0.0})
+	for b.queue.Len() > 0 {
+		next := heap.Pop(&b.queue).(nodeDistance)
+		if b.done[next.node] || next.distance > b.distances[next.node] {
+			continue // A stale entry
+		}
+		b.done[next.node] = true
+		b.settled = append(b.settled, next.node)
+		if next.node != source && next.distance > 0.0 {
+			b.closeness[next.node] += 1.0 / next.distance
+		}
+		for to, e := range b.n.out[next.node] {
+			d := next.distance + e.weight
+			if d > radius {
+				continue
+			}
+			current := b.distances[to]
+			if d < current-brandesEpsilon {
+				if math.IsInf(current, 1) {
+					b.touched = append(b.touched, to)
+				}
+				b.distances[to] = d
+				b.sigma[to] = b.sigma[next.node]
+				b.predecessors[to] = append(b.predecessors[to][0:0], brandesPredecessor{node: next.node, segment: e.segment})
+				heap.Push(&b.queue, nodeDistance{node: to, distance: d})
+			} else if !b.done[to] && math.Abs(d-current) <= brandesEpsilon {
+				b.sigma[to] += b.sigma[next.node]
+				b.predecessors[to] = append(b.predecessors[to], brandesPredecessor{node: next.node, segment: e.segment})
+			}
+		}
+	}
+
+	// Accumulate dependencies in order of decreasing distance from the
+	// source.
+	for i := len(b.settled) - 1; i >= 0; i-- {
+		v := b.settled[i]
+		for _, p := range b.predecessors[v] {
+			c := b.sigma[p.node] / b.sigma[v] * (1.0 + b.delta[v])
+			b.betweenness[p.segment] += c
+			b.delta[p.node] += c
+		}
+	}
+}
+
+// ByPath returns the betweenness and closeness of each path, being the
+// highest score of any of its segments.
+func (c *Centrality) ByPath() (map[b6.FeatureID]float64, map[b6.FeatureID]float64) {
+	byPath := func(scores map[b6.SegmentKey]float64) map[b6.FeatureID]float64 {
+		paths := make(map[b6.FeatureID]float64)
+		for key, score := range scores {
+			if existing, ok := paths[key.ID]; !ok || score > existing {
+				paths[key.ID] = score
+			}
+		}
+		return paths
+	}
+	return byPath(c.Betweenness), byPath(c.Closeness)
+}
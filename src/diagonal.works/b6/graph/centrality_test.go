@@ -0,0 +1,88 @@
+package graph
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/ingest"
+	"diagonal.works/b6/osm"
+)
+
+// buildBarbellWorld returns a world with two triangles of footways, joined
+// by a single footway, way 20, between nodes 3 and 4.
+func buildBarbellWorld(t *testing.T) b6.World {
+	t.Helper()
+	nodes := []osm.Node{
+		{ID: 1, Location: osm.LatLng{Lat: 51.5405, Lng: -0.1410}},
+		{ID: 2, Location: osm.LatLng{Lat: 51.5395, Lng: -0.1410}},
+		{ID: 3, Location: osm.LatLng{Lat: 51.5400, Lng: -0.1400}},
+		{ID: 4, Location: osm.LatLng{Lat: 51.5400, Lng: -0.1380}},
+		{ID: 5, Location: osm.LatLng{Lat: 51.5405, Lng: -0.1370}},
+		{ID: 6, Location: osm.LatLng{Lat: 51.5395, Lng: -0.1370}},
+	}
+	footway := []osm.Tag{{Key: "highway", Value: "footway"}}
+	ways := []osm.Way{
+		{ID: 10, Nodes: []osm.NodeID{1, 2}, Tags: footway},
+		{ID: 11, Nodes: []osm.NodeID{2, 3}, Tags: footway},
+		{ID: 12, Nodes: []osm.NodeID{3, 1}, Tags: footway},
+		{ID: 20, Nodes: []osm.NodeID{3, 4}, Tags: footway},
+		{ID: 30, Nodes: []osm.NodeID{4, 5}, Tags: footway},
+		{ID: 31, Nodes: []osm.NodeID{5, 6}, Tags: footway},
+		{ID: 32, Nodes: []osm.NodeID{6, 4}, Tags: footway},
+	}
+	w, err := ingest.BuildWorldFromOSM(nodes, ways, []osm.Relation{}, &ingest.BuildOptions{Cores: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func TestCentrality(t *testing.T) {
+	w := buildBarbellWorld(t)
+	c, err := ComputeCentrality(context.Background(), SimpleHighwayWeights{}, CentralityOptions{Cores: 2}, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Betweenness) != 7 {
+		t.Fatalf("Expected scores for 7 segments, found %d", len(c.Betweenness))
+	}
+	betweenness, closeness := c.ByPath()
+	bridge := ingest.FromOSMWayID(20)
+	// Every path between the 3 nodes of one triangle, and the 3 of the
+	// other, in either direction, crosses the bridge.
+	if math.Abs(betweenness[bridge]-18.0) > 1e-6 {
+		t.Errorf("Expected betweenness of 18 for the bridge, found %f", betweenness[bridge])
+	}
+	for id, score := range betweenness {
+		if id != bridge && score >= betweenness[bridge] {
+			t.Errorf("Expected bridge to have the highest betweenness, found %f for %s", score, id)
+		}
+		if id != bridge && closeness[id] >= closeness[bridge] {
+			t.Errorf("Expected bridge to have the highest closeness, found %f for %s", closeness[id], id)
+		}
+	}
+
+	sampled, err := ComputeCentrality(context.Background(), SimpleHighwayWeights{}, CentralityOptions{Samples: 6, Seed: 42, Cores: 2}, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, score := range c.Betweenness {
+		if math.Abs(sampled.Betweenness[key]-score) > 1e-6 {
+			t.Errorf("Expected sampling every point to match, found %f vs %f", sampled.Betweenness[key], score)
+		}
+	}
+
+	// With a radius just beyond the length of the bridge, only paths
+	// between its ends, and their immediate neighbours, cross it.
+	radius := b6.AngleToMeters(w.FindFeatureByID(bridge).(b6.PhysicalFeature).Polyline().Length()) * 1.2
+	local, err := ComputeCentrality(context.Background(), SimpleHighwayWeights{}, CentralityOptions{Radius: radius, Cores: 2}, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	betweenness, _ = local.ByPath()
+	if betweenness[bridge] <= 0.0 || betweenness[bridge] >= 18.0 {
+		t.Errorf("Expected reduced betweenness for the bridge with a radius, found %f", betweenness[bridge])
+	}
+}
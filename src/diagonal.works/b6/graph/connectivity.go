@@ -402,6 +402,12 @@ func (c *Connections) EachAddedPath(emit ingest.Emit) error {
 
 func (c *Connections) Change(w b6.World) ingest.Change {
 	change := &ingest.AddFeatures{}
+	// Inserted points need to be added before the paths that reference them
+	f := func(id b6.FeatureID, ll s2.LatLng) error {
+		*change = append(*change, &ingest.GenericFeature{ID: id, Tags: []b6.Tag{{Key: b6.PointTag, Value: b6.NewPointExpressionFromLatLng(ll)}}})
+		return nil
+	}
+	c.EachInsertedPoint(f, w)
 	id := b6.FeatureIDInvalid
 	for _, insertion := range c.insertions {
 		if insertion.PathID != id {
@@ -411,11 +417,6 @@ func (c *Connections) Change(w b6.World) ingest.Change {
 			}
 		}
 	}
-	f := func(id b6.FeatureID, ll s2.LatLng) error {
-		*change = append(*change, &ingest.GenericFeature{ID: id, Tags: []b6.Tag{{Key: b6.PointTag, Value: b6.NewPointExpressionFromLatLng(ll)}}})
-		return nil
-	}
-	c.EachInsertedPoint(f, w)
 	ff := func(f ingest.Feature, _ int) error {
 		*change = append(*change, f.Clone())
 		return nil
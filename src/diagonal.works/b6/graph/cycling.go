@@ -0,0 +1,203 @@
+package graph
+
+import (
+	"strings"
+
+	"diagonal.works/b6"
+)
+
+const CyclingMetersPerSecond = 15000.0 / 3600
+
+// TrafficStress is the level of traffic stress (LTS) experienced by a
+// cyclist on a path, from 1, suitable for children, to 4, tolerated only
+// by confident cyclists. See Mekuria, Furth and Nixon, "Low-Stress
+// Bicycling and Network Connectivity", 2012.
+type TrafficStress int
+
+const (
+	TrafficStressLowest  TrafficStress = 1
+	TrafficStressLow     TrafficStress = 2
+	TrafficStressHigh    TrafficStress = 3
+	TrafficStressHighest TrafficStress = 4
+)
+
+// Factors applied to the time taken to cycle along a path, reflecting a
+// preference for routes with lower traffic stress.
+var trafficStressPenalties = map[TrafficStress]float64{
+	TrafficStressLowest:  1.0,
+	TrafficStressLow:     1.15,
+	TrafficStressHigh:    1.5,
+	TrafficStressHighest: 2.5,
+}
+
+// Factors applied to cycling speed on surfaces other than tarmac.
+var surfaceSpeedFactors = map[string]float64{
+	"compacted":   0.85,
+	"fine_gravel": 0.85,
+	"sett":        0.75,
+	"cobblestone": 0.75,
+	"gravel":      0.6,
+	"unpaved":     0.6,
+	"dirt":        0.6,
+	"ground":      0.6,
+	"grass":       0.6,
+	"mud":         0.6,
+	"sand":        0.6,
+}
+
+// Roads carrying little through traffic, that are comfortable for
+// cyclists at low speeds.
+func isMinorRoad(highway string) bool {
+	switch highway {
+	case "residential", "service", "unclassified", "living_street", "road":
+		return true
+	}
+	return false
+}
+
+func isBicycleAllowed(path b6.Feature) bool {
+	switch path.Get("bicycle").Value.String() {
+	case "yes", "designated", "permissive":
+		return true
+	}
+	return false
+}
+
+func hasPaintedCycleLane(path b6.Feature) bool {
+	for _, key := range []string{"cycleway", "cycleway:left", "cycleway:right", "cycleway:both"} {
+		switch path.Get(key).Value.String() {
+		case "lane", "shared_lane", "share_busway":
+			return true
+		}
+	}
+	return false
+}
+
+func hasSeparatedCycleTrack(path b6.Feature) bool {
+	for _, key := range []string{"cycleway", "cycleway:left", "cycleway:right", "cycleway:both"} {
+		if path.Get(key).Value.String() == "track" {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDismountRequired returns true if cyclists are expected to walk
+// their bicycle along the given path.
+func IsDismountRequired(path b6.Feature) bool {
+	if path.Get("bicycle").Value.String() == "dismount" {
+		return true
+	}
+	switch path.Get("#highway").Value.String() {
+	case "footway", "pedestrian", "steps":
+		return !isBicycleAllowed(path)
+	}
+	return false
+}
+
+// CyclingTrafficStress returns the level of traffic stress of cycling
+// along the given path, based on the presence of cycling infrastructure,
+// and the speed of other traffic.
+func CyclingTrafficStress(path b6.Feature) TrafficStress {
+	if path.Get("diagonal").Value.String() == "connection" || IsDismountRequired(path) {
+		return TrafficStressLowest
+	}
+	highway := path.Get("#highway").Value.String()
+	switch highway {
+	case "cycleway", "path", "bridleway", "track", "footway", "pedestrian", "living_street":
+		return TrafficStressLowest
+	}
+	if hasSeparatedCycleTrack(path) {
+		return TrafficStressLowest
+	}
+	speed := MaxSpeed(path)
+	if hasPaintedCycleLane(path) {
+		if speed <= 50.0*KilometersPerHourToMetersPerSecond {
+			return TrafficStressLow
+		} else if speed <= 65.0*KilometersPerHourToMetersPerSecond {
+			return TrafficStressHigh
+		}
+		return TrafficStressHighest
+	}
+	if speed <= 20.0*MilesPerHourToMetersPerSecond {
+		if isMinorRoad(highway) {
+			return TrafficStressLowest
+		}
+		return TrafficStressLow
+	} else if speed <= 30.0*MilesPerHourToMetersPerSecond {
+		if isMinorRoad(highway) {
+			return TrafficStressLow
+		}
+		return TrafficStressHigh
+	}
+	return TrafficStressHighest
+}
+
+func IsPathUsableByBicycle(path b6.Feature) bool {
+	if path.Get("diagonal").Value.String() == "connection" {
+		return true
+	}
+	highway := path.Get("#highway")
+	if !highway.IsValid() {
+		return false
+	}
+	switch highway.Value.String() {
+	case "motorway", "motorway_link", "proposed", "construction", "raceway":
+		return false
+	}
+	if path.Get("bicycle").Value.String() == "no" {
+		return false
+	}
+	switch path.Get("access").Value.String() {
+	case "no", "private":
+		return isBicycleAllowed(path)
+	}
+	return true
+}
+
+func IsSegmentUseableInThisDirectionByBicycle(segment b6.Segment) bool {
+	if oneway := segment.Feature.Get("oneway"); oneway.Value.String() != "yes" {
+		return true
+	}
+	if oneway := segment.Feature.Get("oneway:bicycle"); oneway.Value.String() == "no" {
+		return true
+	}
+	if strings.HasPrefix(segment.Feature.Get("cycleway").Value.String(), "opposite") {
+		return true
+	}
+	if IsDismountRequired(segment.Feature) {
+		return true // Pedestrians aren't restricted by oneway
+	}
+	return segment.Last > segment.First
+}
+
+// CyclingWeights are the time in seconds taken to cycle along a segment,
+// penalised by its level of traffic stress. Paths where cyclists are
+// expected to dismount are traversed at walking speed.
+type CyclingWeights struct {
+	Speed float64 // Meters per second, CyclingMetersPerSecond if 0
+	// Paths with a higher level of traffic stress aren't used. All paths
+	// are used if 0.
+	MaxStress TrafficStress
+}
+
+func (c CyclingWeights) IsUseable(segment b6.Segment) bool {
+	if !IsPathUsableByBicycle(segment.Feature) || !IsSegmentUseableInThisDirectionByBicycle(segment) {
+		return false
+	}
+	return c.MaxStress == 0 || CyclingTrafficStress(segment.Feature) <= c.MaxStress
+}
+
+func (c CyclingWeights) Weight(segment b6.Segment) float64 {
+	if segment.Feature.Get("diagonal").Value.String() == "connection" || IsDismountRequired(segment.Feature) {
+		return weightFromSegment(segment) / WalkingMetersPerSecond
+	}
+	speed := c.Speed
+	if speed <= 0.0 {
+		speed = CyclingMetersPerSecond
+	}
+	if factor, ok := surfaceSpeedFactors[segment.Feature.Get("surface").Value.String()]; ok {
+		speed *= factor
+	}
+	return weightFromSegment(segment) / speed * trafficStressPenalties[CyclingTrafficStress(segment.Feature)]
+}
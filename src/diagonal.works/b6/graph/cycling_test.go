@@ -0,0 +1,148 @@
+package graph
+
+import (
+	"math"
+	"testing"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/ingest"
+	"diagonal.works/b6/osm"
+)
+
+func TestParseMaxSpeed(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected float64
+		ok       bool
+	}{
+		{"50", 50.0 * KilometersPerHourToMetersPerSecond, true},
+		{"50 km/h", 50.0 * KilometersPerHourToMetersPerSecond, true},
+		{"20 mph", 20.0 * MilesPerHourToMetersPerSecond, true},
+		{"30mph", 30.0 * MilesPerHourToMetersPerSecond, true},
+		{"GB:nsl_single", 60.0 * MilesPerHourToMetersPerSecond, true},
+		{"walk", WalkingMetersPerSecond, true},
+		{"none", 0.0, false},
+		{"signals", 0.0, false},
+		{"", 0.0, false},
+	}
+	for _, test := range tests {
+		speed, ok := ParseMaxSpeed(test.value)
+		if ok != test.ok || math.Abs(speed-test.expected) > 1e-9 {
+			t.Errorf("Expected %f, %v for %q, found %f, %v", test.expected, test.ok, test.value, speed, ok)
+		}
+	}
+}
+
+func TestCyclingTrafficStress(t *testing.T) {
+	tests := []struct {
+		name     string
+		tags     []osm.Tag
+		expected TrafficStress
+	}{
+		{"cycleway", []osm.Tag{{Key: "highway", Value: "cycleway"}}, TrafficStressLowest},
+		{"20mph residential", []osm.Tag{{Key: "highway", Value: "residential"}, {Key: "maxspeed", Value: "20 mph"}}, TrafficStressLowest},
+		{"30mph residential", []osm.Tag{{Key: "highway", Value: "residential"}, {Key: "maxspeed", Value: "30 mph"}}, TrafficStressLow},
+		{"30mph primary", []osm.Tag{{Key: "highway", Value: "primary"}, {Key: "maxspeed", Value: "30 mph"}}, TrafficStressHigh},
+		{"30mph primary with lane", []osm.Tag{{Key: "highway", Value: "primary"}, {Key: "maxspeed", Value: "30 mph"}, {Key: "cycleway:left", Value: "lane"}}, TrafficStressLow},
+		{"primary with track", []osm.Tag{{Key: "highway", Value: "primary"}, {Key: "cycleway", Value: "track"}}, TrafficStressLowest},
+		{"trunk", []osm.Tag{{Key: "highway", Value: "trunk"}}, TrafficStressHighest},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := buildSingleWayWorld(t, test.tags)
+			path := w.FindFeatureByID(ingest.FromOSMWayID(1))
+			if path == nil {
+				t.Fatal("Failed to find way")
+			}
+			if stress := CyclingTrafficStress(path); stress != test.expected {
+				t.Errorf("Expected stress %d, found %d", test.expected, stress)
+			}
+		})
+	}
+}
+
+func TestCyclingWeightsUseability(t *testing.T) {
+	tests := []struct {
+		name     string
+		tags     []osm.Tag
+		forwards bool
+		reverse  bool
+	}{
+		{"residential", []osm.Tag{{Key: "highway", Value: "residential"}}, true, true},
+		{"motorway", []osm.Tag{{Key: "highway", Value: "motorway"}}, false, false},
+		{"bicycle=no", []osm.Tag{{Key: "highway", Value: "primary"}, {Key: "bicycle", Value: "no"}}, false, false},
+		{"private", []osm.Tag{{Key: "highway", Value: "service"}, {Key: "access", Value: "private"}}, false, false},
+		{"private, bicycle=yes", []osm.Tag{{Key: "highway", Value: "service"}, {Key: "access", Value: "private"}, {Key: "bicycle", Value: "yes"}}, true, true},
+		{"oneway", []osm.Tag{{Key: "highway", Value: "residential"}, {Key: "oneway", Value: "yes"}}, true, false},
+		{"oneway:bicycle=no", []osm.Tag{{Key: "highway", Value: "residential"}, {Key: "oneway", Value: "yes"}, {Key: "oneway:bicycle", Value: "no"}}, true, true},
+		{"contraflow", []osm.Tag{{Key: "highway", Value: "residential"}, {Key: "oneway", Value: "yes"}, {Key: "cycleway", Value: "opposite_lane"}}, true, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := buildSingleWayWorld(t, test.tags)
+			path, ok := w.FindFeatureByID(ingest.FromOSMWayID(1)).(b6.PhysicalFeature)
+			if !ok {
+				t.Fatal("Failed to find way")
+			}
+			forwards := b6.ToSegment(path)
+			reverse := b6.Segment{Feature: forwards.Feature, First: forwards.Last, Last: forwards.First}
+			if u := (CyclingWeights{}).IsUseable(forwards); u != test.forwards {
+				t.Errorf("Expected useable=%v forwards, found %v", test.forwards, u)
+			}
+			if u := (CyclingWeights{}).IsUseable(reverse); u != test.reverse {
+				t.Errorf("Expected useable=%v in reverse, found %v", test.reverse, u)
+			}
+		})
+	}
+}
+
+func TestCyclingPrefersLowStressRoutes(t *testing.T) {
+	nodes := []osm.Node{
+		{ID: 7799663850, Location: osm.LatLng{Lat: 51.5409703, Lng: -0.1376308}},
+		{ID: 5336117979, Location: osm.LatLng{Lat: 51.5416858, Lng: -0.1382541}},
+		{ID: 4931754288, Location: osm.LatLng{Lat: 51.5416379, Lng: -0.1382604}},
+	}
+	ways := []osm.Way{
+		{ID: 835622320, Nodes: []osm.NodeID{7799663850, 5336117979}, Tags: []osm.Tag{{Key: "highway", Value: "primary"}, {Key: "maxspeed", Value: "30 mph"}}},
+		{ID: 835622319, Nodes: []osm.NodeID{7799663850, 4931754288, 5336117979}, Tags: []osm.Tag{{Key: "highway", Value: "cycleway"}}},
+	}
+	w, err := ingest.BuildWorldFromOSM(nodes, ways, []osm.Relation{}, &ingest.BuildOptions{Cores: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	from := ingest.FromOSMNodeID(7799663850)
+	to := ingest.FromOSMNodeID(5336117979)
+	if path := ComputeShortestPath(from, to, 500.0, SimpleWeights{}, w); len(path) != 1 || path[0].Feature.FeatureID().Value != uint64(ways[0].ID) {
+		t.Error("Expected shortest path by distance to use road")
+	}
+	if path := ComputeShortestPath(from, to, 500.0, CyclingWeights{}, w); len(path) != 1 || path[0].Feature.FeatureID().Value != uint64(ways[1].ID) {
+		t.Error("Expected cycling path to use cycleway")
+	}
+
+	road := b6.ToSegment(w.FindFeatureByID(ingest.FromOSMWayID(ways[0].ID)).(b6.PhysicalFeature))
+	if (CyclingWeights{MaxStress: TrafficStressLow}).IsUseable(road) {
+		t.Error("Expected road to be unusable with a maximum stress of 2")
+	}
+	car := CarTimeWeights{}.Weight(road)
+	if expected := b6.AngleToMeters(road.Polyline().Length()) / (30.0 * MilesPerHourToMetersPerSecond); math.Abs(car-expected) > 1e-6 {
+		t.Errorf("Expected car weight %f, found %f", expected, car)
+	}
+	if bus := (BusTimeWeights{}).Weight(road); bus != car {
+		t.Errorf("Expected bus weight to match car weight below the bus maximum, found %f vs %f", bus, car)
+	}
+}
+
+func buildSingleWayWorld(t *testing.T, tags []osm.Tag) b6.World {
+	t.Helper()
+	nodes := []osm.Node{
+		{ID: 1, Location: osm.LatLng{Lat: 51.5409703, Lng: -0.1376308}},
+		{ID: 2, Location: osm.LatLng{Lat: 51.5416858, Lng: -0.1382541}},
+	}
+	ways := []osm.Way{{ID: 1, Nodes: []osm.NodeID{1, 2}, Tags: tags}}
+	w, err := ingest.BuildWorldFromOSM(nodes, ways, []osm.Relation{}, &ingest.BuildOptions{Cores: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
@@ -0,0 +1,294 @@
+package graph
+
+import (
+	"sort"
+
+	"diagonal.works/b6"
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// lengthWeights allows traversal of the paths useable with the given
+// Weights in either direction, weighted by their length in meters, to
+// measure distances along the network regardless of the mode of
+// transport.
+type lengthWeights struct {
+	Weights Weights
+}
+
+func (l lengthWeights) IsUseable(segment b6.Segment) bool {
+	return isUseableInEitherDirection(segment, l.Weights)
+}
+
+func (l lengthWeights) Weight(segment b6.Segment) float64 {
+	return b6.AngleToMeters(segment.Polyline().Length())
+}
+
+// components implements union-find over feature IDs.
+type components map[b6.FeatureID]b6.FeatureID
+
+func (c components) find(id b6.FeatureID) b6.FeatureID {
+	root := id
+	for {
+		if parent, ok := c[root]; ok && parent != root {
+			root = parent
+		} else {
+			break
+		}
+	}
+	for id != root {
+		next := c[id]
+		c[id] = root
+		id = next
+	}
+	return root
+}
+
+func (c components) union(a b6.FeatureID, b b6.FeatureID) {
+	if ra, rb := c.find(a), c.find(b); ra != rb {
+		if rb.Less(ra) {
+			ra, rb = rb, ra
+		}
+		c[rb] = ra
+	}
+}
+
+func isUseablePath(f b6.Feature, weights Weights) (b6.PhysicalFeature, bool) {
+	if path, ok := f.(b6.PhysicalFeature); ok && path.GeometryType() == b6.GeometryTypePath && path.GeometryLen() > 1 {
+		return path, isUseableInEitherDirection(b6.ToSegment(path), weights)
+	}
+	return nil, false
+}
+
+// NetworkDiagnosticsOptions controls the problems reported by
+// DiagnoseNetwork.
+type NetworkDiagnosticsOptions struct {
+	// Dead ends closer than this to another path are reported as gaps.
+	GapThreshold s1.Angle
+	// Paths that can be reached from a dead end by travelling less than
+	// this distance along the network aren't considered to be separated
+	// from it by a gap. Defaults to ten times GapThreshold.
+	MaxDetour s1.Angle
+}
+
+// NetworkGap is a dead end that's close to a path it isn't connected to,
+// typically as the result of a mapping error.
+type NetworkGap struct {
+	DeadEnd b6.FeatureID
+	// The projection of the dead end onto the closest path.
+	Access *Projection
+}
+
+// NetworkDiagnostics describes problems with the street network that
+// commonly break routing.
+type NetworkDiagnostics struct {
+	// The connected component of every useable path, numbered from 0 in
+	// decreasing order of the number of paths they contain. Paths are
+	// connected if they share a point, ignoring the direction in which
+	// they can be traversed.
+	Components map[b6.FeatureID]int
+	// The number of paths in each component.
+	ComponentSizes []int
+	// Points at the end of only one useable segment, ordered by ID.
+	DeadEnds []b6.FeatureID
+	// Gaps between dead ends and nearby paths, ordered by dead end.
+	Gaps []NetworkGap
+	// Points tagged with entrance that aren't part of any useable path,
+	// ordered by ID.
+	UnconnectedEntrances []b6.FeatureID
+}
+
+// NetworkComponents returns the connected component of every path
+// useable with the given weights, as described in NetworkDiagnostics,
+// together with the number of paths in each.
+func NetworkComponents(weights Weights, w b6.World) (map[b6.FeatureID]int, []int, error) {
+	c := make(components)
+	paths := make([]b6.FeatureID, 0)
+	each := func(f b6.Feature, goroutine int) error {
+		if path, ok := isUseablePath(f, weights); ok {
+			paths = append(paths, path.FeatureID())
+			for i := 0; i < path.GeometryLen(); i++ {
+				if id := path.Reference(i).Source(); id.IsValid() {
+					c.union(path.FeatureID(), id)
+				}
+			}
+		}
+		return nil
+	}
+	options := b6.EachFeatureOptions{
+		SkipPoints:      true,
+		SkipAreas:       true,
+		SkipRelations:   true,
+		SkipCollections: true,
+		SkipExpressions: true,
+		Goroutines:      1,
+	}
+	if err := w.EachFeature(each, &options); err != nil {
+		return nil, nil, err
+	}
+
+	sizes := make(map[b6.FeatureID]int)
+	for _, id := range paths {
+		sizes[c.find(id)]++
+	}
+	roots := make([]b6.FeatureID, 0, len(sizes))
+	for root := range sizes {
+		roots = append(roots, root)
+	}
+	sort.Slice(roots, func(i, j int) bool {
+		if sizes[roots[i]] == sizes[roots[j]] {
+			return roots[i].Less(roots[j])
+		}
+		return sizes[roots[i]] > sizes[roots[j]]
+	})
+	indices := make(map[b6.FeatureID]int, len(roots))
+	ordered := make([]int, len(roots))
+	for i, root := range roots {
+		indices[root] = i
+		ordered[i] = sizes[root]
+	}
+	byPath := make(map[b6.FeatureID]int, len(paths))
+	for _, id := range paths {
+		byPath[id] = indices[c.find(id)]
+	}
+	return byPath, ordered, nil
+}
+
+// DiagnoseNetwork returns the connected components, dead ends, gaps and
+// unconnected entrances of the street network of the given world, as
+// traversed with the given weights.
+func DiagnoseNetwork(weights Weights, options NetworkDiagnosticsOptions, w b6.World) (*NetworkDiagnostics, error) {
+	if options.MaxDetour <= 0 {
+		options.MaxDetour = options.GapThreshold * 10
+	}
+	d := &NetworkDiagnostics{}
+	var err error
+	if d.Components, d.ComponentSizes, err = NetworkComponents(weights, w); err != nil {
+		return nil, err
+	}
+
+	degrees := make(map[b6.FeatureID]int)
+	for id := range d.Components {
+		path := w.FindFeatureByID(id).(b6.PhysicalFeature)
+		for i := 0; i < path.GeometryLen(); i++ {
+			if id := path.Reference(i).Source(); id.IsValid() {
+				if i > 0 {
+					degrees[id]++
+				}
+				if i < path.GeometryLen()-1 {
+					degrees[id]++
+				}
+			}
+		}
+	}
+	for id, degree := range degrees {
+		if degree == 1 {
+			d.DeadEnds = append(d.DeadEnds, id)
+		}
+	}
+	sort.Slice(d.DeadEnds, func(i, j int) bool { return d.DeadEnds[i].Less(d.DeadEnds[j]) })
+
+	if options.GapThreshold > 0 {
+		gaps := make(map[b6.FeatureID]NetworkGap)
+		for _, id := range d.DeadEnds {
+			if gap, ok := findGap(id, weights, options, w); ok {
+				// Avoid bridging the same gap twice, when two dead ends
+				// face each other.
+				duplicate := false
+				for i := 0; i < gap.Access.Feature.GeometryLen(); i++ {
+					if other, ok := gaps[gap.Access.Feature.Reference(i).Source()]; ok && referencesPoint(other.Access.Feature, id) {
+						duplicate = true
+						break
+					}
+				}
+				if !duplicate {
+					gaps[id] = gap
+					d.Gaps = append(d.Gaps, gap)
+				}
+			}
+		}
+	}
+
+	each := func(f b6.Feature, goroutine int) error {
+		if point, ok := f.(b6.PhysicalFeature); ok && point.Get("entrance").IsValid() {
+			if _, ok := degrees[point.FeatureID()]; !ok {
+				d.UnconnectedEntrances = append(d.UnconnectedEntrances, point.FeatureID())
+			}
+		}
+		return nil
+	}
+	points := b6.EachFeatureOptions{
+		SkipPaths:       true,
+		SkipAreas:       true,
+		SkipRelations:   true,
+		SkipCollections: true,
+		SkipExpressions: true,
+		Goroutines:      1,
+	}
+	if err := w.EachFeature(each, &points); err != nil {
+		return nil, err
+	}
+	sort.Slice(d.UnconnectedEntrances, func(i, j int) bool { return d.UnconnectedEntrances[i].Less(d.UnconnectedEntrances[j]) })
+	return d, nil
+}
+
+func referencesPoint(path b6.PhysicalFeature, id b6.FeatureID) bool {
+	for i := 0; i < path.GeometryLen(); i++ {
+		if path.Reference(i).Source() == id {
+			return true
+		}
+	}
+	return false
+}
+
+// findGap returns the projection of the given dead end onto the closest
+// useable path within the gap threshold, ignoring paths that can be
+// reached from it along the network within the maximum detour.
+func findGap(id b6.FeatureID, weights Weights, options NetworkDiagnosticsOptions, w b6.World) (NetworkGap, bool) {
+	point, ok := w.FindFeatureByID(id).(b6.PhysicalFeature)
+	if !ok {
+		return NetworkGap{}, false
+	}
+	cap := s2.CapFromCenterAngle(point.Point(), options.GapThreshold)
+	paths := w.FindFeatures(b6.Typed{Type: b6.FeatureTypePath, Query: b6.MightIntersect{Region: cap}})
+	var reached map[b6.FeatureID]float64
+	candidates := make([]candidate, 0, 4)
+	for paths.Next() {
+		path, ok := isUseablePath(paths.Feature(), weights)
+		if !ok || referencesPoint(path, id) {
+			continue
+		}
+		if reached == nil {
+			lw := lengthWeights{Weights: weights}
+			s := NewShortestPathSearchFromPoint(id, lw, w)
+			s.ExpandSearch(b6.AngleToMeters(options.MaxDetour), lw, Points, w)
+			reached = s.PointDistances()
+		}
+		nearby := false
+		for i := 0; i < path.GeometryLen(); i++ {
+			if _, ok := reached[path.Reference(i).Source()]; ok {
+				nearby = true
+				break
+			}
+		}
+		if !nearby {
+			candidates = append(candidates, candidate{Feature: path, Polyline: path.Polyline()})
+		}
+	}
+	if access := closestCandidate(point.Point(), candidates); access.Distance < options.GapThreshold {
+		return NetworkGap{DeadEnd: id, Access: access}, true
+	}
+	return NetworkGap{}, false
+}
+
+// FixGaps returns the paths and points that connect each dead end to the
+// path on the other side of its gap. Points added to paths within the
+// cluster threshold of existing points are merged with them.
+func (d *NetworkDiagnostics) FixGaps(clusterThreshold s1.Angle, w b6.World) *Connections {
+	s := InsertNewPointsIntoPaths{Connections: NewConnections(), World: w, ClusterThreshold: clusterThreshold}
+	for _, gap := range d.Gaps {
+		s.ConnectPoint(gap.DeadEnd, gap.DeadEnd, gap.Access, 0)
+	}
+	s.Finish()
+	return s.Connections
+}
@@ -0,0 +1,104 @@
+package graph
+
+import (
+	"testing"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/ingest"
+	"diagonal.works/b6/osm"
+)
+
+// buildDisconnectedWorld returns a world with a street network of ways
+// 10, 11 and 13, where way 13 ends close to way 10, but is connected to it
+// via node 2. Way 20 starts around 2m from the end of way 11, without
+// being connected to it, and way 30 is far away from both. Nodes 1 and 8
+// are entrances, with only node 1 connected to the network.
+func buildDisconnectedWorld(t *testing.T) b6.World {
+	t.Helper()
+	nodes := []osm.Node{
+		{ID: 1, Location: osm.LatLng{Lat: 51.54000, Lng: -0.14000}, Tags: []osm.Tag{{Key: "entrance", Value: "yes"}}},
+		{ID: 2, Location: osm.LatLng{Lat: 51.54000, Lng: -0.13942}},
+		{ID: 3, Location: osm.LatLng{Lat: 51.54000, Lng: -0.13884}},
+		{ID: 4, Location: osm.LatLng{Lat: 51.54000, Lng: -0.13881}},
+		{ID: 5, Location: osm.LatLng{Lat: 51.54000, Lng: -0.13823}},
+		{ID: 6, Location: osm.LatLng{Lat: 51.54500, Lng: -0.14000}},
+		{ID: 7, Location: osm.LatLng{Lat: 51.54500, Lng: -0.13900}},
+		{ID: 8, Location: osm.LatLng{Lat: 51.54100, Lng: -0.14000}, Tags: []osm.Tag{{Key: "entrance", Value: "yes"}}},
+		{ID: 14, Location: osm.LatLng{Lat: 51.54003, Lng: -0.13971}},
+	}
+	footway := []osm.Tag{{Key: "highway", Value: "footway"}}
+	ways := []osm.Way{
+		{ID: 10, Nodes: []osm.NodeID{1, 2}, Tags: footway},
+		{ID: 11, Nodes: []osm.NodeID{2, 3}, Tags: footway},
+		{ID: 13, Nodes: []osm.NodeID{2, 14}, Tags: footway},
+		{ID: 20, Nodes: []osm.NodeID{4, 5}, Tags: footway},
+		{ID: 30, Nodes: []osm.NodeID{6, 7}, Tags: footway},
+	}
+	w, err := ingest.BuildWorldFromOSM(nodes, ways, []osm.Relation{}, &ingest.BuildOptions{Cores: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func TestDiagnoseNetwork(t *testing.T) {
+	w := buildDisconnectedWorld(t)
+	options := NetworkDiagnosticsOptions{GapThreshold: b6.MetersToAngle(5.0)}
+	d, err := DiagnoseNetwork(SimpleHighwayWeights{}, options, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(d.ComponentSizes) != 3 || d.ComponentSizes[0] != 3 {
+		t.Errorf("Expected 3 components, the largest with 3 paths, found %v", d.ComponentSizes)
+	}
+	for _, id := range []osm.WayID{10, 11, 13} {
+		if c := d.Components[ingest.FromOSMWayID(id)]; c != 0 {
+			t.Errorf("Expected way %d in the largest component, found %d", id, c)
+		}
+	}
+	if d.Components[ingest.FromOSMWayID(20)] == d.Components[ingest.FromOSMWayID(30)] {
+		t.Error("Expected ways 20 and 30 in different components")
+	}
+
+	expected := map[b6.FeatureID]struct{}{}
+	for _, id := range []osm.NodeID{1, 3, 4, 5, 6, 7, 14} {
+		expected[ingest.FromOSMNodeID(id)] = struct{}{}
+	}
+	if len(d.DeadEnds) != len(expected) {
+		t.Errorf("Expected %d dead ends, found %d", len(expected), len(d.DeadEnds))
+	}
+	for _, id := range d.DeadEnds {
+		if _, ok := expected[id]; !ok {
+			t.Errorf("Didn't expect %s to be a dead end", id)
+		}
+	}
+
+	// Nodes 3 and 4 face each other across the same gap, which should only
+	// be reported once, while node 14 can be reached from way 10 via node 2.
+	if len(d.Gaps) != 1 {
+		t.Fatalf("Expected 1 gap, found %d", len(d.Gaps))
+	}
+	if d.Gaps[0].DeadEnd != ingest.FromOSMNodeID(3) || d.Gaps[0].Access.Feature.FeatureID() != ingest.FromOSMWayID(20) {
+		t.Errorf("Expected a gap between node 3 and way 20, found %s and %s", d.Gaps[0].DeadEnd, d.Gaps[0].Access.Feature.FeatureID())
+	}
+
+	if len(d.UnconnectedEntrances) != 1 || d.UnconnectedEntrances[0] != ingest.FromOSMNodeID(8) {
+		t.Errorf("Expected only node 8 to be an unconnected entrance, found %v", d.UnconnectedEntrances)
+	}
+
+	m := ingest.NewMutableOverlayWorld(w)
+	if _, err := d.FixGaps(b6.MetersToAngle(4.0), w).Change(w).Apply(m); err != nil {
+		t.Fatal(err)
+	}
+	components, sizes, err := NetworkComponents(SimpleHighwayWeights{}, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sizes) != 2 {
+		t.Errorf("Expected 2 components after fixing gaps, found %v", sizes)
+	}
+	if components[ingest.FromOSMWayID(11)] != components[ingest.FromOSMWayID(20)] {
+		t.Error("Expected ways 11 and 20 to be connected after fixing gaps")
+	}
+}
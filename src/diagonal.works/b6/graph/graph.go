@@ -144,6 +144,107 @@ func (CarWeights) Weight(segment b6.Segment) float64 {
 	return weightFromSegment(segment)
 }
 
+const MilesPerHourToMetersPerSecond = 1609.344 / (60.0 * 60.0)
+const KilometersPerHourToMetersPerSecond = 1000.0 / (60.0 * 60.0)
+
+// Speeds assumed for roads without a maxspeed tag, in km/h, broadly
+// following UK limits.
+var DefaultSpeedsByHighway = map[string]float64{
+	"motorway":       112.0,
+	"motorway_link":  64.0,
+	"trunk":          96.0,
+	"trunk_link":     64.0,
+	"primary":        64.0,
+	"primary_link":   48.0,
+	"secondary":      48.0,
+	"secondary_link": 48.0,
+	"tertiary":       48.0,
+	"tertiary_link":  48.0,
+	"unclassified":   48.0,
+	"residential":    32.0,
+	"busway":         48.0,
+	"road":           32.0,
+	"service":        16.0,
+	"living_street":  16.0,
+}
+
+const DefaultSpeed = 32.0 // km/h, for roads with an unknown highway value
+
+// Maximum speeds implied by values for maxspeed that aren't numbers, in
+// km/h, see https://wiki.openstreetmap.org/wiki/Key:maxspeed
+var implicitMaxSpeeds = map[string]float64{
+	"walk":          WalkingMetersPerSecond / KilometersPerHourToMetersPerSecond,
+	"GB:nsl_single": 60.0 * MilesPerHourToMetersPerSecond / KilometersPerHourToMetersPerSecond,
+	"GB:nsl_dual":   70.0 * MilesPerHourToMetersPerSecond / KilometersPerHourToMetersPerSecond,
+	"GB:motorway":   70.0 * MilesPerHourToMetersPerSecond / KilometersPerHourToMetersPerSecond,
+	"GB:urban":      30.0 * MilesPerHourToMetersPerSecond / KilometersPerHourToMetersPerSecond,
+}
+
+// ParseMaxSpeed returns the speed in meters per second given by an OSM
+// maxspeed value, for example 50, 30 mph or GB:nsl_single, and false if
+// the value doesn't specify a speed, for example none or signals.
+func ParseMaxSpeed(value string) (float64, bool) {
+	value = strings.TrimSpace(value)
+	if kmh, ok := implicitMaxSpeeds[value]; ok {
+		return kmh * KilometersPerHourToMetersPerSecond, true
+	}
+	factor := KilometersPerHourToMetersPerSecond
+	if v, ok := strings.CutSuffix(value, "mph"); ok {
+		value, factor = strings.TrimSpace(v), MilesPerHourToMetersPerSecond
+	} else if v, ok := strings.CutSuffix(value, "km/h"); ok {
+		value = strings.TrimSpace(v)
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil && f > 0.0 {
+		return f * factor, true
+	}
+	return 0.0, false
+}
+
+// MaxSpeed returns the speed limit of the given path in meters per second,
+// from its maxspeed tag if present, otherwise assuming a default for its
+// highway type.
+func MaxSpeed(path b6.Feature) float64 {
+	if speed, ok := ParseMaxSpeed(path.Get("maxspeed").Value.String()); ok {
+		return speed
+	}
+	if kmh, ok := DefaultSpeedsByHighway[path.Get("#highway").Value.String()]; ok {
+		return kmh * KilometersPerHourToMetersPerSecond
+	}
+	return DefaultSpeed * KilometersPerHourToMetersPerSecond
+}
+
+// CarTimeWeights are the time in seconds taken to drive along a segment
+// at its speed limit.
+type CarTimeWeights struct{}
+
+func (CarTimeWeights) IsUseable(segment b6.Segment) bool {
+	return CarWeights{}.IsUseable(segment)
+}
+
+func (CarTimeWeights) Weight(segment b6.Segment) float64 {
+	if segment.Feature.Get("diagonal").Value.String() == "connection" {
+		return weightFromSegment(segment) / WalkingMetersPerSecond
+	}
+	return weightFromSegment(segment) / MaxSpeed(segment.Feature)
+}
+
+const BusMaxMetersPerSecond = 80.0 * KilometersPerHourToMetersPerSecond
+
+// BusTimeWeights are the time in seconds taken for a bus to travel along
+// a segment at its speed limit, or BusMaxMetersPerSecond if that's lower.
+type BusTimeWeights struct{}
+
+func (BusTimeWeights) IsUseable(segment b6.Segment) bool {
+	return BusWeights{}.IsUseable(segment)
+}
+
+func (BusTimeWeights) Weight(segment b6.Segment) float64 {
+	if segment.Feature.Get("diagonal").Value.String() == "connection" {
+		return weightFromSegment(segment) / WalkingMetersPerSecond
+	}
+	return weightFromSegment(segment) / math.Min(MaxSpeed(segment.Feature), BusMaxMetersPerSecond)
+}
+
 func IsPathUsableByPedestrian(path b6.Feature) bool {
 	// Taken from the table here:
 	// https://wiki.openstreetmap.org/wiki/OSM_tags_for_routing/Access_restrictions#United_Kingdom
@@ -507,7 +608,27 @@ func (s *ShortestPathSearch) ExpandSearchTo(to b6.FeatureID, maxDistance float64
 }
 
 func (s *ShortestPathSearch) ExpandSearch(maxDistance float64, weights Weights, features ShortestPathFeatures, w b6.World) {
+	s.ExpandSearchWithCheck(maxDistance, weights, features, w, nil)
+}
+
+// The number of points expanded by ExpandSearchWithCheck between calls
+// to check.
+const CheckInterval = 1024
+
+// ExpandSearchWithCheck expands the search in the same way as ExpandSearch,
+// but calls check with the number of points expanded since the last call
+// after every CheckInterval points, and when the search is complete. The
+// search is abandoned if check returns an error. A nil check is never
+// called.
+func (s *ShortestPathSearch) ExpandSearchWithCheck(maxDistance float64, weights Weights, features ShortestPathFeatures, w b6.World, check func(expanded int) error) error {
+	expanded := 0
 	for s.Len() > 0 {
+		if expanded++; check != nil && expanded == CheckInterval {
+			if err := check(expanded); err != nil {
+				return err
+			}
+			expanded = 0
+		}
 		r := heap.Pop(s).(*reachable)
 		s.byPoint[r.point].visited = true
 		ss := w.Traverse(r.point)
@@ -529,6 +650,10 @@ func (s *ShortestPathSearch) ExpandSearch(maxDistance float64, weights Weights,
 			}
 		}
 	}
+	if check != nil {
+		return check(expanded)
+	}
+	return nil
 }
 
 func (s *ShortestPathSearch) BuildRoute(destination b6.FeatureID) b6.Route {
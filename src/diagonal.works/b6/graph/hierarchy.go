@@ -0,0 +1,844 @@
+package graph
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/ingest"
+)
+
+// hierarchyEdge is an edge in a contraction hierarchy, either a segment of
+// a path from the world, or a shortcut replacing two edges that meet at a
+// contracted node.
+type hierarchyEdge struct {
+	to int32
+	// The node contracted to create a shortcut, or -1 for a segment.
+	middle int32
+	// The index of the segment within the hierarchy, or -1 for a shortcut.
+	segment int32
+	weight  float64
+}
+
+// ContractionHierarchy allows the shortest paths between points to be
+// found considerably faster than with ShortestPathSearch, for a given
+// Weights, by preprocessing the street network. Nodes are contracted in
+// order of importance, adding shortcuts that preserve the shortest paths
+// between the remaining nodes. Searches then only need to follow edges to
+// more important nodes. See Geisberger et al, "Contraction Hierarchies:
+// Faster and Simpler Hierarchical Routing in Road Networks", 2008.
+//
+// A hierarchy remains valid for the world it was built for as long as the
+// world isn't modified along the segments it includes, see IsValidFor.
+type ContractionHierarchy struct {
+	// Describes the weights with which the hierarchy was built, typically
+	// the options passed to graph functions.
+	Weights string
+
+	nodes    []b6.FeatureID
+	byID     map[b6.FeatureID]int32
+	segments []b6.SegmentKey
+	paths    map[b6.FeatureID]struct{}
+	// Edges to nodes contracted later, for searches forwards from an
+	// origin.
+	up [][]hierarchyEdge
+	// Edges from nodes contracted later, reversed, for searches backwards
+	// from a destination.
+	down [][]hierarchyEdge
+
+	// The world for which the hierarchy was built, and its version, if
+	// mutable.
+	base        b6.World
+	baseVersion uint64
+
+	lock  sync.Mutex
+	valid map[uint64]bool
+}
+
+// The maximum number of nodes settled while searching for a path that
+// makes a shortcut unnecessary. Lower values make building faster, at the
+// cost of unnecessary shortcuts.
+const witnessSearchLimit = 256
+
+// BuildContractionHierarchy returns a contraction hierarchy for the paths
+// of the given world, as traversed with the given weights. description is
+// recorded as the hierarchy's Weights.
+func BuildContractionHierarchy(weights Weights, description string, w b6.World) (*ContractionHierarchy, error) {
+	n, err := buildNetwork(weights, w)
+	if err != nil {
+		return nil, err
+	}
+	h := &ContractionHierarchy{
+		Weights:  description,
+		nodes:    n.nodes,
+		byID:     n.byID,
+		segments: n.segments,
+		paths:    make(map[b6.FeatureID]struct{}),
+	}
+	for _, s := range n.segments {
+		h.paths[s.ID] = struct{}{}
+	}
+	c := newContractor(n.out, n.in())
+	c.contract()
+	h.up, h.down = c.up, c.down
+	h.attach(w)
+	return h, nil
+}
+
+type contractor struct {
+	out, in    []map[int32]hierarchyEdge
+	contracted []bool
+	// The number of contracted neighbours of each node, used to spread
+	// contraction evenly across the network.
+	neighbours []int
+	up, down   [][]hierarchyEdge
+
+	witness witnessSearch
+}
+
+func newContractor(out, in []map[int32]hierarchyEdge) *contractor {
+	return &contractor{
+		out:        out,
+		in:         in,
+		contracted: make([]bool, len(out)),
+		neighbours: make([]int, len(out)),
+		up:         make([][]hierarchyEdge, len(out)),
+		down:       make([][]hierarchyEdge, len(out)),
+		witness:    newWitnessSearch(len(out)),
+	}
+}
+
+// shortcuts calls add for each shortcut needed if v were contracted,
+// returning the number needed.
+func (c *contractor) shortcuts(v int32, add func(from int32, e hierarchyEdge)) int {
+	n := 0
+	maxOut := 0.0
+	for _, e := range c.out[v] {
+		maxOut = math.Max(maxOut, e.weight)
+	}
+	for u, in := range c.in[v] {
+		c.witness.search(u, v, in.weight+maxOut, c.out)
+		for x, out := range c.out[v] {
+			if x == u {
+				continue
+			}
+			via := in.weight + out.weight
+			if c.witness.distance(x) <= via {
+				continue
+			}
+			if existing, ok := c.out[u][x]; ok && existing.weight <= via {
+				continue
+			}
+			n++
+			if add != nil {
+				add(u, hierarchyEdge{to: x, middle: v, segment: -1, weight: via})
+			}
+		}
+	}
+	return n
+}
+
+// priority returns the order in which v should be contracted relative to
+// other nodes, with lower values first. Nodes that need few shortcuts
+// relative to the edges they remove are contracted first.
+func (c *contractor) priority(v int32) int {
+	return c.shortcuts(v, nil) - len(c.in[v]) - len(c.out[v]) + c.neighbours[v]
+}
+
+func (c *contractor) contract() {
+	queue := make(contractionQueue, 0, len(c.out))
+	for v := range c.out {
+		queue = append(queue, contractionPriority{node: int32(v), priority: c.priority(int32(v))})
+	}
+	heap.Init(&queue)
+	added := make([]struct {
+		from int32
+		edge hierarchyEdge
+	}, 0)
+	for queue.Len() > 0 {
+		next := heap.Pop(&queue).(contractionPriority)
+		// Priorities change as neighbours are contracted, so recompute
+		// lazily, contracting only if the node is still the most
+		// appropriate.
+		if p := c.priority(next.node); queue.Len() > 0 && p > queue[0].priority {
+			heap.Push(&queue, contractionPriority{node: next.node, priority: p})
+			continue
+		}
+		v := next.node
+		added = added[0:0]
+		c.shortcuts(v, func(from int32, e hierarchyEdge) {
+			added = append(added, struct {
+				from int32
+				edge hierarchyEdge
+			}{from: from, edge: e})
+		})
+		for _, a := range added {
+			if existing, ok := c.out[a.from][a.edge.to]; !ok || a.edge.weight < existing.weight {
+				c.out[a.from][a.edge.to] = a.edge
+				c.in[a.edge.to][a.from] = hierarchyEdge{to: a.from, middle: a.edge.middle, segment: a.edge.segment, weight: a.edge.weight}
+			}
+		}
+		for x, e := range c.out[v] {
+			c.up[v] = append(c.up[v], e)
+			delete(c.in[x], v)
+			c.neighbours[x]++
+		}
+		for u, e := range c.in[v] {
+			c.down[v] = append(c.down[v], e)
+			delete(c.out[u], v)
+			c.neighbours[u]++
+		}
+		c.out[v], c.in[v] = nil, nil
+		c.contracted[v] = true
+	}
+}
+
+type contractionPriority struct {
+	node     int32
+	priority int
+}
+
+type contractionQueue []contractionPriority
+
+func (c contractionQueue) Len() int { return len(c) }
+func (c contractionQueue) Less(i, j int) bool {
+	if c[i].priority == c[j].priority {
+		return c[i].node < c[j].node
+	}
+	return c[i].priority < c[j].priority
+}
+func (c contractionQueue) Swap(i, j int)       { c[i], c[j] = c[j], c[i] }
+func (c *contractionQueue) Push(x interface{}) { *c = append(*c, x.(contractionPriority)) }
+func (c *contractionQueue) Pop() interface{} {
+	old := *c
+	n := len(old)
+	x := old[n-1]
+	*c = old[0 : n-1]
+	return x
+}
+
+// witnessSearch finds the distances from a node to its neighbours without
+// passing through the node being contracted, to determine which shortcuts
+// are needed. State is reused between searches to avoid allocation.
+type witnessSearch struct {
+	distances []float64
+	touched   []int32
+	queue     nodeQueue
+}
+
+func newWitnessSearch(n int) witnessSearch {
+	w := witnessSearch{distances: make([]float64, n)}
+	for i := range w.distances {
+		w.distances[i] = math.Inf(1)
+	}
+	return w
+}
+
+func (w *witnessSearch) distance(v int32) float64 {
+	return w.distances[v]
+}
+
+func (w *witnessSearch) search(from int32, excluding int32, maxWeight float64, out []map[int32]hierarchyEdge) {
+	for _, v := range w.touched {
+		w.distances[v] = math.Inf(1)
+	}
+	w.touched = w.touched[0:0]
+	w.queue = w.queue[0:0]
+	w.distances[from] = 0.0
+	w.touched = append(w.touched, from)
+	heap.Push(&w.queue, nodeDistance{node: from, distance: 0.0})
+	settled := 0
+	for w.queue.Len() > 0 && settled < witnessSearchLimit {
+		next := heap.Pop(&w.queue).(nodeDistance)
+		if next.distance > w.distances[next.node] {
+			continue // A stale entry
+		} else if next.distance > maxWeight {
+			break
+		}
+		settled++
+		for to, e := range out[next.node] {
+			if to == excluding {
+				continue
+			}
+			if d := next.distance + e.weight; d < w.distances[to] {
+				if math.IsInf(w.distances[to], 1) {
+					w.touched = append(w.touched, to)
+				}
+				w.distances[to] = d
+				heap.Push(&w.queue, nodeDistance{node: to, distance: d})
+			}
+		}
+	}
+}
+
+type nodeDistance struct {
+	node     int32
+	distance float64
+}
+
+type nodeQueue []nodeDistance
+
+func (n nodeQueue) Len() int            { return len(n) }
+func (n nodeQueue) Less(i, j int) bool  { return n[i].distance < n[j].distance }
+func (n nodeQueue) Swap(i, j int)       { n[i], n[j] = n[j], n[i] }
+func (n *nodeQueue) Push(x interface{}) { *n = append(*n, x.(nodeDistance)) }
+func (n *nodeQueue) Pop() interface{} {
+	old := *n
+	l := len(old)
+	x := old[l-1]
+	*n = old[0 : l-1]
+	return x
+}
+
+// Contains returns true if the given point is a node in the hierarchy.
+func (h *ContractionHierarchy) Contains(point b6.FeatureID) bool {
+	_, ok := h.byID[point]
+	return ok
+}
+
+// Len returns the number of nodes in the hierarchy.
+func (h *ContractionHierarchy) Len() int {
+	return len(h.nodes)
+}
+
+// Shortcuts returns the number of shortcuts added while building the
+// hierarchy.
+func (h *ContractionHierarchy) Shortcuts() int {
+	n := 0
+	for _, edges := range h.up {
+		for _, e := range edges {
+			if e.middle >= 0 {
+				n++
+			}
+		}
+	}
+	for _, edges := range h.down {
+		for _, e := range edges {
+			if e.middle >= 0 {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+type hierarchyParent struct {
+	node int32
+	edge hierarchyEdge
+}
+
+// upwardSearch settles nodes reachable from origin via the given edges,
+// all of which lead to nodes contracted later.
+type upwardSearch struct {
+	edges     [][]hierarchyEdge
+	distances map[int32]float64
+	parents   map[int32]hierarchyParent
+	queue     nodeQueue
+}
+
+func newUpwardSearch(origin int32, edges [][]hierarchyEdge) *upwardSearch {
+	s := &upwardSearch{
+		edges:     edges,
+		distances: map[int32]float64{origin: 0.0},
+		parents:   make(map[int32]hierarchyParent),
+	}
+	heap.Push(&s.queue, nodeDistance{node: origin, distance: 0.0})
+	return s
+}
+
+func (s *upwardSearch) min() float64 {
+	if s.queue.Len() == 0 {
+		return math.Inf(1)
+	}
+	return s.queue[0].distance
+}
+
+// step settles the next node, returning false if the search is complete.
+func (s *upwardSearch) step(maxWeight float64) (nodeDistance, bool) {
+	for s.queue.Len() > 0 {
+		next := heap.Pop(&s.queue).(nodeDistance)
+		if next.distance > s.distances[next.node] {
+			continue
+		}
+		for _, e := range s.edges[next.node] {
+			d := next.distance + e.weight
+			if d > maxWeight {
+				continue
+			}
+			if current, ok := s.distances[e.to]; !ok || d < current {
+				s.distances[e.to] = d
+				s.parents[e.to] = hierarchyParent{node: next.node, edge: e}
+				heap.Push(&s.queue, nodeDistance{node: e.to, distance: d})
+			}
+		}
+		return next, true
+	}
+	return nodeDistance{}, false
+}
+
+func (s *upwardSearch) run(maxWeight float64) {
+	for {
+		if _, ok := s.step(maxWeight); !ok {
+			return
+		}
+	}
+}
+
+// ShortestRoute returns the shortest route between the given points, and
+// false if there's no route with a weight below maxWeight, or either point
+// isn't part of the hierarchy.
+func (h *ContractionHierarchy) ShortestRoute(from b6.FeatureID, to b6.FeatureID, maxWeight float64) (b6.Route, bool) {
+	f, ok := h.byID[from]
+	if !ok {
+		return b6.Route{}, false
+	}
+	t, ok := h.byID[to]
+	if !ok {
+		return b6.Route{}, false
+	}
+	route := b6.Route{Origin: from}
+	if f == t {
+		return route, true
+	}
+
+	forwards, backwards := newUpwardSearch(f, h.up), newUpwardSearch(t, h.down)
+	best, meeting := math.Inf(1), int32(-1)
+	for math.Min(forwards.min(), backwards.min()) < math.Min(best, maxWeight) {
+		for _, s := range []*upwardSearch{forwards, backwards} {
+			if s.min() >= best {
+				continue
+			}
+			other := backwards
+			if s == backwards {
+				other = forwards
+			}
+			if next, ok := s.step(maxWeight); ok {
+				if d, ok := other.distances[next.node]; ok && next.distance+d < best {
+					best, meeting = next.distance+d, next.node
+				}
+			}
+		}
+	}
+	if meeting < 0 || best >= maxWeight {
+		return b6.Route{}, false
+	}
+
+	// Edges from the origin to the meeting node, followed by those from the
+	// meeting node to the destination, in the direction of travel.
+	edges := make([]hierarchyParent, 0)
+	for n := meeting; n != f; {
+		p := forwards.parents[n]
+		edges = append(edges, hierarchyParent{node: p.node, edge: p.edge})
+		n = p.node
+	}
+	for i := 0; i < len(edges)/2; i++ {
+		edges[i], edges[len(edges)-1-i] = edges[len(edges)-1-i], edges[i]
+	}
+	for n := meeting; n != t; {
+		p := backwards.parents[n]
+		// The edge is stored reversed, from p.node to n, but represents
+		// travel from n to p.node.
+		edges = append(edges, hierarchyParent{node: n, edge: hierarchyEdge{to: p.node, middle: p.edge.middle, segment: p.edge.segment, weight: p.edge.weight}})
+		n = p.node
+	}
+
+	cost := 0.0
+	for _, e := range edges {
+		for _, s := range h.unpack(e.node, e.edge, nil) {
+			cost += s.edge.weight
+			route.Steps = append(route.Steps, b6.Step{
+				Destination: h.nodes[s.edge.to],
+				Via:         h.segments[s.edge.segment].ID,
+				Cost:        cost,
+			})
+		}
+	}
+	return route, true
+}
+
+// unpack appends the segments represented by the edge from the given node
+// to segments, replacing shortcuts with the edges they were created from.
+func (h *ContractionHierarchy) unpack(from int32, e hierarchyEdge, segments []hierarchyParent) []hierarchyParent {
+	if e.middle < 0 {
+		return append(segments, hierarchyParent{node: from, edge: e})
+	}
+	// The middle node was contracted first, so its edge from the origin is
+	// stored in its down edges, and its edge to the destination in its up
+	// edges.
+	var first, second hierarchyEdge
+	first.weight, second.weight = math.Inf(1), math.Inf(1)
+	for _, d := range h.down[e.middle] {
+		if d.to == from && d.weight < first.weight {
+			first = hierarchyEdge{to: e.middle, middle: d.middle, segment: d.segment, weight: d.weight}
+		}
+	}
+	for _, u := range h.up[e.middle] {
+		if u.to == e.to && u.weight < second.weight {
+			second = u
+		}
+	}
+	segments = h.unpack(from, first, segments)
+	return h.unpack(e.middle, second, segments)
+}
+
+// Distances returns the weight of the shortest path from each origin to
+// each destination, or +Inf if there's no path with a weight below
+// maxWeight, or either point isn't part of the hierarchy. Distances are
+// indexed by origin, then destination.
+func (h *ContractionHierarchy) Distances(origins []b6.FeatureID, destinations []b6.FeatureID, maxWeight float64) [][]float64 {
+	type bucketEntry struct {
+		destination int
+		distance    float64
+	}
+	buckets := make(map[int32][]bucketEntry)
+	for i, id := range destinations {
+		if t, ok := h.byID[id]; ok {
+			s := newUpwardSearch(t, h.down)
+			s.run(maxWeight)
+			for n, d := range s.distances {
+				buckets[n] = append(buckets[n], bucketEntry{destination: i, distance: d})
+			}
+		}
+	}
+	distances := make([][]float64, len(origins))
+	for i, id := range origins {
+		distances[i] = make([]float64, len(destinations))
+		for j := range distances[i] {
+			distances[i][j] = math.Inf(1)
+		}
+		if f, ok := h.byID[id]; ok {
+			s := newUpwardSearch(f, h.up)
+			s.run(maxWeight)
+			for n, d := range s.distances {
+				for _, b := range buckets[n] {
+					if total := d + b.distance; total < distances[i][b.destination] && total < maxWeight {
+						distances[i][b.destination] = total
+					}
+				}
+			}
+		}
+	}
+	return distances
+}
+
+// Attach associates a hierarchy read with ReadContractionHierarchy with the
+// world from which it was built, returning an error if the world doesn't
+// contain the points and paths it includes.
+func (h *ContractionHierarchy) Attach(w b6.World) error {
+	for _, id := range h.nodes {
+		if !w.HasFeatureWithID(id) {
+			return fmt.Errorf("point %s from hierarchy not found in world", id)
+		}
+	}
+	for id := range h.paths {
+		if !w.HasFeatureWithID(id) {
+			return fmt.Errorf("path %s from hierarchy not found in world", id)
+		}
+	}
+	h.attach(w)
+	return nil
+}
+
+func (h *ContractionHierarchy) attach(w b6.World) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.base = w
+	if m, ok := w.(ingest.MutableWorld); ok {
+		h.baseVersion = m.Version()
+	}
+	h.valid = nil
+}
+
+// IsValidFor returns true if the hierarchy can be used to find shortest
+// paths in the given world, which must be the world it was built for,
+// optionally with mutable layers above it. The hierarchy is valid if
+// none of the modifications made by those layers affect the paths or
+// points it includes.
+func (h *ContractionHierarchy) IsValidFor(w b6.World) bool {
+	m, ok := w.(ingest.MutableWorld)
+	if !ok {
+		return h.isValidFor(w)
+	}
+	version := m.Version()
+	h.lock.Lock()
+	valid, ok := h.valid[version]
+	h.lock.Unlock()
+	if ok {
+		return valid
+	}
+	valid = h.isValidFor(w)
+	h.lock.Lock()
+	if h.valid == nil || len(h.valid) > 1024 {
+		h.valid = make(map[uint64]bool)
+	}
+	h.valid[version] = valid
+	h.lock.Unlock()
+	return valid
+}
+
+var errModifiedAlongHierarchy = errors.New("modified along hierarchy")
+
+func (h *ContractionHierarchy) isValidFor(w b6.World) bool {
+	if w == h.base {
+		if m, ok := w.(ingest.MutableWorld); ok {
+			return m.Version() == h.baseVersion
+		}
+		return true
+	}
+	l, ok := w.(ingest.LayeredWorld)
+	if !ok {
+		return false // A world other than the one the hierarchy was built for
+	}
+	m, ok := w.(ingest.MutableWorld)
+	if !ok {
+		// Overlays of other worlds can change the network in ways we can't
+		// cheaply determine.
+		return false
+	}
+	affects := func(id b6.FeatureID) bool {
+		switch id.Type {
+		case b6.FeatureTypePath:
+			_, ok := h.paths[id]
+			return ok
+		case b6.FeatureTypePoint:
+			if _, ok := h.byID[id]; ok {
+				return true
+			}
+			paths := w.FindReferences(id, b6.FeatureTypePath)
+			for paths.Next() {
+				if _, ok := h.paths[paths.FeatureID()]; ok {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	features := func(f b6.Feature, goroutine int) error {
+		if affects(f.FeatureID()) {
+			return errModifiedAlongHierarchy
+		}
+		if path, ok := f.(b6.PhysicalFeature); ok && f.FeatureID().Type == b6.FeatureTypePath {
+			// New paths connecting to the network change it.
+			for i := 0; i < path.GeometryLen(); i++ {
+				if _, ok := h.byID[path.Reference(i).Source()]; ok {
+					return errModifiedAlongHierarchy
+				}
+			}
+		}
+		return nil
+	}
+	tags := func(t ingest.ModifiedTag, goroutine int) error {
+		if affects(t.ID) {
+			return errModifiedAlongHierarchy
+		}
+		return nil
+	}
+	options := b6.EachFeatureOptions{Goroutines: 1}
+	if err := m.EachModifiedFeature(features, &options); err != nil {
+		return false
+	}
+	if err := m.EachModifiedTag(tags, &options); err != nil {
+		return false
+	}
+	for _, layer := range l.Layers() {
+		if !h.isValidFor(layer) {
+			return false
+		}
+	}
+	return true
+}
+
+const (
+	hierarchyMagic   = "b6ch"
+	hierarchyVersion = 1
+)
+
+type hierarchyWriter struct {
+	w          *bufio.Writer
+	namespaces map[b6.Namespace]uint64
+	buffer     []byte
+	err        error
+}
+
+func (w *hierarchyWriter) uvarint(v uint64) {
+	if w.err == nil {
+		w.buffer = binary.AppendUvarint(w.buffer[0:0], v)
+		_, w.err = w.w.Write(w.buffer)
+	}
+}
+
+func (w *hierarchyWriter) string(s string) {
+	w.uvarint(uint64(len(s)))
+	if w.err == nil {
+		_, w.err = w.w.WriteString(s)
+	}
+}
+
+func (w *hierarchyWriter) id(id b6.FeatureID) {
+	w.uvarint(uint64(id.Type))
+	w.uvarint(w.namespaces[id.Namespace])
+	w.uvarint(id.Value)
+}
+
+func (w *hierarchyWriter) edges(edges [][]hierarchyEdge) {
+	for _, es := range edges {
+		w.uvarint(uint64(len(es)))
+		for _, e := range es {
+			w.uvarint(uint64(e.to))
+			w.uvarint(uint64(e.middle + 1))
+			w.uvarint(uint64(e.segment + 1))
+			w.uvarint(math.Float64bits(e.weight))
+		}
+	}
+}
+
+// Write writes the hierarchy in a form that can be read with
+// ReadContractionHierarchy.
+func (h *ContractionHierarchy) Write(output io.Writer) error {
+	w := hierarchyWriter{w: bufio.NewWriter(output), namespaces: make(map[b6.Namespace]uint64)}
+	namespaces := make([]b6.Namespace, 0)
+	for _, id := range h.nodes {
+		if _, ok := w.namespaces[id.Namespace]; !ok {
+			w.namespaces[id.Namespace] = uint64(len(namespaces))
+			namespaces = append(namespaces, id.Namespace)
+		}
+	}
+	for _, s := range h.segments {
+		if _, ok := w.namespaces[s.ID.Namespace]; !ok {
+			w.namespaces[s.ID.Namespace] = uint64(len(namespaces))
+			namespaces = append(namespaces, s.ID.Namespace)
+		}
+	}
+	if _, err := w.w.WriteString(hierarchyMagic); err != nil {
+		return err
+	}
+	w.uvarint(hierarchyVersion)
+	w.string(h.Weights)
+	w.uvarint(uint64(len(namespaces)))
+	for _, ns := range namespaces {
+		w.string(ns.String())
+	}
+	w.uvarint(uint64(len(h.nodes)))
+	for _, id := range h.nodes {
+		w.id(id)
+	}
+	w.uvarint(uint64(len(h.segments)))
+	for _, s := range h.segments {
+		w.id(s.ID)
+		w.uvarint(uint64(s.First))
+		w.uvarint(uint64(s.Last))
+	}
+	w.edges(h.up)
+	w.edges(h.down)
+	if w.err != nil {
+		return w.err
+	}
+	return w.w.Flush()
+}
+
+type hierarchyReader struct {
+	r          *bufio.Reader
+	namespaces []b6.Namespace
+	err        error
+}
+
+func (r *hierarchyReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	var v uint64
+	v, r.err = binary.ReadUvarint(r.r)
+	return v
+}
+
+func (r *hierarchyReader) string() string {
+	n := r.uvarint()
+	if r.err != nil {
+		return ""
+	}
+	buffer := make([]byte, n)
+	_, r.err = io.ReadFull(r.r, buffer)
+	return string(buffer)
+}
+
+func (r *hierarchyReader) id() b6.FeatureID {
+	t := b6.FeatureType(r.uvarint())
+	ns := r.uvarint()
+	value := r.uvarint()
+	if r.err == nil && ns >= uint64(len(r.namespaces)) {
+		r.err = fmt.Errorf("bad namespace %d", ns)
+		return b6.FeatureIDInvalid
+	}
+	if r.err != nil {
+		return b6.FeatureIDInvalid
+	}
+	return b6.FeatureID{Type: t, Namespace: r.namespaces[ns], Value: value}
+}
+
+func (r *hierarchyReader) edges(n int, segments int) [][]hierarchyEdge {
+	edges := make([][]hierarchyEdge, n)
+	for i := range edges {
+		edges[i] = make([]hierarchyEdge, r.uvarint())
+		for j := range edges[i] {
+			e := hierarchyEdge{
+				to:      int32(r.uvarint()),
+				middle:  int32(r.uvarint()) - 1,
+				segment: int32(r.uvarint()) - 1,
+				weight:  math.Float64frombits(r.uvarint()),
+			}
+			if r.err == nil && (e.to < 0 || int(e.to) >= n || int(e.middle) >= n || int(e.segment) >= segments) {
+				r.err = fmt.Errorf("bad edge from node %d", i)
+			}
+			edges[i][j] = e
+		}
+		if r.err != nil {
+			return nil
+		}
+	}
+	return edges
+}
+
+// ReadContractionHierarchy reads a hierarchy written with Write.
+func ReadContractionHierarchy(input io.Reader) (*ContractionHierarchy, error) {
+	r := hierarchyReader{r: bufio.NewReader(input)}
+	magic := make([]byte, len(hierarchyMagic))
+	if _, err := io.ReadFull(r.r, magic); err != nil {
+		return nil, err
+	} else if string(magic) != hierarchyMagic {
+		return nil, fmt.Errorf("bad magic: expected %q, found %q", hierarchyMagic, magic)
+	}
+	if version := r.uvarint(); r.err == nil && version != hierarchyVersion {
+		return nil, fmt.Errorf("expected hierarchy version %d, found %d", hierarchyVersion, version)
+	}
+	h := &ContractionHierarchy{
+		byID:  make(map[b6.FeatureID]int32),
+		paths: make(map[b6.FeatureID]struct{}),
+	}
+	h.Weights = r.string()
+	r.namespaces = make([]b6.Namespace, r.uvarint())
+	for i := range r.namespaces {
+		r.namespaces[i] = b6.Namespace(r.string())
+	}
+	h.nodes = make([]b6.FeatureID, r.uvarint())
+	for i := range h.nodes {
+		h.nodes[i] = r.id()
+		h.byID[h.nodes[i]] = int32(i)
+	}
+	h.segments = make([]b6.SegmentKey, r.uvarint())
+	for i := range h.segments {
+		h.segments[i] = b6.SegmentKey{ID: r.id(), First: int(r.uvarint()), Last: int(r.uvarint())}
+		h.paths[h.segments[i].ID] = struct{}{}
+	}
+	h.up = r.edges(len(h.nodes), len(h.segments))
+	h.down = r.edges(len(h.nodes), len(h.segments))
+	if r.err != nil {
+		return nil, r.err
+	}
+	return h, nil
+}
@@ -0,0 +1,165 @@
+package graph
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/ingest"
+	"diagonal.works/b6/test/camden"
+)
+
+func hierarchyTestPoints(h *ContractionHierarchy, n int) []b6.FeatureID {
+	points := make([]b6.FeatureID, 0, n)
+	for i := 0; i < len(h.nodes) && len(points) < n; i += len(h.nodes) / n {
+		points = append(points, h.nodes[i])
+	}
+	return points
+}
+
+func TestContractionHierarchyMatchesShortestPathSearch(t *testing.T) {
+	w := camden.BuildCamdenForTests(t)
+	if w == nil {
+		return
+	}
+	weights := WalkingTimeWeights{Speed: WalkingMetersPerSecond}
+	h, err := BuildContractionHierarchy(weights, "mode=walk", w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Len() == 0 {
+		t.Fatal("Expected nodes in hierarchy")
+	}
+
+	const maxWeight = 1200.0
+	points := hierarchyTestPoints(h, 20)
+	distances := h.Distances(points, points, maxWeight)
+	for i, from := range points {
+		s := NewShortestPathSearchFromPoint(from, weights, w)
+		s.ExpandSearch(maxWeight, weights, Points, w)
+		expected := s.PointDistances()
+		for j, to := range points {
+			e, ok := expected[to]
+			if !ok {
+				e = math.Inf(1)
+			}
+			if math.Abs(distances[i][j]-e) > 1e-6 && !(math.IsInf(e, 1) && math.IsInf(distances[i][j], 1)) {
+				t.Errorf("Expected distance %f from %s to %s, found %f", e, from, to, distances[i][j])
+				continue
+			}
+			route, ok := h.ShortestRoute(from, to, maxWeight)
+			if ok != !math.IsInf(e, 1) {
+				t.Errorf("Expected route from %s to %s: %v, found %v", from, to, !math.IsInf(e, 1), ok)
+			} else if ok && len(route.Steps) > 0 {
+				last := route.Steps[len(route.Steps)-1]
+				if last.Destination != to || math.Abs(last.Cost-e) > 1e-6 {
+					t.Errorf("Expected route to %s with cost %f, found %s with %f", to, e, last.Destination, last.Cost)
+				}
+			}
+		}
+	}
+}
+
+func TestContractionHierarchyRouteFollowsPaths(t *testing.T) {
+	w := camden.BuildCamdenForTests(t)
+	if w == nil {
+		return
+	}
+	h, err := BuildContractionHierarchy(SimpleHighwayWeights{}, "", w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	points := hierarchyTestPoints(h, 2)
+	route, ok := h.ShortestRoute(points[0], points[1], 10000.0)
+	if !ok || len(route.Steps) == 0 {
+		t.Fatalf("Expected a route from %s to %s", points[0], points[1])
+	}
+	from := route.Origin
+	for _, step := range route.Steps {
+		path := w.FindFeatureByID(step.Via).(b6.PhysicalFeature)
+		found := map[b6.FeatureID]bool{}
+		for i := 0; i < path.GeometryLen(); i++ {
+			found[path.Reference(i).Source()] = true
+		}
+		if !found[from] || !found[step.Destination] {
+			t.Errorf("Expected %s to connect %s and %s", step.Via, from, step.Destination)
+		}
+		from = step.Destination
+	}
+}
+
+func TestReadAndWriteContractionHierarchy(t *testing.T) {
+	w := camden.BuildGranarySquareForTests(t)
+	if w == nil {
+		return
+	}
+	h, err := BuildContractionHierarchy(SimpleHighwayWeights{}, "mode=simple", w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buffer bytes.Buffer
+	if err := h.Write(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadContractionHierarchy(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Weights != h.Weights || read.Len() != h.Len() || read.Shortcuts() != h.Shortcuts() {
+		t.Errorf("Expected hierarchy to be unchanged after writing and reading")
+	}
+	if err := read.Attach(w); err != nil {
+		t.Errorf("Expected hierarchy to be valid for world, found %s", err)
+	}
+	points := hierarchyTestPoints(h, 10)
+	before, after := h.Distances(points, points, 1000.0), read.Distances(points, points, 1000.0)
+	for i := range before {
+		for j := range before[i] {
+			if before[i][j] != after[i][j] && !(math.IsInf(before[i][j], 1) && math.IsInf(after[i][j], 1)) {
+				t.Errorf("Expected distance %f, found %f", before[i][j], after[i][j])
+			}
+		}
+	}
+
+	if _, err := ReadContractionHierarchy(bytes.NewBufferString("not a hierarchy")); err == nil {
+		t.Error("Expected an error reading an invalid hierarchy")
+	}
+}
+
+func TestContractionHierarchyIsInvalidatedByModifications(t *testing.T) {
+	base := camden.BuildGranarySquareForTests(t)
+	if base == nil {
+		return
+	}
+	h, err := BuildContractionHierarchy(SimpleHighwayWeights{}, "", base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := ingest.NewMutableOverlayWorld(base)
+	if !h.IsValidFor(w) {
+		t.Error("Expected hierarchy to be valid for an unmodified world")
+	}
+	if h.IsValidFor(ingest.NewMutableOverlayWorld(camden.BuildCamdenForTests(t))) {
+		t.Error("Expected hierarchy to be invalid for a different world")
+	}
+
+	if err := w.AddTag(camden.LightermanID.FeatureID(), b6.Tag{Key: "name", Value: b6.NewStringExpression("The Lighterman")}); err != nil {
+		t.Fatal(err)
+	}
+	if !h.IsValidFor(w) {
+		t.Error("Expected hierarchy to be valid after modifying a feature that isn't part of it")
+	}
+
+	var path b6.FeatureID
+	for id := range h.paths {
+		path = id
+		break
+	}
+	if err := w.AddTag(path, b6.Tag{Key: "#highway", Value: b6.NewStringExpression("motorway")}); err != nil {
+		t.Fatal(err)
+	}
+	if h.IsValidFor(w) {
+		t.Error("Expected hierarchy to be invalid after modifying a path that's part of it")
+	}
+}
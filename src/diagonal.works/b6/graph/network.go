@@ -0,0 +1,114 @@
+package graph
+
+import (
+	"diagonal.works/b6"
+)
+
+func isUseableInEitherDirection(segment b6.Segment, weights Weights) bool {
+	return weights.IsUseable(segment) || weights.IsUseable(b6.Segment{Feature: segment.Feature, First: segment.Last, Last: segment.First})
+}
+
+// network is the street network of a world, as traversed with a Weights.
+// Nodes are the points returned by Traverse, typically junctions, and
+// edges are the segments of paths between them.
+type network struct {
+	nodes    []b6.FeatureID
+	byID     map[b6.FeatureID]int32
+	segments []b6.SegmentKey
+	// The edges leading from each node, keyed by the node they lead to,
+	// keeping only the segment with the lowest weight.
+	out []map[int32]hierarchyEdge
+}
+
+// buildNetwork returns the street network of the given world, found by
+// traversing from the ends of every useable path.
+func buildNetwork(weights Weights, w b6.World) (*network, error) {
+	n := &network{byID: make(map[b6.FeatureID]int32)}
+	queue := make([]int32, 0)
+	add := func(id b6.FeatureID) int32 {
+		if i, ok := n.byID[id]; ok {
+			return i
+		}
+		i := int32(len(n.nodes))
+		n.nodes = append(n.nodes, id)
+		n.byID[id] = i
+		queue = append(queue, i)
+		return i
+	}
+	each := func(f b6.Feature, goroutine int) error {
+		if path, ok := f.(b6.PhysicalFeature); ok && path.GeometryType() == b6.GeometryTypePath && path.GeometryLen() > 1 {
+			if isUseableInEitherDirection(b6.ToSegment(path), weights) {
+				for _, i := range []int{0, path.GeometryLen() - 1} {
+					if id := path.Reference(i).Source(); id.IsValid() {
+						add(id)
+					}
+				}
+			}
+		}
+		return nil
+	}
+	options := b6.EachFeatureOptions{
+		SkipPoints:      true,
+		SkipAreas:       true,
+		SkipRelations:   true,
+		SkipCollections: true,
+		SkipExpressions: true,
+		Goroutines:      1,
+	}
+	if err := w.EachFeature(each, &options); err != nil {
+		return nil, err
+	}
+
+	out := make([]map[int32]hierarchyEdge, 0, len(n.nodes))
+	segments := make(map[b6.SegmentKey]int32)
+	for len(queue) > 0 {
+		from := queue[0]
+		queue = queue[1:]
+		ss := w.Traverse(n.nodes[from])
+		for ss.Next() {
+			segment := ss.Segment()
+			id := segment.LastFeatureID()
+			if !id.IsValid() || id == n.nodes[from] || !isUseableInEitherDirection(segment, weights) {
+				continue
+			}
+			to := add(id)
+			if !weights.IsUseable(segment) {
+				continue
+			}
+			for int(from) >= len(out) {
+				out = append(out, make(map[int32]hierarchyEdge))
+			}
+			weight := weights.Weight(segment)
+			if existing, ok := out[from][to]; !ok || weight < existing.weight {
+				key := segment.ToKey()
+				s, ok := segments[key]
+				if !ok {
+					s = int32(len(n.segments))
+					n.segments = append(n.segments, key)
+					segments[key] = s
+				}
+				out[from][to] = hierarchyEdge{to: to, middle: -1, segment: s, weight: weight}
+			}
+		}
+	}
+	for len(out) < len(n.nodes) {
+		out = append(out, make(map[int32]hierarchyEdge))
+	}
+	n.out = out
+	return n, nil
+}
+
+// in returns the edges leading to each node, keyed by the node they lead
+// from.
+func (n *network) in() []map[int32]hierarchyEdge {
+	in := make([]map[int32]hierarchyEdge, len(n.nodes))
+	for i := range in {
+		in[i] = make(map[int32]hierarchyEdge)
+	}
+	for from, edges := range n.out {
+		for to, e := range edges {
+			in[to][int32(from)] = hierarchyEdge{to: int32(from), middle: e.middle, segment: e.segment, weight: e.weight}
+		}
+	}
+	return in
+}
@@ -0,0 +1,121 @@
+package graph
+
+import (
+	"diagonal.works/b6"
+)
+
+// ProfileRule gives a value for paths, or the points along them, that
+// match a query.
+type ProfileRule struct {
+	Query b6.Query
+	Value float64
+}
+
+// Profile describes routing weights declaratively, allowing new modes of
+// transport to be defined without new implementations of Weights.
+// The weight of a segment is the time in seconds taken to travel along
+// it, multiplied by any matching factors, plus any penalties for the
+// points passed through.
+type Profile struct {
+	Name string
+	// Paths matching Useable, or with a #highway tag if nil, can be used,
+	// unless they match Exclude.
+	Useable b6.Query
+	Exclude b6.Query
+	// Speed in meters per second along paths that match none of Speeds.
+	// Connections between buildings and the street network use walking
+	// speed if 0.
+	Speed float64
+	// Speeds in meters per second for paths matching each query. The
+	// lowest applies if more than one matches. Paths with a speed of 0
+	// can't be used.
+	Speeds []ProfileRule
+	// Factors by which the weights of paths matching each query are
+	// multiplied, for example to prefer quieter streets.
+	Factors []ProfileRule
+	// Times in seconds added for each point passed through that matches
+	// each query, for example barriers or crossings.
+	Penalties []ProfileRule
+	// If true, paths tagged oneway=yes can only be used in the direction
+	// in which they're drawn, unless they're tagged no for one of
+	// OnewayExceptions, for example oneway:bicycle.
+	Oneway           bool
+	OnewayExceptions []string
+}
+
+func (p *Profile) speed(path b6.Feature, w b6.World) float64 {
+	if path.Get("diagonal").Value.String() == "connection" && p.Speed <= 0.0 {
+		return WalkingMetersPerSecond
+	}
+	speed := p.Speed
+	matched := false
+	for _, rule := range p.Speeds {
+		if rule.Query.Matches(path, w) && (!matched || rule.Value < speed) {
+			speed = rule.Value
+			matched = true
+		}
+	}
+	return speed
+}
+
+func (p *Profile) isOneway(path b6.Feature) bool {
+	if !p.Oneway || path.Get("oneway").Value.String() != "yes" {
+		return false
+	}
+	for _, key := range p.OnewayExceptions {
+		if path.Get(key).Value.String() == "no" {
+			return false
+		}
+	}
+	return true
+}
+
+// ProfileWeights are the weights described by a Profile. The world is
+// used to find the points to which penalties apply, and to evaluate
+// queries.
+type ProfileWeights struct {
+	Profile *Profile
+	W       b6.World
+}
+
+func (p ProfileWeights) IsUseable(segment b6.Segment) bool {
+	if segment.Feature.Get("diagonal").Value.String() != "connection" {
+		if p.Profile.Useable == nil {
+			if !segment.Feature.Get("#highway").IsValid() {
+				return false
+			}
+		} else if !p.Profile.Useable.Matches(segment.Feature, p.W) {
+			return false
+		}
+	}
+	if p.Profile.Exclude != nil && p.Profile.Exclude.Matches(segment.Feature, p.W) {
+		return false
+	}
+	if p.Profile.speed(segment.Feature, p.W) <= 0.0 {
+		return false
+	}
+	return !p.Profile.isOneway(segment.Feature) || segment.Last > segment.First
+}
+
+func (p ProfileWeights) Weight(segment b6.Segment) float64 {
+	weight := weightFromSegment(segment) / p.Profile.speed(segment.Feature, p.W)
+	for _, rule := range p.Profile.Factors {
+		if rule.Query.Matches(segment.Feature, p.W) {
+			weight *= rule.Value
+		}
+	}
+	if len(p.Profile.Penalties) > 0 && p.W != nil {
+		// Penalise the points reached by the segment, but not the one it
+		// starts from, to avoid counting points at junctions twice.
+		for i := 1; i < segment.Len(); i++ {
+			if point := p.W.FindFeatureByID(segment.SegmentFeatureID(i)); point != nil {
+				for _, rule := range p.Profile.Penalties {
+					if rule.Query.Matches(point, p.W) {
+						weight += rule.Value
+					}
+				}
+			}
+		}
+	}
+	return weight
+}
@@ -0,0 +1,71 @@
+package graph
+
+import (
+	"math"
+	"testing"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/ingest"
+	"diagonal.works/b6/osm"
+)
+
+func TestProfileWeights(t *testing.T) {
+	nodes := []osm.Node{
+		{ID: 1, Location: osm.LatLng{Lat: 51.5409703, Lng: -0.1376308}},
+		{ID: 2, Location: osm.LatLng{Lat: 51.5413281, Lng: -0.1379425}, Tags: []osm.Tag{{Key: "barrier", Value: "gate"}}},
+		{ID: 3, Location: osm.LatLng{Lat: 51.5416858, Lng: -0.1382541}},
+	}
+	ways := []osm.Way{
+		{ID: 1, Nodes: []osm.NodeID{1, 2, 3}, Tags: []osm.Tag{{Key: "highway", Value: "primary"}, {Key: "oneway", Value: "yes"}}},
+		{ID: 2, Nodes: []osm.NodeID{1, 3}, Tags: []osm.Tag{{Key: "highway", Value: "steps"}}},
+		{ID: 3, Nodes: []osm.NodeID{1, 3}, Tags: []osm.Tag{{Key: "highway", Value: "residential"}, {Key: "surface", Value: "gravel"}}},
+		{ID: 4, Nodes: []osm.NodeID{1, 3}, Tags: []osm.Tag{{Key: "waterway", Value: "canal"}}},
+	}
+	w, err := ingest.BuildWorldFromOSM(nodes, ways, []osm.Relation{}, &ingest.BuildOptions{Cores: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	profile := &Profile{
+		Speed:     2.0,
+		Exclude:   b6.Tagged{Key: "#highway", Value: b6.NewStringExpression("steps")},
+		Speeds:    []ProfileRule{{Query: b6.Tagged{Key: "#highway", Value: b6.NewStringExpression("primary")}, Value: 1.0}},
+		Factors:   []ProfileRule{{Query: b6.Tagged{Key: "surface", Value: b6.NewStringExpression("gravel")}, Value: 3.0}},
+		Penalties: []ProfileRule{{Query: b6.Keyed{Key: "#barrier"}, Value: 60.0}},
+		Oneway:    true,
+	}
+	weights := ProfileWeights{Profile: profile, W: w}
+
+	segment := func(id osm.WayID) b6.Segment {
+		return b6.ToSegment(w.FindFeatureByID(ingest.FromOSMWayID(id)).(b6.PhysicalFeature))
+	}
+	reverse := func(s b6.Segment) b6.Segment {
+		return b6.Segment{Feature: s.Feature, First: s.Last, Last: s.First}
+	}
+
+	primary := segment(1)
+	if !weights.IsUseable(primary) {
+		t.Error("Expected primary road to be useable")
+	}
+	if weights.IsUseable(reverse(primary)) {
+		t.Error("Expected oneway primary road not to be useable in reverse")
+	}
+	if expected := b6.AngleToMeters(primary.Polyline().Length())/1.0 + 60.0; math.Abs(weights.Weight(primary)-expected) > 1e-6 {
+		t.Errorf("Expected weight %f including barrier penalty, found %f", expected, weights.Weight(primary))
+	}
+	if weights.IsUseable(segment(2)) {
+		t.Error("Expected excluded steps not to be useable")
+	}
+	residential := segment(3)
+	if expected := b6.AngleToMeters(residential.Polyline().Length()) / 2.0 * 3.0; math.Abs(weights.Weight(residential)-expected) > 1e-6 {
+		t.Errorf("Expected weight %f with surface factor, found %f", expected, weights.Weight(residential))
+	}
+	if weights.IsUseable(segment(4)) {
+		t.Error("Expected path without a highway tag not to be useable")
+	}
+
+	profile.Speeds = append(profile.Speeds, ProfileRule{Query: b6.Keyed{Key: "surface"}, Value: 0.0})
+	if weights.IsUseable(residential) {
+		t.Error("Expected path with a speed of 0 not to be useable")
+	}
+}
@@ -0,0 +1,274 @@
+package graph
+
+import (
+	"math"
+	"strconv"
+
+	"diagonal.works/b6"
+)
+
+// AlternativeRouteOptions controls the routes returned by
+// AlternativeRoutes. Zero values are replaced by defaults.
+type AlternativeRouteOptions struct {
+	// The maximum number of routes returned, including the shortest.
+	Routes int
+	// The factor by which the weights of segments used by previous routes
+	// are multiplied when searching for the next.
+	Penalty float64
+	// The maximum proportion of a route's length that can be shared with
+	// the routes returned before it.
+	MaxOverlap float64
+	// The maximum ratio between the cost of a route and that of the
+	// shortest.
+	MaxStretch float64
+}
+
+const (
+	DefaultAlternativeRoutes     = 3
+	DefaultAlternativePenalty    = 1.4
+	DefaultAlternativeMaxOverlap = 0.75
+	DefaultAlternativeMaxStretch = 1.5
+)
+
+func (o AlternativeRouteOptions) withDefaults() AlternativeRouteOptions {
+	if o.Routes <= 0 {
+		o.Routes = DefaultAlternativeRoutes
+	}
+	if o.Penalty <= 1.0 {
+		o.Penalty = DefaultAlternativePenalty
+	}
+	if o.MaxOverlap <= 0.0 {
+		o.MaxOverlap = DefaultAlternativeMaxOverlap
+	}
+	if o.MaxStretch <= 1.0 {
+		o.MaxStretch = DefaultAlternativeMaxStretch
+	}
+	return o
+}
+
+// undirectedSegmentKey identifies a segment regardless of the direction in
+// which it's traversed.
+func undirectedSegmentKey(segment b6.Segment) b6.SegmentKey {
+	key := segment.ToKey()
+	if key.First > key.Last {
+		key.First, key.Last = key.Last, key.First
+	}
+	return key
+}
+
+// penalisedWeights multiplies the weight of segments used by previous
+// routes, to find alternatives with the penalty method.
+type penalisedWeights struct {
+	Weights   Weights
+	Penalties map[b6.SegmentKey]float64
+}
+
+func (p penalisedWeights) IsUseable(segment b6.Segment) bool {
+	return p.Weights.IsUseable(segment)
+}
+
+func (p penalisedWeights) Weight(segment b6.Segment) float64 {
+	weight := p.Weights.Weight(segment)
+	if penalty, ok := p.Penalties[undirectedSegmentKey(segment)]; ok {
+		weight *= penalty
+	}
+	return weight
+}
+
+// shortestPathBetween returns the segments along the shortest path from
+// one feature to another, or false if there's no path with a weight
+// below maxWeight. Areas are entered and left via their entrances, or
+// any connected point on their boundary.
+func shortestPathBetween(from b6.Feature, to b6.Feature, maxWeight float64, weights Weights, w b6.World) ([]b6.Segment, bool) {
+	if from.FeatureID() == to.FeatureID() {
+		return []b6.Segment{}, true
+	}
+	s := NewShortestPathSearchFromFeature(from, weights, w)
+	if area, ok := to.(b6.AreaFeature); ok {
+		s.ExpandSearch(maxWeight, weights, PointsAndAreas, w)
+		if entrance, ok := s.AreaEntrances()[area.AreaID()]; ok {
+			return s.BuildPath(entrance), true
+		}
+		return nil, false
+	}
+	s.ExpandSearchTo(to.FeatureID(), maxWeight, weights, w)
+	if d := s.CurrentDistance(to.FeatureID()); math.IsInf(d, 1) {
+		return nil, false
+	}
+	return s.BuildPath(to.FeatureID()), true
+}
+
+// RouteFromSegments returns a route following the given segments, with
+// costs given by weights.
+func RouteFromSegments(segments []b6.Segment, weights Weights) b6.Route {
+	if len(segments) == 0 {
+		return b6.Route{}
+	}
+	route := b6.Route{Origin: segments[0].FirstFeatureID(), Steps: make([]b6.Step, 0, len(segments))}
+	cost := 0.0
+	for _, segment := range segments {
+		cost += weights.Weight(segment)
+		route.Steps = append(route.Steps, b6.Step{Destination: segment.LastFeatureID(), Via: segment.Feature.FeatureID(), Cost: cost})
+	}
+	return route
+}
+
+// AlternativeRoutes returns up to options.Routes routes from one feature
+// to another, starting with the shortest, using the penalty method: after
+// each route is found, the weights of the segments it uses are increased,
+// and the search repeated. Routes that overlap too much with those
+// already found, or that are too costly compared with the shortest, are
+// skipped. Returns no routes if the destination can't be reached within
+// maxWeight.
+func AlternativeRoutes(from b6.Feature, to b6.Feature, maxWeight float64, weights Weights, options AlternativeRouteOptions, w b6.World) []b6.Route {
+	options = options.withDefaults()
+	penalised := penalisedWeights{Weights: weights, Penalties: make(map[b6.SegmentKey]float64)}
+	used := make(map[b6.SegmentKey]struct{})
+	routes := make([]b6.Route, 0, options.Routes)
+	shortest := 0.0
+	// Penalising the same segments repeatedly eventually forces a
+	// different route, but there may be none that are acceptable, so
+	// limit the number of attempts.
+	for attempt := 0; attempt < options.Routes*3 && len(routes) < options.Routes; attempt++ {
+		limit := maxWeight
+		if len(routes) > 0 {
+			limit = math.Min(maxWeight, shortest*options.MaxStretch) * options.Penalty * options.Penalty
+		}
+		segments, ok := shortestPathBetween(from, to, limit, penalised, w)
+		if !ok {
+			break
+		}
+		route := RouteFromSegments(segments, weights)
+		cost := 0.0
+		if len(route.Steps) > 0 {
+			cost = route.Steps[len(route.Steps)-1].Cost
+		}
+		if len(routes) == 0 {
+			if cost >= maxWeight {
+				break
+			}
+			shortest = cost
+		}
+
+		total, overlap := 0.0, 0.0
+		for _, segment := range segments {
+			length := b6.AngleToMeters(segment.Polyline().Length())
+			total += length
+			if _, ok := used[undirectedSegmentKey(segment)]; ok {
+				overlap += length
+			}
+		}
+		accept := len(routes) == 0 || ((total == 0.0 || overlap/total <= options.MaxOverlap) && cost <= shortest*options.MaxStretch && cost < maxWeight)
+		if accept {
+			routes = append(routes, route)
+		}
+		if len(segments) == 0 {
+			break // The origin and destination are the same
+		}
+		for _, segment := range segments {
+			key := undirectedSegmentKey(segment)
+			used[key] = struct{}{}
+			if p, ok := penalised.Penalties[key]; ok {
+				penalised.Penalties[key] = p * options.Penalty
+			} else {
+				penalised.Penalties[key] = options.Penalty
+			}
+		}
+	}
+	return routes
+}
+
+// RouteSegments returns the segments of the paths followed by a route.
+// Steps whose path no longer connects the points either side of them are
+// skipped.
+func RouteSegments(route b6.Route, w b6.World) []b6.Segment {
+	segments := make([]b6.Segment, 0, len(route.Steps))
+	from := route.Origin
+	for _, step := range route.Steps {
+		if path, ok := w.FindFeatureByID(step.Via).(b6.PhysicalFeature); ok && path.GeometryType() == b6.GeometryTypePath {
+			// Choose the closest pair of indices, since paths can visit the
+			// same point more than once.
+			first, last := -1, -1
+			for i := 0; i < path.GeometryLen(); i++ {
+				if path.Reference(i).Source() != from {
+					continue
+				}
+				for j := 0; j < path.GeometryLen(); j++ {
+					if i != j && path.Reference(j).Source() == step.Destination {
+						if first < 0 || abs(i-j) < abs(first-last) {
+							first, last = i, j
+						}
+					}
+				}
+			}
+			if first >= 0 {
+				segments = append(segments, b6.Segment{Feature: path, First: first, Last: last})
+			}
+		}
+		from = step.Destination
+	}
+	return segments
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
+
+// RouteSummary describes a route, for comparison with alternatives.
+type RouteSummary struct {
+	// Length in meters.
+	Length float64
+	// The cost of the last step of the route, typically in seconds.
+	Cost float64
+	// The total climb in meters, from the ele tags of points along the
+	// route, or 0 if there are none.
+	ElevationGain float64
+	// The proportion of the route's length along paths with each value of
+	// the #highway tag, or the empty string for paths without one.
+	Highways map[string]float64
+	// The number of crossings passed through, being points tagged
+	// #highway=crossing, or with a crossing tag.
+	Crossings int
+}
+
+// SummariseRoute returns a summary of the given route.
+func SummariseRoute(route b6.Route, w b6.World) RouteSummary {
+	summary := RouteSummary{Highways: make(map[string]float64)}
+	if len(route.Steps) > 0 {
+		summary.Cost = route.Steps[len(route.Steps)-1].Cost
+	}
+	elevation, hasElevation := 0.0, false
+	visit := func(id b6.FeatureID) {
+		point := w.FindFeatureByID(id)
+		if point == nil {
+			return
+		}
+		if point.Get("#highway").Value.String() == "crossing" || point.Get("crossing").IsValid() {
+			summary.Crossings++
+		}
+		if e, err := strconv.ParseFloat(point.Get("ele").Value.String(), 64); err == nil {
+			if hasElevation && e > elevation {
+				summary.ElevationGain += e - elevation
+			}
+			elevation, hasElevation = e, true
+		}
+	}
+	visit(route.Origin)
+	for _, segment := range RouteSegments(route, w) {
+		length := b6.AngleToMeters(segment.Polyline().Length())
+		summary.Length += length
+		summary.Highways[segment.Feature.Get("#highway").Value.String()] += length
+		for i := 1; i < segment.Len(); i++ {
+			visit(segment.SegmentFeatureID(i))
+		}
+	}
+	if summary.Length > 0.0 {
+		for highway := range summary.Highways {
+			summary.Highways[highway] /= summary.Length
+		}
+	}
+	return summary
+}
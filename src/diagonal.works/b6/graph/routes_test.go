@@ -0,0 +1,93 @@
+package graph
+
+import (
+	"math"
+	"testing"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/ingest"
+	"diagonal.works/b6/osm"
+)
+
+// buildAlternativesWorld returns a world with two routes between nodes 1
+// and 4: a shorter one via footways through a crossing at node 2, and a
+// longer one along a residential street through node 3.
+func buildAlternativesWorld(t *testing.T) b6.World {
+	t.Helper()
+	nodes := []osm.Node{
+		{ID: 1, Location: osm.LatLng{Lat: 51.5400, Lng: -0.1400}, Tags: []osm.Tag{{Key: "ele", Value: "10"}}},
+		{ID: 2, Location: osm.LatLng{Lat: 51.5403, Lng: -0.1350}, Tags: []osm.Tag{{Key: "highway", Value: "crossing"}, {Key: "ele", Value: "15"}}},
+		{ID: 3, Location: osm.LatLng{Lat: 51.5394, Lng: -0.1350}},
+		{ID: 4, Location: osm.LatLng{Lat: 51.5400, Lng: -0.1300}, Tags: []osm.Tag{{Key: "ele", Value: "12"}}},
+	}
+	ways := []osm.Way{
+		{ID: 10, Nodes: []osm.NodeID{1, 2}, Tags: []osm.Tag{{Key: "highway", Value: "footway"}}},
+		{ID: 11, Nodes: []osm.NodeID{2, 4}, Tags: []osm.Tag{{Key: "highway", Value: "footway"}}},
+		{ID: 12, Nodes: []osm.NodeID{1, 3, 4}, Tags: []osm.Tag{{Key: "highway", Value: "residential"}}},
+	}
+	w, err := ingest.BuildWorldFromOSM(nodes, ways, []osm.Relation{}, &ingest.BuildOptions{Cores: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func TestAlternativeRoutes(t *testing.T) {
+	w := buildAlternativesWorld(t)
+	from := w.FindFeatureByID(ingest.FromOSMNodeID(1))
+	to := w.FindFeatureByID(ingest.FromOSMNodeID(4))
+
+	routes := AlternativeRoutes(from, to, 10000.0, SimpleHighwayWeights{}, AlternativeRouteOptions{Routes: 3}, w)
+	if len(routes) != 2 {
+		t.Fatalf("Expected 2 distinct routes, found %d", len(routes))
+	}
+	if routes[0].Steps[0].Via != ingest.FromOSMWayID(10) {
+		t.Errorf("Expected shortest route to start along way 10, found %s", routes[0].Steps[0].Via)
+	}
+	if routes[1].Steps[0].Via != ingest.FromOSMWayID(12) {
+		t.Errorf("Expected alternative route along way 12, found %s", routes[1].Steps[0].Via)
+	}
+	first, second := routes[0].Steps[len(routes[0].Steps)-1].Cost, routes[1].Steps[len(routes[1].Steps)-1].Cost
+	if first >= second {
+		t.Errorf("Expected shortest route first, found costs %f and %f", first, second)
+	}
+
+	if routes := AlternativeRoutes(from, to, 10000.0, SimpleHighwayWeights{}, AlternativeRouteOptions{Routes: 3, MaxStretch: 1.0001}, w); len(routes) != 1 {
+		t.Errorf("Expected only the shortest route with a low maximum stretch, found %d", len(routes))
+	}
+	if routes := AlternativeRoutes(from, to, 10.0, SimpleHighwayWeights{}, AlternativeRouteOptions{}, w); len(routes) != 0 {
+		t.Errorf("Expected no routes beyond the maximum weight, found %d", len(routes))
+	}
+}
+
+func TestSummariseRoute(t *testing.T) {
+	w := buildAlternativesWorld(t)
+	from := w.FindFeatureByID(ingest.FromOSMNodeID(1))
+	to := w.FindFeatureByID(ingest.FromOSMNodeID(4))
+	routes := AlternativeRoutes(from, to, 10000.0, SimpleHighwayWeights{}, AlternativeRouteOptions{Routes: 2}, w)
+	if len(routes) != 2 {
+		t.Fatalf("Expected 2 routes, found %d", len(routes))
+	}
+
+	summary := SummariseRoute(routes[0], w)
+	if math.Abs(summary.Length-summary.Cost) > 1e-6 {
+		t.Errorf("Expected length %f to match cost for distance weights, found %f", summary.Length, summary.Cost)
+	}
+	if summary.Crossings != 1 {
+		t.Errorf("Expected 1 crossing, found %d", summary.Crossings)
+	}
+	if math.Abs(summary.ElevationGain-5.0) > 1e-6 {
+		t.Errorf("Expected elevation gain of 5m, found %f", summary.ElevationGain)
+	}
+	if math.Abs(summary.Highways["footway"]-1.0) > 1e-6 {
+		t.Errorf("Expected route entirely along footways, found %v", summary.Highways)
+	}
+
+	summary = SummariseRoute(routes[1], w)
+	if summary.Crossings != 0 || math.Abs(summary.Highways["residential"]-1.0) > 1e-6 {
+		t.Errorf("Expected alternative along a residential street without crossings, found %+v", summary)
+	}
+	if math.Abs(summary.ElevationGain-2.0) > 1e-6 {
+		t.Errorf("Expected elevation gain of 2m, found %f", summary.ElevationGain)
+	}
+}
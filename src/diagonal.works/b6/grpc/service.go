@@ -2,31 +2,44 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"sync"
+	"time"
 
 	"diagonal.works/b6"
 	"diagonal.works/b6/api"
 	"diagonal.works/b6/api/functions"
+	"diagonal.works/b6/auth"
 	"diagonal.works/b6/ingest"
+	"diagonal.works/b6/ingest/compact"
 	pb "diagonal.works/b6/proto"
 	"golang.org/x/mod/semver"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 )
 
 type service struct {
 	pb.UnimplementedB6Server
-	worlds  ingest.Worlds
-	fs      api.FunctionSymbols
-	a       api.Adaptors
-	options api.Options
-	lock    *sync.RWMutex
+	worlds    ingest.Worlds
+	fs        api.FunctionSymbols
+	a         api.Adaptors
+	options   api.Options
+	completer *api.Completer
+	locks     *ingest.WorldLocks
 }
 
-func (s *service) Evaluate(ctx context.Context, request *pb.EvaluateRequestProto) (*pb.EvaluateResponseProto, error) {
-	s.lock.RLock()
-	defer s.lock.RUnlock()
-	w := s.worlds.FindOrCreateWorld(b6.NewFeatureIDFromProto(request.Root))
+func (s *service) Evaluate(ctx context.Context, request *pb.EvaluateRequestProto) (response *pb.EvaluateResponseProto, err error) {
+	root := b6.NewFeatureIDFromProto(request.Root)
+	if !root.IsValid() {
+		root = ingest.DefaultWorldFeatureID
+	}
+	// Changes only block readers of the world they're applied to.
+	unlock := s.locks.RLock(root)
+	defer unlock()
+	permissions := auth.FromContext(ctx)
+	worlds := permissions.RestrictWorlds(s.worlds)
+	w := worlds.FindOrCreateWorld(root)
 
 	apply := func(change ingest.Change) (b6.Collection[b6.FeatureID, b6.FeatureID], error) {
 		ids, err := change.Apply(w)
@@ -41,75 +54,330 @@ func (s *service) Evaluate(ctx context.Context, request *pb.EvaluateRequestProto
 
 	context := api.Context{
 		World:           w,
-		Worlds:          s.worlds,
+		WorldID:         root,
+		Worlds:          worlds,
+		Locks:           s.locks,
 		FunctionSymbols: s.fs,
 		Adaptors:        s.a,
 		Context:         ctx,
 	}
 	context.FillFromOptions(&s.options)
+	context.FileIOAllowed = context.FileIOAllowed && permissions.FileIO
+	if request.Profile {
+		context.Profile = api.NewProfile()
+	}
 	expression, err := b6.ExpressionFromProto(request.Request)
 	if err != nil {
 		return nil, err
 	}
+	// Converting the result to a proto iterates over lazily evaluated
+	// collections, so the evaluation is only complete once that's done.
+	start := time.Now()
+	defer func() {
+		s.options.Metrics.ObserveEvaluation(expression, start, err)
+	}()
 	simplified := api.Simplify(expression, context.FunctionSymbols)
+	context.StartTimer()
 	v, err := api.Evaluate(simplified, &context)
 	if err != nil {
-		return nil, err
+		return nil, toStatus(err)
 	}
 
 	if change, ok := v.(ingest.Change); ok {
-		s.lock.RUnlock()
-		s.lock.Lock()
+		if err := permissions.CheckModify(root); err != nil {
+			return nil, toStatus(err)
+		}
+		downgrade := s.locks.Upgrade(root)
 		v, err = apply(change)
-		s.lock.Unlock()
-		s.lock.RLock()
+		downgrade()
+		s.options.Metrics.ObserveChange(err)
 		if err != nil {
-			return nil, err
+			return nil, toStatus(err)
 		}
+	} else if change, ok := v.(ingest.WorldsChange); ok {
+		downgrade := s.locks.Upgrade(root)
+		v, err = change.Apply(worlds, root)
+		downgrade()
+		s.options.Metrics.ObserveChange(err)
+		if err != nil {
+			return nil, toStatus(err)
+		}
+	}
+	profile := context.Profile
+	if p, ok := v.(*api.ProfiledResult); ok {
+		v, profile = p.Value, p.Profile
 	}
 	ve, err := b6.FromLiteral(v)
 	if err != nil {
-		return nil, err
+		return nil, toStatus(err)
 	}
 
 	pe, err := ve.ToProto()
 	if err != nil {
-		return nil, err
+		return nil, toStatus(err)
 	}
 
 	r := &pb.EvaluateResponseProto{
 		Result: pe,
 	}
+	if profile != nil {
+		// Converting the result to a proto iterates over lazily evaluated
+		// collections, so the profile is only complete at this point.
+		if r.Profile, err = profile.ToProto(); err != nil {
+			return nil, err
+		}
+	}
 	if _, err := proto.Marshal(r); err != nil {
 		panic(err)
 	}
-	return &pb.EvaluateResponseProto{
-		Result: pe,
-	}, nil
+	return r, nil
+}
+
+// toStatus returns a gRPC status for errors caused by cancellation, by
+// exceeding the limits imposed on an evaluation, by a lack of permission,
+// or by the state of worlds, allowing clients to distinguish them from
+// other failures.
+func toStatus(err error) error {
+	var limit *api.LimitExceededError
+	if errors.As(err, &limit) {
+		if limit.Resource == api.ResourceTime {
+			return status.Error(codes.DeadlineExceeded, err.Error())
+		}
+		return status.Error(codes.ResourceExhausted, err.Error())
+	} else if errors.Is(err, auth.ErrPermissionDenied) {
+		return status.Error(codes.PermissionDenied, err.Error())
+	} else if errors.Is(err, ingest.ErrWorldNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	} else if errors.Is(err, ingest.ErrWorldExists) {
+		return status.Error(codes.AlreadyExists, err.Error())
+	} else if errors.Is(err, ingest.ErrReadOnlyWorld) {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	} else if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	return err
+}
+
+func (s *service) Complete(ctx context.Context, request *pb.CompleteRequestProto) (*pb.CompleteResponseProto, error) {
+	// Completing against an unknown world uses the default world, rather
+	// than creating one.
+	root, unlock := s.locks.RLockExisting(s.worlds, b6.NewFeatureIDFromProto(request.Root))
+	defer unlock()
+	w := s.worlds.FindOrCreateWorld(root)
+	completions := s.completer.Complete(request.Expression, int(request.Cursor), w)
+	response := &pb.CompleteResponseProto{
+		Completions: make([]*pb.CompletionProto, len(completions)),
+	}
+	for i, c := range completions {
+		response.Completions[i] = c.ToProto()
+	}
+	return response, nil
 }
 
 func (s *service) ListWorlds(ctx context.Context, request *pb.ListWorldsRequestProto) (*pb.ListWorldsResponseProto, error) {
 	ids := s.worlds.ListWorlds()
 	response := &pb.ListWorldsResponseProto{
-		Ids: make([]*pb.FeatureIDProto, len(ids)),
+		Ids:    make([]*pb.FeatureIDProto, len(ids)),
+		Worlds: make([]*pb.WorldMetadataProto, len(ids)),
 	}
 	for i, id := range ids {
 		response.Ids[i] = b6.NewProtoFromFeatureID(id)
+		if metadata, err := s.worlds.WorldMetadata(id); err == nil {
+			response.Worlds[i] = newWorldMetadataProto(id, metadata)
+		} else {
+			// Deleted since being listed
+			response.Worlds[i] = &pb.WorldMetadataProto{Id: response.Ids[i]}
+		}
+	}
+	return response, nil
+}
+
+func newWorldMetadataProto(id b6.FeatureID, metadata ingest.WorldMetadata) *pb.WorldMetadataProto {
+	p := &pb.WorldMetadataProto{
+		Id:       b6.NewProtoFromFeatureID(id),
+		Name:     metadata.Name,
+		Author:   metadata.Author,
+		ReadOnly: metadata.ReadOnly,
+	}
+	if !metadata.Created.IsZero() {
+		p.CreatedTimestamp = metadata.Created.Unix()
+	}
+	if metadata.Parent.IsValid() {
+		p.Parent = b6.NewProtoFromFeatureID(metadata.Parent)
+	}
+	return p
+}
+
+func (s *service) WorldInfo(ctx context.Context, request *pb.WorldInfoRequestProto) (*pb.WorldInfoResponseProto, error) {
+	id := b6.NewFeatureIDFromProto(request.Id)
+	if !id.IsValid() {
+		id = ingest.DefaultWorldFeatureID
+	}
+	unlock := s.locks.RLock(id)
+	defer unlock()
+	// Check the world exists first, since asking about a world shouldn't
+	// create it.
+	metadata, err := s.worlds.WorldMetadata(id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	response := &pb.WorldInfoResponseProto{Metadata: newWorldMetadataProto(id, metadata)}
+	for _, m := range compact.FindIndexMetadata(s.worlds.FindOrCreateWorld(id)) {
+		response.Indices = append(response.Indices, &pb.IndexInfoProto{
+			Version:  m.Version,
+			Header:   m.Header,
+			Metadata: m.Metadata,
+		})
 	}
 	return response, nil
 }
 
 func (s *service) DeleteWorld(ctx context.Context, request *pb.DeleteWorldRequestProto) (*pb.DeleteWorldResponseProto, error) {
-	s.worlds.DeleteWorld(b6.NewFeatureIDFromProto(request.Id))
+	id := b6.NewFeatureIDFromProto(request.Id)
+	if err := auth.FromContext(ctx).CheckModify(id); err != nil {
+		return nil, toStatus(err)
+	}
+	// Wait for readers and changes in progress to finish.
+	unlock := s.locks.Lock(id)
+	defer unlock()
+	if err := s.worlds.DeleteWorld(id); err != nil {
+		return nil, toStatus(err)
+	}
 	return &pb.DeleteWorldResponseProto{}, nil
 }
 
-func NewB6Service(worlds ingest.Worlds, options api.Options, lock *sync.RWMutex) pb.B6Server {
+func (s *service) CloneWorld(ctx context.Context, request *pb.CloneWorldRequestProto) (*pb.CloneWorldResponseProto, error) {
+	from := b6.NewFeatureIDFromProto(request.From)
+	if !from.IsValid() {
+		from = ingest.DefaultWorldFeatureID
+	}
+	to := b6.NewFeatureIDFromProto(request.To)
+	if !to.IsValid() {
+		return nil, status.Error(codes.InvalidArgument, "missing ID for new world")
+	}
+	permissions := auth.FromContext(ctx)
+	worlds := permissions.RestrictWorlds(s.worlds)
+	unlock := s.locks.RLock(from)
+	err := worlds.CloneWorld(from, to, request.ReadOnly)
+	unlock()
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	metadata := ingest.WorldMetadata{Name: request.Name, Author: request.Author}
+	if metadata.Author == "" {
+		metadata.Author = permissions.Subject
+	}
+	if err := worlds.SetWorldMetadata(to, metadata); err != nil {
+		return nil, toStatus(err)
+	}
+	if metadata, err = worlds.WorldMetadata(to); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.CloneWorldResponseProto{Metadata: newWorldMetadataProto(to, metadata)}, nil
+}
+
+func (s *service) FreezeWorld(ctx context.Context, request *pb.FreezeWorldRequestProto) (*pb.FreezeWorldResponseProto, error) {
+	id := b6.NewFeatureIDFromProto(request.Id)
+	if !id.IsValid() {
+		id = ingest.DefaultWorldFeatureID
+	}
+	// Wait for changes in progress to be applied.
+	unlock := s.locks.Lock(id)
+	defer unlock()
+	if err := auth.FromContext(ctx).RestrictWorlds(s.worlds).FreezeWorld(id); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.FreezeWorldResponseProto{}, nil
+}
+
+func (s *service) RenameWorld(ctx context.Context, request *pb.RenameWorldRequestProto) (*pb.RenameWorldResponseProto, error) {
+	from := b6.NewFeatureIDFromProto(request.From)
+	if !from.IsValid() {
+		from = ingest.DefaultWorldFeatureID
+	}
+	to := b6.NewFeatureIDFromProto(request.To)
+	if !to.IsValid() {
+		return nil, status.Error(codes.InvalidArgument, "missing new ID for world")
+	}
+	// Readers of the world under its original ID need to finish before
+	// it's available under the new one, as they'll no longer hold its lock.
+	unlock := s.locks.Lock(from)
+	defer unlock()
+	if err := auth.FromContext(ctx).RestrictWorlds(s.worlds).RenameWorld(from, to); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.RenameWorldResponseProto{}, nil
+}
+
+func (s *service) SetWorldMetadata(ctx context.Context, request *pb.SetWorldMetadataRequestProto) (*pb.SetWorldMetadataResponseProto, error) {
+	id := b6.NewFeatureIDFromProto(request.Id)
+	if !id.IsValid() {
+		id = ingest.DefaultWorldFeatureID
+	}
+	worlds := auth.FromContext(ctx).RestrictWorlds(s.worlds)
+	metadata := ingest.WorldMetadata{Name: request.Name, Author: request.Author}
+	if err := worlds.SetWorldMetadata(id, metadata); err != nil {
+		return nil, toStatus(err)
+	}
+	metadata, err := worlds.WorldMetadata(id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.SetWorldMetadataResponseProto{Metadata: newWorldMetadataProto(id, metadata)}, nil
+}
+
+func (s *service) DiffWorlds(ctx context.Context, request *pb.DiffWorldsRequestProto) (*pb.DiffWorldsResponseProto, error) {
+	ids := []b6.FeatureID{b6.NewFeatureIDFromProto(request.From), b6.NewFeatureIDFromProto(request.To)}
+	for i, id := range ids {
+		if !id.IsValid() {
+			ids[i] = ingest.DefaultWorldFeatureID
+		}
+		// Avoid creating worlds that don't exist.
+		if _, err := s.worlds.WorldMetadata(ids[i]); err != nil {
+			return nil, toStatus(err)
+		}
+	}
+	// Locks are taken in a consistent order to avoid deadlocks between
+	// concurrent diffs in opposite directions.
+	locks := []b6.FeatureID{ids[0], ids[1]}
+	if locks[1].Less(locks[0]) {
+		locks[0], locks[1] = locks[1], locks[0]
+	} else if locks[0] == locks[1] {
+		locks = locks[0:1]
+	}
+	for _, id := range locks {
+		unlock := s.locks.RLock(id)
+		defer unlock()
+	}
+	diffs, err := ingest.DiffWorlds(s.worlds.FindOrCreateWorld(ids[0]), s.worlds.FindOrCreateWorld(ids[1]))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	response := &pb.DiffWorldsResponseProto{
+		Features: make([]*pb.FeatureDiffProto, len(diffs)),
+	}
+	for i, diff := range diffs {
+		response.Features[i] = &pb.FeatureDiffProto{
+			Id:          b6.NewProtoFromFeatureID(diff.ID),
+			Added:       diff.Added,
+			Removed:     diff.Removed,
+			RemovedTags: diff.RemovedTags,
+			Members:     diff.Members,
+		}
+		for _, tag := range diff.Tags {
+			response.Features[i].Tags = append(response.Features[i].Tags, &pb.TagProto{Key: tag.Key, Value: tag.Value.String()})
+		}
+	}
+	return response, nil
+}
+
+func NewB6Service(worlds ingest.Worlds, options api.Options, locks *ingest.WorldLocks) pb.B6Server {
 	return &service{
-		worlds:  worlds,
-		fs:      functions.Functions(),
-		a:       functions.Adaptors(),
-		options: options,
-		lock:    lock,
+		worlds:    worlds,
+		fs:        functions.Functions(),
+		a:         functions.Adaptors(),
+		options:   options,
+		completer: functions.NewCompleter(),
+		locks:     locks,
 	}
 }
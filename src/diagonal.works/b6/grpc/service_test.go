@@ -2,15 +2,21 @@ package grpc
 
 import (
 	"context"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"diagonal.works/b6"
 	"diagonal.works/b6/api"
+	"diagonal.works/b6/auth"
 	"diagonal.works/b6/ingest"
+	"diagonal.works/b6/ingest/compact"
 	pb "diagonal.works/b6/proto"
+	"diagonal.works/b6/test"
 	"diagonal.works/b6/test/camden"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func findInTagsProto(tags []*pb.TagProto, key string) (string, bool) {
@@ -40,8 +46,8 @@ func TestGRPC(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			var lock sync.RWMutex
-			test.f(NewB6Service(w, api.Options{Cores: 1}, &lock), w.Base, t)
+			var locks ingest.WorldLocks
+			test.f(NewB6Service(w, api.Options{Cores: 1}, &locks), w.Base, t)
 		})
 	}
 }
@@ -164,3 +170,374 @@ func ValidateRejectRequestsWithDifferentMajorVersion(service pb.B6Server, w b6.W
 		t.Error("Expected error with different major version, found none")
 	}
 }
+
+func TestEvaluateReturnsResourceExhaustedWhenLimitsAreExceeded(t *testing.T) {
+	w := &ingest.MutableWorlds{
+		Base: ingest.NewMutableOverlayWorld(camden.BuildGranarySquareForTests(t)),
+	}
+	var locks ingest.WorldLocks
+	service := NewB6Service(w, api.Options{Cores: 1, Limits: api.Limits{MaxFeatures: 2}}, &locks)
+
+	e, err := api.ParseExpression(`find [#building] | map {b -> get b "building:levels"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := e.ToProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+	request := &pb.EvaluateRequestProto{
+		Request: p,
+		Version: b6.ApiVersion,
+	}
+	_, err = service.Evaluate(context.Background(), request)
+	if s, ok := status.FromError(err); !ok || s.Code() != codes.ResourceExhausted {
+		t.Errorf("Expected ResourceExhausted, found %v", err)
+	}
+}
+
+func TestEvaluateReturnsProfileWhenRequested(t *testing.T) {
+	w := &ingest.MutableWorlds{
+		Base: ingest.NewMutableOverlayWorld(camden.BuildGranarySquareForTests(t)),
+	}
+	var locks ingest.WorldLocks
+	service := NewB6Service(w, api.Options{Cores: 1}, &locks)
+
+	e, err := api.ParseExpression(`find [#building] | map {b -> get b "building:levels"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := e.ToProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+	request := &pb.EvaluateRequestProto{
+		Request: p,
+		Version: b6.ApiVersion,
+		Profile: true,
+	}
+	response, err := service.Evaluate(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	functions := make(map[string]int64)
+	for _, entry := range response.Profile.GetEntries() {
+		functions[entry.Function] = entry.Items
+	}
+	if items, ok := functions["find"]; !ok || items == 0 {
+		t.Errorf("Expected a profile entry for find returning items, found %v", functions)
+	}
+
+	request.Profile = false
+	if response, err = service.Evaluate(context.Background(), request); err != nil {
+		t.Fatal(err)
+	} else if response.Profile != nil {
+		t.Error("Expected no profile when not requested")
+	}
+}
+
+func TestCompleteReflectsChangesToTheWorld(t *testing.T) {
+	w := &ingest.MutableWorlds{
+		Base: ingest.NewMutableOverlayWorld(camden.BuildGranarySquareForTests(t)),
+	}
+	var locks ingest.WorldLocks
+	service := NewB6Service(w, api.Options{Cores: 1}, &locks)
+
+	const partial = "find [#cuisine=ta"
+	complete := func() []string {
+		response, err := service.Complete(context.Background(), &pb.CompleteRequestProto{Expression: partial, Cursor: int32(len(partial))})
+		if err != nil {
+			t.Fatal(err)
+		}
+		texts := make([]string, 0, len(response.Completions))
+		for _, c := range response.Completions {
+			if c.Type != pb.CompletionType_CompletionTypeTag {
+				t.Errorf("Expected a tag completion, found %s", c.Type)
+			}
+			texts = append(texts, c.Text)
+		}
+		return texts
+	}
+	if texts := complete(); len(texts) != 0 {
+		t.Fatalf("Expected no completions, found %v", texts)
+	}
+
+	e, err := api.ParseExpression("add-tag /n/6082053666 #cuisine=tapas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := e.ToProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Evaluate(context.Background(), &pb.EvaluateRequestProto{Request: p, Version: b6.ApiVersion}); err != nil {
+		t.Fatal(err)
+	}
+	if texts := complete(); len(texts) != 1 || texts[0] != "#cuisine=tapas" {
+		t.Errorf("Expected a completion for the added tag, found %v", texts)
+	}
+}
+
+func TestWorldInfoDescribesUnderlyingIndices(t *testing.T) {
+	pbf := ingest.PBFFilesOSMSource{Glob: test.Data(test.GranarySquarePBF), FailWhenNoFiles: true}
+	source, err := ingest.NewFeatureSourceFromPBF(&pbf, &ingest.BuildOptions{Cores: 2}, context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := &pb.CompactHeaderProto{ReplicationTimestamp: 1700000000}
+	index, err := compact.BuildInMemory(source, &compact.Options{Goroutines: 2, Header: header})
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, err := compact.NewWorldFromData(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := &ingest.MutableWorlds{Base: base}
+	var locks ingest.WorldLocks
+	service := NewB6Service(w, api.Options{Cores: 1}, &locks)
+
+	response, err := service.WorldInfo(context.Background(), &pb.WorldInfoRequestProto{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Indices) != 1 {
+		t.Fatalf("Expected 1 index, found %d", len(response.Indices))
+	}
+	info := response.Indices[0]
+	if info.Header.ReplicationTimestamp != header.ReplicationTimestamp {
+		t.Errorf("Expected replication timestamp %d, found %d", header.ReplicationTimestamp, info.Header.ReplicationTimestamp)
+	}
+	if info.Metadata == nil || len(info.Metadata.Blocks) == 0 {
+		t.Errorf("Expected block checksums, found %v", info.Metadata)
+	}
+
+	missing := b6.FeatureID{Type: b6.FeatureTypeCollection, Namespace: ingest.DefaultWorldFeatureID.Namespace, Value: 42}
+	if _, err := service.WorldInfo(context.Background(), &pb.WorldInfoRequestProto{Id: b6.NewProtoFromFeatureID(missing)}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound for a missing world, found %v", err)
+	}
+	if _, err := w.WorldMetadata(missing); err == nil {
+		t.Error("Expected asking about a missing world not to create it")
+	}
+}
+
+func TestEvaluateEnforcesPermissions(t *testing.T) {
+	w := &ingest.MutableWorlds{
+		Base: ingest.NewMutableOverlayWorld(camden.BuildGranarySquareForTests(t)),
+	}
+	var locks ingest.WorldLocks
+	service := NewB6Service(w, api.Options{Cores: 1}, &locks)
+
+	e, err := api.ParseExpression("add-tag /n/6082053666 #cuisine=tapas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := e.ToProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+	allowed := b6.FeatureID{Type: b6.FeatureTypeCollection, Namespace: ingest.DefaultWorldFeatureID.Namespace, Value: 1}
+	other := b6.FeatureID{Type: b6.FeatureTypeCollection, Namespace: ingest.DefaultWorldFeatureID.Namespace, Value: 2}
+	ctx := auth.NewContext(context.Background(), &auth.Permissions{Worlds: []b6.FeatureID{allowed}})
+
+	request := &pb.EvaluateRequestProto{Request: p, Version: b6.ApiVersion, Root: b6.NewProtoFromFeatureID(allowed)}
+	if _, err := service.Evaluate(ctx, request); err != nil {
+		t.Errorf("Expected no error modifying an allowed world, found %s", err)
+	}
+	request.Root = b6.NewProtoFromFeatureID(other)
+	if _, err := service.Evaluate(ctx, request); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied modifying another world, found %v", err)
+	}
+	if _, err := service.DeleteWorld(ctx, &pb.DeleteWorldRequestProto{Id: b6.NewProtoFromFeatureID(other)}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied deleting another world, found %v", err)
+	}
+
+	readOnly := auth.NewContext(context.Background(), &auth.Permissions{ReadOnly: true})
+	e, err = api.ParseExpression("parse-geojson-file \"/dev/null\"")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, err = e.ToProto(); err != nil {
+		t.Fatal(err)
+	}
+	request = &pb.EvaluateRequestProto{Request: p, Version: b6.ApiVersion}
+	service = NewB6Service(w, api.Options{Cores: 1, FileIOAllowed: true}, &locks)
+	if _, err := service.Evaluate(readOnly, request); err == nil || !strings.Contains(err.Error(), "File IO is not allowed") {
+		t.Errorf("Expected an error using file IO without permission, found %v", err)
+	}
+}
+
+func TestChangesOnlyBlockReadersOfTheSameWorld(t *testing.T) {
+	w := &ingest.MutableWorlds{
+		Base: camden.BuildGranarySquareForTests(t),
+	}
+	var locks ingest.WorldLocks
+	service := NewB6Service(w, api.Options{Cores: 1}, &locks)
+
+	e, err := api.ParseExpression(`find [#building] | map {b -> get b "building:levels"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := e.ToProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a long running change to a scenario, during which
+	// evaluations on the default world should proceed.
+	scenario := b6.FeatureID{Type: b6.FeatureTypeCollection, Namespace: ingest.DefaultWorldFeatureID.Namespace, Value: 1}
+	unlock := locks.Lock(scenario)
+	done := make(chan error, 2)
+	go func() {
+		_, err := service.Evaluate(context.Background(), &pb.EvaluateRequestProto{Request: p, Version: b6.ApiVersion})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Expected evaluation on the default world not to wait for the scenario")
+	}
+
+	go func() {
+		_, err := service.Evaluate(context.Background(), &pb.EvaluateRequestProto{Request: p, Version: b6.ApiVersion, Root: b6.NewProtoFromFeatureID(scenario)})
+		done <- err
+	}()
+	select {
+	case <-done:
+		t.Fatal("Expected evaluation on the scenario to wait for the change")
+	case <-time.After(100 * time.Millisecond):
+	}
+	unlock()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeleteWorldWaitsForReaders(t *testing.T) {
+	w := &ingest.MutableWorlds{
+		Base: camden.BuildGranarySquareForTests(t),
+	}
+	var locks ingest.WorldLocks
+	service := NewB6Service(w, api.Options{Cores: 1}, &locks)
+
+	scenario := b6.FeatureID{Type: b6.FeatureTypeCollection, Namespace: ingest.DefaultWorldFeatureID.Namespace, Value: 1}
+	w.FindOrCreateWorld(scenario)
+	unlock := locks.RLock(scenario)
+	done := make(chan error, 1)
+	go func() {
+		_, err := service.DeleteWorld(context.Background(), &pb.DeleteWorldRequestProto{Id: b6.NewProtoFromFeatureID(scenario)})
+		done <- err
+	}()
+	select {
+	case <-done:
+		t.Fatal("Expected deleting the world to wait for readers")
+	case <-time.After(100 * time.Millisecond):
+	}
+	unlock()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.WorldMetadata(scenario); err == nil {
+		t.Error("Expected world to be deleted")
+	}
+}
+
+func TestCloneRenameAndDiffWorlds(t *testing.T) {
+	w := &ingest.MutableWorlds{
+		Base: camden.BuildGranarySquareForTests(t),
+	}
+	var locks ingest.WorldLocks
+	service := NewB6Service(w, api.Options{Cores: 1}, &locks)
+
+	e, err := api.ParseExpression("add-tag /n/6082053666 #cuisine=tapas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := e.ToProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := b6.FeatureID{Type: b6.FeatureTypeCollection, Namespace: ingest.DefaultWorldFeatureID.Namespace, Value: 1}
+	clone := b6.FeatureID{Type: b6.FeatureTypeCollection, Namespace: ingest.DefaultWorldFeatureID.Namespace, Value: 2}
+	renamed := b6.FeatureID{Type: b6.FeatureTypeCollection, Namespace: ingest.DefaultWorldFeatureID.Namespace, Value: 3}
+	ctx := auth.NewContext(context.Background(), &auth.Permissions{Subject: "andrew"})
+	if _, err := service.Evaluate(ctx, &pb.EvaluateRequestProto{Request: p, Version: b6.ApiVersion, Root: b6.NewProtoFromFeatureID(original)}); err != nil {
+		t.Fatal(err)
+	}
+
+	response, err := service.CloneWorld(ctx, &pb.CloneWorldRequestProto{
+		From: b6.NewProtoFromFeatureID(original),
+		To:   b6.NewProtoFromFeatureID(clone),
+		Name: "Tapas",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata := response.Metadata; metadata.Name != "Tapas" || metadata.Author != "andrew" || b6.NewFeatureIDFromProto(metadata.Parent) != original {
+		t.Errorf("Unexpected metadata for clone: %v", metadata)
+	}
+	if _, err := service.CloneWorld(ctx, &pb.CloneWorldRequestProto{From: b6.NewProtoFromFeatureID(original), To: b6.NewProtoFromFeatureID(clone)}); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("Expected AlreadyExists cloning to an existing world, found %v", err)
+	}
+
+	if _, err := service.RenameWorld(ctx, &pb.RenameWorldRequestProto{From: b6.NewProtoFromFeatureID(clone), To: b6.NewProtoFromFeatureID(renamed)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.RenameWorld(ctx, &pb.RenameWorldRequestProto{From: b6.NewProtoFromFeatureID(clone), To: b6.NewProtoFromFeatureID(renamed)}); status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound renaming a missing world, found %v", err)
+	}
+
+	if _, err := service.FreezeWorld(ctx, &pb.FreezeWorldRequestProto{Id: b6.NewProtoFromFeatureID(renamed)}); err != nil {
+		t.Fatal(err)
+	}
+	e, err = api.ParseExpression("remove-tag /n/6082053666 \"#cuisine\"")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, err = e.ToProto(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Evaluate(ctx, &pb.EvaluateRequestProto{Request: p, Version: b6.ApiVersion, Root: b6.NewProtoFromFeatureID(renamed)}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition modifying a frozen world, found %v", err)
+	}
+	if _, err := service.Evaluate(ctx, &pb.EvaluateRequestProto{Request: p, Version: b6.ApiVersion, Root: b6.NewProtoFromFeatureID(original)}); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := service.DiffWorlds(ctx, &pb.DiffWorldsRequestProto{From: b6.NewProtoFromFeatureID(original), To: b6.NewProtoFromFeatureID(renamed)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := b6.FeatureID{Type: b6.FeatureTypePoint, Namespace: b6.NamespaceOSMNode, Value: 6082053666}
+	if len(diff.Features) != 1 || b6.NewFeatureIDFromProto(diff.Features[0].Id) != expected {
+		t.Fatalf("Expected a difference for %s only, found %v", expected, diff.Features)
+	}
+	if cuisine, ok := findInTagsProto(diff.Features[0].Tags, "#cuisine"); !ok || cuisine != "tapas" {
+		t.Errorf("Expected #cuisine=tapas in diff, found %v", diff.Features[0].Tags)
+	}
+}
+
+func TestCompleteDoesntCreateWorlds(t *testing.T) {
+	w := &ingest.MutableWorlds{
+		Base: camden.BuildGranarySquareForTests(t),
+	}
+	var locks ingest.WorldLocks
+	service := NewB6Service(w, api.Options{Cores: 1}, &locks)
+
+	unknown := b6.FeatureID{Type: b6.FeatureTypeCollection, Namespace: ingest.DefaultWorldFeatureID.Namespace, Value: 1}
+	const partial = "find [#amenity=ca"
+	response, err := service.Complete(context.Background(), &pb.CompleteRequestProto{Expression: partial, Cursor: int32(len(partial)), Root: b6.NewProtoFromFeatureID(unknown)})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(response.Completions) == 0 {
+		t.Error("Expected completions from the default world")
+	}
+	for _, id := range w.ListWorlds() {
+		if id == unknown {
+			t.Errorf("Expected completion not to create world %s", unknown)
+		}
+	}
+}
@@ -126,6 +126,16 @@ type Options struct {
 	ScratchDirectory        string
 	OutputFilename          string
 	PointsScratchOutputType OutputType
+	// If true, feature blocks are compressed, making the index smaller at
+	// the cost of decompressing features when they're first read. Features
+	// are first written uncompressed to scratch space, which uses memory
+	// or disk following FeaturesScratchOutputType.
+	CompressFeatures          bool
+	FeaturesScratchOutputType OutputType
+	// Provenance to record in the index header, for example the source
+	// files and flags used. Namespaces, feature counts, the builder and
+	// build time are filled in during the build.
+	Header *pb.CompactHeaderProto
 }
 
 func (o *Options) Output() Output {
@@ -140,6 +150,14 @@ func (o *Options) PointsScratchOutput() Output {
 	}
 }
 
+func (o *Options) FeaturesScratchOutput() Output {
+	if o.FeaturesScratchOutputType == OutputTypeMemory {
+		return &MemoryOutput{}
+	} else {
+		return FileOutput(path.Join(o.ScratchDirectory, "features.scratch"))
+	}
+}
+
 const (
 	maxEncodedFeatureSize = 64 * 1024 * 1204 // Measured empirically
 
@@ -827,7 +845,9 @@ func (l LocationsByID) FindLocationByID(id b6.FeatureID) (s2.LatLng, error) {
 	for _, b := range l.bs {
 		ns := l.nt.Encode(id.Namespace)
 		if b.Namespaces[b6.FeatureTypePoint] == ns {
-			if p := b.Map.FindFirstWithTag(id.Value, PointTag); p != nil {
+			if p, err := b.Map.FindFirstWithTag(id.Value, PointTag); err != nil {
+				return s2.LatLng{}, fmt.Errorf("%s: %w", id, err)
+			} else if p != nil {
 				point := MarshalledTags{p, l.s, l.nt, TypeAndNamespaceInvalid}.Point()
 				if point.Norm() != 0 {
 					return s2.LatLngFromPoint(point), nil
@@ -876,19 +896,61 @@ func buildFeatures(source ingest.FeatureSource, o *Options, sb *encoding.StringT
 	points := make(FeatureBlocks, 0)
 	points.Unmarshal(data)
 
-	offset, err := writePoints(o, points, nt, summary, header.BlockOffset, w)
+	// With compression, blocks are written to scratch space first, since
+	// their compressed length isn't known until they're complete.
+	var features Output
+	var scratch WriteCloserAt
+	featuresW, offset := w, header.BlockOffset
+	if o.CompressFeatures {
+		features = o.FeaturesScratchOutput()
+		if scratch, err = features.Write(); err != nil {
+			return 0, err
+		}
+		featuresW, offset = scratch, 0
+	}
+
+	offset, err = writePoints(o, points, nt, summary, offset, featuresW)
 	log.Printf("writePoints: %d", offset)
 	if err != nil {
 		return 0, err
 	}
 
 	locations := overlayLocationsByID{overlay: NewLocationsByID(points, encoding.NewStringTable(strings[header.StringsOffset:]), nt), base: base}
-	offset, err = writePathsAreasAndRelations(source, o, sb, nt, &locations, summary, offset, w)
+	offset, err = writePathsAreasAndRelations(source, o, sb, nt, &locations, summary, offset, featuresW)
 	log.Printf("writePathsAreasAndRelations: %d", offset)
 	if err != nil {
 		return 0, err
 	}
-	return offset, closer.Close()
+	if err := closer.Close(); err != nil {
+		return 0, err
+	}
+
+	if o.CompressFeatures {
+		if err := scratch.Close(); err != nil {
+			return 0, err
+		}
+		offset, err = compressFeatures(features, o, header.BlockOffset, w)
+		log.Printf("compressFeatures: %d", offset)
+	}
+	return offset, err
+}
+
+// compressFeatures copies the feature blocks written to the given output
+// into w, compressing them.
+func compressFeatures(features Output, o *Options, offset encoding.Offset, w io.WriterAt) (encoding.Offset, error) {
+	data, closer, err := features.Bytes()
+	if err != nil {
+		return offset, err
+	}
+	defer closer.Close()
+	blocks := make(FeatureBlocks, 0)
+	blocks.Unmarshal(data)
+	for _, block := range blocks {
+		if offset, err = block.WriteCompressed(w, offset, o.Goroutines); err != nil {
+			break
+		}
+	}
+	return offset, err
 }
 
 func fillIndex(byID *FeaturesByID, nt *NamespaceTable, index map[string]*FeatureIDs) ([]string, error) {
@@ -1047,7 +1109,11 @@ func buildIndexWithFeaturesOnly(source ingest.FeatureSource, base b6.FeaturesByI
 
 	var buffer [HeaderLength]byte
 	header.VersionOffset = encoding.Offset(header.Marshal(buffer[0:]))
-	n := MarshalString(Version, buffer[0:])
+	version := Version
+	if o.CompressFeatures {
+		version = CompressedVersion
+	}
+	n := MarshalString(version, buffer[0:])
 	if n, err := w.WriteAt(buffer[0:n], int64(header.VersionOffset)); err == nil {
 		header.HeaderProtoOffset = header.VersionOffset.Add(n)
 	} else {
@@ -1062,9 +1128,8 @@ func buildIndexWithFeaturesOnly(source ingest.FeatureSource, base b6.FeaturesByI
 	var nt NamespaceTable
 	fillNamespaceTableFromSummary(summary, &nt)
 
-	var hp pb.CompactHeaderProto
-	nt.FillProto(&hp)
-	header.StringsOffset, err = WriteProto(w, &hp, header.HeaderProtoOffset)
+	hp := fillHeaderProto(o, &nt, summary)
+	header.StringsOffset, err = WriteProto(w, hp, header.HeaderProtoOffset)
 
 	log.Printf("build: write strings")
 	header.BlockOffset, err = sb.Write(w, header.StringsOffset)
@@ -1096,11 +1161,18 @@ func buildIndexWithFeaturesOnly(source ingest.FeatureSource, base b6.FeaturesByI
 
 func build(source ingest.FeatureSource, base b6.FeaturesByID, o *Options, output Output) error {
 	byID, closer, err := buildIndexWithFeaturesOnly(source, base, o, output)
+	if err != nil {
+		return err
+	}
+	defer closer.Close()
+	runtime.GC()
+	bounds, err := pointBounds(byID, o.Goroutines)
 	if err == nil {
-		defer closer.Close()
-		runtime.GC()
 		err = buildIndex(byID, output)
 	}
+	if err == nil {
+		err = writeMetadata(bounds, output)
+	}
 	return err
 }
 
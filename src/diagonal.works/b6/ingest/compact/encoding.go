@@ -23,8 +23,14 @@ import (
 )
 
 // A semver 2.0.0 compliant version for the index format. Indicies generated
-// with a different major version will fail to load.
-const Version = "5.0.0"
+// with a major version other than that of Version or CompressedVersion will
+// fail to load.
+const Version = "5.2.0"
+
+// The version of indices with compressed feature blocks. It has a new major
+// version since readers of version 5 skip blocks they don't recognise, and
+// would load these indices without their features.
+const CompressedVersion = "6.0.0"
 
 const FilenameVersionPattern = "VERSION"
 
@@ -79,6 +85,12 @@ type BlockType uint64
 const (
 	BlockTypeFeatures    BlockType = 0
 	BlockTypeSearchIndex BlockType = 1
+	// Features, with the map's buckets compressed in frames, see
+	// encoding.WriteCompressedUint64Map.
+	BlockTypeCompressedFeatures BlockType = 2
+	// Bounds and checksums of the other blocks, written last, see
+	// pb.CompactMetadataProto.
+	BlockTypeMetadata BlockType = 3
 )
 
 type BlockHeader struct {
@@ -2145,6 +2157,32 @@ func (f *FeatureBlock) Unmarshal(buffer []byte) int {
 	return i + f.Map.Length()
 }
 
+func (f *FeatureBlock) UnmarshalCompressed(buffer []byte) int {
+	i := f.FeatureBlockHeader.Unmarshal(buffer)
+	f.Map = encoding.NewCompressedUint64Map(buffer[i:])
+	return i + f.Map.Length()
+}
+
+// WriteCompressed writes the block with its map compressed, returning the
+// offset of the end of the block.
+func (f *FeatureBlock) WriteCompressed(w io.WriterAt, offset encoding.Offset, goroutines int) (encoding.Offset, error) {
+	var buffer [BlockHeaderLength + FeatureBlockHeaderLength]byte
+	n := f.FeatureBlockHeader.Marshal(buffer[0:])
+	if _, err := w.WriteAt(buffer[0:n], int64(offset.Add(BlockHeaderLength))); err != nil {
+		return offset, err
+	}
+	end, err := encoding.WriteCompressedUint64Map(f.Map, w, offset.Add(BlockHeaderLength+n), goroutines)
+	if err != nil {
+		return offset, err
+	}
+	header := BlockHeader{Type: BlockTypeCompressedFeatures, Length: uint64(end.Difference(offset) - BlockHeaderLength)}
+	n = header.Marshal(buffer[0:])
+	if _, err := w.WriteAt(buffer[0:n], int64(offset)); err != nil {
+		return offset, err
+	}
+	return end, nil
+}
+
 type FeatureBlockBuilder struct {
 	Header FeatureBlockHeader
 	Map    *encoding.Uint64MapBuilder
@@ -2229,6 +2267,9 @@ func (f *FeatureBlocks) Unmarshal(buffer []byte) int {
 		if header.Type == BlockTypeFeatures {
 			block.Unmarshal(buffer[i:])
 			*f = append(*f, block)
+		} else if header.Type == BlockTypeCompressedFeatures {
+			block.UnmarshalCompressed(buffer[i:])
+			*f = append(*f, block)
 		}
 		i += int(header.Length)
 	}
@@ -0,0 +1,346 @@
+package compact
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/encoding"
+	pb "diagonal.works/b6/proto"
+
+	"golang.org/x/sync/errgroup"
+)
+
+func (b BlockType) String() string {
+	switch b {
+	case BlockTypeFeatures:
+		return "features"
+	case BlockTypeSearchIndex:
+		return "search-index"
+	case BlockTypeCompressedFeatures:
+		return "compressed-features"
+	case BlockTypeMetadata:
+		return "metadata"
+	}
+	return fmt.Sprintf("unknown(%d)", uint64(b))
+}
+
+// BlockInfo describes one block of a compact index.
+type BlockInfo struct {
+	Offset      encoding.Offset // Of the block header
+	Type        BlockType
+	Length      uint64         // Excluding the block header
+	FeatureType b6.FeatureType // For feature blocks
+	Namespace   b6.Namespace   // For feature blocks
+	Buckets     int            // For feature blocks
+	Tokens      int            // For search index blocks
+}
+
+// IndexInfo describes the layout of a compact index, as read from its
+// headers, without reading features.
+type IndexInfo struct {
+	Length        int
+	Version       string
+	Namespaces    []b6.Namespace
+	StringsLength int
+	Blocks        []BlockInfo
+}
+
+// ReadIndexInfo returns the layout of the given compact index, or an
+// error if its headers are inconsistent with its length. Unlike Merge,
+// it doesn't require a compatible version, so older indices can be
+// inspected.
+func ReadIndexInfo(data []byte) (*IndexInfo, error) {
+	if len(data) < HeaderLength {
+		return nil, fmt.Errorf("expected at least %d bytes, found %d", HeaderLength, len(data))
+	}
+	var header Header
+	header.Unmarshal(data)
+	if header.Magic != HeaderMagic {
+		return nil, fmt.Errorf("bad header magic: expected %x, found %x", uint64(HeaderMagic), header.Magic)
+	}
+	offsets := []encoding.Offset{header.VersionOffset, header.HeaderProtoOffset, header.StringsOffset, header.BlockOffset, encoding.Offset(len(data))}
+	for i := 1; i < len(offsets); i++ {
+		if offsets[i] < offsets[i-1] {
+			return nil, fmt.Errorf("header offsets out of order: %v", offsets[0:len(offsets)-1])
+		}
+	}
+
+	info := &IndexInfo{
+		Length:        len(data),
+		Version:       header.UnmarshalVersion(data),
+		StringsLength: header.BlockOffset.Difference(header.StringsOffset),
+	}
+	var hp pb.CompactHeaderProto
+	if err := UnmarshalProto(data[header.HeaderProtoOffset:header.StringsOffset], &hp); err != nil {
+		return nil, fmt.Errorf("bad header proto: %w", err)
+	}
+	var nt NamespaceTable
+	nt.FillFromProto(&hp)
+	info.Namespaces = nt.FromEncoded
+
+	offset := header.BlockOffset
+	for offset < encoding.Offset(len(data)) {
+		if offset.Add(BlockHeaderLength) > encoding.Offset(len(data)) {
+			return nil, fmt.Errorf("truncated block header at %d", offset)
+		}
+		block := BlockInfo{Offset: offset}
+		var bh BlockHeader
+		start := offset.Add(bh.Unmarshal(data[offset:]))
+		block.Type = bh.Type
+		block.Length = bh.Length
+		if bh.Length > uint64(len(data)) || start.Add(int(bh.Length)) > encoding.Offset(len(data)) {
+			return nil, fmt.Errorf("%s block at %d with length %d extends beyond end of index", bh.Type, offset, bh.Length)
+		}
+		switch bh.Type {
+		case BlockTypeFeatures, BlockTypeCompressedFeatures:
+			var fbh FeatureBlockHeader
+			if bh.Length < FeatureBlockHeaderLength+encoding.Uint64MapLayoutLength {
+				return nil, fmt.Errorf("%s block at %d too short", bh.Type, offset)
+			}
+			n := fbh.Unmarshal(data[start:])
+			if fbh.FeatureType >= b6.FeatureTypeEnd {
+				return nil, fmt.Errorf("%s block at %d has bad feature type %d", bh.Type, offset, fbh.FeatureType)
+			}
+			if ns := fbh.Namespaces[fbh.FeatureType]; int(ns) < len(nt.FromEncoded) {
+				block.Namespace = nt.Decode(ns)
+			} else {
+				return nil, fmt.Errorf("%s block at %d has bad namespace %d", bh.Type, offset, ns)
+			}
+			block.FeatureType = fbh.FeatureType
+			var layout encoding.Uint64MapLayout
+			layout.Unmarshal(data[start.Add(n):])
+			block.Buckets = layout.SentinelBucket()
+		case BlockTypeSearchIndex:
+			if index, err := NewIndex(data[start:start.Add(int(bh.Length))], &nt, nil); err == nil {
+				block.Tokens = index.NumTokens()
+			} else {
+				return nil, err
+			}
+		}
+		info.Blocks = append(info.Blocks, block)
+		offset = start.Add(int(bh.Length))
+	}
+	return info, nil
+}
+
+// TokenInfo describes a token in the search index.
+type TokenInfo struct {
+	Token    string
+	Features int
+}
+
+// TopTokens returns the n tokens with the longest posting lists, in
+// decreasing order of length, ties broken by token.
+func (w *World) TopTokens(n int) []TokenInfo {
+	tokens := make([]TokenInfo, 0)
+	var header PostingListHeader
+	for _, index := range w.indices {
+		for i := 0; i < index.b.NumItems(); i++ {
+			header.Unmarshal(index.b.Item(i))
+			tokens = append(tokens, TokenInfo{Token: header.Token, Features: header.Features})
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		if tokens[i].Features != tokens[j].Features {
+			return tokens[i].Features > tokens[j].Features
+		}
+		return tokens[i].Token < tokens[j].Token
+	})
+	if len(tokens) > n {
+		tokens = tokens[0:n]
+	}
+	return tokens
+}
+
+// CountFeatures returns the number of features of each type in each
+// namespace, together with the number of points referenced by paths,
+// paths referenced by areas, and relation members.
+func (w *World) CountFeatures(goroutines int) (*NamespacedCounts, error) {
+	counts := NewNamespacedCounts()
+	each := func(f b6.Feature, _ int) error {
+		c := counts.Namespace(f.FeatureID().Namespace)
+		switch f := f.(type) {
+		case b6.AreaFeature:
+			atomic.AddUint64(&c.Areas, 1)
+			if m, ok := f.(*marshalledArea); ok {
+				for i := 0; i < m.Len(); i++ {
+					ids, _ := m.pathIDs(i)
+					atomic.AddUint64(&c.AreaPaths, uint64(len(ids)))
+				}
+			}
+		case b6.RelationFeature:
+			atomic.AddUint64(&c.Relations, 1)
+			atomic.AddUint64(&c.RelationMembers, uint64(f.Len()))
+		case b6.PhysicalFeature:
+			if f.FeatureID().Type == b6.FeatureTypePoint {
+				atomic.AddUint64(&c.Points, 1)
+			} else {
+				atomic.AddUint64(&c.Paths, 1)
+				atomic.AddUint64(&c.PathPoints, uint64(len(f.References())))
+			}
+		}
+		return nil
+	}
+	return counts, w.byID.EachFeature(each, &b6.EachFeatureOptions{Goroutines: goroutines})
+}
+
+// pathIDs returns the IDs of the paths forming the given polygon, or
+// false if the polygon's geometry is stored directly.
+func (m *marshalledArea) pathIDs(i int) ([]b6.FeatureID, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.fillGeometry()
+	references, ok := m.geometry.PathIDs(i)
+	if !ok {
+		return nil, false
+	}
+	ids := make([]b6.FeatureID, len(references))
+	for j, r := range references {
+		typ, ns := r.TypeAndNamespace.Split()
+		ids[j] = b6.FeatureID{Type: typ, Namespace: m.fb.NamespaceTable.Decode(ns), Value: r.Value}
+	}
+	return ids, true
+}
+
+// MaxValidationErrors is the maximum number of errors returned by
+// Validate, beyond which errors are only counted.
+const MaxValidationErrors = 100
+
+// ValidationResult holds the problems found by Validate.
+type ValidationResult struct {
+	Errors []error
+	// The total number of errors, which may exceed len(Errors)
+	NumErrors int
+	// Relation members missing from the world aren't errors, since
+	// extracts commonly include relations that extend beyond them.
+	MissingRelationMembers int
+
+	lock sync.Mutex
+}
+
+func (v *ValidationResult) add(err error) {
+	v.lock.Lock()
+	if len(v.Errors) < MaxValidationErrors {
+		v.Errors = append(v.Errors, err)
+	}
+	v.NumErrors++
+	v.lock.Unlock()
+}
+
+// Validate reads every feature and search index posting list in the
+// world, returning errors for references to features that don't exist,
+// posting lists inconsistent with their headers, and data that can't be
+// decoded. References are resolved via the world's base, allowing
+// overlays to be validated.
+func (w *World) Validate(goroutines int) *ValidationResult {
+	v := &ValidationResult{}
+	each := func(f b6.Feature, _ int) error {
+		defer func() {
+			if r := recover(); r != nil {
+				v.add(fmt.Errorf("%s: can't decode: %v", f.FeatureID(), r))
+			}
+		}()
+		w.validateFeature(f, v)
+		return nil
+	}
+	if err := w.byID.EachFeature(each, &b6.EachFeatureOptions{Goroutines: goroutines}); err != nil {
+		v.add(err)
+	}
+	for _, index := range w.indices {
+		index.validate(goroutines, v)
+	}
+	return v
+}
+
+func (w *World) validateFeature(f b6.Feature, v *ValidationResult) {
+	switch f := f.(type) {
+	case *marshalledArea:
+		for i := 0; i < f.Len(); i++ {
+			ids, ok := f.pathIDs(i)
+			if !ok {
+				continue
+			}
+			for _, id := range ids {
+				if path, ok := w.FindFeatureByID(id).(b6.PhysicalFeature); !ok || path.GeometryType() != b6.GeometryTypePath {
+					v.add(fmt.Errorf("%s: missing path %s", f.FeatureID(), id))
+				}
+			}
+		}
+	case b6.RelationFeature:
+		missing := 0
+		for i := 0; i < f.Len(); i++ {
+			if w.FindFeatureByID(f.Member(i).ID) == nil {
+				missing++
+			}
+		}
+		v.lock.Lock()
+		v.MissingRelationMembers += missing
+		v.lock.Unlock()
+	case b6.PhysicalFeature:
+		if f.FeatureID().Type == b6.FeatureTypePath {
+			for _, r := range f.References() {
+				if _, err := w.FindLocationByID(r.Source()); err != nil {
+					v.add(fmt.Errorf("%s: missing point %s", f.FeatureID(), r.Source()))
+				}
+			}
+		}
+	}
+}
+
+func (i *Index) validate(goroutines int, v *ValidationResult) {
+	if goroutines < 1 {
+		goroutines = 1
+	}
+	tokens := make(chan int)
+	g, ctx := errgroup.WithContext(context.Background())
+	for j := 0; j < goroutines; j++ {
+		g.Go(func() error {
+			for token := range tokens {
+				i.validatePostingList(token, v)
+			}
+			return nil
+		})
+	}
+done:
+	for token := 0; token < i.b.NumItems(); token++ {
+		select {
+		case tokens <- token:
+		case <-ctx.Done():
+			break done
+		}
+	}
+	close(tokens)
+	g.Wait()
+}
+
+func (i *Index) validatePostingList(token int, v *ValidationResult) {
+	item := i.b.Item(token)
+	var header PostingListHeader
+	header.Unmarshal(item)
+	defer func() {
+		if r := recover(); r != nil {
+			v.add(fmt.Errorf("token %q: can't decode posting list: %v", header.Token, r))
+		}
+	}()
+	features := 0
+	var last b6.FeatureID
+	iterator := NewIterator(item, i.nt)
+	for iterator.Next() {
+		id := iterator.FeatureID()
+		if features > 0 && id.Less(last) {
+			v.add(fmt.Errorf("token %q: %s out of order after %s", header.Token, id, last))
+		}
+		if !i.w.HasFeatureWithID(id) {
+			v.add(fmt.Errorf("token %q: missing feature %s", header.Token, id))
+		}
+		last = id
+		features++
+	}
+	if features != header.Features {
+		v.add(fmt.Errorf("token %q: expected %d features, found %d", header.Token, header.Features, features))
+	}
+}
@@ -0,0 +1,212 @@
+package compact
+
+import (
+	"context"
+	"testing"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/ingest"
+	"diagonal.works/b6/test"
+	"diagonal.works/b6/test/camden"
+)
+
+func buildGranarySquareIndex(t *testing.T, options *Options) []byte {
+	t.Helper()
+	pbf := ingest.PBFFilesOSMSource{Glob: test.Data(test.GranarySquarePBF), FailWhenNoFiles: true}
+	source, err := ingest.NewFeatureSourceFromPBF(&pbf, &ingest.BuildOptions{Cores: 2}, context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	index, err := BuildInMemory(source, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return index
+}
+
+func TestReadIndexInfo(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		index := buildGranarySquareIndex(t, &Options{Goroutines: 2, PointsScratchOutputType: OutputTypeMemory, CompressFeatures: compress})
+		info, err := ReadIndexInfo(index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		version := Version
+		if compress {
+			version = CompressedVersion
+		}
+		if info.Version != version || info.Length != len(index) {
+			t.Errorf("Expected version %s and length %d, found %s and %d", version, len(index), info.Version, info.Length)
+		}
+
+		expected := BlockTypeFeatures
+		if compress {
+			expected = BlockTypeCompressedFeatures
+		}
+		points, tokens := false, 0
+		for _, b := range info.Blocks {
+			switch b.Type {
+			case expected:
+				points = points || (b.FeatureType == b6.FeatureTypePoint && b.Namespace == b6.NamespaceOSMNode && b.Buckets > 0)
+			case BlockTypeSearchIndex:
+				tokens += b.Tokens
+			case BlockTypeMetadata:
+				if b.Offset != info.Blocks[len(info.Blocks)-1].Offset {
+					t.Errorf("Expected metadata to be the last block")
+				}
+			default:
+				t.Errorf("Unexpected %s block", b.Type)
+			}
+		}
+		if !points || tokens == 0 {
+			t.Errorf("Expected %s blocks for OSM nodes, and search tokens, found %+v", expected, info.Blocks)
+		}
+
+		if _, err := ReadIndexInfo(index[0 : len(index)-1]); err == nil {
+			t.Error("Expected an error for a truncated index")
+		}
+		corrupt := make([]byte, len(index))
+		copy(corrupt, index)
+		corrupt[0]++
+		if _, err := ReadIndexInfo(corrupt); err == nil {
+			t.Error("Expected an error for an index with bad magic")
+		}
+	}
+}
+
+func TestCountFeaturesAndTopTokens(t *testing.T) {
+	w, err := NewWorldFromData(buildGranarySquareIndex(t, &Options{Goroutines: 2, PointsScratchOutputType: OutputTypeMemory}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts, err := w.CountFeatures(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := counts.Namespace(b6.NamespaceOSMWay)
+	if c.Paths == 0 || c.Areas == 0 || c.PathPoints <= 2*c.Paths || c.AreaPaths < c.Areas {
+		t.Errorf("Expected paths and areas referencing points and paths, found %+v", c)
+	}
+	buildings := 0
+	features := w.FindFeatures(b6.Tagged{Key: "#building", Value: b6.NewStringExpression("yes")})
+	for features.Next() {
+		buildings++
+	}
+
+	tokens := w.TopTokens(1000)
+	if len(tokens) == 0 || len(tokens) > 1000 {
+		t.Fatalf("Expected between 1 and 1000 tokens, found %d", len(tokens))
+	}
+	found := false
+	for i, token := range tokens {
+		if i > 0 && token.Features > tokens[i-1].Features {
+			t.Errorf("Expected tokens in decreasing order of length, found %+v after %+v", token, tokens[i-1])
+		}
+		if token.Token == "building=yes" {
+			found = true
+			if token.Features != buildings {
+				t.Errorf("Expected %d features for building=yes, found %d", buildings, token.Features)
+			}
+		}
+	}
+	if !found {
+		t.Error("Expected a token for building=yes")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		w, err := NewWorldFromData(buildGranarySquareIndex(t, &Options{Goroutines: 2, PointsScratchOutputType: OutputTypeMemory, CompressFeatures: compress}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v := w.Validate(2); v.NumErrors != 0 {
+			t.Errorf("Expected no errors, found %d, including %v", v.NumErrors, v.Errors)
+		}
+	}
+}
+
+func TestValidateOverlayWithoutBase(t *testing.T) {
+	base, err := NewWorldFromData(buildGranarySquareIndex(t, &Options{Goroutines: 2, PointsScratchOutputType: OutputTypeMemory}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := &ingest.GenericFeature{}
+	path.SetFeatureID(b6.FeatureID{Type: b6.FeatureTypePath, Namespace: b6.NamespaceDiagonalAccessPoints, Value: 42})
+	path.AddTag(b6.Tag{Key: b6.PathTag, Value: b6.NewExpressions([]b6.AnyExpression{b6.FeatureIDExpression(ingest.FromOSMNodeID(camden.LightermanEntranceNode)), b6.FeatureIDExpression(ingest.FromOSMNodeID(camden.StableStreetBridgeNorthEndNode))})})
+	overlay, err := BuildOverlayInMemory(ingest.MemoryFeatureSource([]ingest.Feature{path}), &Options{Goroutines: 2, PointsScratchOutputType: OutputTypeMemory}, base)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewWorldWithBase(base)
+	if err := w.Merge(overlay); err != nil {
+		t.Fatal(err)
+	}
+	if v := w.Validate(2); v.NumErrors != 0 {
+		t.Errorf("Expected no errors with a base, found %v", v.Errors)
+	}
+
+	w = NewWorld()
+	if err := w.Merge(overlay); err != nil {
+		t.Fatal(err)
+	}
+	if v := w.Validate(2); v.NumErrors != 2 {
+		t.Errorf("Expected errors for both missing points without a base, found %v", v.Errors)
+	}
+}
+
+func TestValidateReportsCorruptFrames(t *testing.T) {
+	index := buildGranarySquareIndex(t, &Options{Goroutines: 2, PointsScratchOutputType: OutputTypeMemory, CompressFeatures: true})
+	valid, err := NewWorldFromData(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := ReadIndexInfo(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Overwrite the end of the block of compressed points, which holds
+	// the last frame of buckets.
+	corrupt := make([]byte, len(index))
+	copy(corrupt, index)
+	for i, b := range info.Blocks {
+		if b.Type == BlockTypeCompressedFeatures && b.FeatureType == b6.FeatureTypePoint {
+			end := info.Blocks[i+1].Offset
+			for j := end - 16; j < end; j++ {
+				corrupt[j] = 0xff
+			}
+			break
+		}
+	}
+	w, err := NewWorldFromData(corrupt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := w.Validate(2); v.NumErrors == 0 {
+		t.Error("Expected errors for a corrupt frame")
+	}
+
+	ids := make([]b6.FeatureID, 0)
+	each := func(f b6.Feature, _ int) error {
+		ids = append(ids, f.FeatureID())
+		return nil
+	}
+	if err := valid.EachFeature(each, &b6.EachFeatureOptions{SkipPaths: true, SkipAreas: true, SkipRelations: true, SkipCollections: true, SkipExpressions: true}); err != nil {
+		t.Fatal(err)
+	}
+	missing := 0
+	for _, id := range ids {
+		if _, err := w.FindLocationByID(id); err != nil {
+			missing++
+			if w.FindFeatureByID(id) != nil {
+				t.Errorf("Expected no feature for %s in a corrupt frame", id)
+			}
+		}
+	}
+	if missing == 0 {
+		t.Error("Expected errors finding the locations of points in a corrupt frame")
+	}
+}
@@ -0,0 +1,47 @@
+package compact
+
+import (
+	"diagonal.works/b6"
+	"diagonal.works/b6/ingest"
+)
+
+// NewMergedIndexSource returns a source that reads the features of each
+// of the given compact indices, allowing them to be built into a single
+// index. Each index may be an overlay on those before it. When a feature
+// with the same ID appears in more than one index, the one from the last
+// takes precedence.
+func NewMergedIndexSource(indices [][]byte) (ingest.FeatureSource, error) {
+	// Features from each index are read with the earlier indices as a
+	// base, to resolve the paths of areas built as overlays. Since the
+	// base is also used by HasFeatureWithID, precedence is determined
+	// using a separate world for each index.
+	worlds := make([]*World, len(indices))
+	alone := make([]*World, len(indices))
+	var base b6.FeaturesByID = emptyFeaturesByID{}
+	for i, index := range indices {
+		worlds[i] = NewWorldWithBase(base)
+		if err := worlds[i].Merge(index); err != nil {
+			return nil, err
+		}
+		base = worlds[i]
+		alone[i] = NewWorld()
+		if err := alone[i].Merge(index); err != nil {
+			return nil, err
+		}
+	}
+
+	merged := make(ingest.MergedFeatureSource, len(indices))
+	for i := range worlds {
+		later := alone[i+1:]
+		filter := func(f b6.Feature) bool {
+			for _, w := range later {
+				if w.HasFeatureWithID(f.FeatureID()) {
+					return false
+				}
+			}
+			return true
+		}
+		merged[i] = ingest.WorldFeatureSource{World: worlds[i], Filter: filter}
+	}
+	return merged, nil
+}
@@ -0,0 +1,70 @@
+package compact
+
+import (
+	"testing"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/ingest"
+	"diagonal.works/b6/test/camden"
+)
+
+func TestMergeIndicesWithOverlay(t *testing.T) {
+	options := Options{Goroutines: 2, PointsScratchOutputType: OutputTypeMemory}
+	index := buildGranarySquareIndex(t, &options)
+	base, err := NewWorldFromData(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Overlays can only include areas whose paths they also include.
+	lighterman := ingest.NewFeatureFromWorld(base.FindFeatureByID(camden.LightermanID.FeatureID()))
+	lighterman.ModifyOrAddTag(b6.Tag{Key: "#amenity", Value: b6.NewStringExpression("restaurant")})
+	outline := ingest.NewFeatureFromWorld(base.FindFeatureByID(b6.FeatureID{Type: b6.FeatureTypePath, Namespace: b6.NamespaceOSMWay, Value: uint64(camden.LightermanWay)}))
+	path := &ingest.GenericFeature{}
+	path.SetFeatureID(b6.FeatureID{Type: b6.FeatureTypePath, Namespace: b6.NamespaceDiagonalAccessPoints, Value: 42})
+	path.AddTag(b6.Tag{Key: "#highway", Value: b6.NewStringExpression("cycleway")})
+	path.AddTag(b6.Tag{Key: b6.PathTag, Value: b6.NewExpressions([]b6.AnyExpression{b6.FeatureIDExpression(ingest.FromOSMNodeID(camden.LightermanEntranceNode)), b6.FeatureIDExpression(ingest.FromOSMNodeID(camden.StableStreetBridgeNorthEndNode))})})
+	overlay, err := BuildOverlayInMemory(ingest.MemoryFeatureSource([]ingest.Feature{lighterman, outline, path}), &options, base)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source, err := NewMergedIndexSource([][]byte{index, overlay})
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged, err := BuildInMemory(source, &options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := NewWorldFromData(merged)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a, ok := w.FindFeatureByID(camden.LightermanID.FeatureID()).(b6.AreaFeature); !ok {
+		t.Error("Expected to find The Lighterman")
+	} else if amenity := a.Get("#amenity").Value.String(); amenity != "restaurant" {
+		t.Errorf("Expected the tag from the overlay to take precedence, found %q", amenity)
+	} else if a.Polygon(0).Area() != base.FindFeatureByID(camden.LightermanID.FeatureID()).(b6.AreaFeature).Polygon(0).Area() {
+		t.Error("Expected The Lighterman's geometry to be unchanged")
+	}
+	if p, ok := w.FindFeatureByID(path.FeatureID()).(b6.PhysicalFeature); !ok || p.GeometryLen() != 2 {
+		t.Error("Expected to find the path from the overlay, with its points")
+	}
+
+	before, err := base.CountFeatures(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := w.CountFeatures(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, a := before.Namespace(b6.NamespaceOSMWay), after.Namespace(b6.NamespaceOSMWay); *a != *b {
+		t.Errorf("Expected the same OSM ways in the merged index, found %+v vs %+v", a, b)
+	}
+	if v := w.Validate(2); v.NumErrors != 0 {
+		t.Errorf("Expected no errors in merged index, found %v", v.Errors)
+	}
+}
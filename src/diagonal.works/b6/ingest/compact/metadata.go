@@ -0,0 +1,282 @@
+package compact
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/encoding"
+	"diagonal.works/b6/ingest"
+	pb "diagonal.works/b6/proto"
+
+	"github.com/apache/beam/sdks/go/pkg/beam/io/filesystem"
+	"github.com/golang/geo/s2"
+	"google.golang.org/protobuf/proto"
+)
+
+// Checksums use CRC-32C, as used by cloud storage, which is hardware
+// accelerated on most platforms.
+var crc32c = crc32.MakeTable(crc32.Castagnoli)
+
+// IndexMetadata describes how a compact index was built, and the data it
+// was built from.
+type IndexMetadata struct {
+	Version string
+	Header  *pb.CompactHeaderProto
+	// Bounds and checksums. Nil for indices built before version 5.2.0,
+	// which didn't include them.
+	Metadata *pb.CompactMetadataProto
+}
+
+// Metadata returns the metadata of the indices merged into the world, in
+// the order they were merged.
+func (w *World) Metadata() []*IndexMetadata {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return append([]*IndexMetadata{}, w.metadata...)
+}
+
+// FindIndexMetadata returns the metadata of the compact indices
+// underlying the given world, including those beneath overlays, in
+// increasing order of precedence.
+func FindIndexMetadata(w b6.World) []*IndexMetadata {
+	switch w := w.(type) {
+	case *World:
+		return w.Metadata()
+	case ingest.LayeredWorld:
+		metadata := make([]*IndexMetadata, 0)
+		for _, layer := range w.Layers() {
+			metadata = append(metadata, FindIndexMetadata(layer)...)
+		}
+		return metadata
+	}
+	return nil
+}
+
+// ReadIndexMetadata returns the metadata of the given compact index.
+func ReadIndexMetadata(data []byte) (*IndexMetadata, error) {
+	var header Header
+	header.Unmarshal(data)
+	if header.Magic != HeaderMagic {
+		return nil, fmt.Errorf("bad header magic: expected %x, found %x", uint64(HeaderMagic), header.Magic)
+	}
+	m := &IndexMetadata{Version: header.UnmarshalVersion(data), Header: &pb.CompactHeaderProto{}}
+	if err := UnmarshalProto(data[header.HeaderProtoOffset:], m.Header); err != nil {
+		return nil, err
+	}
+	offset := header.BlockOffset
+	for offset < encoding.Offset(len(data)) {
+		var block BlockHeader
+		offset += encoding.Offset(block.Unmarshal(data[offset:]))
+		if block.Type == BlockTypeMetadata {
+			m.Metadata = &pb.CompactMetadataProto{}
+			if err := UnmarshalProto(data[offset:], m.Metadata); err != nil {
+				return nil, err
+			}
+		}
+		offset += encoding.Offset(block.Length)
+	}
+	return m, nil
+}
+
+// VerifyChecksums returns an error if the content of the given index
+// doesn't match the checksums recorded in its metadata.
+func VerifyChecksums(data []byte, metadata *pb.CompactMetadataProto) error {
+	var header Header
+	header.Unmarshal(data)
+	if c := crc32.Checksum(data[0:header.BlockOffset], crc32c); c != metadata.HeaderCrc32C {
+		return fmt.Errorf("header checksum mismatch: expected %08x, found %08x", metadata.HeaderCrc32C, c)
+	}
+	for _, checksum := range metadata.Blocks {
+		if checksum.Offset+BlockHeaderLength > uint64(len(data)) {
+			return fmt.Errorf("block at %d beyond end of index", checksum.Offset)
+		}
+		var block BlockHeader
+		block.Unmarshal(data[checksum.Offset:])
+		end := checksum.Offset + BlockHeaderLength + block.Length
+		if block.Length > uint64(len(data)) || end > uint64(len(data)) {
+			return fmt.Errorf("%s block at %d extends beyond end of index", block.Type, checksum.Offset)
+		}
+		if c := crc32.Checksum(data[checksum.Offset:end], crc32c); c != checksum.Crc32C {
+			return fmt.Errorf("%s block at %d checksum mismatch: expected %08x, found %08x", BlockType(checksum.Type), checksum.Offset, checksum.Crc32C, c)
+		}
+	}
+	return nil
+}
+
+// NewSourceProto returns a description of the given file, including
+// a hash of its content, for inclusion in an index header.
+func NewSourceProto(filename string) (*pb.CompactSourceProto, error) {
+	ctx := context.Background()
+	fs, err := filesystem.New(ctx, filename)
+	if err != nil {
+		return nil, err
+	}
+	defer fs.Close()
+	r, err := fs.OpenRead(ctx, filename)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, r)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return &pb.CompactSourceProto{Filename: filename, Size: n, Sha256: hex.EncodeToString(h.Sum(nil))}, nil
+}
+
+func builderVersion() string {
+	if b6.BackendVersion != "" {
+		return "b6 " + b6.BackendVersion
+	}
+	return "b6 " + b6.ApiVersion
+}
+
+// fillHeaderProto returns the header to write for an index, starting
+// from the provenance given in options.
+func fillHeaderProto(o *Options, nt *NamespaceTable, summary *Summary) *pb.CompactHeaderProto {
+	var hp *pb.CompactHeaderProto
+	if o.Header != nil {
+		hp = proto.Clone(o.Header).(*pb.CompactHeaderProto)
+	} else {
+		hp = &pb.CompactHeaderProto{}
+	}
+	nt.FillProto(hp)
+	if hp.Builder == "" {
+		hp.Builder = builderVersion()
+	}
+	if hp.BuildTimestamp == 0 {
+		hp.BuildTimestamp = time.Now().Unix()
+	}
+	hp.Counts = hp.Counts[0:0]
+	for ns, c := range summary.Counts.ByNamespace {
+		if c.Points > 0 || c.Paths > 0 || c.Areas > 0 || c.Relations > 0 {
+			hp.Counts = append(hp.Counts, &pb.CompactCountsProto{
+				Namespace: string(ns),
+				Points:    c.Points,
+				Paths:     c.Paths,
+				Areas:     c.Areas,
+				Relations: c.Relations,
+			})
+		}
+	}
+	sort.Slice(hp.Counts, func(i, j int) bool {
+		return hp.Counts[i].Namespace < hp.Counts[j].Namespace
+	})
+	return hp
+}
+
+func pointBounds(byID *FeaturesByID, goroutines int) (s2.Rect, error) {
+	if goroutines < 1 {
+		goroutines = 1
+	}
+	rects := make([]s2.Rect, goroutines)
+	for i := range rects {
+		rects[i] = s2.EmptyRect()
+	}
+	each := func(f b6.Feature, goroutine int) error {
+		if p, ok := f.(b6.PhysicalFeature); ok {
+			rects[goroutine] = rects[goroutine].AddPoint(s2.LatLngFromPoint(p.Point()))
+		}
+		return nil
+	}
+	options := b6.EachFeatureOptions{
+		SkipPaths:       true,
+		SkipAreas:       true,
+		SkipRelations:   true,
+		SkipCollections: true,
+		SkipExpressions: true,
+		Goroutines:      goroutines,
+	}
+	bounds := s2.EmptyRect()
+	err := byID.EachFeature(each, &options)
+	for _, r := range rects {
+		bounds = bounds.Union(r)
+	}
+	return bounds, err
+}
+
+// checksumBlocks fills the checksums of the header, and every block of the
+// given index.
+func checksumBlocks(data []byte, metadata *pb.CompactMetadataProto) {
+	var header Header
+	header.Unmarshal(data)
+	metadata.HeaderCrc32C = crc32.Checksum(data[0:header.BlockOffset], crc32c)
+
+	offsets := make([]encoding.Offset, 0)
+	offset := header.BlockOffset
+	for offset < encoding.Offset(len(data)) {
+		offsets = append(offsets, offset)
+		var block BlockHeader
+		offset = offset.Add(block.Unmarshal(data[offset:]) + int(block.Length))
+	}
+	metadata.Blocks = make([]*pb.CompactBlockChecksumProto, len(offsets))
+	var wg sync.WaitGroup
+	wg.Add(len(offsets))
+	for i := range offsets {
+		go func(i int) {
+			var block BlockHeader
+			start := offsets[i]
+			end := start.Add(block.Unmarshal(data[start:]) + int(block.Length))
+			metadata.Blocks[i] = &pb.CompactBlockChecksumProto{
+				Offset: uint64(start),
+				Type:   uint64(block.Type),
+				Crc32C: crc32.Checksum(data[start:end], crc32c),
+			}
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+}
+
+// writeMetadata appends a block to the index in output, recording the
+// given bounds, and checksums of the existing content.
+func writeMetadata(bounds s2.Rect, output Output) error {
+	var metadata pb.CompactMetadataProto
+	if !bounds.IsEmpty() {
+		metadata.BoundsLo = b6.NewPointProtoFromS2LatLng(bounds.Lo())
+		metadata.BoundsHi = b6.NewPointProtoFromS2LatLng(bounds.Hi())
+	}
+	data, closer, err := output.Bytes()
+	if err != nil {
+		return err
+	}
+	checksumBlocks(data, &metadata)
+	offset := encoding.Offset(len(data))
+	if err := closer.Close(); err != nil {
+		return err
+	}
+
+	marshalled, err := proto.Marshal(&metadata)
+	if err != nil {
+		return err
+	}
+	var buffer [BlockHeaderLength + binary.MaxVarintLen64]byte
+	block := BlockHeader{
+		Type:   BlockTypeMetadata,
+		Length: uint64(len(marshalled) + binary.PutUvarint(buffer[:], uint64(len(marshalled)))),
+	}
+	w, err := output.ReadWrite()
+	if err != nil {
+		return err
+	}
+	n := block.Marshal(buffer[:])
+	if _, err := w.WriteAt(buffer[0:n], int64(offset)); err != nil {
+		w.Close()
+		return err
+	}
+	if _, err := WriteProto(w, &metadata, offset.Add(n)); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
@@ -0,0 +1,95 @@
+package compact
+
+import (
+	"testing"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/ingest"
+	pb "diagonal.works/b6/proto"
+	"diagonal.works/b6/test"
+	"diagonal.works/b6/test/camden"
+
+	"github.com/golang/geo/s2"
+)
+
+func TestIndexMetadata(t *testing.T) {
+	source, err := NewSourceProto(test.Data(test.GranarySquarePBF))
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := &pb.CompactHeaderProto{
+		Sources:              []*pb.CompactSourceProto{source},
+		ReplicationTimestamp: 1700000000,
+		Options:              map[string]string{"cores": "2"},
+		TagMapping:           ingest.OSMTagMapping(),
+	}
+	index := buildGranarySquareIndex(t, &Options{Goroutines: 2, PointsScratchOutputType: OutputTypeMemory, Header: header})
+
+	m, err := ReadIndexMetadata(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Version != Version {
+		t.Errorf("Expected version %s, found %s", Version, m.Version)
+	}
+	if len(m.Header.Sources) != 1 || len(m.Header.Sources[0].Sha256) != 64 || m.Header.Sources[0].Size == 0 {
+		t.Errorf("Expected a source with a hash and size, found %v", m.Header.Sources)
+	}
+	if m.Header.ReplicationTimestamp != header.ReplicationTimestamp {
+		t.Errorf("Expected replication timestamp %d, found %d", header.ReplicationTimestamp, m.Header.ReplicationTimestamp)
+	}
+	if m.Header.TagMapping["building"] != "#building" || m.Header.Options["cores"] != "2" {
+		t.Errorf("Expected tag mapping and options from the given header, found %v and %v", m.Header.TagMapping, m.Header.Options)
+	}
+	if m.Header.Builder == "" || m.Header.BuildTimestamp == 0 {
+		t.Error("Expected builder and build timestamp to be filled")
+	}
+	found := false
+	for _, c := range m.Header.Counts {
+		if c.Namespace == string(b6.NamespaceOSMNode) {
+			found = c.Points > 0
+		}
+	}
+	if !found {
+		t.Errorf("Expected counts of OSM nodes, found %v", m.Header.Counts)
+	}
+
+	if m.Metadata == nil {
+		t.Fatal("Expected metadata block")
+	}
+	w, err := NewWorldFromData(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bounds := s2.RectFromLatLng(b6.PointProtoToS2LatLng(m.Metadata.BoundsLo)).AddPoint(b6.PointProtoToS2LatLng(m.Metadata.BoundsHi))
+	if dishoom, ok := w.FindFeatureByID(camden.DishoomID).(b6.PhysicalFeature); !ok {
+		t.Error("Expected to find Dishoom")
+	} else if !bounds.ContainsPoint(dishoom.Point()) {
+		t.Errorf("Expected Dishoom within bounds %s", bounds)
+	}
+	if err := VerifyChecksums(index, m.Metadata); err != nil {
+		t.Errorf("Expected checksums to match, found: %s", err)
+	}
+
+	info, err := ReadIndexInfo(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	corrupted := append([]byte{}, index...)
+	corrupted[info.Blocks[0].Offset.Add(BlockHeaderLength+100)] ^= 0xff
+	if err := VerifyChecksums(corrupted, m.Metadata); err == nil {
+		t.Error("Expected an error for a corrupted block")
+	}
+}
+
+func TestFindIndexMetadataThroughOverlays(t *testing.T) {
+	index := buildGranarySquareIndex(t, &Options{Goroutines: 2, PointsScratchOutputType: OutputTypeMemory})
+	w, err := NewWorldFromData(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := ingest.NewMutableOverlayWorld(ingest.NewOverlayWorld(ingest.NewBasicMutableWorld(), w))
+	if metadata := FindIndexMetadata(m); len(metadata) != 1 || metadata[0].Metadata == nil {
+		t.Errorf("Expected metadata for one index, found %v", metadata)
+	}
+}
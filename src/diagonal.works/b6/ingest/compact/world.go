@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"slices"
 	"sort"
+	"strings"
 	"sync"
 
 	"diagonal.works/b6"
@@ -26,6 +27,27 @@ type featureBlock struct {
 	NamespaceTable *NamespaceTable
 }
 
+// findFirst returns the first item for the given ID, for lookups that
+// can't return errors. A corrupt map is logged, and the item treated as
+// missing.
+func (fb *featureBlock) findFirst(id uint64) (encoding.Tagged, bool) {
+	t, ok, err := fb.Map.FindFirst(id)
+	if err != nil {
+		log.Printf("%s: %s", fb.FeatureType, err)
+	}
+	return t, ok
+}
+
+// findFirstWithTag is the equivalent of findFirst for items with a
+// given tag.
+func (fb *featureBlock) findFirstWithTag(id uint64, tag encoding.Tag) []byte {
+	b, err := fb.Map.FindFirstWithTag(id, tag)
+	if err != nil {
+		log.Printf("%s: %s", fb.FeatureType, err)
+	}
+	return b
+}
+
 const FeaturesByIDCacheSize = 4000 // Empirically the working-set size of Galashiels
 
 type FeaturesByID struct {
@@ -49,14 +71,21 @@ func NewFeaturesByIDFromData(data []byte, base b6.FeaturesByID) (*FeaturesByID,
 }
 
 func verifyVersion(header *Header, buffer []byte) error {
-	v := header.UnmarshalVersion(buffer)
+	return checkVersion(header.UnmarshalVersion(buffer), Version, CompressedVersion)
+}
+
+// checkVersion returns an error unless the index version v has the same
+// major version as one of those supported.
+func checkVersion(v string, supported ...string) error {
 	if !semver.IsValid("v" + v) {
 		return fmt.Errorf("invalid index version %s", v)
 	}
-	if semver.Major("v"+v) != semver.Major("v"+Version) {
-		return fmt.Errorf("need index version %s, found %s", Version, v)
+	for _, s := range supported {
+		if semver.Major("v"+v) == semver.Major("v"+s) {
+			return nil
+		}
 	}
-	return nil
+	return fmt.Errorf("need index version %s, found %s", strings.Join(supported, " or "), v)
 }
 
 func (f *FeaturesByID) Merge(data []byte) error {
@@ -81,9 +110,13 @@ func (f *FeaturesByID) Merge(data []byte) error {
 	var block BlockHeader
 	for offset < encoding.Offset(len(data)) {
 		offset += encoding.Offset(block.Unmarshal(data[offset:]))
-		if block.Type == BlockTypeFeatures {
+		if block.Type == BlockTypeFeatures || block.Type == BlockTypeCompressedFeatures {
 			fb := &featureBlock{Strings: strings, NamespaceTable: &nt}
-			fb.Unmarshal(data[offset:])
+			if block.Type == BlockTypeFeatures {
+				fb.Unmarshal(data[offset:])
+			} else {
+				fb.UnmarshalCompressed(data[offset:])
+			}
 			f.features[fb.FeatureType] = append(f.features[fb.FeatureType], fb)
 		}
 		offset += encoding.Offset(block.Length)
@@ -154,7 +187,7 @@ func hasFeatureWithID(id b6.FeatureID, fbs []*featureBlock) bool {
 	for _, fb := range fbs {
 		ns, ok := fb.NamespaceTable.MaybeEncode(id.Namespace)
 		if ok && ns == fb.Namespaces[id.Type] {
-			_, ok := fb.Map.FindFirst(id.Value)
+			_, ok := fb.findFirst(id.Value)
 			return ok
 		}
 	}
@@ -164,7 +197,10 @@ func hasFeatureWithID(id b6.FeatureID, fbs []*featureBlock) bool {
 func (f *FeaturesByID) FindLocationByID(id b6.FeatureID) (s2.LatLng, error) {
 	for _, fb := range f.features[b6.FeatureTypePoint] {
 		if ns, ok := fb.NamespaceTable.MaybeEncode(id.Namespace); ok && ns == fb.Namespaces[b6.FeatureTypePoint] {
-			if t, ok := fb.Map.FindFirst(id.Value); ok {
+			t, ok, err := fb.Map.FindFirst(id.Value)
+			if err != nil {
+				return s2.LatLng{}, fmt.Errorf("%s: %w", id, err)
+			} else if ok {
 				if t.Tag != PointTagReferencesOnly {
 					point := MarshalledTags{t.Data, fb.Strings, fb.NamespaceTable, TypeAndNamespaceInvalid}.Point()
 					if point.Norm() != 0 {
@@ -178,7 +214,7 @@ func (f *FeaturesByID) FindLocationByID(id b6.FeatureID) (s2.LatLng, error) {
 }
 
 func (f *FeaturesByID) newPhysicalFeature(fb *featureBlock, typ b6.FeatureType, id uint64) b6.Feature {
-	t, ok := fb.Map.FindFirst(id)
+	t, ok := fb.findFirst(id)
 	if ok {
 		return f.newPhysicalFeatureFromTagged(fb, b6.FeatureID{typ, fb.NamespaceTable.Decode(fb.Namespaces[typ]), id}, t)
 	}
@@ -286,7 +322,7 @@ func (m *wrappedMarshalledPhysicalFeature) Feature(i int) b6.PhysicalFeature {
 }
 
 func (f *FeaturesByID) newWrappedPhysicalFeature(fb *featureBlock, id uint64) b6.PhysicalFeature {
-	b := fb.Map.FindFirstWithTag(id, encoding.NoTag)
+	b := fb.findFirstWithTag(id, encoding.NoTag)
 	if b != nil {
 		return f.newWrappedPhysicalFeatureFromBuffer(fb, id, b)
 	}
@@ -432,7 +468,7 @@ func (m *marshalledArea) Point() s2.Point {
 }
 
 func (f *FeaturesByID) newArea(fb *featureBlock, id uint64) b6.AreaFeature {
-	if b := fb.Map.FindFirstWithTag(id, encoding.NoTag); len(b) > 0 {
+	if b := fb.findFirstWithTag(id, encoding.NoTag); len(b) > 0 {
 		return f.newAreaFromBuffer(fb, id, b)
 	}
 	return nil
@@ -503,7 +539,7 @@ func (m marshalledRelation) Reference(i int) b6.Reference {
 }
 
 func (f *FeaturesByID) newRelation(fb *featureBlock, id uint64) b6.RelationFeature {
-	b := fb.Map.FindFirstWithTag(id, encoding.NoTag)
+	b := fb.findFirstWithTag(id, encoding.NoTag)
 	if b != nil {
 		return f.newRelationFromBuffer(fb, id, b)
 	}
@@ -612,7 +648,7 @@ func (f *FeaturesByID) FindReferences(id b6.FeatureID, typed ...b6.FeatureType)
 func (f *FeaturesByID) findPathsByPoint(id b6.FeatureID, paths []b6.FeatureID) []b6.FeatureID {
 	for _, fb := range f.features[b6.FeatureTypePoint] {
 		if ns, ok := fb.NamespaceTable.MaybeEncode(id.Namespace); ok && ns == fb.Namespaces[b6.FeatureTypePoint] {
-			t, ok := fb.Map.FindFirst(id.Value)
+			t, ok := fb.findFirst(id.Value)
 			if ok {
 				switch t.Tag {
 				case PointTagCommon:
@@ -645,7 +681,7 @@ func (f *FeaturesByID) FindAreasByPoint(id b6.FeatureID) b6.AreaFeatures {
 	features := make([]b6.AreaFeature, 0, 1)
 	for _, fb := range f.features[b6.FeatureTypePoint] {
 		if ns, ok := fb.NamespaceTable.MaybeEncode(id.Namespace); ok && fb.Namespaces[b6.FeatureTypePoint] == ns {
-			t, ok := fb.Map.FindFirst(id.Value)
+			t, ok := fb.findFirst(id.Value)
 			var paths []Reference
 			if ok {
 				switch t.Tag {
@@ -665,7 +701,7 @@ func (f *FeaturesByID) FindAreasByPoint(id b6.FeatureID) b6.AreaFeatures {
 				for _, pm := range f.features[b6.FeatureTypePath] {
 					_, ns := path.TypeAndNamespace.Split()
 					if pm.Namespaces[b6.FeatureTypePath] == ns {
-						if b := pm.Map.FindFirstWithTag(path.Value, encoding.NoTag); len(b) > 0 {
+						if b := pm.findFirstWithTag(path.Value, encoding.NoTag); len(b) > 0 {
 							p.Unmarshal(&pm.Namespaces, b)
 							for _, area := range p.Areas {
 								areas[area] = struct{}{}
@@ -704,7 +740,7 @@ func (f *FeaturesByID) Traverse(id b6.FeatureID) b6.Segments {
 func (f *FeaturesByID) fillPathSegments(point b6.FeatureID, path b6.FeatureID, segments []b6.Segment) []b6.Segment {
 	for _, fb := range f.features[b6.FeatureTypePath] {
 		if ns, ok := fb.NamespaceTable.MaybeEncode(path.Namespace); ok && ns == fb.Namespaces[b6.FeatureTypePath] {
-			b := fb.Map.FindFirstWithTag(path.Value, encoding.NoTag)
+			b := fb.findFirstWithTag(path.Value, encoding.NoTag)
 			if b == nil {
 				continue
 			}
@@ -752,7 +788,7 @@ func (f *FeaturesByID) isGraphNode(point Reference) bool {
 	for _, fb := range f.features[b6.FeatureTypePoint] {
 		_, ns := point.TypeAndNamespace.Split()
 		if fb.Namespaces[b6.FeatureTypePoint] == ns {
-			t, ok := fb.Map.FindFirst(point.Value)
+			t, ok := fb.findFirst(point.Value)
 			if ok {
 				switch t.Tag {
 				case PointTagCommon:
@@ -807,7 +843,7 @@ func (f *FeaturesByID) FindRelationsByFeature(id b6.FeatureID) b6.RelationFeatur
 }
 
 func (f *FeaturesByID) fillRelationsFromPoint(fb *featureBlock, id uint64, relations []b6.RelationFeature) []b6.RelationFeature {
-	t, ok := fb.Map.FindFirst(id)
+	t, ok := fb.findFirst(id)
 	if ok && t.Tag == PointTagFull {
 		var p FullPoint
 		// TODO: don't need to unmarshal everything
@@ -825,7 +861,7 @@ func (f *FeaturesByID) fillRelationsFromPoint(fb *featureBlock, id uint64, relat
 }
 
 func (f *FeaturesByID) fillRelationsFromPath(fb *featureBlock, id uint64, relations []b6.RelationFeature) []b6.RelationFeature {
-	b := fb.Map.FindFirstWithTag(id, encoding.NoTag)
+	b := fb.findFirstWithTag(id, encoding.NoTag)
 	if b != nil {
 		var p Path
 		p.Unmarshal(&fb.Namespaces, b)
@@ -842,7 +878,7 @@ func (f *FeaturesByID) fillRelationsFromPath(fb *featureBlock, id uint64, relati
 }
 
 func (f *FeaturesByID) fillRelationsFromArea(fb *featureBlock, id uint64, relations []b6.RelationFeature) []b6.RelationFeature {
-	b := fb.Map.FindFirstWithTag(id, encoding.NoTag)
+	b := fb.findFirstWithTag(id, encoding.NoTag)
 	if b != nil {
 		var a Area
 		a.Unmarshal(&fb.Namespaces, b)
@@ -859,7 +895,7 @@ func (f *FeaturesByID) fillRelationsFromArea(fb *featureBlock, id uint64, relati
 }
 
 func (f *FeaturesByID) fillRelationsFromRelation(fb *featureBlock, id uint64, relations []b6.RelationFeature) []b6.RelationFeature {
-	b := fb.Map.FindFirstWithTag(id, encoding.NoTag)
+	b := fb.findFirstWithTag(id, encoding.NoTag)
 	if b != nil {
 		var r Relation
 		r.Unmarshal(b6.FeatureTypePath, &fb.Namespaces, b)
@@ -909,17 +945,22 @@ func (f *FeaturesByID) StatusText() string {
 	status := ""
 	for _, fbs := range f.features {
 		for _, fb := range fbs {
-			status += fmt.Sprintf("%s: %d: %d bytes\n", fb.FeatureType, fb.Namespaces[fb.FeatureType], fb.Map.Length())
+			compressed := ""
+			if fb.Map.IsCompressed() {
+				compressed = " (compressed)"
+			}
+			status += fmt.Sprintf("%s: %d: %d bytes%s\n", fb.FeatureType, fb.Namespaces[fb.FeatureType], fb.Map.Length(), compressed)
 		}
 	}
 	return status
 }
 
 type World struct {
-	byID    *FeaturesByID
-	indices []*Index
-	status  string
-	lock    sync.Mutex
+	byID     *FeaturesByID
+	indices  []*Index
+	metadata []*IndexMetadata
+	status   string
+	lock     sync.Mutex
 }
 
 func (w *World) FindFeatureByID(id b6.FeatureID) b6.Feature {
@@ -1058,20 +1099,28 @@ func (w *World) Merge(data []byte) error {
 	}
 	var nt NamespaceTable
 	nt.FillFromProto(&hp)
+	metadata := &IndexMetadata{Version: header.UnmarshalVersion(data), Header: &hp}
 
 	offset := header.BlockOffset
 	for offset < encoding.Offset(len(data)) {
 		var header BlockHeader
 		offset += encoding.Offset(header.Unmarshal(data[offset:]))
-		if header.Type == BlockTypeSearchIndex {
+		switch header.Type {
+		case BlockTypeSearchIndex:
 			index, err := NewIndex(data[offset:], &nt, w)
 			if err != nil {
 				return fmt.Errorf("Failed to create search index: %s", err)
 			}
 			w.indices = append(w.indices, index)
+		case BlockTypeMetadata:
+			metadata.Metadata = &pb.CompactMetadataProto{}
+			if err := UnmarshalProto(data[offset:], metadata.Metadata); err != nil {
+				return err
+			}
 		}
 		offset += encoding.Offset(header.Length)
 	}
+	w.metadata = append(w.metadata, metadata)
 	return nil
 }
 
@@ -2,6 +2,7 @@ package compact
 
 import (
 	"context"
+	"math/rand"
 	"slices"
 	"testing"
 
@@ -9,24 +10,29 @@ import (
 	"diagonal.works/b6/ingest"
 	"diagonal.works/b6/osm"
 	"diagonal.works/b6/test"
+	"diagonal.works/b6/test/camden"
 
 	"github.com/golang/geo/s2"
 )
 
 func mergeOSM(nodes []osm.Node, ways []osm.Way, relations []osm.Relation, base b6.World, w *World, o *ingest.BuildOptions) error {
+	options := Options{Goroutines: 2, PointsScratchOutputType: OutputTypeMemory}
+	return mergeOSMWithOptions(nodes, ways, relations, base, w, o, &options)
+}
+
+func mergeOSMWithOptions(nodes []osm.Node, ways []osm.Way, relations []osm.Relation, base b6.World, w *World, o *ingest.BuildOptions, options *Options) error {
 	osmSource := ingest.MemoryOSMSource{Nodes: nodes, Ways: ways, Relations: relations}
 	source, err := ingest.NewFeatureSourceFromPBF(&osmSource, o, context.Background())
 	if err != nil {
 		return err
 	}
-	options := Options{Goroutines: 2, PointsScratchOutputType: OutputTypeMemory}
 	var index []byte
 	if base == nil {
-		if index, err = BuildInMemory(source, &options); err != nil {
+		if index, err = BuildInMemory(source, options); err != nil {
 			return err
 		}
 	} else {
-		if index, err = BuildOverlayInMemory(source, &options, base); err != nil {
+		if index, err = BuildOverlayInMemory(source, options, base); err != nil {
 			return err
 		}
 	}
@@ -41,6 +47,69 @@ func TestValidateWorld(t *testing.T) {
 	ingest.ValidateWorld("Compact", build, t)
 }
 
+func TestValidateWorldWithCompressedFeatures(t *testing.T) {
+	build := func(nodes []osm.Node, ways []osm.Way, relations []osm.Relation, o *ingest.BuildOptions) (b6.World, error) {
+		w := NewWorld()
+		options := Options{Goroutines: 2, PointsScratchOutputType: OutputTypeMemory, CompressFeatures: true}
+		return w, mergeOSMWithOptions(nodes, ways, relations, nil, w, o, &options)
+	}
+	ingest.ValidateWorld("CompactCompressed", build, t)
+}
+
+func TestCompressedFeaturesAreSmaller(t *testing.T) {
+	pbf := ingest.PBFFilesOSMSource{Glob: test.Data(test.GranarySquarePBF), FailWhenNoFiles: true}
+	source, err := ingest.NewFeatureSourceFromPBF(&pbf, &ingest.BuildOptions{Cores: 2}, context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	uncompressed, err := BuildInMemory(source, &Options{Goroutines: 2, PointsScratchOutputType: OutputTypeMemory})
+	if err != nil {
+		t.Fatal(err)
+	}
+	compressed, err := BuildInMemory(source, &Options{Goroutines: 2, PointsScratchOutputType: OutputTypeMemory, CompressFeatures: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(compressed) >= len(uncompressed) {
+		t.Errorf("Expected compressed index to be smaller, found %d vs %d bytes", len(compressed), len(uncompressed))
+	}
+
+	w := NewWorld()
+	if err := w.Merge(compressed); err != nil {
+		t.Fatal(err)
+	}
+	if w.FindFeatureByID(camden.LightermanID.FeatureID()) == nil {
+		t.Error("Expected to find The Lighterman in the compressed index")
+	}
+	features := w.FindFeatures(b6.Keyed{Key: "#building"})
+	n := 0
+	for features.Next() {
+		n++
+	}
+	if n == 0 {
+		t.Error("Expected to find buildings in the compressed index")
+	}
+}
+
+func TestVersion5ReadersRejectCompressedFeatures(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		index := buildGranarySquareIndex(t, &Options{Goroutines: 2, PointsScratchOutputType: OutputTypeMemory, CompressFeatures: compress})
+		var header Header
+		header.Unmarshal(index)
+		v := header.UnmarshalVersion(index)
+		// Readers of version 5.0.0 predate compressed feature blocks, and
+		// would skip them.
+		if err := checkVersion(v, "5.0.0"); compress && err == nil {
+			t.Errorf("Expected version %s to fail to load with version 5.0.0", v)
+		} else if !compress && err != nil {
+			t.Errorf("Expected uncompressed index to load with version 5.0.0, found %s", err)
+		}
+		if err := NewWorld().Merge(index); err != nil {
+			t.Errorf("Expected index to load with version %s, found %s", Version, err)
+		}
+	}
+}
+
 func TestPointPathExpressionTypesCorrectlyInferred(t *testing.T) {
 	nodes := []osm.Node{
 		{
@@ -219,15 +288,18 @@ func TestAreasForPointWithSameNamespaceInMultipleBlocks(t *testing.T) {
 // based on it
 
 func mustBuildCamdenForBenchmarks() b6.World {
+	return mustBuildCamdenForBenchmarksWithOptions(&Options{Goroutines: 2, PointsScratchOutputType: OutputTypeMemory})
+}
+
+func mustBuildCamdenForBenchmarksWithOptions(options *Options) b6.World {
 	pbf := ingest.PBFFilesOSMSource{Glob: test.Data(test.CamdenPBF), FailWhenNoFiles: true}
 	source, err := ingest.NewFeatureSourceFromPBF(&pbf, &ingest.BuildOptions{Cores: 2}, context.Background())
 	if err != nil {
 		panic(err)
 	}
 
-	options := Options{Goroutines: 2, PointsScratchOutputType: OutputTypeMemory}
 	var index []byte
-	if index, err = BuildInMemory(source, &options); err != nil {
+	if index, err = BuildInMemory(source, options); err != nil {
 		panic(err)
 	}
 
@@ -271,3 +343,34 @@ func BenchmarkSearchModifiedWorld(b *testing.B) {
 		}
 	}
 }
+
+// Compare the time taken to lookup features by ID with and without
+// compressed feature blocks.
+
+func benchmarkFindFeatureByID(options *Options, b *testing.B) {
+	w := mustBuildCamdenForBenchmarksWithOptions(options)
+	ids := make([]b6.FeatureID, 0)
+	features := w.FindFeatures(b6.All{})
+	for features.Next() {
+		ids = append(ids, features.FeatureID())
+	}
+	// Visit features in an order unrelated to their IDs, to avoid
+	// benefitting unrealistically from caches.
+	rand.New(rand.NewSource(42)).Shuffle(len(ids), func(i, j int) {
+		ids[i], ids[j] = ids[j], ids[i]
+	})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if w.FindFeatureByID(ids[i%len(ids)]) == nil {
+			b.Fatalf("Expected to find %s", ids[i%len(ids)])
+		}
+	}
+}
+
+func BenchmarkFindFeatureByID(b *testing.B) {
+	benchmarkFindFeatureByID(&Options{Goroutines: 2, PointsScratchOutputType: OutputTypeMemory}, b)
+}
+
+func BenchmarkFindFeatureByIDCompressed(b *testing.B) {
+	benchmarkFindFeatureByID(&Options{Goroutines: 2, PointsScratchOutputType: OutputTypeMemory, CompressFeatures: true}, b)
+}
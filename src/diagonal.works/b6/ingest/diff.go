@@ -0,0 +1,122 @@
+package ingest
+
+import (
+	"bytes"
+	"sort"
+
+	"diagonal.works/b6"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// FeatureDiff describes how a feature differs between two worlds.
+type FeatureDiff struct {
+	ID b6.FeatureID
+	// True if the feature is only present in the second world.
+	Added bool
+	// True if the feature is only present in the first world.
+	Removed bool
+	// Tags present in the second world with a different value, or
+	// missing, in the first. Includes the location of points and the
+	// points along paths.
+	Tags []b6.Tag
+	// The keys of tags present in the first world, but not the second.
+	RemovedTags []string
+	// True if the members of an area, relation or collection differ.
+	Members bool
+}
+
+// DiffWorlds returns the differences between the features and tags
+// modified in two worlds, ordered by feature ID. Both worlds are assumed
+// to share the same base, as with worlds created by MutableWorlds, and
+// only features modified in at least one of them are compared. Callers
+// must hold read locks on both.
+func DiffWorlds(from MutableWorld, to MutableWorld) ([]FeatureDiff, error) {
+	ids := make(map[b6.FeatureID]struct{})
+	features := func(f b6.Feature, goroutine int) error {
+		ids[f.FeatureID()] = struct{}{}
+		return nil
+	}
+	tags := func(t ModifiedTag, goroutine int) error {
+		ids[t.ID] = struct{}{}
+		return nil
+	}
+	options := b6.EachFeatureOptions{Goroutines: 1}
+	for _, w := range []MutableWorld{from, to} {
+		if err := w.EachModifiedFeature(features, &options); err != nil {
+			return nil, err
+		}
+		if err := w.EachModifiedTag(tags, &options); err != nil {
+			return nil, err
+		}
+	}
+
+	sorted := make([]b6.FeatureID, 0, len(ids))
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Less(sorted[j])
+	})
+
+	diffs := make([]FeatureDiff, 0)
+	for _, id := range sorted {
+		diff := FeatureDiff{ID: id}
+		before, after := from.FindFeatureByID(id), to.FindFeatureByID(id)
+		if before == nil && after == nil {
+			continue
+		} else if before == nil {
+			diff.Added = true
+		} else if after == nil {
+			diff.Removed = true
+		} else {
+			diffTags(before, after, &diff)
+			members, err := membersDiffer(before, after)
+			if err != nil {
+				return nil, err
+			}
+			diff.Members = members
+			if len(diff.Tags) == 0 && len(diff.RemovedTags) == 0 && !diff.Members {
+				continue
+			}
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
+
+func diffTags(before b6.Feature, after b6.Feature, diff *FeatureDiff) {
+	values := make(map[string]string)
+	for _, tag := range before.AllTags() {
+		values[tag.Key] = tag.Value.String()
+	}
+	for _, tag := range after.AllTags() {
+		if value, ok := values[tag.Key]; !ok || value != tag.Value.String() {
+			diff.Tags = append(diff.Tags, tag)
+		}
+		delete(values, tag.Key)
+	}
+	for _, tag := range before.AllTags() {
+		if _, ok := values[tag.Key]; ok {
+			diff.RemovedTags = append(diff.RemovedTags, tag.Key)
+		}
+	}
+}
+
+// membersDiffer compares features by their YAML representation, without
+// tags, since that covers the members of every feature type.
+func membersDiffer(before b6.Feature, after b6.Feature) (bool, error) {
+	marshal := func(f b6.Feature) ([]byte, error) {
+		copy := NewFeatureFromWorld(f)
+		copy.RemoveAllTags()
+		return yaml.Marshal(copy)
+	}
+	b, err := marshal(before)
+	if err != nil {
+		return false, err
+	}
+	a, err := marshal(after)
+	if err != nil {
+		return false, err
+	}
+	return !bytes.Equal(a, b), nil
+}
@@ -0,0 +1,93 @@
+package ingest
+
+import (
+	"sync"
+
+	"diagonal.works/b6"
+)
+
+// Extract describes a subset of the features of a world.
+type Extract struct {
+	// If non-empty, only features in these namespaces are selected
+	Namespaces []b6.Namespace
+	// If non-nil, only features matching this query are selected
+	Query b6.Query
+}
+
+func (e *Extract) inNamespaces(id b6.FeatureID) bool {
+	if len(e.Namespaces) == 0 {
+		return true
+	}
+	for _, ns := range e.Namespaces {
+		if id.Namespace == ns {
+			return true
+		}
+	}
+	return false
+}
+
+// FeatureIDs returns the IDs of the features of w selected by the extract,
+// together with those of the features needed to build their geometry: the
+// points of selected paths, and the paths of selected areas, and their
+// points, regardless of namespace. Relation members aren't included
+// unless selected, since relations may legitimately refer to features
+// beyond a world.
+func (e *Extract) FeatureIDs(w b6.World, goroutines int) (map[b6.FeatureID]struct{}, error) {
+	ids := make(map[b6.FeatureID]struct{})
+	var lock sync.Mutex
+	add := func(f b6.Feature) {
+		referenced := make([]b6.FeatureID, 0, 1)
+		referenced = append(referenced, f.FeatureID())
+		switch f := f.(type) {
+		case b6.AreaFeature:
+			for i := 0; i < f.Len(); i++ {
+				for _, path := range f.Feature(i) {
+					referenced = append(referenced, path.FeatureID())
+					for _, r := range path.References() {
+						referenced = append(referenced, r.Source())
+					}
+				}
+			}
+		case b6.PhysicalFeature:
+			for _, r := range f.References() {
+				referenced = append(referenced, r.Source())
+			}
+		}
+		lock.Lock()
+		for _, id := range referenced {
+			ids[id] = struct{}{}
+		}
+		lock.Unlock()
+	}
+
+	if e.Query != nil {
+		features := w.FindFeatures(e.Query)
+		for features.Next() {
+			if e.inNamespaces(features.FeatureID()) {
+				add(features.Feature())
+			}
+		}
+		return ids, nil
+	}
+	each := func(f b6.Feature, _ int) error {
+		if e.inNamespaces(f.FeatureID()) {
+			add(f)
+		}
+		return nil
+	}
+	return ids, w.EachFeature(each, &b6.EachFeatureOptions{Goroutines: goroutines})
+}
+
+// NewExtractSource returns a source that reads the features of w selected
+// by the extract, and those needed to build their geometry.
+func NewExtractSource(w b6.World, e *Extract, goroutines int) (FeatureSource, error) {
+	ids, err := e.FeatureIDs(w, goroutines)
+	if err != nil {
+		return nil, err
+	}
+	filter := func(f b6.Feature) bool {
+		_, ok := ids[f.FeatureID()]
+		return ok
+	}
+	return WorldFeatureSource{World: w, Filter: filter}, nil
+}
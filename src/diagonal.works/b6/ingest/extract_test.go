@@ -0,0 +1,87 @@
+package ingest
+
+import (
+	"testing"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/osm"
+	"diagonal.works/b6/test"
+)
+
+func TestExtractBuildingsIncludesGeometry(t *testing.T) {
+	nodes, ways, relations, err := osm.ReadWholePBF(test.Data(test.GranarySquarePBF))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := BuildWorldFromOSM(nodes, ways, relations, &BuildOptions{Cores: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source, err := NewExtractSource(w, &Extract{Query: b6.Keyed{Key: "#building"}}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	extracted, err := NewWorldFromSource(source, &BuildOptions{Cores: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buildings := w.FindFeatures(b6.Keyed{Key: "#building"})
+	n := 0
+	for buildings.Next() {
+		n++
+		building, ok := extracted.FindFeatureByID(buildings.FeatureID()).(b6.AreaFeature)
+		if !ok {
+			t.Errorf("Expected to find %s in extract", buildings.FeatureID())
+			continue
+		}
+		if expected, actual := buildings.Feature().(b6.AreaFeature).Polygon(0).Area(), building.Polygon(0).Area(); expected != actual {
+			t.Errorf("Expected area %f for %s, found %f", expected, buildings.FeatureID(), actual)
+		}
+	}
+	if n == 0 {
+		t.Fatal("Expected some buildings")
+	}
+
+	highways := extracted.FindFeatures(b6.Keyed{Key: "#highway"})
+	for highways.Next() {
+		if !highways.Feature().Get("#building").IsValid() {
+			t.Errorf("Expected only buildings in extract, found %s", highways.FeatureID())
+		}
+	}
+}
+
+func TestExtractByNamespace(t *testing.T) {
+	nodes, ways, relations, err := osm.ReadWholePBF(test.Data(test.GranarySquarePBF))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := BuildWorldFromOSM(nodes, ways, relations, &BuildOptions{Cores: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := Extract{Namespaces: []b6.Namespace{b6.NamespaceOSMRelation}}
+	ids, err := e.FeatureIDs(w, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	relation, paths, points := 0, 0, 0
+	for id := range ids {
+		switch id.Namespace {
+		case b6.NamespaceOSMRelation:
+			relation++
+		case b6.NamespaceOSMWay:
+			paths++
+			if id.Type != b6.FeatureTypePath {
+				t.Errorf("Expected only paths forming relation areas, found %s", id)
+			}
+		case b6.NamespaceOSMNode:
+			points++
+		}
+	}
+	if relation == 0 || paths == 0 || points == 0 {
+		t.Errorf("Expected relations, with paths and points, found %d, %d and %d", relation, paths, points)
+	}
+}
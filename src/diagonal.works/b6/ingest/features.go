@@ -743,6 +743,9 @@ func NewFilledFeatureReferences(byID *FeaturesByID) *FeatureReferencesByID {
 func (f *FeatureReferencesByID) findReferences(id b6.FeatureID, m *map[b6.Reference]bool) {
 	if references, ok := (*f)[id]; ok {
 		for _, reference := range references {
+			if (*m)[reference] {
+				continue // Relations can reference each other
+			}
 			(*m)[reference] = true
 			f.findReferences(reference.Source(), m)
 		}
@@ -820,6 +823,8 @@ type ReadOptions struct {
 
 type WorldFeatureSource struct {
 	World b6.World
+	// If set, only features for which Filter returns true are read
+	Filter func(f b6.Feature) bool
 }
 
 func (w WorldFeatureSource) Read(options ReadOptions, emit Emit, ctx context.Context) error {
@@ -832,6 +837,9 @@ func (w WorldFeatureSource) Read(options ReadOptions, emit Emit, ctx context.Con
 		Goroutines:      options.Goroutines,
 	}
 	f := func(f b6.Feature, goroutine int) error {
+		if w.Filter != nil && !w.Filter(f) {
+			return nil
+		}
 		return emit(NewFeatureFromWorld(f), goroutine)
 	}
 	return w.World.EachFeature(f, &o)
@@ -212,6 +212,30 @@ func TestMergeRelation(t *testing.T) {
 	}
 }
 
+func TestFindReferencesWithRelationsReferencingEachOther(t *testing.T) {
+	path := FromOSMWayID(673447480).FeatureID()
+	a := NewRelationFeature(2)
+	a.RelationID = FromOSMRelationID(11502000)
+	a.Members[0] = b6.RelationMember{ID: path}
+	a.Members[1] = b6.RelationMember{ID: FromOSMRelationID(12564854).FeatureID()}
+	b := NewRelationFeature(1)
+	b.RelationID = FromOSMRelationID(12564854)
+	b.Members[0] = b6.RelationMember{ID: a.FeatureID()}
+
+	references := NewFeatureReferences()
+	references.AddFeature(a)
+	references.AddFeature(b)
+
+	found := make(map[b6.FeatureID]struct{})
+	for _, r := range references.FindReferences(path) {
+		found[r.Source()] = struct{}{}
+	}
+	expected := map[b6.FeatureID]struct{}{a.FeatureID(): {}, b.FeatureID(): {}}
+	if !reflect.DeepEqual(expected, found) {
+		t.Errorf("Expected references from both relations, found %v", found)
+	}
+}
+
 func TestMergeCollection(t *testing.T) {
 	before := CollectionFeature{
 		CollectionID: b6.MakeCollectionID(b6.NamespacePrivate, 1),
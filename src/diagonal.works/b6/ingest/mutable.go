@@ -8,6 +8,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"diagonal.works/b6"
 	"diagonal.works/b6/search"
@@ -24,6 +25,18 @@ type MutableWorld interface {
 	RemoveTag(id b6.FeatureID, key string) error
 	EachModifiedFeature(each func(f b6.Feature, goroutine int) error, options *b6.EachFeatureOptions) error
 	EachModifiedTag(each func(f ModifiedTag, goroutine int) error, options *b6.EachFeatureOptions) error
+
+	// Version returns a number that changes whenever the world is modified.
+	// Versions are unique across all worlds in the process, allowing them
+	// to be used as part of a cache key without also considering the
+	// identity of the world.
+	Version() uint64
+}
+
+var lastWorldVersion atomic.Uint64
+
+func nextWorldVersion() uint64 {
+	return lastWorldVersion.Add(1)
 }
 
 func sortAndDiffTokens(before []string, after []string) ([]string, []string) {
@@ -57,8 +70,16 @@ func sortAndDiffTokens(before []string, after []string) ([]string, []string) {
 	return added, removed
 }
 
+// ReadOnlyWorld adapts a b6.World to MutableWorld, rejecting any attempt
+// to modify it. As the world can't change, its version is fixed when it's
+// created.
 type ReadOnlyWorld struct {
-	World b6.World
+	World   b6.World
+	version uint64
+}
+
+func NewReadOnlyWorld(w b6.World) ReadOnlyWorld {
+	return ReadOnlyWorld{World: w, version: nextWorldVersion()}
 }
 
 func (r ReadOnlyWorld) FindFeatureByID(id b6.FeatureID) b6.Feature {
@@ -109,10 +130,18 @@ func (r ReadOnlyWorld) EachModifiedTag(each func(f ModifiedTag, goroutine int) e
 	return nil
 }
 
+func (r ReadOnlyWorld) Layers() []b6.World {
+	return []b6.World{r.World}
+}
+
 func (r ReadOnlyWorld) Tokens() []string {
 	return r.World.Tokens()
 }
 
+func (r ReadOnlyWorld) Version() uint64 {
+	return r.version
+}
+
 func (r ReadOnlyWorld) AddFeature(f Feature) error {
 	return errors.New("World is read-only")
 }
@@ -153,6 +182,7 @@ type BasicMutableWorld struct {
 	features   *FeaturesByID
 	references *FeatureReferencesByID
 	index      *mutableFeatureIndex
+	version    uint64
 }
 
 func NewBasicMutableWorld() *BasicMutableWorld {
@@ -161,6 +191,7 @@ func NewBasicMutableWorld() *BasicMutableWorld {
 		features:   features,
 		references: NewFeatureReferences(),
 		index:      newMutableFeatureIndex(features),
+		version:    nextWorldVersion(),
 	}
 	return w
 }
@@ -249,6 +280,10 @@ func (m *BasicMutableWorld) Tokens() []string {
 	return search.AllTokens(m.index.Tokens())
 }
 
+func (m *BasicMutableWorld) Version() uint64 {
+	return m.version
+}
+
 func (m *BasicMutableWorld) AddFeature(f Feature) error {
 	if err := ValidateFeature(f, &ValidateOptions{InvertClockwisePaths: false}, m); err != nil {
 		return err
@@ -268,6 +303,7 @@ func (m *BasicMutableWorld) AddFeature(f Feature) error {
 
 	modified := NewModifiedFeatures(f, references, m.features, m)
 	modified.Update(m.features, m.references, m.index, m)
+	m.version = nextWorldVersion()
 	return nil
 }
 
@@ -285,6 +321,7 @@ func (m *BasicMutableWorld) AddTag(id b6.FeatureID, tag b6.Tag) error {
 		if indexedAfter && (!indexedBefore || tokenAfter != tokenBefore) {
 			m.index.Add(f, []string{tokenAfter})
 		}
+		m.version = nextWorldVersion()
 		return nil
 	}
 	return fmt.Errorf("No feature with ID %s", id)
@@ -298,6 +335,7 @@ func (m *BasicMutableWorld) RemoveTag(id b6.FeatureID, key string) error {
 			}
 		}
 		f.RemoveTag(key)
+		m.version = nextWorldVersion()
 	}
 	return nil
 }
@@ -667,6 +705,7 @@ type MutableOverlayWorld struct {
 	base       b6.World
 	tags       ModifiedTags
 	epoch      int
+	version    uint64
 }
 
 func NewMutableOverlayWorld(base b6.World) *MutableOverlayWorld {
@@ -676,6 +715,7 @@ func NewMutableOverlayWorld(base b6.World) *MutableOverlayWorld {
 		base:       base,
 		tags:       NewModifiedTags(),
 		epoch:      0,
+		version:    nextWorldVersion(),
 	}
 	w.index = newMutableFeatureIndex(w)
 	return w
@@ -835,6 +875,10 @@ func (m *MutableOverlayWorld) EachModifiedTag(each func(f ModifiedTag, goroutine
 	return m.tags.EachModifiedTag(each, options)
 }
 
+func (m *MutableOverlayWorld) Layers() []b6.World {
+	return []b6.World{m.base}
+}
+
 func (m *MutableOverlayWorld) Tokens() []string {
 	tokens := make(map[string]struct{})
 	for _, token := range m.base.Tokens() {
@@ -851,6 +895,12 @@ func (m *MutableOverlayWorld) Tokens() []string {
 	return all
 }
 
+// Version returns the version of this world's modifications. The base
+// world is assumed not to change.
+func (m *MutableOverlayWorld) Version() uint64 {
+	return m.version
+}
+
 func (m *MutableOverlayWorld) AddFeature(f Feature) error {
 	if err := ValidateFeature(f, &ValidateOptions{InvertClockwisePaths: false}, m); err != nil {
 		return err
@@ -874,6 +924,7 @@ func (m *MutableOverlayWorld) AddFeature(f Feature) error {
 	modified.Update(m.features, m.references, m.index, m)
 	delete(m.tags, f.FeatureID())
 	m.epoch++
+	m.version = nextWorldVersion()
 	return nil
 }
 
@@ -906,6 +957,7 @@ func (m *MutableOverlayWorld) AddTag(id b6.FeatureID, tag b6.Tag) error {
 			m.tags.ModifyOrAddTag(id, tag)
 		}
 	}
+	m.version = nextWorldVersion()
 	return nil
 }
 
@@ -934,6 +986,7 @@ func (m *MutableOverlayWorld) RemoveTag(id b6.FeatureID, key string) error {
 			}
 		}
 	}
+	m.version = nextWorldVersion()
 	return nil
 }
 
@@ -1218,6 +1271,10 @@ func (m *MutableTagsOverlayWorld) EachFeature(each func(f b6.Feature, goroutine
 	return m.base.EachFeature(wrap, options)
 }
 
+func (m *MutableTagsOverlayWorld) Layers() []b6.World {
+	return []b6.World{m.base}
+}
+
 func (m *MutableTagsOverlayWorld) Tokens() []string {
 	return m.base.Tokens()
 }
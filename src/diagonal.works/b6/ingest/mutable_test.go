@@ -102,6 +102,7 @@ func TestMutableWorlds(t *testing.T) {
 		{"AddSearchableTagToExistingFeature", ValidateAddSearchableTagToExistingFeature},
 		{"ChangeSearchableTagOnExistingFeature", ValidateChangeSearchableTagOnExistingFeature},
 		{"AddTagToNonExistingFeature", ValidateAddTagToNonExistingFeature},
+		{"VersionChangesWithModifications", ValidateVersionChangesWithModifications},
 	}
 
 	for _, creator := range mutableWorldCreators {
@@ -815,6 +816,35 @@ func ValidateAddTagToNonExistingFeature(w MutableWorld, t *testing.T) {
 	}
 }
 
+func ValidateVersionChangesWithModifications(w MutableWorld, t *testing.T) {
+	caravan := osmPoint(2300722786, 51.5357237, -0.1253052)
+	versions := []uint64{w.Version()}
+	if err := w.AddFeature(caravan); err != nil {
+		t.Fatal(err)
+	}
+	versions = append(versions, w.Version())
+	if err := w.AddTag(caravan.FeatureID(), b6.Tag{Key: "#amenity", Value: b6.NewStringExpression("restaurant")}); err != nil {
+		t.Fatal(err)
+	}
+	versions = append(versions, w.Version())
+	if err := w.RemoveTag(caravan.FeatureID(), "#amenity"); err != nil {
+		t.Fatal(err)
+	}
+	versions = append(versions, w.Version())
+	w.FindFeatureByID(caravan.FeatureID())
+	if w.Version() != versions[len(versions)-1] {
+		t.Error("Expected version to remain unchanged after a read")
+	}
+	for i := 1; i < len(versions); i++ {
+		if versions[i] == versions[i-1] {
+			t.Errorf("Expected version to change after modification %d", i)
+		}
+	}
+	if other := NewBasicMutableWorld(); other.Version() == w.Version() {
+		t.Error("Expected versions to be unique across worlds")
+	}
+}
+
 func TestModifyPathInExistingWorld(t *testing.T) {
 	// Extend the Western Transit Shed in Granary Square to cover the Eastern
 	// Handyside Canopy, by switching out points in the path, and ensure we
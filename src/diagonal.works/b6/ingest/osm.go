@@ -180,6 +180,16 @@ func KeyForOSMKey(key string) string {
 	return key
 }
 
+// OSMTagMapping returns a copy of the mapping from OSM tag keys to b6
+// tag keys applied to features read from OSM data.
+func OSMTagMapping() map[string]string {
+	m := make(map[string]string, len(osmTagMapping))
+	for k, v := range osmTagMapping {
+		m[k] = v
+	}
+	return m
+}
+
 func NewWorldFromPBFFile(filename string, o *BuildOptions) (b6.World, error) {
 	source := PBFFilesOSMSource{Glob: filename, FailWhenNoFiles: true}
 	return NewWorldFromOSMSource(&source, o)
@@ -14,6 +14,19 @@ func NewOverlayWorld(overlay b6.World, base b6.World) b6.World {
 	return &OverlayWorld{overlay: overlay, base: base}
 }
 
+// LayeredWorld is implemented by worlds built on top of others, allowing
+// properties of the underlying worlds, like the metadata of compact
+// indices, to be found.
+type LayeredWorld interface {
+	// Layers returns the underlying worlds, in increasing order of
+	// precedence.
+	Layers() []b6.World
+}
+
+func (o *OverlayWorld) Layers() []b6.World {
+	return []b6.World{o.base, o.overlay}
+}
+
 type overlayFeatures struct {
 	base      b6.Features
 	overlay   b6.Features
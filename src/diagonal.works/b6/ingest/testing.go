@@ -3,6 +3,7 @@ package ingest
 import (
 	"fmt"
 	"log"
+	"math"
 	"sort"
 	"sync/atomic"
 	"testing"
@@ -54,6 +55,7 @@ func ValidateWorld(name string, buildWorld BuildOSMWorld, t *testing.T) {
 		{"BrokenOSMWayForArea", ValidateBrokenOSMWayForArea},
 		{"SearchableRelationsNotReferencedByAFeature", ValidateSearchableRelationsNotReferencedByAFeature},
 		{"EachFeature", ValidateEachFeature},
+		{"FindNearestFeatures", ValidateFindNearestFeatures},
 	}
 
 	for _, test := range tests {
@@ -1546,3 +1548,64 @@ func ValidateEachFeature(buildWorld BuildOSMWorld, t *testing.T) {
 		t.Errorf("Expected %d areas, found %d", expectedAreas, areas)
 	}
 }
+
+func ValidateFindNearestFeatures(buildWorld BuildOSMWorld, t *testing.T) {
+	nodes, ways, relations, err := osm.ReadWholePBF(test.Data(test.GranarySquarePBF))
+	if err != nil {
+		t.Errorf("Failed to read world: %s", err)
+		return
+	}
+	w, err := buildWorld(nodes, ways, relations, &BuildOptions{Cores: 2})
+	if err != nil {
+		t.Errorf("Failed to build world: %s", err)
+		return
+	}
+
+	origin := s2.PointFromLatLng(s2.LatLngFromDegrees(51.53531, -0.12434))
+	tests := []struct {
+		name        string
+		query       b6.Query
+		k           int
+		maxDistance s1.Angle
+	}{
+		{"Buildings", b6.Keyed{Key: "#building"}, 5, s1.InfAngle()},
+		// Enough features to subdivide cells while searching
+		{"All", b6.All{}, 200, s1.InfAngle()},
+		{"AllWithin50m", b6.All{}, 1000, b6.MetersToAngle(50)},
+	}
+	for _, test := range tests {
+		// Find the expected distances by brute force
+		distances := make(map[b6.FeatureID]s1.Angle)
+		expected := make([]s1.Angle, 0)
+		features := w.FindFeatures(test.query)
+		for features.Next() {
+			if d, ok := b6.DistanceToFeature(origin, features.Feature()); ok && d <= test.maxDistance {
+				distances[features.FeatureID()] = d
+				expected = append(expected, d)
+			}
+		}
+		sort.Slice(expected, func(i, j int) bool { return expected[i] < expected[j] })
+		if len(expected) > test.k {
+			expected = expected[0:test.k]
+		}
+
+		nearest := b6.NewNearestFeatures(w, origin, test.query, test.maxDistance)
+		found := make([]s1.Angle, 0)
+		for len(found) < test.k && nearest.Next() {
+			if d, ok := distances[nearest.FeatureID()]; !ok || math.Abs(float64(d-nearest.Distance())) > 1e-12 {
+				t.Errorf("%s: unexpected feature %s at %f meters", test.name, nearest.FeatureID(), b6.AngleToMeters(nearest.Distance()))
+			}
+			found = append(found, nearest.Distance())
+		}
+		if len(found) != len(expected) {
+			t.Errorf("%s: expected %d features, found %d", test.name, len(expected), len(found))
+			continue
+		}
+		for i := range found {
+			if math.Abs(float64(found[i]-expected[i])) > 1e-12 {
+				t.Errorf("%s: expected feature %d at %f meters, found %f", test.name, i, b6.AngleToMeters(expected[i]), b6.AngleToMeters(found[i]))
+				break
+			}
+		}
+	}
+}
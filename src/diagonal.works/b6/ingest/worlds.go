@@ -1,7 +1,10 @@
 package ingest
 
 import (
+	"errors"
+	"fmt"
 	"sync"
+	"time"
 
 	"diagonal.works/b6"
 )
@@ -12,16 +15,65 @@ var DefaultWorldFeatureID = b6.FeatureID{
 	Value:     0,
 }
 
+var (
+	ErrWorldNotFound = errors.New("world not found")
+	ErrWorldExists   = errors.New("world already exists")
+	ErrReadOnlyWorld = errors.New("world is read-only")
+)
+
+// WorldMetadata describes a world, allowing scenarios to be identified by
+// more than their ID.
+type WorldMetadata struct {
+	Name    string
+	Author  string
+	Created time.Time
+	// The world this one was cloned from, or FeatureIDInvalid if it wasn't.
+	Parent b6.FeatureID
+	// True if the world has been frozen, and can no longer be modified.
+	ReadOnly bool
+}
+
 type Worlds interface {
 	FindOrCreateWorld(id b6.FeatureID) MutableWorld
+	// FindWorld returns the world with the given ID if it exists, or
+	// otherwise an unmodified world that isn't retained, for readers that
+	// mustn't create worlds by using them.
+	FindWorld(id b6.FeatureID) MutableWorld
 	ListWorlds() []b6.FeatureID
-	DeleteWorld(id b6.FeatureID)
+	DeleteWorld(id b6.FeatureID) error
+
+	// CloneWorld creates a new world with the given ID, containing the
+	// modifications made to another, which callers must hold a read lock
+	// on. If readOnly is true, the clone is frozen, as a snapshot of the
+	// original.
+	CloneWorld(from b6.FeatureID, to b6.FeatureID, readOnly bool) error
+	// FreezeWorld prevents further modification of a world, which callers
+	// must hold a write lock on, such that AddFeature, AddTag and RemoveTag
+	// return errors wrapping ErrReadOnlyWorld.
+	FreezeWorld(id b6.FeatureID) error
+	// RenameWorld moves a world to a new ID, which mustn't already be in
+	// use. Callers must hold a write lock on the world's original ID.
+	RenameWorld(from b6.FeatureID, to b6.FeatureID) error
+	WorldMetadata(id b6.FeatureID) (WorldMetadata, error)
+	// SetWorldMetadata sets the name and author of a world. Other fields
+	// are maintained by Worlds, and ignored.
+	SetWorldMetadata(id b6.FeatureID, metadata WorldMetadata) error
+}
+
+// WorldsChange changes the worlds themselves, for example by renaming one,
+// rather than the features within a world. Like Change, they're returned by
+// functions, and applied after evaluation, with a write lock held on the
+// root world, with the given ID. Apply returns the ID of the world
+// changed or created.
+type WorldsChange interface {
+	Apply(worlds Worlds, root b6.FeatureID) (b6.FeatureID, error)
 }
 
 type MutableWorlds struct {
-	Base    b6.World
-	Mutable map[b6.FeatureID]MutableWorld
-	lock    sync.Mutex
+	Base     b6.World
+	Mutable  map[b6.FeatureID]MutableWorld
+	metadata map[b6.FeatureID]*WorldMetadata
+	lock     sync.Mutex
 }
 
 func (m *MutableWorlds) FindOrCreateWorld(id b6.FeatureID) MutableWorld {
@@ -32,15 +84,48 @@ func (m *MutableWorlds) FindOrCreateWorld(id b6.FeatureID) MutableWorld {
 	defer m.lock.Unlock()
 	w, ok := m.Mutable[id]
 	if !ok {
-		if m.Mutable == nil {
-			m.Mutable = make(map[b6.FeatureID]MutableWorld)
-		}
 		w = NewMutableOverlayWorld(m.Base)
-		m.Mutable[id] = w
+		m.add(id, w, &WorldMetadata{Created: time.Now(), Parent: b6.FeatureIDInvalid})
 	}
 	return w
 }
 
+func (m *MutableWorlds) FindWorld(id b6.FeatureID) MutableWorld {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if w, err := m.find(id); err == nil {
+		return w
+	}
+	return NewMutableOverlayWorld(m.Base)
+}
+
+func (m *MutableWorlds) add(id b6.FeatureID, w MutableWorld, metadata *WorldMetadata) {
+	if m.Mutable == nil {
+		m.Mutable = make(map[b6.FeatureID]MutableWorld)
+	}
+	m.Mutable[id] = w
+	if m.metadata == nil {
+		m.metadata = make(map[b6.FeatureID]*WorldMetadata)
+	}
+	m.metadata[id] = metadata
+}
+
+// find returns the world with the given ID, creating the default world if
+// necessary, since it's assumed to always exist. Callers must hold lock.
+func (m *MutableWorlds) find(id b6.FeatureID) (MutableWorld, error) {
+	if !id.IsValid() {
+		id = DefaultWorldFeatureID
+	}
+	if w, ok := m.Mutable[id]; ok {
+		return w, nil
+	} else if id == DefaultWorldFeatureID {
+		w = NewMutableOverlayWorld(m.Base)
+		m.add(id, w, &WorldMetadata{Created: time.Now(), Parent: b6.FeatureIDInvalid})
+		return w, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrWorldNotFound, id)
+}
+
 func (m *MutableWorlds) ListWorlds() []b6.FeatureID {
 	m.lock.Lock()
 	defer m.lock.Unlock()
@@ -54,22 +139,340 @@ func (m *MutableWorlds) ListWorlds() []b6.FeatureID {
 	return ids
 }
 
-func (m *MutableWorlds) DeleteWorld(id b6.FeatureID) {
+func (m *MutableWorlds) DeleteWorld(id b6.FeatureID) error {
 	m.lock.Lock()
 	defer m.lock.Unlock()
 	delete(m.Mutable, id)
+	delete(m.metadata, id)
+	return nil
+}
+
+func (m *MutableWorlds) CloneWorld(from b6.FeatureID, to b6.FeatureID, readOnly bool) error {
+	if !to.IsValid() {
+		return fmt.Errorf("can't clone to invalid world ID")
+	}
+	m.lock.Lock()
+	source, err := m.find(from)
+	if err == nil {
+		if _, ok := m.Mutable[to]; ok {
+			err = fmt.Errorf("%w: %s", ErrWorldExists, to)
+		}
+	}
+	m.lock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	// Copying modifications may take a while, so we avoid blocking access
+	// to other worlds, and instead check for a conflicting world once done.
+	if f, ok := source.(frozenWorld); ok {
+		source = f.MutableWorld
+	}
+	base := m.Base
+	if l, ok := source.(LayeredWorld); ok {
+		if layers := l.Layers(); len(layers) == 1 {
+			base = layers[0] // For example, a world added with --add-world
+		}
+	}
+	var clone MutableWorld = NewMutableOverlayWorld(base)
+	if err := CopyModifications(source, clone); err != nil {
+		return err
+	}
+	if readOnly {
+		clone = frozenWorld{MutableWorld: clone}
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if _, ok := m.Mutable[to]; ok {
+		return fmt.Errorf("%w: %s", ErrWorldExists, to)
+	}
+	if !from.IsValid() {
+		from = DefaultWorldFeatureID
+	}
+	m.add(to, clone, &WorldMetadata{Created: time.Now(), Parent: from})
+	return nil
+}
+
+func (m *MutableWorlds) FreezeWorld(id b6.FeatureID) error {
+	if !id.IsValid() {
+		id = DefaultWorldFeatureID
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	w, err := m.find(id)
+	if err != nil {
+		return err
+	}
+	if _, ok := w.(frozenWorld); !ok {
+		m.Mutable[id] = frozenWorld{MutableWorld: w}
+	}
+	return nil
+}
+
+func (m *MutableWorlds) RenameWorld(from b6.FeatureID, to b6.FeatureID) error {
+	if !from.IsValid() {
+		from = DefaultWorldFeatureID
+	}
+	if !to.IsValid() {
+		return fmt.Errorf("can't rename to invalid world ID")
+	} else if from == to {
+		return nil
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	w, err := m.find(from)
+	if err != nil {
+		return err
+	} else if _, ok := m.Mutable[to]; ok {
+		return fmt.Errorf("%w: %s", ErrWorldExists, to)
+	}
+	metadata := m.metadata[from]
+	if metadata == nil {
+		metadata = &WorldMetadata{Parent: b6.FeatureIDInvalid}
+	}
+	delete(m.Mutable, from)
+	delete(m.metadata, from)
+	m.add(to, w, metadata)
+	return nil
+}
+
+func (m *MutableWorlds) WorldMetadata(id b6.FeatureID) (WorldMetadata, error) {
+	if !id.IsValid() {
+		id = DefaultWorldFeatureID
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	w, err := m.find(id)
+	if err != nil {
+		return WorldMetadata{}, err
+	}
+	// Worlds passed in Mutable have no recorded metadata.
+	metadata := WorldMetadata{Parent: b6.FeatureIDInvalid}
+	if existing, ok := m.metadata[id]; ok {
+		metadata = *existing
+	}
+	_, metadata.ReadOnly = w.(frozenWorld)
+	return metadata, nil
+}
+
+func (m *MutableWorlds) SetWorldMetadata(id b6.FeatureID, metadata WorldMetadata) error {
+	if !id.IsValid() {
+		id = DefaultWorldFeatureID
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if _, err := m.find(id); err != nil {
+		return err
+	}
+	existing, ok := m.metadata[id]
+	if !ok {
+		existing = &WorldMetadata{Parent: b6.FeatureIDInvalid}
+		if m.metadata == nil {
+			m.metadata = make(map[b6.FeatureID]*WorldMetadata)
+		}
+		m.metadata[id] = existing
+	}
+	existing.Name = metadata.Name
+	existing.Author = metadata.Author
+	return nil
+}
+
+// frozenWorld prevents modification of a world by FreezeWorld, while
+// continuing to report its version and modifications, so it can be cloned,
+// and results cached for it remain valid.
+type frozenWorld struct {
+	MutableWorld
+}
+
+func (f frozenWorld) AddFeature(feature Feature) error {
+	return fmt.Errorf("%w: can't add %s", ErrReadOnlyWorld, feature.FeatureID())
+}
+
+func (f frozenWorld) AddTag(id b6.FeatureID, tag b6.Tag) error {
+	return fmt.Errorf("%w: can't add tag to %s", ErrReadOnlyWorld, id)
+}
+
+func (f frozenWorld) RemoveTag(id b6.FeatureID, key string) error {
+	return fmt.Errorf("%w: can't remove tag from %s", ErrReadOnlyWorld, id)
+}
+
+func (f frozenWorld) Layers() []b6.World {
+	return []b6.World{f.MutableWorld}
+}
+
+// CopyModifications applies the features and tags modified in one world
+// to another.
+func CopyModifications(from MutableWorld, to MutableWorld) error {
+	features := func(f b6.Feature, goroutine int) error {
+		return to.AddFeature(NewFeatureFromWorld(f))
+	}
+	options := b6.EachFeatureOptions{Goroutines: 1, FeedReferencesFirst: true}
+	if err := from.EachModifiedFeature(features, &options); err != nil {
+		return err
+	}
+	tags := func(t ModifiedTag, goroutine int) error {
+		if t.Deleted {
+			return to.RemoveTag(t.ID, t.Tag.Key)
+		}
+		return to.AddTag(t.ID, t.Tag)
+	}
+	return from.EachModifiedTag(tags, &b6.EachFeatureOptions{Goroutines: 1})
 }
 
 type ReadOnlyWorlds struct {
-	Base b6.World
+	world ReadOnlyWorld
+}
+
+func NewReadOnlyWorlds(base b6.World) ReadOnlyWorlds {
+	return ReadOnlyWorlds{world: NewReadOnlyWorld(base)}
 }
 
 func (r ReadOnlyWorlds) FindOrCreateWorld(id b6.FeatureID) MutableWorld {
-	return ReadOnlyWorld{World: r.Base}
+	return r.world
+}
+
+func (r ReadOnlyWorlds) FindWorld(id b6.FeatureID) MutableWorld {
+	return r.world
 }
 
 func (r ReadOnlyWorlds) ListWorlds() []b6.FeatureID {
 	return []b6.FeatureID{DefaultWorldFeatureID}
 }
 
-func (r ReadOnlyWorlds) DeleteWorld(id b6.FeatureID) {}
+func (r ReadOnlyWorlds) DeleteWorld(id b6.FeatureID) error {
+	return fmt.Errorf("%w: can't delete %s", ErrReadOnlyWorld, id)
+}
+
+func (r ReadOnlyWorlds) CloneWorld(from b6.FeatureID, to b6.FeatureID, readOnly bool) error {
+	return fmt.Errorf("%w: can't create %s", ErrReadOnlyWorld, to)
+}
+
+func (r ReadOnlyWorlds) FreezeWorld(id b6.FeatureID) error {
+	return nil
+}
+
+func (r ReadOnlyWorlds) RenameWorld(from b6.FeatureID, to b6.FeatureID) error {
+	return fmt.Errorf("%w: can't rename %s", ErrReadOnlyWorld, from)
+}
+
+func (r ReadOnlyWorlds) WorldMetadata(id b6.FeatureID) (WorldMetadata, error) {
+	return WorldMetadata{Parent: b6.FeatureIDInvalid, ReadOnly: true}, nil
+}
+
+func (r ReadOnlyWorlds) SetWorldMetadata(id b6.FeatureID, metadata WorldMetadata) error {
+	return fmt.Errorf("%w: can't set metadata of %s", ErrReadOnlyWorld, id)
+}
+
+// WorldLocks provides a read/write lock for each world, allowing a change
+// to be applied to one world without blocking readers of the others. This
+// relies on worlds created by MutableWorlds being independent overlays on
+// the same immutable base, such that a change to one is never visible from
+// another. Locks are forgotten once no caller holds or is waiting for
+// them, so locking worlds with arbitrary IDs doesn't leak memory.
+type WorldLocks struct {
+	locks map[b6.FeatureID]*worldLock
+	lock  sync.Mutex
+}
+
+type worldLock struct {
+	sync.RWMutex
+	users int // Protected by WorldLocks.lock
+}
+
+// use returns the lock for the world with the given ID, or for the
+// default world if the ID is invalid, matching FindOrCreateWorld, counting
+// the caller as a user until it calls release.
+func (w *WorldLocks) use(id b6.FeatureID) (b6.FeatureID, *worldLock) {
+	if !id.IsValid() {
+		id = DefaultWorldFeatureID
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	l, ok := w.locks[id]
+	if !ok {
+		if w.locks == nil {
+			w.locks = make(map[b6.FeatureID]*worldLock)
+		}
+		l = &worldLock{}
+		w.locks[id] = l
+	}
+	l.users++
+	return id, l
+}
+
+func (w *WorldLocks) release(id b6.FeatureID, l *worldLock) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if l.users--; l.users == 0 {
+		delete(w.locks, id)
+	}
+}
+
+// Lock takes a write lock on the world with the given ID, returning a
+// function that releases it.
+func (w *WorldLocks) Lock(id b6.FeatureID) func() {
+	id, l := w.use(id)
+	l.Lock()
+	return func() {
+		l.Unlock()
+		w.release(id, l)
+	}
+}
+
+// RLock takes a read lock on the world with the given ID, returning a
+// function that releases it.
+func (w *WorldLocks) RLock(id b6.FeatureID) func() {
+	id, l := w.use(id)
+	l.RLock()
+	return func() {
+		l.RUnlock()
+		w.release(id, l)
+	}
+}
+
+// TryRLock takes a read lock on the world with the given ID if it's
+// available without waiting, returning a function that releases it.
+func (w *WorldLocks) TryRLock(id b6.FeatureID) (func(), bool) {
+	id, l := w.use(id)
+	if !l.TryRLock() {
+		w.release(id, l)
+		return nil, false
+	}
+	return func() {
+		l.RUnlock()
+		w.release(id, l)
+	}, true
+}
+
+// Upgrade exchanges a read lock the caller holds on the world with the
+// given ID for a write lock, returning a function that exchanges it back.
+// As the read lock is released first, other writers may take the lock in
+// between.
+func (w *WorldLocks) Upgrade(id b6.FeatureID) func() {
+	id, l := w.use(id)
+	l.RUnlock()
+	l.Lock()
+	return func() {
+		l.Unlock()
+		l.RLock()
+		w.release(id, l)
+	}
+}
+
+// RLockExisting takes a read lock on the world with the given ID if it
+// exists, or otherwise on the default world, for readers that mustn't
+// create worlds by using them. It returns the ID of the world locked, and
+// a function that releases the lock.
+func (w *WorldLocks) RLockExisting(worlds Worlds, id b6.FeatureID) (b6.FeatureID, func()) {
+	if !id.IsValid() {
+		id = DefaultWorldFeatureID
+	}
+	unlock := w.RLock(id)
+	if _, err := worlds.WorldMetadata(id); err != nil {
+		unlock()
+		id = DefaultWorldFeatureID
+		unlock = w.RLock(id)
+	}
+	return id, unlock
+}
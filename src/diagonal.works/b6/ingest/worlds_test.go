@@ -0,0 +1,273 @@
+package ingest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"diagonal.works/b6"
+	"github.com/google/go-cmp/cmp"
+)
+
+var (
+	world1 = b6.FeatureID{Type: b6.FeatureTypeCollection, Namespace: DefaultWorldFeatureID.Namespace, Value: 1}
+	world2 = b6.FeatureID{Type: b6.FeatureTypeCollection, Namespace: DefaultWorldFeatureID.Namespace, Value: 2}
+	world3 = b6.FeatureID{Type: b6.FeatureTypeCollection, Namespace: DefaultWorldFeatureID.Namespace, Value: 3}
+)
+
+// buildWorldsForTests returns worlds with a base containing a path along
+// Goods Way, and a point and collection near it.
+func buildWorldsForTests(t *testing.T) (*MutableWorlds, Feature) {
+	t.Helper()
+	a := osmPoint(7555161584, 51.5345488, -0.1251005)
+	b := osmPoint(6384669830, 51.5342291, -0.1262792)
+	ab := osmPath(807924986, []Feature{a, b})
+	ab.AddTag(b6.Tag{Key: "#highway", Value: b6.NewStringExpression("tertiary")})
+	ab.AddTag(b6.Tag{Key: "lit", Value: b6.NewStringExpression("no")})
+	base := NewBasicMutableWorld()
+	if err := addFeatures(base, a, b, ab); err != nil {
+		t.Fatal(err)
+	}
+	return &MutableWorlds{Base: base}, ab
+}
+
+func TestCloneWorld(t *testing.T) {
+	worlds, ab := buildWorldsForTests(t)
+	original := worlds.FindOrCreateWorld(world1)
+	if err := original.AddTag(ab.FeatureID(), b6.Tag{Key: "lit", Value: b6.NewStringExpression("yes")}); err != nil {
+		t.Fatal(err)
+	}
+	caravan := osmPoint(2300722786, 51.5357237, -0.1253052)
+	if err := original.AddFeature(caravan); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := worlds.CloneWorld(world1, world2, false); err != nil {
+		t.Fatal(err)
+	}
+	clone := worlds.FindOrCreateWorld(world2)
+	if lit := clone.FindFeatureByID(ab.FeatureID()).Get("lit"); lit.Value.String() != "yes" {
+		t.Errorf("Expected modified tag in clone, found %s", lit)
+	}
+	if clone.FindFeatureByID(caravan.FeatureID()) == nil {
+		t.Error("Expected added feature in clone")
+	}
+
+	if err := clone.AddTag(ab.FeatureID(), b6.Tag{Key: "#highway", Value: b6.NewStringExpression("primary")}); err != nil {
+		t.Fatal(err)
+	}
+	if highway := original.FindFeatureByID(ab.FeatureID()).Get("#highway"); highway.Value.String() != "tertiary" {
+		t.Errorf("Expected original to be unaffected by changes to clone, found %s", highway)
+	}
+
+	metadata, err := worlds.WorldMetadata(world2)
+	if err != nil {
+		t.Fatal(err)
+	} else if metadata.Parent != world1 || metadata.Created.IsZero() || metadata.ReadOnly {
+		t.Errorf("Unexpected metadata for clone: %+v", metadata)
+	}
+
+	if err := worlds.CloneWorld(world1, world2, false); !errors.Is(err, ErrWorldExists) {
+		t.Errorf("Expected ErrWorldExists, found %v", err)
+	}
+	if err := worlds.CloneWorld(world3, world2, false); !errors.Is(err, ErrWorldNotFound) {
+		t.Errorf("Expected ErrWorldNotFound, found %v", err)
+	}
+}
+
+func TestFreezeWorld(t *testing.T) {
+	worlds, ab := buildWorldsForTests(t)
+	lit := b6.Tag{Key: "lit", Value: b6.NewStringExpression("yes")}
+	if err := worlds.FindOrCreateWorld(world1).AddTag(ab.FeatureID(), lit); err != nil {
+		t.Fatal(err)
+	}
+	if err := worlds.FreezeWorld(world1); err != nil {
+		t.Fatal(err)
+	}
+	frozen := worlds.FindOrCreateWorld(world1)
+	if err := frozen.AddTag(ab.FeatureID(), lit); !errors.Is(err, ErrReadOnlyWorld) {
+		t.Errorf("Expected ErrReadOnlyWorld, found %v", err)
+	}
+	if metadata, err := worlds.WorldMetadata(world1); err != nil || !metadata.ReadOnly {
+		t.Errorf("Expected world to be read-only, found %+v, %v", metadata, err)
+	}
+
+	if err := worlds.CloneWorld(world1, world2, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := worlds.FindOrCreateWorld(world2).AddTag(ab.FeatureID(), lit); err != nil {
+		t.Errorf("Expected clone of frozen world to be modifiable, found %v", err)
+	}
+
+	if err := worlds.CloneWorld(world2, world3, true); err != nil {
+		t.Fatal(err)
+	}
+	snapshot := worlds.FindOrCreateWorld(world3)
+	if err := snapshot.AddTag(ab.FeatureID(), lit); !errors.Is(err, ErrReadOnlyWorld) {
+		t.Errorf("Expected snapshot to be read-only, found %v", err)
+	}
+	if lit := snapshot.FindFeatureByID(ab.FeatureID()).Get("lit"); lit.Value.String() != "yes" {
+		t.Errorf("Expected modified tag in snapshot, found %s", lit)
+	}
+}
+
+func TestRenameWorld(t *testing.T) {
+	worlds, ab := buildWorldsForTests(t)
+	if err := worlds.FindOrCreateWorld(world1).AddTag(ab.FeatureID(), b6.Tag{Key: "lit", Value: b6.NewStringExpression("yes")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := worlds.SetWorldMetadata(world1, WorldMetadata{Name: "Goods Way lighting", Author: "andrew"}); err != nil {
+		t.Fatal(err)
+	}
+	worlds.FindOrCreateWorld(world3)
+
+	if err := worlds.RenameWorld(world1, world2); err != nil {
+		t.Fatal(err)
+	}
+	ids := worlds.ListWorlds()
+	if !containsWorld(ids, world2) || containsWorld(ids, world1) {
+		t.Errorf("Expected world to be listed under its new ID only, found %v", ids)
+	}
+	if lit := worlds.FindOrCreateWorld(world2).FindFeatureByID(ab.FeatureID()).Get("lit"); lit.Value.String() != "yes" {
+		t.Errorf("Expected modified tag in renamed world, found %s", lit)
+	}
+	if metadata, err := worlds.WorldMetadata(world2); err != nil || metadata.Name != "Goods Way lighting" || metadata.Author != "andrew" {
+		t.Errorf("Expected metadata to move with world, found %+v, %v", metadata, err)
+	}
+
+	if err := worlds.RenameWorld(world1, world2); !errors.Is(err, ErrWorldNotFound) {
+		t.Errorf("Expected ErrWorldNotFound, found %v", err)
+	}
+	if err := worlds.RenameWorld(world2, world3); !errors.Is(err, ErrWorldExists) {
+		t.Errorf("Expected ErrWorldExists, found %v", err)
+	}
+}
+
+func TestFindWorld(t *testing.T) {
+	worlds, ab := buildWorldsForTests(t)
+	if err := worlds.FindOrCreateWorld(world1).AddTag(ab.FeatureID(), b6.Tag{Key: "lit", Value: b6.NewStringExpression("yes")}); err != nil {
+		t.Fatal(err)
+	}
+	if lit := worlds.FindWorld(world1).FindFeatureByID(ab.FeatureID()).Get("lit"); lit.Value.String() != "yes" {
+		t.Errorf("Expected modified tag in existing world, found %s", lit)
+	}
+	missing := worlds.FindWorld(world2)
+	if lit := missing.FindFeatureByID(ab.FeatureID()).Get("lit"); lit.Value.String() != "no" {
+		t.Errorf("Expected unmodified tag in missing world, found %s", lit)
+	}
+	if containsWorld(worlds.ListWorlds(), world2) {
+		t.Error("Expected FindWorld not to create the world")
+	}
+}
+
+func containsWorld(ids []b6.FeatureID, id b6.FeatureID) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDiffWorlds(t *testing.T) {
+	worlds, ab := buildWorldsForTests(t)
+	from := worlds.FindOrCreateWorld(world1)
+	caravan := osmPoint(2300722786, 51.5357237, -0.1253052)
+	collection := simpleCollection(b6.CollectionID{Namespace: "diagonal.works/test", Value: 1}, "lit", "yes")
+	if err := addFeatures(from, caravan, collection); err != nil {
+		t.Fatal(err)
+	}
+	if err := from.AddTag(ab.FeatureID(), b6.Tag{Key: "lit", Value: b6.NewStringExpression("yes")}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := worlds.CloneWorld(world1, world2, false); err != nil {
+		t.Fatal(err)
+	}
+	to := worlds.FindOrCreateWorld(world2)
+	if err := to.AddTag(ab.FeatureID(), b6.Tag{Key: "lit", Value: b6.NewStringExpression("no")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := to.RemoveTag(ab.FeatureID(), "#highway"); err != nil {
+		t.Fatal(err)
+	}
+	if err := to.AddFeature(simpleCollection(collection.CollectionID, "lit", "no")); err != nil {
+		t.Fatal(err)
+	}
+	granary := osmPoint(4966136655, 51.5352746, -0.1244493)
+	if err := to.AddFeature(granary); err != nil {
+		t.Fatal(err)
+	}
+	coalDrops := osmPoint(2300722787, 51.5360, -0.1260)
+	if err := from.AddFeature(coalDrops); err != nil {
+		t.Fatal(err)
+	}
+
+	diffs, err := DiffWorlds(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := make([]string, len(diffs))
+	for i, diff := range diffs {
+		found[i] = fmt.Sprintf("%s added=%v removed=%v tags=%v removed-tags=%v members=%v", diff.ID, diff.Added, diff.Removed, diff.Tags, diff.RemovedTags, diff.Members)
+	}
+	expected := []string{
+		fmt.Sprintf("%s added=false removed=true tags=[] removed-tags=[] members=false", coalDrops.FeatureID()),
+		fmt.Sprintf("%s added=true removed=false tags=[] removed-tags=[] members=false", granary.FeatureID()),
+		fmt.Sprintf("%s added=false removed=false tags=[lit=no] removed-tags=[#highway] members=false", ab.FeatureID()),
+		fmt.Sprintf("%s added=false removed=false tags=[] removed-tags=[] members=true", collection.FeatureID()),
+	}
+	if diff := cmp.Diff(expected, found); diff != "" {
+		t.Errorf("Found diff (-want, +got):\n%s", diff)
+	}
+
+	if diffs, err := DiffWorlds(to, to); err != nil || len(diffs) != 0 {
+		t.Errorf("Expected no differences between a world and itself, found %v, %v", diffs, err)
+	}
+}
+
+func TestReadOnlyWorldsHaveUniqueVersions(t *testing.T) {
+	base := NewBasicMutableWorld()
+	a := NewReadOnlyWorlds(base)
+	b := NewReadOnlyWorlds(base)
+	va := a.FindWorld(DefaultWorldFeatureID).Version()
+	if va == 0 || va == base.Version() || va == b.FindWorld(DefaultWorldFeatureID).Version() {
+		t.Errorf("Expected a unique version, found %d", va)
+	}
+	if again := a.FindOrCreateWorld(world1).Version(); again != va {
+		t.Errorf("Expected version to be stable, found %d then %d", va, again)
+	}
+}
+
+func TestWorldLocksForgetUnusedLocks(t *testing.T) {
+	var locks WorldLocks
+	for i := uint64(0); i < 100; i++ {
+		id := b6.FeatureID{Type: b6.FeatureTypeCollection, Namespace: DefaultWorldFeatureID.Namespace, Value: i}
+		locks.Lock(id)()
+		locks.RLock(id)()
+		if unlock, ok := locks.TryRLock(id); ok {
+			unlock()
+		} else {
+			t.Fatal("Expected to lock an unused world")
+		}
+	}
+	if len(locks.locks) != 0 {
+		t.Errorf("Expected unused locks to be forgotten, found %d", len(locks.locks))
+	}
+
+	runlock := locks.RLock(world1)
+	downgrade := locks.Upgrade(world1)
+	if _, ok := locks.TryRLock(world1); ok {
+		t.Error("Expected an upgraded lock to exclude readers")
+	}
+	downgrade()
+	unlock, ok := locks.TryRLock(world1)
+	if !ok {
+		t.Fatal("Expected a downgraded lock to allow readers")
+	}
+	unlock()
+	runlock()
+	if len(locks.locks) != 0 {
+		t.Errorf("Expected unused locks to be forgotten, found %d", len(locks.locks))
+	}
+}
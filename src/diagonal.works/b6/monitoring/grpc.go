@@ -0,0 +1,114 @@
+package monitoring
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// The gRPC metadata key from which request IDs are read, and to which
+// they're written in response headers.
+const RequestIDMetadata = "x-request-id"
+
+// GRPCMetrics counts calls to, and the latency of, gRPC methods.
+type GRPCMetrics struct {
+	requests  *prometheus.CounterVec
+	durations *prometheus.HistogramVec
+}
+
+func NewGRPCMetrics(r prometheus.Registerer) *GRPCMetrics {
+	f := promauto.With(r)
+	return &GRPCMetrics{
+		requests: f.NewCounterVec(prometheus.CounterOpts{
+			Name: "b6_grpc_requests_total",
+			Help: "gRPC calls, by method and status code.",
+		}, []string{"method", "code"}),
+		durations: f.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "b6_grpc_request_duration_seconds",
+			Help:    "Latency of gRPC calls, by method.",
+			Buckets: DurationBuckets,
+		}, []string{"method"}),
+	}
+}
+
+func levelForCode(code codes.Code) slog.Level {
+	switch code {
+	case codes.OK:
+		return slog.LevelDebug
+	case codes.Unknown, codes.Internal, codes.Unavailable, codes.DataLoss, codes.Unimplemented:
+		return slog.LevelError
+	}
+	return slog.LevelWarn
+}
+
+func (g *GRPCMetrics) start(ctx context.Context) context.Context {
+	id := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(RequestIDMetadata); len(ids) > 0 && validRequestID(ids[0]) {
+			id = ids[0]
+		}
+	}
+	if id == "" {
+		id = NewRequestID()
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(RequestIDMetadata, id))
+	return WithRequestID(ctx, id)
+}
+
+func (g *GRPCMetrics) finish(ctx context.Context, method string, start time.Time, err error) {
+	duration := time.Since(start)
+	code := status.Code(err)
+	g.durations.WithLabelValues(method).Observe(duration.Seconds())
+	g.requests.WithLabelValues(method, code.String()).Inc()
+	attrs := []any{
+		slog.String("method", method),
+		slog.String("code", code.String()),
+		slog.Duration("duration", duration),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	slog.Log(ctx, levelForCode(code), "grpc request", attrs...)
+}
+
+// UnaryServerInterceptor assigns each call an ID, either from
+// RequestIDMetadata, or a new one, which is returned in the same response
+// header, and added to the call's context for logging. Calls are logged
+// on completion, at debug level if they succeed, and recorded in metrics.
+func (g *GRPCMetrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		ctx = g.start(ctx)
+		response, err := handler(ctx, request)
+		g.finish(ctx, info.FullMethod, start, err)
+		return response, err
+	}
+}
+
+type streamWithContext struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *streamWithContext) Context() context.Context {
+	return s.ctx
+}
+
+// StreamServerInterceptor is the equivalent of UnaryServerInterceptor for
+// streaming calls.
+func (g *GRPCMetrics) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(server interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx := g.start(stream.Context())
+		err := handler(server, &streamWithContext{ServerStream: stream, ctx: ctx})
+		g.finish(ctx, info.FullMethod, start, err)
+		return err
+	}
+}
@@ -0,0 +1,126 @@
+package monitoring
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// The header from which request IDs are read, if set by a load balancer,
+// and to which they're written.
+const RequestIDHeader = "X-Request-ID"
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	if s.status == 0 {
+		s.status = status
+	}
+	s.ResponseWriter.WriteHeader(status)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	return s.ResponseWriter.Write(b)
+}
+
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+func (s *statusRecorder) Status() int {
+	if s.status == 0 {
+		return http.StatusOK
+	}
+	return s.status
+}
+
+func validRequestID(id string) bool {
+	if len(id) == 0 || len(id) > 64 {
+		return false
+	}
+	for _, r := range id {
+		if r <= ' ' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func levelForStatus(status int) slog.Level {
+	switch {
+	case status >= 500:
+		return slog.LevelError
+	case status >= 400:
+		return slog.LevelWarn
+	}
+	return slog.LevelDebug
+}
+
+// Handler assigns each request an ID, either from RequestIDHeader, or a
+// new one, which is returned in the same header, and added to the
+// request's context for logging. Requests are logged on completion, at
+// debug level if they succeed.
+func Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = NewRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+		ctx := WithRequestID(r.Context(), id)
+		recorder := &statusRecorder{ResponseWriter: w}
+		start := time.Now()
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+		status := recorder.Status()
+		slog.Log(ctx, levelForStatus(status), "http request",
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", status),
+			slog.Duration("duration", time.Since(start)),
+			slog.String("remote", r.RemoteAddr),
+		)
+	})
+}
+
+// HTTPMetrics counts requests to, and the latency of, HTTP handlers.
+type HTTPMetrics struct {
+	requests  *prometheus.CounterVec
+	durations *prometheus.HistogramVec
+}
+
+func NewHTTPMetrics(r prometheus.Registerer) *HTTPMetrics {
+	f := promauto.With(r)
+	return &HTTPMetrics{
+		requests: f.NewCounterVec(prometheus.CounterOpts{
+			Name: "b6_http_requests_total",
+			Help: "HTTP requests, by handler and status code.",
+		}, []string{"handler", "code"}),
+		durations: f.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "b6_http_request_duration_seconds",
+			Help:    "Latency of HTTP requests, by handler.",
+			Buckets: DurationBuckets,
+		}, []string{"handler"}),
+	}
+}
+
+// Instrument returns a handler recording metrics for requests to the given
+// handler under the given name, matching ui.Options.InstrumentHandler.
+func (h *HTTPMetrics) Instrument(handler http.Handler, name string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := &statusRecorder{ResponseWriter: w}
+		start := time.Now()
+		handler.ServeHTTP(recorder, r)
+		h.durations.WithLabelValues(name).Observe(time.Since(start).Seconds())
+		h.requests.WithLabelValues(name, strconv.Itoa(recorder.Status())).Inc()
+	})
+}
@@ -0,0 +1,78 @@
+package monitoring
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+type requestIDKey struct{}
+
+// NewRequestID returns a random ID with which to identify a request in
+// logs.
+func NewRequestID() string {
+	var b [8]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// WithRequestID returns a context carrying the given request ID, which
+// is added to records logged with it by handlers from NewLogHandler.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by the given context, or an
+// empty string if there isn't one.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+type logHandler struct {
+	slog.Handler
+}
+
+// NewLogHandler returns a handler that adds a request_id attribute to
+// records logged with a context carrying one, before passing them to the
+// given handler.
+func NewLogHandler(h slog.Handler) slog.Handler {
+	return logHandler{Handler: h}
+}
+
+func (l logHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r = r.Clone()
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	return l.Handler.Handle(ctx, r)
+}
+
+func (l logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return logHandler{Handler: l.Handler.WithAttrs(attrs)}
+}
+
+func (l logHandler) WithGroup(name string) slog.Handler {
+	return logHandler{Handler: l.Handler.WithGroup(name)}
+}
+
+// NewLogger returns a logger writing to w in the given format, either
+// text or json, that adds request IDs to records, as described in
+// NewLogHandler.
+func NewLogger(w io.Writer, format string, level slog.Level) (*slog.Logger, error) {
+	options := &slog.HandlerOptions{Level: level}
+	var h slog.Handler
+	switch strings.ToLower(format) {
+	case "text":
+		h = slog.NewTextHandler(w, options)
+	case "json":
+		h = slog.NewJSONHandler(w, options)
+	default:
+		return nil, fmt.Errorf("unknown log format %q, expected text or json", format)
+	}
+	return slog.New(NewLogHandler(h)), nil
+}
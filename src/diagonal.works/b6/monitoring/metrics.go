@@ -0,0 +1,21 @@
+// Package monitoring exports metrics to Prometheus, and logs requests to
+// the b6 gRPC and HTTP servers.
+package monitoring
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// Buckets suitable for the duration of requests, in seconds, from
+// milliseconds for tiles to minutes for large evaluations.
+var DurationBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 120}
+
+// NewRegistry returns a registry holding the standard metrics for the Go
+// runtime and the process, to which b6's own metrics are added.
+func NewRegistry() *prometheus.Registry {
+	r := prometheus.NewRegistry()
+	r.MustRegister(collectors.NewGoCollector())
+	r.MustRegister(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	return r
+}
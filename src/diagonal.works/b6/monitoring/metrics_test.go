@@ -0,0 +1,105 @@
+package monitoring
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func TestHandlerLogsRequestIDs(t *testing.T) {
+	var logs bytes.Buffer
+	logger, err := NewLogger(&logs, "json", slog.LevelDebug)
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := slog.Default()
+	slog.SetDefault(logger)
+	defer slog.SetDefault(previous)
+
+	var seen string
+	handler := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestID(r.Context())
+		slog.InfoContext(r.Context(), "handling")
+		http.Error(w, "not found", http.StatusNotFound)
+	}))
+
+	request := httptest.NewRequest(http.MethodGet, "/evaluate", nil)
+	request.Header.Set(RequestIDHeader, "from-load-balancer")
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	if seen != "from-load-balancer" {
+		t.Errorf("Expected request ID from header, found %q", seen)
+	}
+	if id := response.Header().Get(RequestIDHeader); id != seen {
+		t.Errorf("Expected response header %q, found %q", seen, id)
+	}
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 log lines, found %d", len(lines))
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, `"request_id":"from-load-balancer"`) {
+			t.Errorf("Expected request ID in %s", line)
+		}
+	}
+	if !strings.Contains(lines[1], `"level":"WARN"`) || !strings.Contains(lines[1], `"status":404`) {
+		t.Errorf("Expected warning with status, found %s", lines[1])
+	}
+
+	request = httptest.NewRequest(http.MethodGet, "/evaluate", nil)
+	request.Header.Set(RequestIDHeader, "invalid\nid")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+	if seen == "" || seen == "invalid\nid" {
+		t.Errorf("Expected a new request ID, found %q", seen)
+	}
+}
+
+func TestInstrumentCountsRequests(t *testing.T) {
+	r := NewRegistry()
+	m := NewHTTPMetrics(r)
+	handler := m.Instrument(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("e") == "" {
+			http.Error(w, "missing expression", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte("ok"))
+	}), "evaluate")
+	for _, url := range []string{"/evaluate?e=1", "/evaluate?e=2", "/evaluate"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, url, nil))
+	}
+
+	response := httptest.NewRecorder()
+	promhttp.HandlerFor(r, promhttp.HandlerOpts{}).ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	for _, expected := range []string{
+		`b6_http_requests_total{code="200",handler="evaluate"} 2`,
+		`b6_http_requests_total{code="400",handler="evaluate"} 1`,
+		`b6_http_request_duration_seconds_count{handler="evaluate"} 3`,
+		`go_goroutines `,
+	} {
+		if !strings.Contains(response.Body.String(), expected) {
+			t.Errorf("Expected %q in metrics", expected)
+		}
+	}
+}
+
+func TestLogHandlerIgnoresContextsWithoutRequestIDs(t *testing.T) {
+	var logs bytes.Buffer
+	logger, err := NewLogger(&logs, "text", slog.LevelInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.InfoContext(context.Background(), "started")
+	logger.DebugContext(WithRequestID(context.Background(), "abc"), "ignored")
+	if strings.Contains(logs.String(), "request_id") || strings.Contains(logs.String(), "ignored") {
+		t.Errorf("Unexpected log output: %s", logs.String())
+	}
+	if _, err := NewLogger(&logs, "xml", slog.LevelInfo); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,178 @@
+package b6
+
+import (
+	"container/heap"
+
+	"diagonal.works/b6/search"
+
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+)
+
+// Cells containing more than this number of matching features are
+// subdivided, rather than having all their features considered, unless
+// they're at search.MaxIndexedCellLevel.
+const nearestMaxFeaturesPerCell = 64
+
+// nearestItem is either a cell yet to be searched, or a feature whose
+// distance is known.
+type nearestItem struct {
+	distance s1.ChordAngle
+	cell     s2.CellID
+	feature  Feature
+}
+
+type nearestQueue []nearestItem
+
+func (n nearestQueue) Len() int { return len(n) }
+
+func (n nearestQueue) Less(i, j int) bool {
+	if n[i].distance != n[j].distance {
+		return n[i].distance < n[j].distance
+	}
+	// Emit features before searching cells at the same distance, and
+	// features at the same distance in a stable order.
+	if (n[i].feature == nil) != (n[j].feature == nil) {
+		return n[i].feature != nil
+	} else if n[i].feature != nil {
+		return n[i].feature.FeatureID().Less(n[j].feature.FeatureID())
+	}
+	return n[i].cell < n[j].cell
+}
+
+func (n nearestQueue) Swap(i, j int) { n[i], n[j] = n[j], n[i] }
+
+func (n *nearestQueue) Push(x interface{}) { *n = append(*n, x.(nearestItem)) }
+
+func (n *nearestQueue) Pop() interface{} {
+	item := (*n)[len(*n)-1]
+	*n = (*n)[0 : len(*n)-1]
+	return item
+}
+
+// NearestFeatures iterates over the features matching a query in order of
+// increasing straight line distance from a point. It searches S2 cells
+// best first, starting from the faces of the cube, subdividing cells
+// containing many matching features down to search.MaxIndexedCellLevel,
+// so only features from cells closer than the last feature returned are
+// considered. It works with any world, since cells are searched with
+// IntersectsCells queries.
+type NearestFeatures struct {
+	w           World
+	origin      s2.Point
+	query       Query
+	maxDistance s1.ChordAngle
+	queue       nearestQueue
+	seen        map[FeatureID]struct{}
+	current     nearestItem
+}
+
+// NewNearestFeatures returns the features in w that match q, ordered by
+// distance from origin, ignoring features further than maxDistance.
+// Use s1.InfAngle() for no limit.
+func NewNearestFeatures(w World, origin s2.Point, q Query, maxDistance s1.Angle) *NearestFeatures {
+	n := &NearestFeatures{
+		w:           w,
+		origin:      origin,
+		query:       q,
+		maxDistance: s1.ChordAngleFromAngle(maxDistance),
+		seen:        make(map[FeatureID]struct{}),
+	}
+	for face := 0; face < 6; face++ {
+		n.pushCell(s2.CellIDFromFace(face))
+	}
+	return n
+}
+
+func (n *NearestFeatures) pushCell(id s2.CellID) {
+	if d := s2.CellFromCellID(id).Distance(n.origin); d <= n.maxDistance {
+		heap.Push(&n.queue, nearestItem{distance: d, cell: id})
+	}
+}
+
+// searchCell adds the features matching the query in the given cell to
+// the queue, or subdivides the cell if it contains too many.
+func (n *NearestFeatures) searchCell(id s2.CellID) {
+	features := n.w.FindFeatures(Intersection{n.query, NewIntersectsCellID(id)})
+	found := make([]Feature, 0)
+	for features.Next() {
+		if _, ok := n.seen[features.FeatureID()]; ok {
+			continue
+		}
+		found = append(found, features.Feature())
+		if len(found) > nearestMaxFeaturesPerCell && id.Level() < search.MaxIndexedCellLevel {
+			for _, child := range id.Children() {
+				n.pushCell(child)
+			}
+			return
+		}
+	}
+	for _, f := range found {
+		n.seen[f.FeatureID()] = struct{}{}
+		if d, ok := DistanceToFeature(n.origin, f); ok {
+			if d := s1.ChordAngleFromAngle(d); d <= n.maxDistance {
+				heap.Push(&n.queue, nearestItem{distance: d, feature: f})
+			}
+		}
+	}
+}
+
+func (n *NearestFeatures) Next() bool {
+	for n.queue.Len() > 0 {
+		item := heap.Pop(&n.queue).(nearestItem)
+		if item.feature != nil {
+			n.current = item
+			return true
+		}
+		n.searchCell(item.cell)
+	}
+	return false
+}
+
+func (n *NearestFeatures) Feature() Feature {
+	return n.current.feature
+}
+
+func (n *NearestFeatures) FeatureID() FeatureID {
+	return n.current.feature.FeatureID()
+}
+
+// Distance returns the distance between the origin and the current
+// feature.
+func (n *NearestFeatures) Distance() s1.Angle {
+	return n.current.distance.Angle()
+}
+
+var _ Features = &NearestFeatures{}
+
+// DistanceToFeature returns the straight line distance between the point
+// and the closest part of the feature's geometry, which is zero if it lies
+// within an area, or false if the feature has no geometry.
+func DistanceToFeature(p s2.Point, f Feature) (s1.Angle, bool) {
+	if a, ok := f.(AreaFeature); ok {
+		distance := s1.InfAngle()
+		for i := 0; i < a.Len(); i++ {
+			polygon := a.Polygon(i)
+			if polygon.ContainsPoint(p) {
+				return 0, true
+			}
+			for _, loop := range polygon.Loops() {
+				for j := 0; j < loop.NumVertices(); j++ {
+					if d := s2.DistanceFromSegment(p, loop.Vertex(j), loop.Vertex(j+1)); d < distance {
+						distance = d
+					}
+				}
+			}
+		}
+		return distance, a.Len() > 0
+	} else if g, ok := f.(Geometry); ok {
+		switch g.GeometryType() {
+		case GeometryTypePoint:
+			return p.Distance(g.Point()), true
+		case GeometryTypePath:
+			projection, _ := g.Polyline().Project(p)
+			return p.Distance(projection), true
+		}
+	}
+	return 0, false
+}
@@ -112,6 +112,23 @@ func readBlobs(r io.Reader, blobs chan<- *blob, ctx context.Context) error {
 	}
 }
 
+// ReadHeader returns the header block from the start of a PBF file,
+// which includes the replication timestamp, if set.
+func ReadHeader(r io.Reader) (*pb.HeaderBlock, error) {
+	t, data, err := readBlob(r)
+	if err != nil {
+		return nil, err
+	}
+	if t != blobTypeOSMHeader {
+		return nil, fmt.Errorf("expected %s blob, found %s", blobTypeOSMHeader, t)
+	}
+	var header pb.HeaderBlock
+	if err := proto.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+	return &header, nil
+}
+
 type ReadOptions struct {
 	SkipTags      bool
 	SkipNodes     bool
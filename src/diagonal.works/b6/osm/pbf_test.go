@@ -84,6 +84,24 @@ func TestParsePBF(t *testing.T) {
 	}
 }
 
+func TestReadHeader(t *testing.T) {
+	f, err := os.Open(test.Data(test.GranarySquarePBF))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	header, err := ReadHeader(f)
+	if err != nil {
+		t.Fatalf("ReadHeader() failed with: %v", err)
+	}
+	if program := header.GetWritingprogram(); program != "osmium/1.13.1" {
+		t.Errorf("Expected writing program osmium/1.13.1, found %q", program)
+	}
+	if header.OsmosisReplicationTimestamp != nil {
+		t.Errorf("Expected no replication timestamp, found %d", header.GetOsmosisReplicationTimestamp())
+	}
+}
+
 func TestParsePBFSkippingTags(t *testing.T) {
 	f, err := os.Open(test.Data(test.GranarySquarePBF))
 	if err != nil {
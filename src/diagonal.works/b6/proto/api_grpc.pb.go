@@ -19,9 +19,16 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	B6_Evaluate_FullMethodName    = "/api.B6/Evaluate"
-	B6_DeleteWorld_FullMethodName = "/api.B6/DeleteWorld"
-	B6_ListWorlds_FullMethodName  = "/api.B6/ListWorlds"
+	B6_Evaluate_FullMethodName         = "/api.B6/Evaluate"
+	B6_DeleteWorld_FullMethodName      = "/api.B6/DeleteWorld"
+	B6_ListWorlds_FullMethodName       = "/api.B6/ListWorlds"
+	B6_WorldInfo_FullMethodName        = "/api.B6/WorldInfo"
+	B6_Complete_FullMethodName         = "/api.B6/Complete"
+	B6_CloneWorld_FullMethodName       = "/api.B6/CloneWorld"
+	B6_FreezeWorld_FullMethodName      = "/api.B6/FreezeWorld"
+	B6_RenameWorld_FullMethodName      = "/api.B6/RenameWorld"
+	B6_SetWorldMetadata_FullMethodName = "/api.B6/SetWorldMetadata"
+	B6_DiffWorlds_FullMethodName       = "/api.B6/DiffWorlds"
 )
 
 // B6Client is the client API for B6 service.
@@ -31,6 +38,13 @@ type B6Client interface {
 	Evaluate(ctx context.Context, in *EvaluateRequestProto, opts ...grpc.CallOption) (*EvaluateResponseProto, error)
 	DeleteWorld(ctx context.Context, in *DeleteWorldRequestProto, opts ...grpc.CallOption) (*DeleteWorldResponseProto, error)
 	ListWorlds(ctx context.Context, in *ListWorldsRequestProto, opts ...grpc.CallOption) (*ListWorldsResponseProto, error)
+	WorldInfo(ctx context.Context, in *WorldInfoRequestProto, opts ...grpc.CallOption) (*WorldInfoResponseProto, error)
+	Complete(ctx context.Context, in *CompleteRequestProto, opts ...grpc.CallOption) (*CompleteResponseProto, error)
+	CloneWorld(ctx context.Context, in *CloneWorldRequestProto, opts ...grpc.CallOption) (*CloneWorldResponseProto, error)
+	FreezeWorld(ctx context.Context, in *FreezeWorldRequestProto, opts ...grpc.CallOption) (*FreezeWorldResponseProto, error)
+	RenameWorld(ctx context.Context, in *RenameWorldRequestProto, opts ...grpc.CallOption) (*RenameWorldResponseProto, error)
+	SetWorldMetadata(ctx context.Context, in *SetWorldMetadataRequestProto, opts ...grpc.CallOption) (*SetWorldMetadataResponseProto, error)
+	DiffWorlds(ctx context.Context, in *DiffWorldsRequestProto, opts ...grpc.CallOption) (*DiffWorldsResponseProto, error)
 }
 
 type b6Client struct {
@@ -68,6 +82,69 @@ func (c *b6Client) ListWorlds(ctx context.Context, in *ListWorldsRequestProto, o
 	return out, nil
 }
 
+func (c *b6Client) WorldInfo(ctx context.Context, in *WorldInfoRequestProto, opts ...grpc.CallOption) (*WorldInfoResponseProto, error) {
+	out := new(WorldInfoResponseProto)
+	err := c.cc.Invoke(ctx, B6_WorldInfo_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *b6Client) Complete(ctx context.Context, in *CompleteRequestProto, opts ...grpc.CallOption) (*CompleteResponseProto, error) {
+	out := new(CompleteResponseProto)
+	err := c.cc.Invoke(ctx, B6_Complete_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *b6Client) CloneWorld(ctx context.Context, in *CloneWorldRequestProto, opts ...grpc.CallOption) (*CloneWorldResponseProto, error) {
+	out := new(CloneWorldResponseProto)
+	err := c.cc.Invoke(ctx, B6_CloneWorld_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *b6Client) FreezeWorld(ctx context.Context, in *FreezeWorldRequestProto, opts ...grpc.CallOption) (*FreezeWorldResponseProto, error) {
+	out := new(FreezeWorldResponseProto)
+	err := c.cc.Invoke(ctx, B6_FreezeWorld_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *b6Client) RenameWorld(ctx context.Context, in *RenameWorldRequestProto, opts ...grpc.CallOption) (*RenameWorldResponseProto, error) {
+	out := new(RenameWorldResponseProto)
+	err := c.cc.Invoke(ctx, B6_RenameWorld_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *b6Client) SetWorldMetadata(ctx context.Context, in *SetWorldMetadataRequestProto, opts ...grpc.CallOption) (*SetWorldMetadataResponseProto, error) {
+	out := new(SetWorldMetadataResponseProto)
+	err := c.cc.Invoke(ctx, B6_SetWorldMetadata_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *b6Client) DiffWorlds(ctx context.Context, in *DiffWorldsRequestProto, opts ...grpc.CallOption) (*DiffWorldsResponseProto, error) {
+	out := new(DiffWorldsResponseProto)
+	err := c.cc.Invoke(ctx, B6_DiffWorlds_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // B6Server is the server API for B6 service.
 // All implementations must embed UnimplementedB6Server
 // for forward compatibility
@@ -75,6 +152,13 @@ type B6Server interface {
 	Evaluate(context.Context, *EvaluateRequestProto) (*EvaluateResponseProto, error)
 	DeleteWorld(context.Context, *DeleteWorldRequestProto) (*DeleteWorldResponseProto, error)
 	ListWorlds(context.Context, *ListWorldsRequestProto) (*ListWorldsResponseProto, error)
+	WorldInfo(context.Context, *WorldInfoRequestProto) (*WorldInfoResponseProto, error)
+	Complete(context.Context, *CompleteRequestProto) (*CompleteResponseProto, error)
+	CloneWorld(context.Context, *CloneWorldRequestProto) (*CloneWorldResponseProto, error)
+	FreezeWorld(context.Context, *FreezeWorldRequestProto) (*FreezeWorldResponseProto, error)
+	RenameWorld(context.Context, *RenameWorldRequestProto) (*RenameWorldResponseProto, error)
+	SetWorldMetadata(context.Context, *SetWorldMetadataRequestProto) (*SetWorldMetadataResponseProto, error)
+	DiffWorlds(context.Context, *DiffWorldsRequestProto) (*DiffWorldsResponseProto, error)
 	mustEmbedUnimplementedB6Server()
 }
 
@@ -91,6 +175,27 @@ func (UnimplementedB6Server) DeleteWorld(context.Context, *DeleteWorldRequestPro
 func (UnimplementedB6Server) ListWorlds(context.Context, *ListWorldsRequestProto) (*ListWorldsResponseProto, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListWorlds not implemented")
 }
+func (UnimplementedB6Server) WorldInfo(context.Context, *WorldInfoRequestProto) (*WorldInfoResponseProto, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WorldInfo not implemented")
+}
+func (UnimplementedB6Server) Complete(context.Context, *CompleteRequestProto) (*CompleteResponseProto, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Complete not implemented")
+}
+func (UnimplementedB6Server) CloneWorld(context.Context, *CloneWorldRequestProto) (*CloneWorldResponseProto, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloneWorld not implemented")
+}
+func (UnimplementedB6Server) FreezeWorld(context.Context, *FreezeWorldRequestProto) (*FreezeWorldResponseProto, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FreezeWorld not implemented")
+}
+func (UnimplementedB6Server) RenameWorld(context.Context, *RenameWorldRequestProto) (*RenameWorldResponseProto, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameWorld not implemented")
+}
+func (UnimplementedB6Server) SetWorldMetadata(context.Context, *SetWorldMetadataRequestProto) (*SetWorldMetadataResponseProto, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetWorldMetadata not implemented")
+}
+func (UnimplementedB6Server) DiffWorlds(context.Context, *DiffWorldsRequestProto) (*DiffWorldsResponseProto, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiffWorlds not implemented")
+}
 func (UnimplementedB6Server) mustEmbedUnimplementedB6Server() {}
 
 // UnsafeB6Server may be embedded to opt out of forward compatibility for this service.
@@ -158,6 +263,132 @@ func _B6_ListWorlds_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _B6_WorldInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorldInfoRequestProto)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(B6Server).WorldInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: B6_WorldInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(B6Server).WorldInfo(ctx, req.(*WorldInfoRequestProto))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _B6_Complete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompleteRequestProto)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(B6Server).Complete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: B6_Complete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(B6Server).Complete(ctx, req.(*CompleteRequestProto))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _B6_CloneWorld_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloneWorldRequestProto)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(B6Server).CloneWorld(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: B6_CloneWorld_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(B6Server).CloneWorld(ctx, req.(*CloneWorldRequestProto))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _B6_FreezeWorld_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FreezeWorldRequestProto)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(B6Server).FreezeWorld(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: B6_FreezeWorld_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(B6Server).FreezeWorld(ctx, req.(*FreezeWorldRequestProto))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _B6_RenameWorld_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameWorldRequestProto)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(B6Server).RenameWorld(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: B6_RenameWorld_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(B6Server).RenameWorld(ctx, req.(*RenameWorldRequestProto))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _B6_SetWorldMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetWorldMetadataRequestProto)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(B6Server).SetWorldMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: B6_SetWorldMetadata_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(B6Server).SetWorldMetadata(ctx, req.(*SetWorldMetadataRequestProto))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _B6_DiffWorlds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffWorldsRequestProto)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(B6Server).DiffWorlds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: B6_DiffWorlds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(B6Server).DiffWorlds(ctx, req.(*DiffWorldsRequestProto))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // B6_ServiceDesc is the grpc.ServiceDesc for B6 service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -177,6 +408,34 @@ var B6_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListWorlds",
 			Handler:    _B6_ListWorlds_Handler,
 		},
+		{
+			MethodName: "WorldInfo",
+			Handler:    _B6_WorldInfo_Handler,
+		},
+		{
+			MethodName: "Complete",
+			Handler:    _B6_Complete_Handler,
+		},
+		{
+			MethodName: "CloneWorld",
+			Handler:    _B6_CloneWorld_Handler,
+		},
+		{
+			MethodName: "FreezeWorld",
+			Handler:    _B6_FreezeWorld_Handler,
+		},
+		{
+			MethodName: "RenameWorld",
+			Handler:    _B6_RenameWorld_Handler,
+		},
+		{
+			MethodName: "SetWorldMetadata",
+			Handler:    _B6_SetWorldMetadata_Handler,
+		},
+		{
+			MethodName: "DiffWorlds",
+			Handler:    _B6_DiffWorlds_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api.proto",
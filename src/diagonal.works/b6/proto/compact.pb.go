@@ -1,7 +1,7 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.25.0-devel
-// 	protoc        v3.21.12
+// 	protoc-gen-go v1.35.1
+// 	protoc        v5.28.3
 // source: compact.proto
 
 package proto
@@ -27,15 +27,26 @@ type CompactHeaderProto struct {
 
 	Namespaces []string `protobuf:"bytes,1,rep,name=namespaces,proto3" json:"namespaces,omitempty"`
 	Builder    string   `protobuf:"bytes,2,opt,name=Builder,proto3" json:"Builder,omitempty"`
+	// The files features were read from, if known.
+	Sources []*CompactSourceProto `protobuf:"bytes,3,rep,name=sources,proto3" json:"sources,omitempty"`
+	// The latest OSM replication timestamp of the sources, in seconds since
+	// the epoch, or 0 if unknown.
+	ReplicationTimestamp int64 `protobuf:"varint,4,opt,name=replication_timestamp,json=replicationTimestamp,proto3" json:"replication_timestamp,omitempty"`
+	// When the index was built, in seconds since the epoch.
+	BuildTimestamp int64 `protobuf:"varint,5,opt,name=build_timestamp,json=buildTimestamp,proto3" json:"build_timestamp,omitempty"`
+	// The options used to build the index, for example command line flags.
+	Options map[string]string `protobuf:"bytes,6,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The mapping from OSM tag keys to b6 tag keys used while ingesting,
+	// for example building -> #building.
+	TagMapping map[string]string     `protobuf:"bytes,7,rep,name=tag_mapping,json=tagMapping,proto3" json:"tag_mapping,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Counts     []*CompactCountsProto `protobuf:"bytes,8,rep,name=counts,proto3" json:"counts,omitempty"`
 }
 
 func (x *CompactHeaderProto) Reset() {
 	*x = CompactHeaderProto{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_compact_proto_msgTypes[0]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_compact_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *CompactHeaderProto) String() string {
@@ -46,7 +57,7 @@ func (*CompactHeaderProto) ProtoMessage() {}
 
 func (x *CompactHeaderProto) ProtoReflect() protoreflect.Message {
 	mi := &file_compact_proto_msgTypes[0]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -75,18 +86,402 @@ func (x *CompactHeaderProto) GetBuilder() string {
 	return ""
 }
 
+func (x *CompactHeaderProto) GetSources() []*CompactSourceProto {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+func (x *CompactHeaderProto) GetReplicationTimestamp() int64 {
+	if x != nil {
+		return x.ReplicationTimestamp
+	}
+	return 0
+}
+
+func (x *CompactHeaderProto) GetBuildTimestamp() int64 {
+	if x != nil {
+		return x.BuildTimestamp
+	}
+	return 0
+}
+
+func (x *CompactHeaderProto) GetOptions() map[string]string {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *CompactHeaderProto) GetTagMapping() map[string]string {
+	if x != nil {
+		return x.TagMapping
+	}
+	return nil
+}
+
+func (x *CompactHeaderProto) GetCounts() []*CompactCountsProto {
+	if x != nil {
+		return x.Counts
+	}
+	return nil
+}
+
+type CompactSourceProto struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Filename string `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	Size     int64  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	// Hex encoded SHA-256 of the file's content.
+	Sha256 string `protobuf:"bytes,3,opt,name=sha256,proto3" json:"sha256,omitempty"`
+}
+
+func (x *CompactSourceProto) Reset() {
+	*x = CompactSourceProto{}
+	mi := &file_compact_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompactSourceProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompactSourceProto) ProtoMessage() {}
+
+func (x *CompactSourceProto) ProtoReflect() protoreflect.Message {
+	mi := &file_compact_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompactSourceProto.ProtoReflect.Descriptor instead.
+func (*CompactSourceProto) Descriptor() ([]byte, []int) {
+	return file_compact_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CompactSourceProto) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *CompactSourceProto) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *CompactSourceProto) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+type CompactCountsProto struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Namespace string `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Points    uint64 `protobuf:"varint,2,opt,name=points,proto3" json:"points,omitempty"`
+	Paths     uint64 `protobuf:"varint,3,opt,name=paths,proto3" json:"paths,omitempty"`
+	Areas     uint64 `protobuf:"varint,4,opt,name=areas,proto3" json:"areas,omitempty"`
+	Relations uint64 `protobuf:"varint,5,opt,name=relations,proto3" json:"relations,omitempty"`
+}
+
+func (x *CompactCountsProto) Reset() {
+	*x = CompactCountsProto{}
+	mi := &file_compact_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompactCountsProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompactCountsProto) ProtoMessage() {}
+
+func (x *CompactCountsProto) ProtoReflect() protoreflect.Message {
+	mi := &file_compact_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompactCountsProto.ProtoReflect.Descriptor instead.
+func (*CompactCountsProto) Descriptor() ([]byte, []int) {
+	return file_compact_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CompactCountsProto) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *CompactCountsProto) GetPoints() uint64 {
+	if x != nil {
+		return x.Points
+	}
+	return 0
+}
+
+func (x *CompactCountsProto) GetPaths() uint64 {
+	if x != nil {
+		return x.Paths
+	}
+	return 0
+}
+
+func (x *CompactCountsProto) GetAreas() uint64 {
+	if x != nil {
+		return x.Areas
+	}
+	return 0
+}
+
+func (x *CompactCountsProto) GetRelations() uint64 {
+	if x != nil {
+		return x.Relations
+	}
+	return 0
+}
+
+// Written in the last block of an index, since it's only known once the
+// other blocks are complete.
+type CompactMetadataProto struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The bounding box of all points in the index, unset if there are none.
+	BoundsLo *PointProto `protobuf:"bytes,1,opt,name=bounds_lo,json=boundsLo,proto3" json:"bounds_lo,omitempty"`
+	BoundsHi *PointProto `protobuf:"bytes,2,opt,name=bounds_hi,json=boundsHi,proto3" json:"bounds_hi,omitempty"`
+	// CRC-32C (Castagnoli) checksum of the index header, version, header
+	// proto and strings, ie everything preceding the first block.
+	HeaderCrc32C uint32                       `protobuf:"varint,3,opt,name=header_crc32c,json=headerCrc32c,proto3" json:"header_crc32c,omitempty"`
+	Blocks       []*CompactBlockChecksumProto `protobuf:"bytes,4,rep,name=blocks,proto3" json:"blocks,omitempty"`
+}
+
+func (x *CompactMetadataProto) Reset() {
+	*x = CompactMetadataProto{}
+	mi := &file_compact_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompactMetadataProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompactMetadataProto) ProtoMessage() {}
+
+func (x *CompactMetadataProto) ProtoReflect() protoreflect.Message {
+	mi := &file_compact_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompactMetadataProto.ProtoReflect.Descriptor instead.
+func (*CompactMetadataProto) Descriptor() ([]byte, []int) {
+	return file_compact_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CompactMetadataProto) GetBoundsLo() *PointProto {
+	if x != nil {
+		return x.BoundsLo
+	}
+	return nil
+}
+
+func (x *CompactMetadataProto) GetBoundsHi() *PointProto {
+	if x != nil {
+		return x.BoundsHi
+	}
+	return nil
+}
+
+func (x *CompactMetadataProto) GetHeaderCrc32C() uint32 {
+	if x != nil {
+		return x.HeaderCrc32C
+	}
+	return 0
+}
+
+func (x *CompactMetadataProto) GetBlocks() []*CompactBlockChecksumProto {
+	if x != nil {
+		return x.Blocks
+	}
+	return nil
+}
+
+type CompactBlockChecksumProto struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Offset of the block header.
+	Offset uint64 `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
+	Type   uint64 `protobuf:"varint,2,opt,name=type,proto3" json:"type,omitempty"`
+	// CRC-32C (Castagnoli) checksum of the block, including its header.
+	Crc32C uint32 `protobuf:"varint,3,opt,name=crc32c,proto3" json:"crc32c,omitempty"`
+}
+
+func (x *CompactBlockChecksumProto) Reset() {
+	*x = CompactBlockChecksumProto{}
+	mi := &file_compact_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompactBlockChecksumProto) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompactBlockChecksumProto) ProtoMessage() {}
+
+func (x *CompactBlockChecksumProto) ProtoReflect() protoreflect.Message {
+	mi := &file_compact_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompactBlockChecksumProto.ProtoReflect.Descriptor instead.
+func (*CompactBlockChecksumProto) Descriptor() ([]byte, []int) {
+	return file_compact_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CompactBlockChecksumProto) GetOffset() uint64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *CompactBlockChecksumProto) GetType() uint64 {
+	if x != nil {
+		return x.Type
+	}
+	return 0
+}
+
+func (x *CompactBlockChecksumProto) GetCrc32C() uint32 {
+	if x != nil {
+		return x.Crc32C
+	}
+	return 0
+}
+
 var File_compact_proto protoreflect.FileDescriptor
 
 var file_compact_proto_rawDesc = []byte{
 	0x0a, 0x0d, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
-	0x07, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x22, 0x4e, 0x0a, 0x12, 0x43, 0x6f, 0x6d, 0x70,
-	0x61, 0x63, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x1e,
-	0x0a, 0x0a, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03,
-	0x28, 0x09, 0x52, 0x0a, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x12, 0x18,
-	0x0a, 0x07, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x07, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x65, 0x72, 0x42, 0x19, 0x5a, 0x17, 0x64, 0x69, 0x61, 0x67,
-	0x6f, 0x6e, 0x61, 0x6c, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x2f, 0x62, 0x36, 0x2f, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x07, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x1a, 0x0e, 0x67, 0x65, 0x6f, 0x6d, 0x65, 0x74,
+	0x72, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xa5, 0x04, 0x0a, 0x12, 0x43, 0x6f, 0x6d,
+	0x70, 0x61, 0x63, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x1e, 0x0a, 0x0a, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x0a, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x12,
+	0x18, 0x0a, 0x07, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x65, 0x72, 0x12, 0x35, 0x0a, 0x07, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x63, 0x6f, 0x6d,
+	0x70, 0x61, 0x63, 0x74, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x53, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x52, 0x07, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73,
+	0x12, 0x33, 0x0a, 0x15, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
+	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x14, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x27, 0x0a, 0x0f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x5f, 0x74,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e,
+	0x62, 0x75, 0x69, 0x6c, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x42,
+	0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x28, 0x2e, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63,
+	0x74, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x12, 0x4c, 0x0a, 0x0b, 0x74, 0x61, 0x67, 0x5f, 0x6d, 0x61, 0x70, 0x70, 0x69, 0x6e,
+	0x67, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x63,
+	0x74, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x50,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x61, 0x67, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x0a, 0x74, 0x61, 0x67, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67,
+	0x12, 0x33, 0x0a, 0x06, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x1b, 0x2e, 0x63, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61,
+	0x63, 0x74, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x52, 0x06, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x73, 0x1a, 0x3a, 0x0a, 0x0c, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38,
+	0x01, 0x1a, 0x3d, 0x0a, 0x0f, 0x54, 0x61, 0x67, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x22, 0x5c, 0x0a, 0x12, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x53, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x22, 0x94,
+	0x01, 0x0a, 0x12, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x73,
+	0x50, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70,
+	0x61, 0x63, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x06, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x70,
+	0x61, 0x74, 0x68, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x70, 0x61, 0x74, 0x68,
+	0x73, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x72, 0x65, 0x61, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x05, 0x61, 0x72, 0x65, 0x61, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x6c, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x72, 0x65, 0x6c, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xdd, 0x01, 0x0a, 0x14, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63,
+	0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x31,
+	0x0a, 0x09, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x73, 0x5f, 0x6c, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x14, 0x2e, 0x67, 0x65, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x79, 0x2e, 0x50, 0x6f, 0x69,
+	0x6e, 0x74, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x52, 0x08, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x73, 0x4c,
+	0x6f, 0x12, 0x31, 0x0a, 0x09, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x73, 0x5f, 0x68, 0x69, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x65, 0x6f, 0x6d, 0x65, 0x74, 0x72, 0x79, 0x2e,
+	0x50, 0x6f, 0x69, 0x6e, 0x74, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x52, 0x08, 0x62, 0x6f, 0x75, 0x6e,
+	0x64, 0x73, 0x48, 0x69, 0x12, 0x23, 0x0a, 0x0d, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x5f, 0x63,
+	0x72, 0x63, 0x33, 0x32, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x68, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x43, 0x72, 0x63, 0x33, 0x32, 0x63, 0x12, 0x3a, 0x0a, 0x06, 0x62, 0x6c, 0x6f,
+	0x63, 0x6b, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x63, 0x6f, 0x6d, 0x70,
+	0x61, 0x63, 0x74, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x43, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x52, 0x06, 0x62,
+	0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x22, 0x5f, 0x0a, 0x19, 0x43, 0x6f, 0x6d, 0x70, 0x61, 0x63, 0x74,
+	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x50, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x63, 0x72, 0x63, 0x33, 0x32, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06,
+	0x63, 0x72, 0x63, 0x33, 0x32, 0x63, 0x42, 0x19, 0x5a, 0x17, 0x64, 0x69, 0x61, 0x67, 0x6f, 0x6e,
+	0x61, 0x6c, 0x2e, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x2f, 0x62, 0x36, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -101,16 +496,30 @@ func file_compact_proto_rawDescGZIP() []byte {
 	return file_compact_proto_rawDescData
 }
 
-var file_compact_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
-var file_compact_proto_goTypes = []interface{}{
-	(*CompactHeaderProto)(nil), // 0: compact.CompactHeaderProto
+var file_compact_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_compact_proto_goTypes = []any{
+	(*CompactHeaderProto)(nil),        // 0: compact.CompactHeaderProto
+	(*CompactSourceProto)(nil),        // 1: compact.CompactSourceProto
+	(*CompactCountsProto)(nil),        // 2: compact.CompactCountsProto
+	(*CompactMetadataProto)(nil),      // 3: compact.CompactMetadataProto
+	(*CompactBlockChecksumProto)(nil), // 4: compact.CompactBlockChecksumProto
+	nil,                               // 5: compact.CompactHeaderProto.OptionsEntry
+	nil,                               // 6: compact.CompactHeaderProto.TagMappingEntry
+	(*PointProto)(nil),                // 7: geometry.PointProto
 }
 var file_compact_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	1, // 0: compact.CompactHeaderProto.sources:type_name -> compact.CompactSourceProto
+	5, // 1: compact.CompactHeaderProto.options:type_name -> compact.CompactHeaderProto.OptionsEntry
+	6, // 2: compact.CompactHeaderProto.tag_mapping:type_name -> compact.CompactHeaderProto.TagMappingEntry
+	2, // 3: compact.CompactHeaderProto.counts:type_name -> compact.CompactCountsProto
+	7, // 4: compact.CompactMetadataProto.bounds_lo:type_name -> geometry.PointProto
+	7, // 5: compact.CompactMetadataProto.bounds_hi:type_name -> geometry.PointProto
+	4, // 6: compact.CompactMetadataProto.blocks:type_name -> compact.CompactBlockChecksumProto
+	7, // [7:7] is the sub-list for method output_type
+	7, // [7:7] is the sub-list for method input_type
+	7, // [7:7] is the sub-list for extension type_name
+	7, // [7:7] is the sub-list for extension extendee
+	0, // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_compact_proto_init() }
@@ -118,27 +527,14 @@ func file_compact_proto_init() {
 	if File_compact_proto != nil {
 		return
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_compact_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CompactHeaderProto); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-	}
+	file_geometry_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_compact_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   1,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,391 @@
+// Package raster draws geometries into images, allowing maps to be
+// rendered to PNG on the server, for example for figures in reports.
+package raster
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+
+	"diagonal.works/b6"
+
+	"github.com/golang/geo/r2"
+	"github.com/golang/geo/s2"
+	"golang.org/x/image/vector"
+)
+
+// Style describes how geometries are drawn. Polygons are filled with
+// Fill, and outlined with Stroke, while lines are drawn with Stroke, and
+// points as circles. Nil colours aren't drawn. Widths are in pixels.
+type Style struct {
+	Fill        color.Color
+	Stroke      color.Color
+	StrokeWidth float64
+	PointRadius float64
+}
+
+// The largest width or height of a canvas, limiting memory use.
+const MaxSize = 8192
+
+// Geometries are clipped to the canvas, plus this margin, in pixels,
+// ensuring the edges of clipped polygons aren't visible.
+const clipMargin = 64
+
+// Canvas is an image onto which geometries are drawn, using the web
+// mercator projection.
+type Canvas struct {
+	image      *image.RGBA
+	rasterizer *vector.Rasterizer
+	mercator   s2.Projection
+	// The projected center of the canvas, and the number of pixels per
+	// unit of the projection, in which the world is 1 unit wide.
+	center r2.Point
+	scale  float64
+}
+
+// NewCanvas returns a canvas of the given size, filled with the given
+// background colour, showing the given bounds, inset by padding pixels
+// on each side. The bounds are centered within the canvas, which shows a
+// larger area if its aspect ratio differs from that of the bounds.
+func NewCanvas(bounds s2.Rect, width int, height int, padding int, background color.Color) (*Canvas, error) {
+	if width <= 0 || height <= 0 || width > MaxSize || height > MaxSize {
+		return nil, fmt.Errorf("expected a size between 1 and %d pixels, found %dx%d", MaxSize, width, height)
+	}
+	if padding < 0 || 2*padding >= width || 2*padding >= height {
+		return nil, fmt.Errorf("expected padding smaller than half the size, found %d", padding)
+	}
+	if bounds.IsEmpty() {
+		return nil, fmt.Errorf("expected non-empty bounds")
+	}
+	c := &Canvas{
+		image:      image.NewRGBA(image.Rect(0, 0, width, height)),
+		rasterizer: vector.NewRasterizer(0, 0),
+		mercator:   s2.NewMercatorProjection(0.5),
+	}
+	lo := c.project(s2.PointFromLatLng(bounds.Lo()))
+	hi := c.project(s2.PointFromLatLng(bounds.Hi()))
+	c.center = r2.Point{X: (lo.X + hi.X) / 2, Y: (lo.Y + hi.Y) / 2}
+	dx, dy := math.Abs(hi.X-lo.X), math.Abs(hi.Y-lo.Y)
+	c.scale = math.Inf(1)
+	if dx > 0 {
+		c.scale = float64(width-2*padding) / dx
+	}
+	if dy > 0 {
+		c.scale = math.Min(c.scale, float64(height-2*padding)/dy)
+	}
+	if math.IsInf(c.scale, 1) {
+		// Bounds containing a single point are shown at street level
+		c.scale = 256 * (1 << 17)
+	}
+	if background != nil {
+		draw.Draw(c.image, c.image.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+	}
+	return c, nil
+}
+
+// project returns the given point in a web mercator projection in which
+// the world spans 0 to 1 in both dimensions, with y increasing southwards.
+func (c *Canvas) project(p s2.Point) r2.Point {
+	projected := c.mercator.Project(p)
+	return r2.Point{X: projected.X + 0.5, Y: 0.5 - projected.Y}
+}
+
+// ToPixel returns the pixel coordinates of the given point.
+func (c *Canvas) ToPixel(p s2.Point) r2.Point {
+	projected := c.project(p)
+	size := c.image.Bounds().Size()
+	return r2.Point{
+		X: (projected.X-c.center.X)*c.scale + float64(size.X)/2,
+		Y: (projected.Y-c.center.Y)*c.scale + float64(size.Y)/2,
+	}
+}
+
+// Bounds returns the area shown by the canvas.
+func (c *Canvas) Bounds() s2.Rect {
+	size := c.image.Bounds().Size()
+	unproject := func(x, y float64) s2.LatLng {
+		// Canvases can show more than the width of the world
+		u := math.Max(0, math.Min(1, (x-float64(size.X)/2)/c.scale+c.center.X))
+		v := math.Max(0, math.Min(1, (y-float64(size.Y)/2)/c.scale+c.center.Y))
+		return s2.LatLngFromPoint(c.mercator.Unproject(r2.Point{X: u - 0.5, Y: 0.5 - v}))
+	}
+	return s2.RectFromLatLng(unproject(0, float64(size.Y))).AddPoint(unproject(float64(size.X), 0))
+}
+
+// Zoom returns the web map zoom level at which 256 pixel tiles have the
+// same scale as the canvas.
+func (c *Canvas) Zoom() float64 {
+	return math.Log2(c.scale / 256)
+}
+
+func (c *Canvas) Image() image.Image {
+	return c.image
+}
+
+func (c *Canvas) EncodePNG(w io.Writer) error {
+	return png.Encode(w, c.image)
+}
+
+// DrawGeometry draws the given geometry, which can be a point, path or
+// area.
+func (c *Canvas) DrawGeometry(g b6.Geometry, style *Style) {
+	switch g := g.(type) {
+	case b6.Area:
+		for i := 0; i < g.Len(); i++ {
+			c.DrawPolygon(g.Polygon(i), style)
+		}
+		return
+	}
+	switch g.GeometryType() {
+	case b6.GeometryTypePoint:
+		c.DrawPoint(g.Point(), style)
+	case b6.GeometryTypePath:
+		c.DrawPolyline(g.Polyline(), style)
+	}
+}
+
+func (c *Canvas) DrawPoint(p s2.Point, style *Style) {
+	if style.PointRadius <= 0 {
+		return
+	}
+	center := c.ToPixel(p)
+	if style.Stroke != nil && style.StrokeWidth > 0 {
+		c.fill([][]r2.Point{circle(center, style.PointRadius+style.StrokeWidth/2)}, style.Stroke)
+	}
+	if style.Fill != nil {
+		radius := style.PointRadius
+		if style.Stroke != nil && style.StrokeWidth > 0 {
+			radius -= style.StrokeWidth / 2
+		}
+		c.fill([][]r2.Point{circle(center, radius)}, style.Fill)
+	}
+}
+
+func (c *Canvas) DrawPolyline(l *s2.Polyline, style *Style) {
+	if style.Stroke == nil || style.StrokeWidth <= 0 {
+		return
+	}
+	points := make([]r2.Point, len(*l))
+	for i, p := range *l {
+		points[i] = c.ToPixel(p)
+	}
+	c.fill(c.stroke(points, false, style.StrokeWidth), style.Stroke)
+}
+
+func (c *Canvas) DrawPolygon(p *s2.Polygon, style *Style) {
+	// Holes are oriented in the opposite direction to shells, so aren't
+	// filled.
+	rings := make([][]r2.Point, 0, p.NumLoops())
+	for _, loop := range p.Loops() {
+		ring := make([]r2.Point, loop.NumVertices())
+		for i := range ring {
+			ring[i] = c.ToPixel(loop.OrientedVertex(i))
+		}
+		rings = append(rings, ring)
+	}
+	if style.Fill != nil {
+		clipped := make([][]r2.Point, 0, len(rings))
+		for _, ring := range rings {
+			if ring = c.clipRing(ring); len(ring) > 2 {
+				clipped = append(clipped, ring)
+			}
+		}
+		c.fill(clipped, style.Fill)
+	}
+	if style.Stroke != nil && style.StrokeWidth > 0 {
+		var outlines [][]r2.Point
+		for _, ring := range rings {
+			outlines = append(outlines, c.stroke(ring, true, style.StrokeWidth)...)
+		}
+		c.fill(outlines, style.Stroke)
+	}
+}
+
+// fill draws the given rings, whose coordinates must already be clipped,
+// with the given colour. Overlapping rings with the same orientation are
+// unioned, while those with opposite orientations, like holes, aren't
+// filled.
+func (c *Canvas) fill(rings [][]r2.Point, colour color.Color) {
+	bounds := r2.EmptyRect()
+	for _, ring := range rings {
+		for _, p := range ring {
+			bounds = bounds.AddPoint(p)
+		}
+	}
+	if bounds.IsEmpty() {
+		return
+	}
+	r := image.Rect(int(math.Floor(bounds.X.Lo)), int(math.Floor(bounds.Y.Lo)), int(math.Ceil(bounds.X.Hi)), int(math.Ceil(bounds.Y.Hi)))
+	r = r.Intersect(c.image.Bounds())
+	if r.Empty() {
+		return
+	}
+	// Rasterize only the area covered by the rings, rather than the
+	// whole canvas.
+	c.rasterizer.Reset(r.Dx(), r.Dy())
+	for _, ring := range rings {
+		for i, p := range ring {
+			x, y := float32(p.X-float64(r.Min.X)), float32(p.Y-float64(r.Min.Y))
+			if i == 0 {
+				c.rasterizer.MoveTo(x, y)
+			} else {
+				c.rasterizer.LineTo(x, y)
+			}
+		}
+		c.rasterizer.ClosePath()
+	}
+	c.rasterizer.Draw(c.image, r, image.NewUniform(colour), image.Point{})
+}
+
+// stroke returns rings that together cover a line of the given width
+// along the given points, with rounded joins and ends. All rings have
+// the same orientation, so overlaps are filled once.
+func (c *Canvas) stroke(points []r2.Point, closed bool, width float64) [][]r2.Point {
+	if closed && len(points) > 0 {
+		points = append(points[0:len(points):len(points)], points[0])
+	}
+	clip := c.clipRect(width)
+	half := width / 2
+	rings := make([][]r2.Point, 0, 2*len(points))
+	for i := 0; i+1 < len(points); i++ {
+		a, b, ok := clipSegment(points[i], points[i+1], clip)
+		if !ok {
+			continue
+		}
+		d := b.Sub(a)
+		if n := d.Norm(); n > 0 {
+			normal := d.Ortho().Mul(half / n)
+			rings = append(rings, []r2.Point{a.Add(normal), b.Add(normal), b.Sub(normal), a.Sub(normal)})
+		}
+		if width >= 2 {
+			rings = append(rings, circle(a, half), circle(b, half))
+		}
+	}
+	return rings
+}
+
+func (c *Canvas) clipRect(margin float64) r2.Rect {
+	size := c.image.Bounds().Size()
+	return r2.RectFromPoints(r2.Point{X: -clipMargin - margin, Y: -clipMargin - margin}, r2.Point{X: float64(size.X) + clipMargin + margin, Y: float64(size.Y) + clipMargin + margin})
+}
+
+// clipRing returns the given ring clipped to the canvas, using the
+// Sutherland-Hodgman algorithm.
+func (c *Canvas) clipRing(ring []r2.Point) []r2.Point {
+	clip := c.clipRect(0)
+	edges := []struct {
+		inside    func(p r2.Point) bool
+		intersect func(a, b r2.Point) r2.Point
+	}{
+		{func(p r2.Point) bool { return p.X >= clip.X.Lo }, func(a, b r2.Point) r2.Point { return intersectX(a, b, clip.X.Lo) }},
+		{func(p r2.Point) bool { return p.X <= clip.X.Hi }, func(a, b r2.Point) r2.Point { return intersectX(a, b, clip.X.Hi) }},
+		{func(p r2.Point) bool { return p.Y >= clip.Y.Lo }, func(a, b r2.Point) r2.Point { return intersectY(a, b, clip.Y.Lo) }},
+		{func(p r2.Point) bool { return p.Y <= clip.Y.Hi }, func(a, b r2.Point) r2.Point { return intersectY(a, b, clip.Y.Hi) }},
+	}
+	for _, edge := range edges {
+		if len(ring) == 0 {
+			break
+		}
+		clipped := make([]r2.Point, 0, len(ring))
+		previous := ring[len(ring)-1]
+		for _, p := range ring {
+			if edge.inside(p) {
+				if !edge.inside(previous) {
+					clipped = append(clipped, edge.intersect(previous, p))
+				}
+				clipped = append(clipped, p)
+			} else if edge.inside(previous) {
+				clipped = append(clipped, edge.intersect(previous, p))
+			}
+			previous = p
+		}
+		ring = clipped
+	}
+	return ring
+}
+
+func intersectX(a, b r2.Point, x float64) r2.Point {
+	return r2.Point{X: x, Y: a.Y + (b.Y-a.Y)*(x-a.X)/(b.X-a.X)}
+}
+
+func intersectY(a, b r2.Point, y float64) r2.Point {
+	return r2.Point{X: a.X + (b.X-a.X)*(y-a.Y)/(b.Y-a.Y), Y: y}
+}
+
+// clipSegment returns the part of the segment from a to b within the
+// given rectangle, using the Liang-Barsky algorithm, or false if it lies
+// entirely outside.
+func clipSegment(a, b r2.Point, clip r2.Rect) (r2.Point, r2.Point, bool) {
+	t0, t1 := 0.0, 1.0
+	d := b.Sub(a)
+	for _, edge := range [][2]float64{
+		{-d.X, a.X - clip.X.Lo},
+		{d.X, clip.X.Hi - a.X},
+		{-d.Y, a.Y - clip.Y.Lo},
+		{d.Y, clip.Y.Hi - a.Y},
+	} {
+		p, q := edge[0], edge[1]
+		if p == 0 {
+			if q < 0 {
+				return a, b, false
+			}
+			continue
+		}
+		t := q / p
+		if p < 0 {
+			if t > t1 {
+				return a, b, false
+			} else if t > t0 {
+				t0 = t
+			}
+		} else {
+			if t < t0 {
+				return a, b, false
+			} else if t < t1 {
+				t1 = t
+			}
+		}
+	}
+	return a.Add(d.Mul(t0)), a.Add(d.Mul(t1)), true
+}
+
+// circle returns a ring approximating a circle, with the same orientation
+// as the rings returned by stroke.
+func circle(center r2.Point, radius float64) []r2.Point {
+	n := 8
+	if radius > 4 {
+		n = 24
+	}
+	ring := make([]r2.Point, n)
+	for i := range ring {
+		angle := -2 * math.Pi * float64(i) / float64(n)
+		ring[i] = r2.Point{X: center.X + radius*math.Cos(angle), Y: center.Y + radius*math.Sin(angle)}
+	}
+	return ring
+}
+
+// ColourFromHexString returns the colour represented by a string like
+// #rrggbb, or #rrggbbaa, or false if the string isn't valid.
+func ColourFromHexString(s string) (color.RGBA, bool) {
+	var r, g, b, a uint8
+	a = 0xff
+	switch len(s) {
+	case 7:
+		if n, err := fmt.Sscanf(s, "#%02x%02x%02x", &r, &g, &b); err != nil || n != 3 {
+			return color.RGBA{}, false
+		}
+	case 9:
+		if n, err := fmt.Sscanf(s, "#%02x%02x%02x%02x", &r, &g, &b, &a); err != nil || n != 4 {
+			return color.RGBA{}, false
+		}
+	default:
+		return color.RGBA{}, false
+	}
+	// color.RGBA uses premultiplied alpha
+	premultiply := func(v uint8) uint8 { return uint8(uint16(v) * uint16(a) / 0xff) }
+	return color.RGBA{R: premultiply(r), G: premultiply(g), B: premultiply(b), A: a}, true
+}
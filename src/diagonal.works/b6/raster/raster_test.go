@@ -0,0 +1,164 @@
+package raster
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"math"
+	"testing"
+
+	"github.com/golang/geo/s2"
+)
+
+var (
+	white = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	red   = color.RGBA{R: 0xff, A: 0xff}
+	blue  = color.RGBA{B: 0xff, A: 0xff}
+)
+
+func newTestCanvas(t *testing.T) *Canvas {
+	t.Helper()
+	bounds := s2.RectFromLatLng(s2.LatLngFromDegrees(51.53, -0.13)).AddPoint(s2.LatLngFromDegrees(51.54, -0.12))
+	c, err := NewCanvas(bounds, 200, 100, 0, white)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func pixel(c *Canvas, x, y int) color.RGBA {
+	return c.image.RGBAAt(x, y)
+}
+
+func TestCanvasFitsBounds(t *testing.T) {
+	c := newTestCanvas(t)
+	// The bounds are taller than they are wide once projected, so
+	// they're fitted vertically, and centered horizontally.
+	top := c.ToPixel(s2.PointFromLatLng(s2.LatLngFromDegrees(51.54, -0.125)))
+	bottom := c.ToPixel(s2.PointFromLatLng(s2.LatLngFromDegrees(51.53, -0.125)))
+	if math.Abs(top.X-100) > 1e-6 || math.Abs(top.Y) > 1e-6 || math.Abs(bottom.Y-100) > 1e-6 {
+		t.Errorf("Expected bounds to span the canvas vertically, found %v and %v", top, bottom)
+	}
+	bounds := c.Bounds()
+	if !bounds.Contains(s2.RectFromLatLng(s2.LatLngFromDegrees(51.535, -0.13))) {
+		t.Errorf("Expected canvas bounds %v to contain the original bounds", bounds)
+	}
+	if zoom := c.Zoom(); zoom < 13 || zoom > 14 {
+		t.Errorf("Expected a zoom level between 13 and 14, found %f", zoom)
+	}
+}
+
+func TestDrawPolygonWithHole(t *testing.T) {
+	c := newTestCanvas(t)
+	ring := func(lat0, lng0, lat1, lng1 float64) *s2.Loop {
+		loop := s2.LoopFromPoints([]s2.Point{
+			s2.PointFromLatLng(s2.LatLngFromDegrees(lat0, lng0)),
+			s2.PointFromLatLng(s2.LatLngFromDegrees(lat0, lng1)),
+			s2.PointFromLatLng(s2.LatLngFromDegrees(lat1, lng1)),
+			s2.PointFromLatLng(s2.LatLngFromDegrees(lat1, lng0)),
+		})
+		loop.Normalize()
+		return loop
+	}
+	polygon := s2.PolygonFromLoops([]*s2.Loop{ring(51.531, -0.129, 51.539, -0.121), ring(51.534, -0.126, 51.536, -0.124)})
+	c.DrawPolygon(polygon, &Style{Fill: red})
+
+	if p := pixel(c, 100, 10); p != red {
+		t.Errorf("Expected polygon to be filled, found %v", p)
+	}
+	if p := pixel(c, 100, 50); p != white {
+		t.Errorf("Expected hole not to be filled, found %v", p)
+	}
+	if p := pixel(c, 2, 50); p != white {
+		t.Errorf("Expected area outside polygon not to be filled, found %v", p)
+	}
+}
+
+func TestDrawPolygonLargerThanCanvas(t *testing.T) {
+	c := newTestCanvas(t)
+	loop := s2.LoopFromPoints([]s2.Point{
+		s2.PointFromLatLng(s2.LatLngFromDegrees(40, -10)),
+		s2.PointFromLatLng(s2.LatLngFromDegrees(40, 10)),
+		s2.PointFromLatLng(s2.LatLngFromDegrees(60, 10)),
+		s2.PointFromLatLng(s2.LatLngFromDegrees(60, -10)),
+	})
+	c.DrawPolygon(s2.PolygonFromLoops([]*s2.Loop{loop}), &Style{Fill: blue})
+	for _, p := range [][2]int{{0, 0}, {199, 0}, {0, 99}, {199, 99}, {100, 50}} {
+		if found := pixel(c, p[0], p[1]); found != blue {
+			t.Errorf("Expected %v to be filled, found %v", p, found)
+		}
+	}
+}
+
+func TestDrawPolyline(t *testing.T) {
+	c := newTestCanvas(t)
+	line := s2.PolylineFromLatLngs([]s2.LatLng{
+		s2.LatLngFromDegrees(51.535, -1.0),
+		s2.LatLngFromDegrees(51.535, 1.0),
+	})
+	c.DrawPolyline(line, &Style{Stroke: red, StrokeWidth: 4})
+	if p := pixel(c, 100, 50); p != red {
+		t.Errorf("Expected line to be drawn, found %v", p)
+	}
+	if p := pixel(c, 100, 40); p != white {
+		t.Errorf("Expected pixels away from the line to be unchanged, found %v", p)
+	}
+}
+
+func TestDrawPoint(t *testing.T) {
+	c := newTestCanvas(t)
+	c.DrawPoint(s2.PointFromLatLng(s2.LatLngFromDegrees(51.535, -0.125)), &Style{Fill: red, Stroke: blue, StrokeWidth: 2, PointRadius: 10})
+	center := c.ToPixel(s2.PointFromLatLng(s2.LatLngFromDegrees(51.535, -0.125)))
+	x, y := int(center.X), int(center.Y)
+	if p := pixel(c, x, y); p != red {
+		t.Errorf("Expected point to be filled, found %v", p)
+	}
+	if p := pixel(c, x+10, y); p.B == 0 {
+		t.Errorf("Expected point to be outlined, found %v", p)
+	}
+	if p := pixel(c, x+15, y); p != white {
+		t.Errorf("Expected pixels away from point to be unchanged, found %v", p)
+	}
+}
+
+func TestEncodePNG(t *testing.T) {
+	c := newTestCanvas(t)
+	var buffer bytes.Buffer
+	if err := c.EncodePNG(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := png.Decode(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := decoded.Bounds().Size(); size.X != 200 || size.Y != 100 {
+		t.Errorf("Expected a 200x100 image, found %dx%d", size.X, size.Y)
+	}
+}
+
+func TestNewCanvasRejectsInvalidSizes(t *testing.T) {
+	bounds := s2.RectFromLatLng(s2.LatLngFromDegrees(51.53, -0.13)).AddPoint(s2.LatLngFromDegrees(51.54, -0.12))
+	for _, size := range [][3]int{{0, 100, 0}, {100, MaxSize + 1, 0}, {100, 100, 50}} {
+		if _, err := NewCanvas(bounds, size[0], size[1], size[2], nil); err == nil {
+			t.Errorf("Expected an error for size %v", size)
+		}
+	}
+}
+
+func TestColourFromHexString(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected color.RGBA
+		ok       bool
+	}{
+		{"#ff8000", color.RGBA{R: 0xff, G: 0x80, A: 0xff}, true},
+		{"#ff000080", color.RGBA{R: 0x80, A: 0x80}, true},
+		{"ff8000", color.RGBA{}, false},
+		{"#zz8000", color.RGBA{}, false},
+	}
+	for _, test := range tests {
+		if c, ok := ColourFromHexString(test.s); ok != test.ok || c != test.expected {
+			t.Errorf("Expected %v, %v for %q, found %v, %v", test.expected, test.ok, test.s, c, ok)
+		}
+	}
+}
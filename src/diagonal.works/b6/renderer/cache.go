@@ -0,0 +1,463 @@
+package renderer
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/ingest"
+	"diagonal.works/b6/search"
+
+	"github.com/golang/geo/s2"
+)
+
+type TileCacheOptions struct {
+	// The approximate maximum memory used by cached tiles, in bytes.
+	MaxBytes int
+	// If set, tiles evicted from memory are written to this directory,
+	// rather than discarded. Its contents are only valid for the lifetime
+	// of the process, and are removed when the cache is created.
+	Directory string
+	// The maximum size of tiles written to Directory, in bytes.
+	MaxDiskBytes int
+}
+
+type TileCacheStats struct {
+	Hits     int
+	DiskHits int
+	Misses   int
+	// Tiles removed because the features they depend on changed
+	Invalidations int
+	Evictions     int
+	Entries       int
+	Bytes         int
+	DiskEntries   int
+	DiskBytes     int
+}
+
+// TileCacheKey identifies a rendered tile. Renderer distinguishes
+// different renderers sharing a cache, while Q and V are the renderer's
+// arguments.
+type TileCacheKey struct {
+	Renderer string
+	Tile     b6.Tile
+	Q        string
+	V        string
+	World    b6.FeatureID
+	// True if the tile's content may depend on features outside its
+	// bounds, as with tiles rendered from query expressions, in which case
+	// it's invalidated by any change to the world.
+	Unbounded bool
+}
+
+func (k TileCacheKey) filename() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%q\x00%q\x00%s", k.Renderer, k.Tile, k.Q, k.V, k.World)
+	return hex.EncodeToString(h.Sum(nil)) + ".mvt"
+}
+
+// CachedTile is an encoded tile, together with an ETag that changes
+// whenever its content does.
+type CachedTile struct {
+	Data []byte
+	ETag string
+}
+
+func newCachedTile(data []byte) *CachedTile {
+	hash := sha256.Sum256(data)
+	return &CachedTile{Data: data, ETag: "\"" + hex.EncodeToString(hash[0:12]) + "\""}
+}
+
+type tileCacheEntry struct {
+	key      TileCacheKey
+	tile     *CachedTile // nil when the tile is only stored on disk
+	etag     string
+	bytes    int
+	covering s2.CellUnion
+	lru      *list.Element
+	disk     *list.Element
+}
+
+// tileCacheFingerprint summarises the state of a modified feature, allowing
+// changes to it to be detected.
+type tileCacheFingerprint struct {
+	hash     uint64
+	covering s2.CellUnion
+	// False for features without a location, like collections, changes to
+	// which could affect any tile.
+	located bool
+}
+
+type tileCacheWorld struct {
+	// Held while the world's modified features are fingerprinted, which
+	// happens without holding the cache's lock, as it can be slow for
+	// large worlds.
+	fingerprinting sync.Mutex
+	// The version of the world last fingerprinted, or 0 if it hasn't been.
+	version      uint64
+	fingerprints map[b6.FeatureID]tileCacheFingerprint
+	entries      map[TileCacheKey]*tileCacheEntry
+}
+
+// TileCache is an LRU cache for encoded tiles. Tiles are cached against
+// the version of the world from which they were rendered. When a world
+// changes, only those tiles whose bounds intersect modified features,
+// either before or after the change, are invalidated, on the assumption
+// that a tile's content depends only on the features within it. Changes
+// to features without a location, like collections, invalidate all tiles
+// rendered from the world, as does any change for tiles with keys marked
+// Unbounded. Worlds are forgotten once all their tiles have been evicted,
+// so those that are deleted, or renamed, aren't retained.
+type TileCache struct {
+	options TileCacheOptions
+
+	lock    sync.Mutex
+	entries map[TileCacheKey]*tileCacheEntry
+	lru     *list.List
+	disk    *list.List
+	worlds  map[b6.FeatureID]*tileCacheWorld
+	stats   TileCacheStats
+}
+
+const tileCacheEntryOverheadBytes = 256
+
+// The coverer used for both tile bounds, and modified features. Coarse
+// coverings may invalidate more tiles than necessary, but are cheap to
+// compare.
+var tileCacheCoverer = s2.RegionCoverer{MaxLevel: search.MaxIndexedCellLevel, MaxCells: 8}
+
+func NewTileCache(options TileCacheOptions) (*TileCache, error) {
+	if options.Directory != "" {
+		if err := os.RemoveAll(options.Directory); err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(options.Directory, 0755); err != nil {
+			return nil, err
+		}
+	}
+	return &TileCache{
+		options: options,
+		entries: make(map[TileCacheKey]*tileCacheEntry),
+		lru:     list.New(),
+		disk:    list.New(),
+		worlds:  make(map[b6.FeatureID]*tileCacheWorld),
+	}, nil
+}
+
+// Get returns the cached tile for the given key, if it's still valid for
+// the current version of the world w.
+func (c *TileCache) Get(key TileCacheKey, w ingest.MutableWorld) (*CachedTile, bool) {
+	version := w.Version()
+	c.update(key.World, w, version)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	e, ok := c.entries[key]
+	if world := c.worlds[key.World]; !ok || world == nil || world.version != version {
+		c.stats.Misses++
+		return nil, false
+	}
+	if e.tile != nil {
+		c.lru.MoveToFront(e.lru)
+		c.stats.Hits++
+		return e.tile, true
+	}
+	data, err := os.ReadFile(filepath.Join(c.options.Directory, key.filename()))
+	if err != nil {
+		log.Printf("Failed to read cached tile: %s", err)
+		c.remove(e)
+		c.stats.Misses++
+		return nil, false
+	}
+	// Tiles read from disk are moved back into memory
+	c.removeFromDisk(e)
+	e.tile = &CachedTile{Data: data, ETag: e.etag}
+	e.lru = c.lru.PushFront(e)
+	c.stats.Entries++
+	c.stats.Bytes += e.bytes
+	c.evict()
+	c.stats.DiskHits++
+	return e.tile, true
+}
+
+// Add caches the given encoded tile, which was rendered from the given
+// version of the world w, returning it with its ETag. Tiles rendered from
+// a version that's no longer current aren't cached.
+func (c *TileCache) Add(key TileCacheKey, w ingest.MutableWorld, version uint64, data []byte) *CachedTile {
+	tile := newCachedTile(data)
+	bytes := len(data) + len(key.Q) + len(key.V) + tileCacheEntryOverheadBytes
+	if bytes > c.options.MaxBytes {
+		return tile
+	}
+
+	c.update(key.World, w, w.Version())
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	world, ok := c.worlds[key.World]
+	if !ok || world.version != version {
+		return tile
+	}
+	if e, ok := c.entries[key]; ok {
+		// Another request rendered the same tile concurrently
+		c.remove(e)
+	}
+	e := &tileCacheEntry{
+		key:      key,
+		tile:     tile,
+		etag:     tile.ETag,
+		bytes:    bytes,
+		covering: tileCacheCoverer.Covering(key.Tile.RectBound()),
+	}
+	e.lru = c.lru.PushFront(e)
+	c.entries[key] = e
+	world.entries[key] = e
+	c.stats.Entries++
+	c.stats.Bytes += bytes
+	c.evict()
+	return tile
+}
+
+// evict moves the least recently used tiles to disk, if a directory is
+// configured, or otherwise discards them, until the cache is within its
+// memory limit.
+func (c *TileCache) evict() {
+	for c.stats.Bytes > c.options.MaxBytes && c.lru.Len() > 0 {
+		e := c.lru.Back().Value.(*tileCacheEntry)
+		c.removeFromMemory(e)
+		c.stats.Evictions++
+		if c.options.Directory == "" || e.bytes > c.options.MaxDiskBytes {
+			c.forget(e)
+			c.forgetWorldIfEmpty(e.key.World)
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(c.options.Directory, e.key.filename()), e.tile.Data, 0644); err != nil {
+			log.Printf("Failed to write cached tile: %s", err)
+			c.forget(e)
+			c.forgetWorldIfEmpty(e.key.World)
+			continue
+		}
+		e.tile = nil
+		e.disk = c.disk.PushFront(e)
+		c.stats.DiskEntries++
+		c.stats.DiskBytes += e.bytes
+		for c.stats.DiskBytes > c.options.MaxDiskBytes && c.disk.Len() > 0 {
+			evicted := c.disk.Back().Value.(*tileCacheEntry)
+			c.remove(evicted)
+			c.forgetWorldIfEmpty(evicted.key.World)
+		}
+	}
+}
+
+func (c *TileCache) removeFromMemory(e *tileCacheEntry) {
+	if e.lru != nil {
+		c.lru.Remove(e.lru)
+		e.lru = nil
+		c.stats.Entries--
+		c.stats.Bytes -= e.bytes
+	}
+}
+
+func (c *TileCache) removeFromDisk(e *tileCacheEntry) {
+	if e.disk != nil {
+		c.disk.Remove(e.disk)
+		e.disk = nil
+		c.stats.DiskEntries--
+		c.stats.DiskBytes -= e.bytes
+		os.Remove(filepath.Join(c.options.Directory, e.key.filename()))
+	}
+}
+
+func (c *TileCache) forget(e *tileCacheEntry) {
+	delete(c.entries, e.key)
+	if world, ok := c.worlds[e.key.World]; ok {
+		delete(world.entries, e.key)
+	}
+}
+
+func (c *TileCache) remove(e *tileCacheEntry) {
+	c.removeFromMemory(e)
+	c.removeFromDisk(e)
+	c.forget(e)
+}
+
+// forgetWorldIfEmpty removes the world with the given ID once its last
+// tile has been evicted. Worlds aren't removed when their tiles are
+// invalidated, as they're likely to be requested again.
+func (c *TileCache) forgetWorldIfEmpty(id b6.FeatureID) {
+	if world, ok := c.worlds[id]; ok && len(world.entries) == 0 {
+		delete(c.worlds, id)
+	}
+}
+
+// update brings the cache's view of the world with the given ID up to
+// date with the given version, invalidating tiles affected by changes since
+// it was last seen. The world's modified features are fingerprinted without
+// holding lock, which callers mustn't hold, so that requests for other
+// worlds aren't held up by changes to a large one.
+func (c *TileCache) update(id b6.FeatureID, w ingest.MutableWorld, version uint64) {
+	c.lock.Lock()
+	world, ok := c.worlds[id]
+	if !ok {
+		world = &tileCacheWorld{entries: make(map[TileCacheKey]*tileCacheEntry)}
+		c.worlds[id] = world
+	}
+	current := world.version == version
+	c.lock.Unlock()
+	if current {
+		return
+	}
+
+	world.fingerprinting.Lock()
+	defer world.fingerprinting.Unlock()
+	c.lock.Lock()
+	current = world.version == version
+	c.lock.Unlock()
+	if current {
+		// Another request fingerprinted the world while we waited
+		return
+	}
+	fingerprints := fingerprintModifiedFeatures(w)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.worlds[id] != world {
+		// The world was forgotten while it was fingerprinted, and so had
+		// no tiles to invalidate.
+		return
+	}
+	var changed s2.CellUnion
+	all := false
+	diff := func(from, to map[b6.FeatureID]tileCacheFingerprint) {
+		for id, f := range from {
+			if g, ok := to[id]; !ok || f.hash != g.hash {
+				if !f.located {
+					all = true
+				}
+				changed = append(changed, f.covering...)
+			}
+		}
+	}
+	diff(world.fingerprints, fingerprints)
+	diff(fingerprints, world.fingerprints)
+	changed.Normalize()
+	for _, e := range world.entries {
+		if all || e.key.Unbounded || changed.Intersects(e.covering) {
+			c.remove(e)
+			c.stats.Invalidations++
+		}
+	}
+	world.version = version
+	world.fingerprints = fingerprints
+}
+
+func (c *TileCache) Clear() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, e := range c.entries {
+		c.remove(e)
+	}
+	c.worlds = make(map[b6.FeatureID]*tileCacheWorld)
+}
+
+func (c *TileCache) Stats() TileCacheStats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.stats
+}
+
+// fingerprintModifiedFeatures returns fingerprints for the features that
+// have been added to the world, or have had their tags modified, together
+// with the paths and areas that reference them, whose geometry changes
+// with the features they reference.
+func fingerprintModifiedFeatures(w ingest.MutableWorld) map[b6.FeatureID]tileCacheFingerprint {
+	ids := make(map[b6.FeatureID]struct{})
+	options := b6.EachFeatureOptions{Goroutines: 1}
+	w.EachModifiedFeature(func(f b6.Feature, _ int) error {
+		ids[f.FeatureID()] = struct{}{}
+		return nil
+	}, &options)
+	w.EachModifiedTag(func(t ingest.ModifiedTag, _ int) error {
+		ids[t.ID] = struct{}{}
+		return nil
+	}, &options)
+
+	fingerprints := make(map[b6.FeatureID]tileCacheFingerprint, len(ids))
+	var add func(f b6.Feature)
+	add = func(f b6.Feature) {
+		if _, ok := fingerprints[f.FeatureID()]; ok {
+			return
+		}
+		fingerprints[f.FeatureID()] = fingerprintFeature(f)
+		switch f.FeatureID().Type {
+		case b6.FeatureTypePoint, b6.FeatureTypePath:
+			references := w.FindReferences(f.FeatureID(), b6.FeatureTypePath, b6.FeatureTypeArea)
+			for references.Next() {
+				add(references.Feature())
+			}
+		}
+	}
+	for id := range ids {
+		if f := w.FindFeatureByID(id); f != nil {
+			add(f)
+		}
+	}
+	return fingerprints
+}
+
+func fingerprintFeature(f b6.Feature) tileCacheFingerprint {
+	h := fnv.New64a()
+	for _, tag := range f.AllTags() {
+		io.WriteString(h, tag.String())
+		h.Write([]byte{0})
+	}
+	point := func(p s2.Point) {
+		var b [24]byte
+		binary.LittleEndian.PutUint64(b[0:], math.Float64bits(p.X))
+		binary.LittleEndian.PutUint64(b[8:], math.Float64bits(p.Y))
+		binary.LittleEndian.PutUint64(b[16:], math.Float64bits(p.Z))
+		h.Write(b[0:])
+	}
+	var fingerprint tileCacheFingerprint
+	switch f.FeatureID().Type {
+	case b6.FeatureTypeArea:
+		a := f.(b6.AreaFeature)
+		for i := 0; i < a.Len(); i++ {
+			for _, loop := range a.Polygon(i).Loops() {
+				for _, v := range loop.Vertices() {
+					point(v)
+				}
+			}
+		}
+		fingerprint.covering = b6.Covering(a, tileCacheCoverer)
+		fingerprint.located = true
+	case b6.FeatureTypePoint, b6.FeatureTypePath:
+		p := f.(b6.PhysicalFeature)
+		if p.GeometryType() == b6.GeometryTypePoint {
+			point(p.Point())
+		}
+		for i := 0; i < p.GeometryLen(); i++ {
+			point(p.PointAt(i))
+		}
+		fingerprint.covering = b6.Covering(p, tileCacheCoverer)
+		fingerprint.located = true
+	case b6.FeatureTypeCollection:
+		i := f.(b6.CollectionFeature).BeginUntyped()
+		for {
+			if ok, err := i.Next(); err != nil || !ok {
+				break
+			}
+			fmt.Fprintf(h, "%v\x00%v\x00", i.Key(), i.Value())
+		}
+	}
+	fingerprint.hash = h.Sum64()
+	return fingerprint
+}
@@ -0,0 +1,259 @@
+package renderer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/ingest"
+	"diagonal.works/b6/test/camden"
+
+	"github.com/golang/geo/s2"
+)
+
+func TestTileCacheInvalidatesOnlyTilesWithModifiedFeatures(t *testing.T) {
+	worlds := &ingest.MutableWorlds{Base: camden.BuildGranarySquareForTests(t)}
+	w := worlds.FindOrCreateWorld(ingest.DefaultWorldFeatureID)
+	cache, err := NewTileCache(TileCacheOptions{MaxBytes: 1024 * 1024})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	projection := b6.NewTileMercatorProjection(16)
+	lighterman := projection.TileFromLatLng(s2.LatLngFromDegrees(51.53531, -0.12434))
+	elsewhere := projection.TileFromLatLng(s2.LatLngFromDegrees(51.52, -0.10))
+	keys := []TileCacheKey{
+		{Renderer: "base", Tile: lighterman, World: ingest.DefaultWorldFeatureID},
+		{Renderer: "base", Tile: elsewhere, World: ingest.DefaultWorldFeatureID},
+	}
+	for _, key := range keys {
+		if _, ok := cache.Get(key, w); ok {
+			t.Fatalf("Expected no cached tile for %s", key.Tile)
+		}
+		cache.Add(key, w, w.Version(), []byte(key.Tile.String()))
+		if cached, ok := cache.Get(key, w); !ok || string(cached.Data) != key.Tile.String() {
+			t.Fatalf("Expected a cached tile for %s", key.Tile)
+		}
+	}
+
+	id := ingest.AreaIDFromOSMWayID(camden.LightermanWay).FeatureID()
+	if err := w.AddTag(id, b6.Tag{Key: "b6:colour", Value: b6.NewStringExpression("#ff0000")}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.Get(keys[0], w); ok {
+		t.Error("Expected tile containing modified feature to be invalidated")
+	}
+	if _, ok := cache.Get(keys[1], w); !ok {
+		t.Error("Expected tile without modified features to remain cached")
+	}
+
+	collection := &ingest.CollectionFeature{
+		CollectionID: b6.CollectionID{Namespace: b6.NamespacePrivate, Value: 1},
+		Keys:         []interface{}{0},
+		Values:       []interface{}{id},
+	}
+	if err := w.AddFeature(collection); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.Get(keys[1], w); ok {
+		t.Error("Expected modified collection to invalidate all tiles")
+	}
+	if stats := cache.Stats(); stats.Invalidations != 2 {
+		t.Errorf("Expected 2 invalidations, found %d", stats.Invalidations)
+	}
+}
+
+func TestTileCacheIgnoresTilesFromOldVersions(t *testing.T) {
+	worlds := &ingest.MutableWorlds{Base: camden.BuildGranarySquareForTests(t)}
+	w := worlds.FindOrCreateWorld(ingest.DefaultWorldFeatureID)
+	cache, err := NewTileCache(TileCacheOptions{MaxBytes: 1024 * 1024})
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := TileCacheKey{Renderer: "base", Tile: b6.Tile{X: 32744, Y: 21784, Z: 16}, World: ingest.DefaultWorldFeatureID}
+	version := w.Version()
+	id := ingest.AreaIDFromOSMWayID(camden.LightermanWay).FeatureID()
+	if err := w.AddTag(id, b6.Tag{Key: "#building", Value: b6.NewStringExpression("office")}); err != nil {
+		t.Fatal(err)
+	}
+	cache.Add(key, w, version, []byte("stale"))
+	if _, ok := cache.Get(key, w); ok {
+		t.Error("Expected tile rendered from an old version not to be cached")
+	}
+}
+
+func TestTileCacheMovesEvictedTilesToDisk(t *testing.T) {
+	worlds := ingest.NewReadOnlyWorlds(camden.BuildGranarySquareForTests(t))
+	w := worlds.FindOrCreateWorld(ingest.DefaultWorldFeatureID)
+	data := make([]byte, 1024)
+	cache, err := NewTileCache(TileCacheOptions{
+		MaxBytes:     len(data) + tileCacheEntryOverheadBytes,
+		Directory:    t.TempDir(),
+		MaxDiskBytes: 1024 * 1024,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := TileCacheKey{Renderer: "base", Tile: b6.Tile{X: 0, Y: 0, Z: 1}}
+	second := TileCacheKey{Renderer: "base", Tile: b6.Tile{X: 1, Y: 0, Z: 1}}
+	added := cache.Add(first, w, w.Version(), data)
+	cache.Add(second, w, w.Version(), data)
+	if stats := cache.Stats(); stats.Entries != 1 || stats.DiskEntries != 1 {
+		t.Fatalf("Expected one tile in memory and one on disk, found %+v", stats)
+	}
+	if cached, ok := cache.Get(first, w); !ok || cached.ETag != added.ETag || len(cached.Data) != len(data) {
+		t.Fatal("Expected to find evicted tile on disk")
+	}
+	if stats := cache.Stats(); stats.DiskHits != 1 || stats.Entries != 1 || stats.DiskEntries != 1 {
+		t.Errorf("Expected tiles to swap between memory and disk, found %+v", stats)
+	}
+}
+
+func TestTileHandlerWithCacheReturnsNotModified(t *testing.T) {
+	worlds := &ingest.MutableWorlds{Base: camden.BuildGranarySquareForTests(t)}
+	cache, err := NewTileCache(TileCacheOptions{MaxBytes: 1024 * 1024})
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := &TileHandler{
+		Renderer: &BasemapRenderer{RenderRules: BasemapRenderRules, Worlds: worlds},
+		Cache:    cache,
+		Name:     "base",
+		Worlds:   worlds,
+	}
+	tile := b6.NewTileMercatorProjection(16).TileFromLatLng(s2.LatLngFromDegrees(51.53531, -0.12434))
+	path := "/tiles/base/" + tile.String() + ".mvt"
+
+	get := func(etag string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest("GET", path, nil)
+		if etag != "" {
+			request.Header.Set("If-None-Match", etag)
+		}
+		response := httptest.NewRecorder()
+		handler.ServeHTTP(response, request)
+		return response
+	}
+
+	response := get("")
+	etag := response.Header().Get("ETag")
+	if response.Code != http.StatusOK || etag == "" {
+		t.Fatalf("Expected a tile with an ETag, found %d %q", response.Code, etag)
+	}
+	if cc := response.Header().Get("Cache-Control"); cc != "no-cache" {
+		t.Errorf("Expected no-cache for a mutable world, found %q", cc)
+	}
+	if response := get(etag); response.Code != http.StatusNotModified {
+		t.Errorf("Expected %d, found %d", http.StatusNotModified, response.Code)
+	}
+
+	w := worlds.FindOrCreateWorld(ingest.DefaultWorldFeatureID)
+	id := ingest.AreaIDFromOSMWayID(camden.LightermanWay).FeatureID()
+	if err := w.AddTag(id, b6.Tag{Key: "b6:colour", Value: b6.NewStringExpression("#ff0000")}); err != nil {
+		t.Fatal(err)
+	}
+	response = get(etag)
+	if response.Code != http.StatusOK || response.Header().Get("ETag") == etag {
+		t.Errorf("Expected a new tile with a different ETag, found %d", response.Code)
+	}
+}
+
+func TestTileCacheInvalidatesAllUnboundedTiles(t *testing.T) {
+	worlds := &ingest.MutableWorlds{Base: camden.BuildGranarySquareForTests(t)}
+	w := worlds.FindOrCreateWorld(ingest.DefaultWorldFeatureID)
+	cache, err := NewTileCache(TileCacheOptions{MaxBytes: 1024 * 1024})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	elsewhere := b6.NewTileMercatorProjection(16).TileFromLatLng(s2.LatLngFromDegrees(51.52, -0.10))
+	base := TileCacheKey{Renderer: "base", Tile: elsewhere, World: ingest.DefaultWorldFeatureID}
+	query := TileCacheKey{Renderer: "query", Tile: elsewhere, Q: "all-features", World: ingest.DefaultWorldFeatureID, Unbounded: true}
+	for _, key := range []TileCacheKey{base, query} {
+		cache.Add(key, w, w.Version(), []byte(key.Renderer))
+	}
+
+	id := ingest.AreaIDFromOSMWayID(camden.LightermanWay).FeatureID()
+	if err := w.AddTag(id, b6.Tag{Key: "b6:colour", Value: b6.NewStringExpression("#ff0000")}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.Get(query, w); ok {
+		t.Error("Expected unbounded tile to be invalidated by a change outside it")
+	}
+	if _, ok := cache.Get(base, w); !ok {
+		t.Error("Expected tile without modified features to remain cached")
+	}
+}
+
+// blockingWorld blocks iteration over its modified features until
+// unblocked, simulating a world with many modifications.
+type blockingWorld struct {
+	ingest.MutableWorld
+	started chan struct{}
+	unblock chan struct{}
+}
+
+func (b *blockingWorld) EachModifiedFeature(each func(f b6.Feature, goroutine int) error, options *b6.EachFeatureOptions) error {
+	close(b.started)
+	<-b.unblock
+	return b.MutableWorld.EachModifiedFeature(each, options)
+}
+
+func TestTileCacheDoesntBlockOtherWorldsWhileFingerprinting(t *testing.T) {
+	worlds := &ingest.MutableWorlds{Base: camden.BuildGranarySquareForTests(t)}
+	world1 := b6.FeatureID{Type: b6.FeatureTypeCollection, Namespace: ingest.DefaultWorldFeatureID.Namespace, Value: 1}
+	blocking := &blockingWorld{
+		MutableWorld: worlds.FindOrCreateWorld(world1),
+		started:      make(chan struct{}),
+		unblock:      make(chan struct{}),
+	}
+	other := worlds.FindOrCreateWorld(ingest.DefaultWorldFeatureID)
+	cache, err := NewTileCache(TileCacheOptions{MaxBytes: 1024 * 1024})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tile := b6.Tile{X: 32744, Y: 21784, Z: 16}
+	done := make(chan struct{})
+	go func() {
+		cache.Get(TileCacheKey{Renderer: "base", Tile: tile, World: world1}, blocking)
+		close(done)
+	}()
+	<-blocking.started
+
+	key := TileCacheKey{Renderer: "base", Tile: tile, World: ingest.DefaultWorldFeatureID}
+	added := make(chan struct{})
+	go func() {
+		cache.Add(key, other, other.Version(), []byte("base"))
+		cache.Get(key, other)
+		close(added)
+	}()
+	select {
+	case <-added:
+	case <-time.After(10 * time.Second):
+		t.Error("Expected other worlds to be usable while one is fingerprinted")
+	}
+	close(blocking.unblock)
+	<-done
+}
+
+func TestTileCacheForgetsWorldsWithoutTiles(t *testing.T) {
+	worlds := &ingest.MutableWorlds{Base: camden.BuildGranarySquareForTests(t)}
+	data := make([]byte, 1024)
+	cache, err := NewTileCache(TileCacheOptions{MaxBytes: len(data) + tileCacheEntryOverheadBytes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tile := b6.Tile{X: 32744, Y: 21784, Z: 16}
+	for i := 1; i <= 10; i++ {
+		id := b6.FeatureID{Type: b6.FeatureTypeCollection, Namespace: ingest.DefaultWorldFeatureID.Namespace, Value: uint64(i)}
+		w := worlds.FindOrCreateWorld(id)
+		cache.Add(TileCacheKey{Renderer: "base", Tile: tile, World: id}, w, w.Version(), data)
+	}
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	if len(cache.worlds) != 1 {
+		t.Errorf("Expected only the world with a cached tile to be retained, found %d", len(cache.worlds))
+	}
+}
@@ -1,6 +1,7 @@
 package renderer
 
 import (
+	"context"
 	"fmt"
 
 	"diagonal.works/b6"
@@ -19,7 +20,7 @@ func NewCollectionRenderer(rules RenderRules, worlds ingest.Worlds) *CollectionR
 	}
 }
 
-func (r *CollectionRenderer) Render(tile b6.Tile, args *TileArgs) (*Tile, error) {
+func (r *CollectionRenderer) Render(ctx context.Context, tile b6.Tile, args *TileArgs) (*Tile, error) {
 	w := r.worlds.FindOrCreateWorld(args.R)
 
 	id := b6.FeatureIDFromString(args.Q)
@@ -51,7 +52,7 @@ func (r *CollectionRenderer) Render(tile b6.Tile, args *TileArgs) (*Tile, error)
 		for id := range features {
 			if f := w.FindFeatureByID(id); f != nil {
 				if bounds.Matches(f, w) {
-					tags = r.rules.AddTags(f, tags[0:0])
+					tags = r.rules.AddTags(f, w, tags[0:0])
 					rendered = FillFeaturesFromFeature(f, tags, rendered, &RenderRule{Label: true}, w)
 				}
 			}
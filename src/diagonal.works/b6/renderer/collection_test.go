@@ -1,6 +1,7 @@
 package renderer
 
 import (
+	"context"
 	"testing"
 
 	"diagonal.works/b6"
@@ -28,7 +29,7 @@ func TestCollectionWithBoundaries(t *testing.T) {
 	r := NewCollectionRenderer(BasemapRenderRules, worlds)
 	args := &TileArgs{Q: collection.FeatureID().String()}
 	projection := b6.NewTileMercatorProjection(16)
-	tile, err := r.Render(projection.TileFromLatLng(s2.LatLngFromDegrees(51.535268, -0.124603)), args)
+	tile, err := r.Render(context.Background(), projection.TileFromLatLng(s2.LatLngFromDegrees(51.535268, -0.124603)), args)
 	if err != nil {
 		t.Fatalf("Expected no error, found: %s", err)
 	}
@@ -62,7 +63,7 @@ func TestCollectionWithFeatureKeysAndValues(t *testing.T) {
 	r := NewCollectionRenderer(BasemapRenderRules, worlds)
 	args := &TileArgs{Q: collection.FeatureID().String()}
 	projection := b6.NewTileMercatorProjection(16)
-	tile, err := r.Render(projection.TileFromLatLng(s2.LatLngFromDegrees(51.535268, -0.124603)), args)
+	tile, err := r.Render(context.Background(), projection.TileFromLatLng(s2.LatLngFromDegrees(51.535268, -0.124603)), args)
 	if err != nil {
 		t.Fatalf("Expected no error, found: %s", err)
 	}
@@ -41,9 +41,9 @@ func EncodeTile(location b6.Tile, content *Tile) *pb.TileProto {
 		for _, feature := range layer.Features {
 			switch g := feature.Geometry.(type) {
 			case *Polygon:
-				simplifyAndEncodePolygon(g.ToS2Polygon(), encoder, projection)
+				simplifyAndEncodePolygon(g.ToS2Polygon(), feature.Simplify, encoder, projection)
 			case *LineString:
-				simplifyAndEncodeLineString(g.ToS2Polyline(), encoder, projection)
+				simplifyAndEncodeLineString(g.ToS2Polyline(), feature.Simplify, encoder, projection)
 			case *Point:
 				encodePoint(g.ToS2Point(), encoder, projection)
 			default:
@@ -74,12 +74,14 @@ func newBackgroundLayer() *pb.TileProto_Layer {
 	return e.Layer()
 }
 
-func simplifyAndEncodePolygon(polygon *s2.Polygon, e *Encoder, projection *b6.TileMercatorProjection) {
+func simplifyAndEncodePolygon(polygon *s2.Polygon, tolerance float64, e *Encoder, projection *b6.TileMercatorProjection) {
 	feature := e.StartFeature()
 	feature.Type = pb.TileProto_POLYGON.Enum()
 	for _, loop := range polygon.Loops() {
 		points := projectLoop(loop, projection)
-		if len(points) > 1000 {
+		if tolerance > 0 {
+			points = Simplify(points, tolerance)
+		} else if len(points) > 1000 {
 			points = Simplify(points, 5.0)
 		}
 		if len(points) > 1 {
@@ -112,14 +114,21 @@ func projectLoop(loop *s2.Loop, projection *b6.TileMercatorProjection) []r2.Poin
 	return points
 }
 
-func simplifyAndEncodeLineString(line *s2.Polyline, e *Encoder, projection *b6.TileMercatorProjection) {
+func simplifyAndEncodeLineString(line *s2.Polyline, tolerance float64, e *Encoder, projection *b6.TileMercatorProjection) {
 	tf := e.StartFeature()
 	tf.Type = pb.TileProto_LINESTRING.Enum()
+	points := make([]r2.Point, len(*line))
+	for i, p := range *line {
+		points[i] = projection.Project(p)
+	}
+	if tolerance > 0 {
+		points = Simplify(points, tolerance)
+	}
 	e.MoveTo(1)
-	e.Point(projection.Project((*line)[0]))
-	e.LineTo(len(*line) - 1)
-	for i := 1; i < len(*line); i++ {
-		e.Point(projection.Project((*line)[i]))
+	e.Point(points[0])
+	e.LineTo(len(points) - 1)
+	for i := 1; i < len(points); i++ {
+		e.Point(points[i])
 	}
 }
 
@@ -49,6 +49,9 @@ type Feature struct {
 	Geometry Geometry
 	ID       uint64
 	Tags     map[string]string
+	// The tolerance, in tile units, with which to simplify the feature's
+	// geometry when encoded, or 0 for the default.
+	Simplify float64
 }
 
 func NewFeature(g Geometry) *Feature {
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"diagonal.works/b6"
+	"diagonal.works/b6/ingest"
 
 	"github.com/golang/geo/s2"
 	"google.golang.org/protobuf/proto"
@@ -20,6 +21,16 @@ type TileArgs struct {
 
 type TileHandler struct {
 	Renderer Renderer
+	// If set, encoded tiles are cached, using Name to distinguish them
+	// from those of other renderers, and Worlds to find the version of
+	// the world from which they're rendered.
+	Cache  *TileCache
+	Name   string
+	Worlds ingest.Worlds
+	// If true, tiles may depend on features outside their bounds, as with
+	// those rendered from query expressions, so cached tiles are
+	// invalidated by any change to the world.
+	Unbounded bool
 }
 
 func (h *TileHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -35,7 +46,11 @@ func (h *TileHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 	query := r.URL.Query()
 	args := TileArgs{Q: query.Get("q"), V: query.Get("v"), R: b6.FeatureIDFromString(query.Get("r"))}
-	rendered, err := h.Renderer.Render(tile, &args)
+	if h.Cache != nil && query.Get("o") != "d" {
+		h.serveCached(tile, &args, w, r)
+		return
+	}
+	rendered, err := h.Renderer.Render(r.Context(), tile, &args)
 	if err != nil {
 		log.Printf("Failed to render tile: %v", err)
 		http.Error(w, "Failed to render tile", http.StatusInternalServerError)
@@ -75,13 +90,59 @@ func outputPlainTextTile(tile b6.Tile, rendered *Tile, w http.ResponseWriter, r
 	w.Write([]byte(b.String()))
 }
 
+func (h *TileHandler) serveCached(tile b6.Tile, args *TileArgs, w http.ResponseWriter, r *http.Request) {
+	root := args.R
+	if !root.IsValid() {
+		root = ingest.DefaultWorldFeatureID
+	}
+	// Requesting tiles shouldn't create the world they're rendered from.
+	world := h.Worlds.FindWorld(root)
+	key := TileCacheKey{Renderer: h.Name, Tile: tile, Q: args.Q, V: args.V, World: root, Unbounded: h.Unbounded}
+	cached, ok := h.Cache.Get(key, world)
+	if !ok {
+		version := world.Version()
+		rendered, err := h.Renderer.Render(r.Context(), tile, args)
+		if err != nil {
+			log.Printf("Failed to render tile: %v", err)
+			http.Error(w, "Failed to render tile", http.StatusInternalServerError)
+			return
+		}
+		marshaled, err := proto.Marshal(EncodeTile(tile, rendered))
+		if err != nil {
+			log.Printf("Failed to marshal tile: %v", err)
+			http.Error(w, "Failed to render tile", http.StatusInternalServerError)
+			return
+		}
+		cached = h.Cache.Add(key, world, version, marshaled)
+	}
+	w.Header().Set("ETag", cached.ETag)
+	if _, ok := world.(ingest.ReadOnlyWorld); ok {
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+	} else {
+		// Tiles from mutable worlds can change at any time, so clients
+		// need to revalidate them, which is cheap given the ETag.
+		w.Header().Set("Cache-Control", "no-cache")
+	}
+	if match := r.Header.Get("If-None-Match"); match != "" && match == cached.ETag {
+		w.Header().Add("Access-Control-Allow-Origin", "*")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeEncodedTile(cached.Data, w)
+}
+
 func outputEncodedTile(tile b6.Tile, rendered *Tile, w http.ResponseWriter, r *http.Request) {
 	encoded := EncodeTile(tile, rendered)
 	marshaled, err := proto.Marshal(encoded)
 	if err != nil {
 		log.Printf("Failed to marshal tile: %v", err)
 		http.Error(w, "Failed to render tile", http.StatusInternalServerError)
+		return
 	}
+	writeEncodedTile(marshaled, w)
+}
+
+func writeEncodedTile(marshaled []byte, w http.ResponseWriter) {
 	w.Header().Set("Content-Type", "application/x-protobuf")
 	w.Header().Add("Content-Length", fmt.Sprintf("%d", len(marshaled)))
 	w.Header().Add("Access-Control-Allow-Origin", "*")
@@ -1,6 +1,7 @@
 package renderer
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 
@@ -20,7 +21,7 @@ func NewHistogramRenderer(rules RenderRules, worlds ingest.Worlds) *HistogramRen
 	}
 }
 
-func (r *HistogramRenderer) Render(tile b6.Tile, args *TileArgs) (*Tile, error) {
+func (r *HistogramRenderer) Render(ctx context.Context, tile b6.Tile, args *TileArgs) (*Tile, error) {
 	w := r.worlds.FindOrCreateWorld(args.R)
 
 	id := b6.FeatureIDFromString(args.Q)
@@ -69,7 +70,7 @@ func (r *HistogramRenderer) Render(tile b6.Tile, args *TileArgs) (*Tile, error)
 	for features.Next() {
 		if value, ok := findBucket(features.FeatureID(), values); ok {
 			f := features.Feature()
-			tags = r.rules.AddTags(f, tags[0:0])
+			tags = r.rules.AddTags(f, w, tags[0:0])
 			tags = append(tags, b6.Tag{Key: "bucket", Value: b6.NewStringExpression(strconv.Itoa(value))})
 			rendered = FillFeaturesFromFeature(features.Feature(), tags, rendered, &RenderRule{Label: true}, w)
 		}
@@ -1,6 +1,7 @@
 package renderer
 
 import (
+	"context"
 	"log"
 	"testing"
 
@@ -35,7 +36,7 @@ func TestHistogramWithBucketedFeature(t *testing.T) {
 	r := NewHistogramRenderer(BasemapRenderRules, worlds)
 	args := &TileArgs{Q: histogramID.String(), R: worldID.FeatureID()}
 	projection := b6.NewTileMercatorProjection(16)
-	tile, err := r.Render(projection.TileFromLatLng(s2.LatLngFromDegrees(51.53531, -0.12434)), args)
+	tile, err := r.Render(context.Background(), projection.TileFromLatLng(s2.LatLngFromDegrees(51.53531, -0.12434)), args)
 	if err != nil {
 		t.Fatalf("Expected no error, found: %s", err)
 	}
@@ -72,7 +73,7 @@ func TestHistogramWithInvalidCollection(t *testing.T) {
 	r := NewHistogramRenderer(BasemapRenderRules, worlds)
 	args := &TileArgs{Q: histogramID.String(), R: worldID.FeatureID()}
 	projection := b6.NewTileMercatorProjection(16)
-	tile, err := r.Render(projection.TileFromLatLng(s2.LatLngFromDegrees(51.53531, -0.12434)), args)
+	tile, err := r.Render(context.Background(), projection.TileFromLatLng(s2.LatLngFromDegrees(51.53531, -0.12434)), args)
 	if err != nil {
 		t.Fatalf("Expected no error, found: %s", err)
 	}
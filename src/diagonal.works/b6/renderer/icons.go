@@ -70,7 +70,7 @@ var iconsForTag = []struct {
 
 func IconForTag(t b6.Tag) (string, bool) {
 	for _, i := range iconsForTag {
-		if i.Tag.Key == t.Key && (i.Tag.Value.String() == "" || i.Tag.Value.String() == t.Value.String()) {
+		if i.Tag.Key == t.Key && matchesIconValue(i.Tag, t) {
 			return i.Icon, true
 		}
 	}
@@ -79,9 +79,15 @@ func IconForTag(t b6.Tag) (string, bool) {
 
 func IconForFeature(f b6.Feature) (string, bool) {
 	for _, i := range iconsForTag {
-		if t := f.Get(i.Tag.Key); t.IsValid() && (i.Tag.Value.String() == "" || i.Tag.Value.String() == t.Value.String()) {
+		if t := f.Get(i.Tag.Key); t.IsValid() && matchesIconValue(i.Tag, t) {
 			return i.Icon, true
 		}
 	}
 	return "dot", false
 }
+
+// matchesIconValue returns true if the value of tag t matches that of the
+// tag for an icon, which matches all values if it's empty, or missing.
+func matchesIconValue(icon b6.Tag, t b6.Tag) bool {
+	return icon.Value.AnyExpression == nil || icon.Value.String() == "" || icon.Value.String() == t.Value.String()
+}
@@ -0,0 +1,24 @@
+package renderer
+
+import (
+	"testing"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/ingest"
+	"diagonal.works/b6/test/camden"
+)
+
+func TestIconForBuildingWithoutSpecificIcon(t *testing.T) {
+	tag := b6.Tag{Key: "#building", Value: b6.NewStringExpression("office")}
+	if icon, ok := IconForTag(tag); !ok || icon != "building" {
+		t.Errorf("Expected building icon for tag, found %q", icon)
+	}
+
+	w := ingest.NewMutableOverlayWorld(camden.BuildGranarySquareForTests(t))
+	if err := w.AddTag(ingest.AreaIDFromOSMWayID(camden.CoalDropsYardWestBuildingWay).FeatureID(), tag); err != nil {
+		t.Fatal(err)
+	}
+	if icon, ok := IconForFeature(w.FindFeatureByID(ingest.AreaIDFromOSMWayID(camden.CoalDropsYardWestBuildingWay).FeatureID())); !ok || icon != "building" {
+		t.Errorf("Expected building icon for feature, found %q", icon)
+	}
+}
@@ -0,0 +1,139 @@
+// Package mbtiles writes tiles to MBTiles archives. It's separate from
+// renderer, since the SQLite driver it uses requires cgo.
+package mbtiles
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/renderer"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// The SQLite application ID for MBTiles archives, "MPBX"
+const applicationID = 0x4d504258
+
+// Writer writes tiles to an MBTiles archive.
+// See https://github.com/mapbox/mbtiles-spec/blob/master/1.3/spec.md
+type Writer struct {
+	db     *sql.DB
+	tx     *sql.Tx
+	insert *sql.Stmt
+}
+
+func NewWriter(filename string) (*Writer, error) {
+	if err := os.Remove(filename); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite3", filename)
+	if err != nil {
+		return nil, err
+	}
+	schema := []string{
+		fmt.Sprintf("PRAGMA application_id = %d", applicationID),
+		"CREATE TABLE metadata (name text, value text)",
+		"CREATE TABLE tiles (zoom_level integer, tile_column integer, tile_row integer, tile_data blob)",
+	}
+	for _, statement := range schema {
+		if _, err := db.Exec(statement); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+	// Tiles are written in a single transaction, as committing each
+	// individually is slow.
+	tx, err := db.Begin()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	insert, err := tx.Prepare("INSERT INTO tiles (zoom_level, tile_column, tile_row, tile_data) VALUES (?, ?, ?, ?)")
+	if err != nil {
+		tx.Rollback()
+		db.Close()
+		return nil, err
+	}
+	return &Writer{db: db, tx: tx, insert: insert}, nil
+}
+
+func (w *Writer) WriteTile(tile b6.Tile, data []byte) error {
+	// MBTiles numbers rows from the south, following the TMS convention
+	row := (int64(1) << tile.Z) - 1 - int64(tile.Y)
+	_, err := w.insert.Exec(tile.Z, tile.X, row, data)
+	return err
+}
+
+func (w *Writer) Close(metadata *renderer.TilesetMetadata) error {
+	err := w.close(metadata)
+	if err != nil {
+		w.tx.Rollback()
+	}
+	if cerr := w.db.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (w *Writer) close(metadata *renderer.TilesetMetadata) error {
+	if err := w.insert.Close(); err != nil {
+		return err
+	}
+	layers, err := json.Marshal(struct {
+		VectorLayers []renderer.VectorLayer `json:"vector_layers"`
+	}{VectorLayers: metadata.Layers})
+	if err != nil {
+		return err
+	}
+	center, zoom := metadata.Center()
+	values := [][2]string{
+		{"name", metadata.Name},
+		{"format", "pbf"},
+		{"bounds", boundsToString(metadata)},
+		{"center", fmt.Sprintf("%s,%d", formatDegrees(center.Lng.Degrees(), center.Lat.Degrees()), zoom)},
+		{"minzoom", strconv.Itoa(int(metadata.MinZoom))},
+		{"maxzoom", strconv.Itoa(int(metadata.MaxZoom))},
+		{"type", "baselayer"},
+		{"json", string(layers)},
+	}
+	if metadata.Description != "" {
+		values = append(values, [2]string{"description", metadata.Description})
+	}
+	if metadata.Attribution != "" {
+		values = append(values, [2]string{"attribution", metadata.Attribution})
+	}
+	for _, v := range values {
+		if _, err := w.tx.Exec("INSERT INTO metadata (name, value) VALUES (?, ?)", v[0], v[1]); err != nil {
+			return err
+		}
+	}
+	// Building the index once all tiles are written is faster than
+	// maintaining it as they are.
+	if _, err := w.tx.Exec("CREATE UNIQUE INDEX tile_index on tiles (zoom_level, tile_column, tile_row)"); err != nil {
+		return err
+	}
+	return w.tx.Commit()
+}
+
+// boundsToString returns the bounds of the tileset as minimum longitude,
+// latitude, then maximum longitude, latitude.
+func boundsToString(metadata *renderer.TilesetMetadata) string {
+	lo, hi := metadata.Bounds.Lo(), metadata.Bounds.Hi()
+	return formatDegrees(lo.Lng.Degrees(), lo.Lat.Degrees(), hi.Lng.Degrees(), hi.Lat.Degrees())
+}
+
+func formatDegrees(degrees ...float64) string {
+	s := ""
+	for i, d := range degrees {
+		if i > 0 {
+			s += ","
+		}
+		s += strconv.FormatFloat(d, 'f', 7, 64)
+	}
+	return s
+}
@@ -0,0 +1,108 @@
+package mbtiles
+
+import (
+	"bytes"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/renderer"
+
+	"github.com/golang/geo/s2"
+)
+
+func TestWriter(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.mbtiles")
+	w, err := NewWriter(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const n = 2000
+	tileData := func(i int) []byte {
+		data := make([]byte, 16+(i%7)*2000)
+		for j := range data {
+			data[j] = byte(i + j)
+		}
+		return data
+	}
+	for i := 0; i < n; i++ {
+		if err := w.WriteTile(b6.Tile{X: uint(i % 64), Y: uint(i / 64), Z: 6}, tileData(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	metadata := renderer.TilesetMetadata{
+		Name:    "test",
+		MinZoom: 6,
+		MaxZoom: 6,
+		Bounds:  s2.RectFromLatLng(s2.LatLngFromDegrees(51.5354, -0.1255)).AddPoint(s2.LatLngFromDegrees(51.5368, -0.1236)),
+	}
+	if err := w.Close(&metadata); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite3", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var integrity string
+	if err := db.QueryRow("PRAGMA integrity_check").Scan(&integrity); err != nil || integrity != "ok" {
+		t.Errorf("Expected integrity check to pass, found %q, %v", integrity, err)
+	}
+	var id int64
+	if err := db.QueryRow("PRAGMA application_id").Scan(&id); err != nil || id != applicationID {
+		t.Errorf("Expected application ID %x, found %x, %v", applicationID, id, err)
+	}
+
+	rows, err := db.Query("SELECT zoom_level, tile_column, tile_row, tile_data FROM tiles ORDER BY rowid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := 0
+	for rows.Next() {
+		var z, x, y int64
+		var data []byte
+		if err := rows.Scan(&z, &x, &y, &data); err != nil {
+			t.Fatal(err)
+		}
+		// Rows are flipped, following TMS
+		if z != 6 || x != int64(i%64) || y != int64(63-i/64) {
+			t.Errorf("Unexpected tile %d/%d/%d for row %d", z, x, y, i)
+		}
+		if !bytes.Equal(data, tileData(i)) {
+			t.Errorf("Unexpected data for row %d", i)
+		}
+		i++
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	} else if i != n {
+		t.Errorf("Expected %d tiles, found %d", n, i)
+	}
+
+	var data []byte
+	if err := db.QueryRow("SELECT tile_data FROM tiles WHERE zoom_level = 6 AND tile_column = 1 AND tile_row = 63").Scan(&data); err != nil || !bytes.Equal(data, tileData(1)) {
+		t.Errorf("Expected to find tile by index, found %v", err)
+	}
+
+	values := make(map[string]string)
+	metadataRows, err := db.Query("SELECT name, value FROM metadata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for metadataRows.Next() {
+		var name, value string
+		if err := metadataRows.Scan(&name, &value); err != nil {
+			t.Fatal(err)
+		}
+		values[name] = value
+	}
+	if values["name"] != "test" || values["format"] != "pbf" {
+		t.Errorf("Expected name and format in metadata, found %v", values)
+	}
+	if expected := "-0.1255000,51.5354000,-0.1236000,51.5368000"; values["bounds"] != expected {
+		t.Errorf("Expected bounds %q, found %q", expected, values["bounds"])
+	}
+}
@@ -0,0 +1,276 @@
+package renderer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"diagonal.works/b6"
+
+	"github.com/golang/geo/s1"
+)
+
+// See https://github.com/protomaps/PMTiles/blob/main/spec/v3/spec.md
+const (
+	pmtilesVersion      = 3
+	pmtilesHeaderLength = 127
+	// The header and root directory must fit within the first 16KiB of
+	// the file, allowing clients to fetch them with a single request.
+	pmtilesMaxRootLength = 16384 - pmtilesHeaderLength
+
+	pmtilesCompressionGzip = 2
+	pmtilesTileTypeMVT     = 1
+)
+
+// PMTilesTileID returns the ID of the given tile in a PMTiles archive,
+// which orders tiles by zoom level, and then along a Hilbert curve.
+func PMTilesTileID(tile b6.Tile) uint64 {
+	// The number of tiles in all lower zoom levels
+	id := ((uint64(1) << (2 * tile.Z)) - 1) / 3
+	x, y := uint64(tile.X), uint64(tile.Y)
+	for s := (uint64(1) << tile.Z) >> 1; s > 0; s >>= 1 {
+		var rx, ry uint64
+		if x&s != 0 {
+			rx = 1
+		}
+		if y&s != 0 {
+			ry = 1
+		}
+		id += s * s * ((3 * rx) ^ ry)
+		if ry == 0 {
+			if rx == 1 {
+				x = s - 1 - x
+				y = s - 1 - y
+			}
+			x, y = y, x
+		}
+	}
+	return id
+}
+
+type pmtilesEntry struct {
+	TileID    uint64
+	Offset    uint64
+	Length    uint32
+	RunLength uint32
+}
+
+type pmtilesContent struct {
+	offset uint64
+	length uint32
+}
+
+// PMTilesWriter writes tiles to a PMTiles archive. Tiles with identical
+// content are stored once. Tile data is written to a temporary file as
+// it's received, and copied into the archive once it's closed.
+type PMTilesWriter struct {
+	f        *os.File
+	data     *os.File
+	length   uint64
+	entries  []pmtilesEntry
+	contents map[[sha256.Size]byte]pmtilesContent
+}
+
+func NewPMTilesWriter(filename string) (*PMTilesWriter, error) {
+	f, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".data.*")
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &PMTilesWriter{f: f, data: data, contents: make(map[[sha256.Size]byte]pmtilesContent)}, nil
+}
+
+func (p *PMTilesWriter) WriteTile(tile b6.Tile, data []byte) error {
+	hash := sha256.Sum256(data)
+	content, ok := p.contents[hash]
+	if !ok {
+		if _, err := p.data.Write(data); err != nil {
+			return err
+		}
+		content = pmtilesContent{offset: p.length, length: uint32(len(data))}
+		p.contents[hash] = content
+		p.length += uint64(len(data))
+	}
+	p.entries = append(p.entries, pmtilesEntry{TileID: PMTilesTileID(tile), Offset: content.offset, Length: content.length, RunLength: 1})
+	return nil
+}
+
+func (p *PMTilesWriter) Close(metadata *TilesetMetadata) error {
+	err := p.close(metadata)
+	if cerr := p.f.Close(); err == nil {
+		err = cerr
+	}
+	p.data.Close()
+	if rerr := os.Remove(p.data.Name()); err == nil {
+		err = rerr
+	}
+	return err
+}
+
+func (p *PMTilesWriter) close(metadata *TilesetMetadata) error {
+	sort.Slice(p.entries, func(i, j int) bool {
+		return p.entries[i].TileID < p.entries[j].TileID
+	})
+	// Consecutive tiles with the same content are stored as a single run
+	entries := make([]pmtilesEntry, 0, len(p.entries))
+	for _, e := range p.entries {
+		if n := len(entries); n > 0 {
+			last := &entries[n-1]
+			if last.Offset == e.Offset && last.Length == e.Length && last.TileID+uint64(last.RunLength) == e.TileID {
+				last.RunLength++
+				continue
+			}
+		}
+		entries = append(entries, e)
+	}
+
+	root, leaves, err := buildPMTilesDirectories(entries)
+	if err != nil {
+		return err
+	}
+	encodedMetadata, err := encodePMTilesMetadata(metadata)
+	if err != nil {
+		return err
+	}
+
+	header := make([]byte, pmtilesHeaderLength)
+	copy(header, "PMTiles")
+	header[7] = pmtilesVersion
+	offset := uint64(pmtilesHeaderLength)
+	for i, section := range []uint64{uint64(len(root)), uint64(len(encodedMetadata)), uint64(len(leaves)), p.length} {
+		binary.LittleEndian.PutUint64(header[8+i*16:], offset)
+		binary.LittleEndian.PutUint64(header[16+i*16:], section)
+		offset += section
+	}
+	binary.LittleEndian.PutUint64(header[72:], uint64(len(p.entries)))
+	binary.LittleEndian.PutUint64(header[80:], uint64(len(entries)))
+	binary.LittleEndian.PutUint64(header[88:], uint64(len(p.contents)))
+	header[96] = 0 // Tile data isn't ordered by tile ID
+	header[97] = pmtilesCompressionGzip
+	header[98] = pmtilesCompressionGzip
+	header[99] = pmtilesTileTypeMVT
+	header[100] = byte(metadata.MinZoom)
+	header[101] = byte(metadata.MaxZoom)
+	putE7 := func(b []byte, a s1.Angle) {
+		binary.LittleEndian.PutUint32(b, uint32(int32(a.E7())))
+	}
+	putE7(header[102:], metadata.Bounds.Lo().Lng)
+	putE7(header[106:], metadata.Bounds.Lo().Lat)
+	putE7(header[110:], metadata.Bounds.Hi().Lng)
+	putE7(header[114:], metadata.Bounds.Hi().Lat)
+	center, zoom := metadata.Center()
+	header[118] = byte(zoom)
+	putE7(header[119:], center.Lng)
+	putE7(header[123:], center.Lat)
+
+	for _, section := range [][]byte{header, root, encodedMetadata, leaves} {
+		if _, err := p.f.Write(section); err != nil {
+			return err
+		}
+	}
+	if _, err := p.data.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	_, err = io.Copy(p.f, p.data)
+	return err
+}
+
+// buildPMTilesDirectories returns the encoded root directory, and any leaf
+// directories needed to keep the root within the size limit.
+func buildPMTilesDirectories(entries []pmtilesEntry) ([]byte, []byte, error) {
+	root, err := encodePMTilesDirectory(entries)
+	if err != nil || len(root) <= pmtilesMaxRootLength {
+		return root, nil, err
+	}
+	for leafSize := 4096; ; leafSize *= 2 {
+		var leaves bytes.Buffer
+		var rootEntries []pmtilesEntry
+		for begin := 0; begin < len(entries); begin += leafSize {
+			end := begin + leafSize
+			if end > len(entries) {
+				end = len(entries)
+			}
+			leaf, err := encodePMTilesDirectory(entries[begin:end])
+			if err != nil {
+				return nil, nil, err
+			}
+			// Entries with a run length of 0 refer to leaf directories
+			rootEntries = append(rootEntries, pmtilesEntry{TileID: entries[begin].TileID, Offset: uint64(leaves.Len()), Length: uint32(len(leaf))})
+			leaves.Write(leaf)
+		}
+		if root, err = encodePMTilesDirectory(rootEntries); err != nil {
+			return nil, nil, err
+		} else if len(root) <= pmtilesMaxRootLength {
+			return root, leaves.Bytes(), nil
+		}
+	}
+}
+
+// encodePMTilesDirectory returns the gzip compressed encoding of the given
+// entries, which must be ordered by tile ID.
+func encodePMTilesDirectory(entries []pmtilesEntry) ([]byte, error) {
+	encoded := binary.AppendUvarint(nil, uint64(len(entries)))
+	var last uint64
+	for _, e := range entries {
+		encoded = binary.AppendUvarint(encoded, e.TileID-last)
+		last = e.TileID
+	}
+	for _, e := range entries {
+		encoded = binary.AppendUvarint(encoded, uint64(e.RunLength))
+	}
+	for _, e := range entries {
+		encoded = binary.AppendUvarint(encoded, uint64(e.Length))
+	}
+	for i, e := range entries {
+		// Offsets immediately following the previous entry are encoded
+		// as 0, and others with 1 added.
+		if i > 0 && e.Offset == entries[i-1].Offset+uint64(entries[i-1].Length) {
+			encoded = binary.AppendUvarint(encoded, 0)
+		} else {
+			encoded = binary.AppendUvarint(encoded, e.Offset+1)
+		}
+	}
+	return compressWithGzip(encoded)
+}
+
+func encodePMTilesMetadata(metadata *TilesetMetadata) ([]byte, error) {
+	encoded, err := json.Marshal(struct {
+		Name         string        `json:"name,omitempty"`
+		Description  string        `json:"description,omitempty"`
+		Attribution  string        `json:"attribution,omitempty"`
+		Type         string        `json:"type"`
+		VectorLayers []VectorLayer `json:"vector_layers"`
+	}{
+		Name:         metadata.Name,
+		Description:  metadata.Description,
+		Attribution:  metadata.Attribution,
+		Type:         "baselayer",
+		VectorLayers: metadata.Layers,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return compressWithGzip(encoded)
+}
+
+func compressWithGzip(data []byte) ([]byte, error) {
+	var buffer bytes.Buffer
+	w := gzip.NewWriter(&buffer)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,204 @@
+package renderer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"diagonal.works/b6"
+
+	"github.com/golang/geo/s2"
+)
+
+func TestPMTilesTileID(t *testing.T) {
+	// Examples from the PMTiles specification
+	tests := []struct {
+		tile b6.Tile
+		id   uint64
+	}{
+		{b6.Tile{X: 0, Y: 0, Z: 0}, 0},
+		{b6.Tile{X: 0, Y: 0, Z: 1}, 1},
+		{b6.Tile{X: 0, Y: 1, Z: 1}, 2},
+		{b6.Tile{X: 1, Y: 1, Z: 1}, 3},
+		{b6.Tile{X: 1, Y: 0, Z: 1}, 4},
+		{b6.Tile{X: 0, Y: 0, Z: 2}, 5},
+		{b6.Tile{X: 3, Y: 0, Z: 2}, 20},
+	}
+	for _, test := range tests {
+		if id := PMTilesTileID(test.tile); id != test.id {
+			t.Errorf("Expected ID %d for %s, found %d", test.id, test.tile, id)
+		}
+	}
+}
+
+func gunzip(t *testing.T, data []byte) []byte {
+	t.Helper()
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	decompressed, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return decompressed
+}
+
+func decodePMTilesDirectory(t *testing.T, data []byte) []pmtilesEntry {
+	t.Helper()
+	r := bytes.NewReader(gunzip(t, data))
+	next := func() uint64 {
+		v, err := binary.ReadUvarint(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	entries := make([]pmtilesEntry, next())
+	var id uint64
+	for i := range entries {
+		id += next()
+		entries[i].TileID = id
+	}
+	for i := range entries {
+		entries[i].RunLength = uint32(next())
+	}
+	for i := range entries {
+		entries[i].Length = uint32(next())
+	}
+	for i := range entries {
+		if offset := next(); offset == 0 && i > 0 {
+			entries[i].Offset = entries[i-1].Offset + uint64(entries[i-1].Length)
+		} else {
+			entries[i].Offset = offset - 1
+		}
+	}
+	return entries
+}
+
+func TestPMTilesWriter(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "test.pmtiles")
+	w, err := NewPMTilesWriter(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tiles := map[b6.Tile][]byte{
+		{X: 0, Y: 0, Z: 1}: []byte("empty"),
+		{X: 0, Y: 1, Z: 1}: []byte("empty"),
+		{X: 1, Y: 1, Z: 1}: []byte("land"),
+		{X: 1, Y: 0, Z: 1}: []byte("empty"),
+		{X: 0, Y: 0, Z: 0}: []byte("world"),
+	}
+	for tile, data := range tiles {
+		if err := w.WriteTile(tile, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	metadata := TilesetMetadata{
+		Name:    "test",
+		MinZoom: 0,
+		MaxZoom: 1,
+		Bounds:  s2.RectFromLatLng(s2.LatLngFromDegrees(51.5354, -0.1255)).AddPoint(s2.LatLngFromDegrees(51.5368, -0.1236)),
+		Layers:  []VectorLayer{{ID: "background", MaxZoom: 1}},
+	}
+	if err := w.Close(&metadata); err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(archive[0:7]) != "PMTiles" || archive[7] != 3 {
+		t.Fatalf("Expected a PMTiles v3 header, found %q", archive[0:8])
+	}
+	section := func(i int) []byte {
+		offset := binary.LittleEndian.Uint64(archive[8+i*16:])
+		length := binary.LittleEndian.Uint64(archive[16+i*16:])
+		return archive[offset : offset+length]
+	}
+	if addressed, contents := binary.LittleEndian.Uint64(archive[72:]), binary.LittleEndian.Uint64(archive[88:]); addressed != 5 || contents != 3 {
+		t.Errorf("Expected 5 tiles with 3 distinct contents, found %d and %d", addressed, contents)
+	}
+	if lat := int32(binary.LittleEndian.Uint32(archive[114:])); lat != 515368000 {
+		t.Errorf("Expected max latitude 515368000, found %d", lat)
+	}
+
+	entries := decodePMTilesDirectory(t, section(0))
+	// Tiles 2 to 4 are empty, but tile 3 separates them into two runs
+	if len(entries) != 4 {
+		t.Errorf("Expected 4 entries, found %d", len(entries))
+	}
+	data := section(3)
+	for tile, expected := range tiles {
+		id := PMTilesTileID(tile)
+		found := false
+		for _, e := range entries {
+			if id >= e.TileID && id < e.TileID+uint64(e.RunLength) {
+				found = true
+				if content := data[e.Offset : e.Offset+uint64(e.Length)]; !bytes.Equal(content, expected) {
+					t.Errorf("Expected %q for %s, found %q", expected, tile, content)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("Expected an entry for %s", tile)
+		}
+	}
+
+	var decoded struct {
+		Name         string        `json:"name"`
+		VectorLayers []VectorLayer `json:"vector_layers"`
+	}
+	if err := json.Unmarshal(gunzip(t, section(1)), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Name != "test" || len(decoded.VectorLayers) != 1 {
+		t.Errorf("Expected metadata to be stored, found %+v", decoded)
+	}
+	if matches, _ := filepath.Glob(filename + ".data.*"); len(matches) > 0 {
+		t.Errorf("Expected temporary data to be removed, found %v", matches)
+	}
+}
+
+func TestPMTilesLeafDirectories(t *testing.T) {
+	entries := make([]pmtilesEntry, 100000)
+	for i := range entries {
+		// Distinct, non-contiguous offsets, preventing efficient compression
+		entries[i] = pmtilesEntry{TileID: uint64(i * 2), Offset: uint64(i*i) % 1000003 * 64, Length: uint32(i%1000 + 1), RunLength: 1}
+	}
+	root, leaves, err := buildPMTilesDirectories(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(root) > pmtilesMaxRootLength {
+		t.Errorf("Expected root to be at most %d bytes, found %d", pmtilesMaxRootLength, len(root))
+	}
+	if len(leaves) == 0 {
+		t.Fatal("Expected leaf directories")
+	}
+	n := 0
+	for _, e := range decodePMTilesDirectory(t, root) {
+		if e.RunLength != 0 {
+			t.Fatalf("Expected root entries to reference leaves")
+		}
+		leaf := decodePMTilesDirectory(t, leaves[e.Offset:e.Offset+uint64(e.Length)])
+		if leaf[0].TileID != e.TileID {
+			t.Errorf("Expected leaf to begin with tile %d, found %d", e.TileID, leaf[0].TileID)
+		}
+		for _, l := range leaf {
+			if l != entries[n] {
+				t.Fatalf("Expected %+v, found %+v", entries[n], l)
+			}
+			n++
+		}
+	}
+	if n != len(entries) {
+		t.Errorf("Expected %d entries in leaves, found %d", len(entries), n)
+	}
+}
@@ -0,0 +1,289 @@
+package renderer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync"
+
+	"diagonal.works/b6"
+
+	"github.com/golang/geo/s1"
+	"github.com/golang/geo/s2"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/proto"
+)
+
+// TileArchive stores a pyramid of encoded tiles, allowing them to be served
+// statically, without rendering them from a world.
+type TileArchive interface {
+	// WriteTile stores the encoded, gzip compressed data for a tile.
+	// Tiles can be written in any order, but not concurrently.
+	WriteTile(tile b6.Tile, data []byte) error
+	// Close completes the archive, storing the given metadata.
+	Close(metadata *TilesetMetadata) error
+}
+
+// TilesetMetadata describes the tiles in an archive, following the
+// conventions of the MBTiles specification, which PMTiles also uses.
+// See https://github.com/mapbox/mbtiles-spec/blob/master/1.3/spec.md
+type TilesetMetadata struct {
+	Name        string
+	Description string
+	Attribution string
+	MinZoom     uint
+	MaxZoom     uint
+	Bounds      s2.Rect
+	Layers      []VectorLayer
+}
+
+// VectorLayer describes a layer in an archive's tiles, and the fields
+// of the features it contains.
+type VectorLayer struct {
+	ID          string            `json:"id"`
+	Description string            `json:"description,omitempty"`
+	MinZoom     uint              `json:"minzoom"`
+	MaxZoom     uint              `json:"maxzoom"`
+	Fields      map[string]string `json:"fields"`
+}
+
+// Center returns the point at which clients should initially display the
+// tileset, and the zoom level to use.
+func (t *TilesetMetadata) Center() (s2.LatLng, uint) {
+	zoom := t.MinZoom
+	if zoom < t.MaxZoom {
+		zoom++
+	}
+	return t.Bounds.Center(), zoom
+}
+
+// Encoded tiles are limited to the range of latitudes that can be
+// represented by the web mercator projection.
+const maxMercatorLatitude = 85.0511287798
+
+// TileRange returns the top left and bottom right tiles covering the
+// given bounds at zoom level z.
+func TileRange(bounds s2.Rect, z uint) (b6.Tile, b6.Tile) {
+	if z == 0 {
+		return b6.Tile{}, b6.Tile{}
+	}
+	clamp := func(lat s1.Angle) s1.Angle {
+		return s1.Angle(math.Max(-maxMercatorLatitude, math.Min(maxMercatorLatitude, lat.Degrees()))) * s1.Degree
+	}
+	projection := b6.NewTileMercatorProjection(z)
+	topLeft := projection.TileFromLatLng(s2.LatLng{Lat: clamp(bounds.Hi().Lat), Lng: bounds.Lo().Lng})
+	bottomRight := projection.TileFromLatLng(s2.LatLng{Lat: clamp(bounds.Lo().Lat), Lng: bounds.Hi().Lng})
+	// Points on the eastern and southern edges of the world project to
+	// tiles beyond the last one.
+	n := uint(1) << z
+	if bottomRight.X >= n {
+		bottomRight.X = n - 1
+	}
+	if bottomRight.Y >= n {
+		bottomRight.Y = n - 1
+	}
+	return topLeft, bottomRight
+}
+
+// CountTilesInPyramid returns the number of tiles needed to cover the given
+// bounds between the given zoom levels, inclusive.
+func CountTilesInPyramid(bounds s2.Rect, minZoom uint, maxZoom uint) int {
+	n := 0
+	for z := minZoom; z <= maxZoom; z++ {
+		topLeft, bottomRight := TileRange(bounds, z)
+		n += int(bottomRight.X-topLeft.X+1) * int(bottomRight.Y-topLeft.Y+1)
+	}
+	return n
+}
+
+type PyramidOptions struct {
+	Bounds  s2.Rect
+	MinZoom uint
+	MaxZoom uint
+	Args    TileArgs
+	Cores   int
+}
+
+// RenderPyramid renders and encodes every tile covering the given bounds
+// between the given zoom levels, writing them to the archive. Tiles are
+// rendered in parallel, and written as they're completed. The archive is
+// not closed, allowing the caller to provide its metadata.
+func RenderPyramid(r Renderer, options *PyramidOptions, archive TileArchive) error {
+	if options.MinZoom > options.MaxZoom {
+		return fmt.Errorf("min zoom %d is greater than max zoom %d", options.MinZoom, options.MaxZoom)
+	}
+	cores := options.Cores
+	if cores < 1 {
+		cores = 1
+	}
+	tiles := make(chan b6.Tile, cores)
+	type encoded struct {
+		tile b6.Tile
+		data []byte
+	}
+	results := make(chan encoded, cores)
+
+	// Stop rendering if the archive fails
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		defer close(tiles)
+		for z := options.MinZoom; z <= options.MaxZoom; z++ {
+			topLeft, bottomRight := TileRange(options.Bounds, z)
+			for x := topLeft.X; x <= bottomRight.X; x++ {
+				for y := topLeft.Y; y <= bottomRight.Y; y++ {
+					select {
+					case tiles <- b6.Tile{X: x, Y: y, Z: z}:
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+				}
+			}
+		}
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < cores; i++ {
+		wg.Add(1)
+		g.Go(func() error {
+			defer wg.Done()
+			for tile := range tiles {
+				args := options.Args
+				rendered, err := r.Render(ctx, tile, &args)
+				if err != nil {
+					return fmt.Errorf("%s: %w", tile, err)
+				}
+				marshalled, err := proto.Marshal(EncodeTile(tile, rendered))
+				if err != nil {
+					return fmt.Errorf("%s: %w", tile, err)
+				}
+				compressed, err := compressWithGzip(marshalled)
+				if err != nil {
+					return err
+				}
+				select {
+				case results <- encoded{tile: tile, data: compressed}:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return nil
+		})
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	total := CountTilesInPyramid(options.Bounds, options.MinZoom, options.MaxZoom)
+	written := 0
+	var err error
+	for result := range results {
+		if err == nil {
+			if err = archive.WriteTile(result.tile, result.data); err != nil {
+				cancel()
+			}
+			written++
+			if written%10000 == 0 {
+				log.Printf("Rendered %d/%d tiles", written, total)
+			}
+		}
+	}
+	if gerr := g.Wait(); err == nil {
+		err = gerr
+	}
+	return err
+}
+
+func (b BasemapLayer) Description() string {
+	switch b {
+	case BasemapLayerBoundary:
+		return "Coastlines and other boundaries"
+	case BasemapLayerContour:
+		return "Contour lines"
+	case BasemapLayerWater:
+		return "Bodies of water and waterways"
+	case BasemapLayerRoad:
+		return "Roads, paths and railways"
+	case BasemapLayerLandUse:
+		return "Parks, gardens and other land use"
+	case BasemapLayerBuilding:
+		return "Building outlines"
+	case BasemapLayerPoint:
+		return "Points of interest"
+	case BasemapLayerLabel:
+		return "Labels for places"
+	case BasemapLayerAmenity:
+		return "Amenities, with icons"
+	}
+	return ""
+}
+
+// VectorLayers returns descriptions of the layers present in tiles rendered
+// with these rules, between the given zoom levels.
+func (rs RenderRules) VectorLayers(minZoom uint, maxZoom uint) []VectorLayer {
+	layers := make(map[string]*VectorLayer)
+	for _, rule := range rs {
+		min, max := minZoom, maxZoom
+		if rule.MinZoom > min {
+			min = rule.MinZoom
+		}
+		if rule.MaxZoom > 0 && rule.MaxZoom < max {
+			max = rule.MaxZoom
+		}
+		if min > max {
+			continue
+		}
+		layer, ok := layers[rule.LayerName()]
+		if !ok {
+			layer = &VectorLayer{
+				ID:          rule.LayerName(),
+				Description: rule.Layer.Description(),
+				MinZoom:     min,
+				MaxZoom:     max,
+				Fields: map[string]string{
+					"id":         "Feature ID, in hex, without its namespace",
+					"ns":         "Feature ID namespace",
+					b6.ColourTag: "Colour, as a hex string or palette index",
+				},
+			}
+			layers[rule.LayerName()] = layer
+		}
+		if min < layer.MinZoom {
+			layer.MinZoom = min
+		}
+		if max > layer.MaxZoom {
+			layer.MaxZoom = max
+		}
+		if rule.Tag.Key != "" {
+			layer.Fields[tileTagKey(rule.Tag.Key)] = "Value of the " + rule.Tag.Key + " tag"
+		}
+		for _, key := range rule.Attributes {
+			layer.Fields[tileTagKey(key)] = "Value of the " + key + " tag"
+		}
+		if rule.Label {
+			layer.Fields["name"] = "Name of the feature"
+		}
+		if rule.Icon {
+			layer.Fields["icon"] = "Icon for the feature"
+		}
+	}
+	vectorLayers := []VectorLayer{{
+		ID:          "background",
+		Description: "A polygon covering the tile",
+		MinZoom:     minZoom,
+		MaxZoom:     maxZoom,
+		Fields:      map[string]string{},
+	}}
+	for _, layer := range layers {
+		vectorLayers = append(vectorLayers, *layer)
+	}
+	sort.Slice(vectorLayers[1:], func(i, j int) bool {
+		return vectorLayers[i+1].ID < vectorLayers[j+1].ID
+	})
+	return vectorLayers
+}
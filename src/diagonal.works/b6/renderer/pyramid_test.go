@@ -0,0 +1,120 @@
+package renderer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/ingest"
+	pb "diagonal.works/b6/proto"
+	"diagonal.works/b6/test/camden"
+
+	"github.com/golang/geo/s2"
+	"google.golang.org/protobuf/proto"
+)
+
+type memoryArchive struct {
+	tiles    map[b6.Tile][]byte
+	metadata *TilesetMetadata
+}
+
+func (m *memoryArchive) WriteTile(tile b6.Tile, data []byte) error {
+	m.tiles[tile] = data
+	return nil
+}
+
+func (m *memoryArchive) Close(metadata *TilesetMetadata) error {
+	m.metadata = metadata
+	return nil
+}
+
+func TestTileRange(t *testing.T) {
+	granarySquare := s2.RectFromLatLng(s2.LatLngFromDegrees(51.5354, -0.1255)).AddPoint(s2.LatLngFromDegrees(51.5368, -0.1236))
+	topLeft, bottomRight := TileRange(granarySquare, 16)
+	if topLeft != (b6.Tile{X: 32745, Y: 21783, Z: 16}) || bottomRight != (b6.Tile{X: 32745, Y: 21784, Z: 16}) {
+		t.Errorf("Expected tiles from 16/32745/21783 to 16/32745/21784, found %s to %s", topLeft, bottomRight)
+	}
+	if n := CountTilesInPyramid(granarySquare, 15, 16); n != 4 {
+		t.Errorf("Expected 4 tiles, found %d", n)
+	}
+	if topLeft, bottomRight := TileRange(s2.FullRect(), 2); topLeft != (b6.Tile{X: 0, Y: 0, Z: 2}) || bottomRight != (b6.Tile{X: 3, Y: 3, Z: 2}) {
+		t.Errorf("Expected the whole world to be covered, found %s to %s", topLeft, bottomRight)
+	}
+}
+
+func TestRenderPyramid(t *testing.T) {
+	w := camden.BuildGranarySquareForTests(t)
+	r := &BasemapRenderer{RenderRules: BasemapRenderRules, Worlds: ingest.NewReadOnlyWorlds(w)}
+	options := PyramidOptions{
+		Bounds:  s2.RectFromLatLng(s2.LatLngFromDegrees(51.5354, -0.1255)).AddPoint(s2.LatLngFromDegrees(51.5368, -0.1236)),
+		MinZoom: 14,
+		MaxZoom: 16,
+		Cores:   2,
+	}
+	archive := &memoryArchive{tiles: make(map[b6.Tile][]byte)}
+	if err := RenderPyramid(r, &options, archive); err != nil {
+		t.Fatal(err)
+	}
+	if n := CountTilesInPyramid(options.Bounds, options.MinZoom, options.MaxZoom); len(archive.tiles) != n {
+		t.Fatalf("Expected %d tiles, found %d", n, len(archive.tiles))
+	}
+
+	buildings := 0
+	for z := options.MinZoom; z <= options.MaxZoom; z++ {
+		topLeft, bottomRight := TileRange(options.Bounds, z)
+		for x := topLeft.X; x <= bottomRight.X; x++ {
+			for y := topLeft.Y; y <= bottomRight.Y; y++ {
+				data, ok := archive.tiles[b6.Tile{X: x, Y: y, Z: z}]
+				if !ok {
+					t.Fatalf("Expected tile %d/%d/%d", z, x, y)
+				}
+				gz, err := gzip.NewReader(bytes.NewReader(data))
+				if err != nil {
+					t.Fatal(err)
+				}
+				decompressed, err := io.ReadAll(gz)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var tile pb.TileProto
+				if err := proto.Unmarshal(decompressed, &tile); err != nil {
+					t.Fatal(err)
+				}
+				for _, layer := range tile.Layers {
+					if layer.GetName() == BasemapLayerBuilding.String() {
+						buildings += len(layer.Features)
+					}
+				}
+			}
+		}
+	}
+	if buildings == 0 {
+		t.Error("Expected tiles to include buildings")
+	}
+}
+
+func TestVectorLayers(t *testing.T) {
+	layers := BasemapRenderRules.VectorLayers(8, 16)
+	byID := make(map[string]VectorLayer)
+	for _, l := range layers {
+		byID[l.ID] = l
+	}
+	if _, ok := byID["background"]; !ok {
+		t.Error("Expected a background layer")
+	}
+	if road, ok := byID[BasemapLayerRoad.String()]; !ok {
+		t.Error("Expected a road layer")
+	} else {
+		if _, ok := road.Fields["highway"]; !ok {
+			t.Errorf("Expected road layer to have a highway field, found %v", road.Fields)
+		}
+		if _, ok := road.Fields["name"]; !ok {
+			t.Errorf("Expected road layer to have a name field, found %v", road.Fields)
+		}
+	}
+	if label := byID[BasemapLayerLabel.String()]; label.MaxZoom != 14 {
+		t.Errorf("Expected label layer to end at zoom 14, found %d", label.MaxZoom)
+	}
+}
@@ -8,15 +8,16 @@ import (
 	"diagonal.works/b6"
 	"diagonal.works/b6/api"
 	"diagonal.works/b6/api/functions"
+	"diagonal.works/b6/auth"
 	"diagonal.works/b6/ingest"
 )
 
 type QueryRenderer struct {
-	rules  RenderRules
-	worlds ingest.Worlds
-	cores  int
-	fs     api.FunctionSymbols
-	a      api.Adaptors
+	rules   RenderRules
+	worlds  ingest.Worlds
+	options api.Options
+	fs      api.FunctionSymbols
+	a       api.Adaptors
 }
 
 // QueryRenderRules is used to fill in a tile feature attributre
@@ -35,25 +36,39 @@ var QueryRenderRules = RenderRules{
 
 const QueryRendererMaxFeaturesPerTile = 10000
 
-func NewQueryRenderer(worlds ingest.Worlds, cores int) *QueryRenderer {
+func NewQueryRenderer(worlds ingest.Worlds, options api.Options) *QueryRenderer {
 	return &QueryRenderer{
-		rules:  QueryRenderRules,
-		worlds: worlds,
-		cores:  cores,
-		fs:     functions.Functions(),
-		a:      functions.Adaptors(),
+		rules:   QueryRenderRules,
+		worlds:  worlds,
+		options: options,
+		fs:      functions.Functions(),
+		a:       functions.Adaptors(),
 	}
 }
 
-func (r *QueryRenderer) Render(tile b6.Tile, args *TileArgs) (*Tile, error) {
-	w := r.worlds.FindOrCreateWorld(args.R)
+// Render evaluates the query in args with the permissions carried by ctx,
+// such that, as with api.Evaluator, it can't modify or create worlds the
+// token isn't allowed to.
+func (r *QueryRenderer) Render(ctx context.Context, tile b6.Tile, args *TileArgs) (*Tile, error) {
+	root := args.R
+	if !root.IsValid() {
+		root = ingest.DefaultWorldFeatureID
+	}
+	permissions := auth.FromContext(ctx)
+	worlds := permissions.RestrictWorlds(r.worlds)
+	w := worlds.FindOrCreateWorld(root)
 	context := api.Context{
 		World:           w,
+		WorldID:         root,
+		Worlds:          worlds,
 		FunctionSymbols: r.fs,
 		Adaptors:        r.a,
-		Cores:           r.cores,
-		Context:         context.Background(),
+		Context:         ctx,
 	}
+	context.FillFromOptions(&r.options)
+	// Expressions in tile URLs have never needed file IO, so don't inherit
+	// the server's setting, whatever the permissions of the token used.
+	context.FileIOAllowed = false
 	v, err := api.EvaluateString(args.Q, &context)
 	if err != nil {
 		return nil, err
@@ -1,9 +1,13 @@
 package renderer
 
 import (
+	"context"
+	"strings"
 	"testing"
 
 	"diagonal.works/b6"
+	"diagonal.works/b6/api"
+	"diagonal.works/b6/auth"
 	"diagonal.works/b6/ingest"
 	"diagonal.works/b6/test/camden"
 	"github.com/golang/geo/s2"
@@ -15,9 +19,9 @@ func TestQueryRenderer(t *testing.T) {
 	}
 
 	projection := b6.NewTileMercatorProjection(16)
-	r := NewQueryRenderer(w, 2)
+	r := NewQueryRenderer(w, api.Options{Cores: 2})
 	args := &TileArgs{Q: "[#amenity=cafe]"}
-	tile, err := r.Render(projection.TileFromLatLng(s2.LatLngFromDegrees(51.53531, -0.12434)), args)
+	tile, err := r.Render(context.Background(), projection.TileFromLatLng(s2.LatLngFromDegrees(51.53531, -0.12434)), args)
 	if err != nil {
 		t.Fatalf("Expected no error, found: %s", err)
 	}
@@ -44,9 +48,9 @@ func TestQueryRendererWithValueFunction(t *testing.T) {
 	}
 
 	projection := b6.NewTileMercatorProjection(16)
-	r := NewQueryRenderer(w, 2)
+	r := NewQueryRenderer(w, api.Options{Cores: 2})
 	args := &TileArgs{Q: "[#amenity=cafe]", V: "get-string \"cuisine\""}
-	tile, err := r.Render(projection.TileFromLatLng(s2.LatLngFromDegrees(51.53531, -0.12434)), args)
+	tile, err := r.Render(context.Background(), projection.TileFromLatLng(s2.LatLngFromDegrees(51.53531, -0.12434)), args)
 	if err != nil {
 		t.Fatalf("Expected no error, found: %s", err)
 	}
@@ -66,3 +70,44 @@ func TestQueryRendererWithValueFunction(t *testing.T) {
 		t.Errorf("Expected at least 4 cuisines for features in layer, got %d", len(cuisines))
 	}
 }
+
+func TestQueryRendererDisallowsFileIO(t *testing.T) {
+	w := &ingest.MutableWorlds{
+		Base: camden.BuildGranarySquareForTests(t),
+	}
+
+	projection := b6.NewTileMercatorProjection(16)
+	r := NewQueryRenderer(w, api.Options{Cores: 2, FileIOAllowed: true})
+	args := &TileArgs{Q: "parse-geojson-file \"/dev/null\""}
+	_, err := r.Render(context.Background(), projection.TileFromLatLng(s2.LatLngFromDegrees(51.53531, -0.12434)), args)
+	if err == nil || !strings.Contains(err.Error(), "File IO is not allowed") {
+		t.Errorf("Expected an error using file IO from a tile query, found %v", err)
+	}
+}
+
+func TestQueryRendererEnforcesPermissions(t *testing.T) {
+	w := &ingest.MutableWorlds{
+		Base: camden.BuildGranarySquareForTests(t),
+	}
+
+	projection := b6.NewTileMercatorProjection(16)
+	r := NewQueryRenderer(w, api.Options{Cores: 2, FileIOAllowed: true})
+	ctx := auth.NewContext(context.Background(), &auth.Permissions{Subject: "test", ReadOnly: true})
+	missing := b6.FeatureID{Type: b6.FeatureTypeCollection, Namespace: ingest.DefaultWorldFeatureID.Namespace, Value: 42}
+	tile := projection.TileFromLatLng(s2.LatLngFromDegrees(51.53531, -0.12434))
+
+	args := &TileArgs{Q: "parse-geojson-file \"/dev/null\"", R: missing}
+	if _, err := r.Render(ctx, tile, args); err == nil || !strings.Contains(err.Error(), "File IO is not allowed") {
+		t.Errorf("Expected an error using file IO from a tile query, found %v", err)
+	}
+
+	args = &TileArgs{Q: "[#amenity=cafe]", R: missing}
+	if rendered, err := r.Render(ctx, tile, args); err != nil {
+		t.Errorf("Expected no error, found: %s", err)
+	} else if len(rendered.Layers) != 1 || len(rendered.Layers[0].Features) == 0 {
+		t.Error("Expected features from the base world")
+	}
+	if _, err := w.WorldMetadata(missing); err == nil {
+		t.Error("Expected a read-only token not to create the world")
+	}
+}
@@ -0,0 +1,296 @@
+package renderer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image/color"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/ingest"
+	"diagonal.works/b6/raster"
+)
+
+// RasterStyle returns the style with which to draw a feature from the
+// given layer of a tile into an image at the given zoom level, or false
+// if the feature shouldn't be drawn.
+type RasterStyle func(layer string, f *Feature, zoom float64) (raster.Style, bool)
+
+func hexColour(s string) color.RGBA {
+	c, _ := raster.ColourFromHexString(s)
+	return c
+}
+
+// Colours follow those of the default map style used by the frontend.
+var (
+	RasterBackgroundColour = hexColour("#eceff8")
+
+	rasterOutlineColour = hexColour("#4f5a7d")
+	rasterRoadColour    = hexColour("#9aa4cc")
+	rasterWaterColour   = hexColour("#b3bfe5")
+	rasterContourColour = hexColour("#e1e1ed")
+	rasterQueryColour   = hexColour("#ff0000")
+	rasterGreenColour   = hexColour("#e1e1ee")
+	rasterNatureColour  = hexColour("#dbdeeb")
+	rasterUrbanColour   = hexColour("#c5cadd")
+	rasterWhiteColour   = hexColour("#ffffff")
+
+	// Colours for integer values of b6:colour, matching the frontend.
+	rasterPalette = []color.RGBA{
+		hexColour("#c7e3f1"),
+		hexColour("#def2f6"),
+		hexColour("#d6f5e6"),
+		hexColour("#f4fde5"),
+	}
+)
+
+// Relative widths of roads, by highway tag.
+var rasterRoadWidths = map[string]float64{
+	"motorway":     1.5,
+	"trunk":        1.5,
+	"primary":      1.2,
+	"secondary":    1.1,
+	"tertiary":     1.1,
+	"street":       1.1,
+	"unclassified": 1.0,
+	"residential":  1.0,
+	"service":      1.0,
+	"cycleway":     0.5,
+	"footway":      0.5,
+	"path":         0.5,
+}
+
+// rasterRoadWidth returns the width of a road in pixels, interpolating
+// exponentially between widths at zoom levels 12 and 20.
+func rasterRoadWidth(highway string, zoom float64) float64 {
+	scale, ok := rasterRoadWidths[highway]
+	if !ok {
+		scale = 1.0
+	}
+	zoom = math.Max(12, math.Min(20, zoom))
+	t := (math.Exp2(zoom-12) - 1) / (math.Exp2(20-12) - 1)
+	lo, hi := math.Exp2(1.2), math.Exp2(5.5)
+	return scale * (lo + t*(hi-lo))
+}
+
+// rasterColourFromTags returns the colour given by a feature's b6:colour
+// tag, which is either a hex colour, or an index into a palette.
+func rasterColourFromTags(tags map[string]string) (color.RGBA, bool) {
+	v, ok := tags[b6.ColourTag]
+	if !ok {
+		return color.RGBA{}, false
+	}
+	if c, ok := raster.ColourFromHexString(v); ok {
+		return c, true
+	} else if i, err := strconv.Atoi(v); err == nil && i >= 0 {
+		return rasterPalette[i%len(rasterPalette)], true
+	}
+	return color.RGBA{}, false
+}
+
+// DefaultRasterStyle draws the layers of the basemap, query and
+// collection tiles in the style of the frontend's default map. Labels and
+// icons aren't drawn.
+func DefaultRasterStyle(layer string, f *Feature, zoom float64) (raster.Style, bool) {
+	var style raster.Style
+	switch layer {
+	case BasemapLayerBoundary.String():
+		if f.Tags["natural"] != "coastline" {
+			return style, false
+		}
+		style = raster.Style{Stroke: rasterOutlineColour, StrokeWidth: 2.3}
+	case BasemapLayerContour.String():
+		style = raster.Style{Stroke: rasterContourColour, StrokeWidth: 1}
+	case BasemapLayerWater.String():
+		style = raster.Style{Fill: rasterWaterColour, Stroke: rasterWaterColour}
+		if _, ok := f.Geometry.(*LineString); ok {
+			style.StrokeWidth = 2
+		}
+	case BasemapLayerRoad.String():
+		if _, ok := f.Tags["railway"]; ok {
+			style = raster.Style{Stroke: rasterRoadColour, StrokeWidth: 2}
+		} else {
+			style = raster.Style{Stroke: rasterRoadColour, StrokeWidth: rasterRoadWidth(f.Tags["highway"], zoom)}
+		}
+	case BasemapLayerLandUse.String():
+		switch f.Tags["landuse"] {
+		case "meadow", "heath":
+			style.Fill = rasterNatureColour
+		case "forest", "commercial", "residential", "industrial":
+			style.Fill = rasterUrbanColour
+		default:
+			style.Fill = rasterGreenColour
+		}
+	case BasemapLayerBuilding.String():
+		style = raster.Style{Fill: rasterWhiteColour, Stroke: rasterOutlineColour, StrokeWidth: 0.33}
+	case BasemapLayerPoint.String(), BasemapLayerLabel.String(), BasemapLayerAmenity.String():
+		return style, false
+	default:
+		style = raster.Style{Fill: rasterQueryColour, Stroke: rasterOutlineColour, StrokeWidth: 1, PointRadius: 4}
+		if _, ok := f.Geometry.(*LineString); ok {
+			style.Stroke = rasterQueryColour
+			style.StrokeWidth = 2
+		}
+	}
+	if c, ok := rasterColourFromTags(f.Tags); ok {
+		if style.Fill != nil {
+			style.Fill = c
+		} else {
+			style.Stroke = c
+		}
+	}
+	return style, true
+}
+
+func drawFeature(c *raster.Canvas, f *Feature, style *raster.Style) {
+	switch g := f.Geometry.(type) {
+	case *Point:
+		c.DrawPoint(g.ToS2Point(), style)
+	case *LineString:
+		c.DrawPolyline(g.ToS2Polyline(), style)
+	case *Polygon:
+		c.DrawPolygon(g.ToS2Polygon(), style)
+	}
+}
+
+// The maximum zoom level at which tiles are rendered for images.
+const rasterMaxZoom = 20
+
+// RasterZoom returns the zoom level of the tiles to render for drawing
+// onto the given canvas.
+func RasterZoom(c *raster.Canvas) uint {
+	return uint(math.Max(0, math.Min(rasterMaxZoom, math.Round(c.Zoom()))))
+}
+
+// DrawTiles draws the given tiles onto the canvas, drawing the layers
+// of all tiles in order, so features from earlier layers in one tile
+// aren't drawn over those of later layers in another. Features that
+// appear in multiple tiles are drawn once.
+func DrawTiles(c *raster.Canvas, tiles []*Tile, style RasterStyle) {
+	type drawn struct {
+		layer string
+		id    uint64
+	}
+	var names []string
+	layers := make(map[string][]*Layer)
+	for _, tile := range tiles {
+		for _, layer := range tile.Layers {
+			if _, ok := layers[layer.Name]; !ok {
+				names = append(names, layer.Name)
+			}
+			layers[layer.Name] = append(layers[layer.Name], layer)
+		}
+	}
+	zoom := c.Zoom()
+	seen := make(map[drawn]struct{})
+	for _, name := range names {
+		for _, layer := range layers[name] {
+			for _, f := range layer.Features {
+				if f.ID != 0 {
+					key := drawn{layer: name, id: f.ID}
+					if _, ok := seen[key]; ok {
+						continue
+					}
+					seen[key] = struct{}{}
+				}
+				if s, ok := style(name, f, zoom); ok {
+					drawFeature(c, f, &s)
+				}
+			}
+		}
+	}
+}
+
+// RenderTiles renders the tiles that cover the canvas with each of the
+// given renderers in turn, and draws them onto it.
+func RenderTiles(ctx context.Context, c *raster.Canvas, renderers []Renderer, args []*TileArgs, style RasterStyle) error {
+	z := RasterZoom(c)
+	min, max := TileRange(c.Bounds(), z)
+	for i, r := range renderers {
+		tiles := make([]*Tile, 0, (max.X-min.X+1)*(max.Y-min.Y+1))
+		for x := min.X; x <= max.X; x++ {
+			for y := min.Y; y <= max.Y; y++ {
+				tile, err := r.Render(ctx, b6.Tile{X: x, Y: y, Z: z}, args[i])
+				if err != nil {
+					return err
+				}
+				tiles = append(tiles, tile)
+			}
+		}
+		DrawTiles(c, tiles, style)
+	}
+	return nil
+}
+
+const (
+	RasterDefaultWidth  = 1024
+	RasterDefaultHeight = 768
+)
+
+// RasterHandler serves PNG images of the basemap for the bounds given by
+// the bounds parameter, as lat,lng,lat,lng, optionally highlighting the
+// features matching the query given by the q parameter. The w and h
+// parameters give the size of the image.
+type RasterHandler struct {
+	Basemap Renderer
+	Query   Renderer
+	Style   RasterStyle
+}
+
+func (h *RasterHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	bounds, err := ingest.ParseBoundingBox(query.Get("bounds"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Bad bounds: %s", err), http.StatusBadRequest)
+		return
+	} else if bounds.IsFull() {
+		http.Error(w, "Expected bounds", http.StatusBadRequest)
+		return
+	}
+	width, height := RasterDefaultWidth, RasterDefaultHeight
+	for _, param := range []struct {
+		name  string
+		value *int
+	}{{"w", &width}, {"h", &height}} {
+		if v := query.Get(param.name); v != "" {
+			if *param.value, err = strconv.Atoi(v); err != nil {
+				http.Error(w, fmt.Sprintf("Bad %s: %s", param.name, err), http.StatusBadRequest)
+				return
+			}
+		}
+	}
+	c, err := raster.NewCanvas(bounds, width, height, 0, RasterBackgroundColour)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	root := b6.FeatureIDFromString(query.Get("r"))
+	renderers := []Renderer{h.Basemap}
+	args := []*TileArgs{{R: root}}
+	if q := query.Get("q"); q != "" && h.Query != nil {
+		renderers = append(renderers, h.Query)
+		args = append(args, &TileArgs{Q: q, V: query.Get("v"), R: root})
+	}
+	style := h.Style
+	if style == nil {
+		style = DefaultRasterStyle
+	}
+	if err := RenderTiles(r.Context(), c, renderers, args, style); err != nil {
+		log.Printf("Failed to render image: %v", err)
+		http.Error(w, "Failed to render image", http.StatusInternalServerError)
+		return
+	}
+	var buffer bytes.Buffer
+	if err := c.EncodePNG(&buffer); err != nil {
+		log.Printf("Failed to encode image: %v", err)
+		http.Error(w, "Failed to encode image", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Add("Access-Control-Allow-Origin", "*")
+	w.Write(buffer.Bytes())
+}
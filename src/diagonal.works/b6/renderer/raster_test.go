@@ -0,0 +1,82 @@
+package renderer
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"diagonal.works/b6/api"
+	"diagonal.works/b6/ingest"
+	"diagonal.works/b6/raster"
+	"diagonal.works/b6/test/camden"
+
+	"github.com/golang/geo/s2"
+)
+
+func renderTestImage(t *testing.T, h http.Handler, url string) image.Image {
+	t.Helper()
+	request := httptest.NewRequest("GET", url, nil)
+	response := httptest.NewRecorder()
+	h.ServeHTTP(response, request)
+	if response.Code != http.StatusOK {
+		t.Fatalf("Expected status OK, found %d: %s", response.Code, response.Body.String())
+	}
+	if contentType := response.Header().Get("Content-Type"); contentType != "image/png" {
+		t.Fatalf("Expected a PNG, found %q", contentType)
+	}
+	decoded, err := png.Decode(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return decoded
+}
+
+func TestRasterHandler(t *testing.T) {
+	worlds := &ingest.MutableWorlds{Base: camden.BuildGranarySquareForTests(t)}
+	h := &RasterHandler{
+		Basemap: &BasemapRenderer{RenderRules: BasemapRenderRules, Worlds: worlds},
+		Query:   NewQueryRenderer(worlds, api.Options{}),
+	}
+
+	// A small area centered on The Lighterman.
+	const bounds = "51.53510,-0.12470,51.53550,-0.12400"
+	lighterman := s2.LatLngFromDegrees(51.53531, -0.12434)
+	decoded := renderTestImage(t, h, "/render.png?bounds="+bounds+"&w=400&h=300")
+	if size := decoded.Bounds().Size(); size.X != 400 || size.Y != 300 {
+		t.Errorf("Expected a 400x300 image, found %dx%d", size.X, size.Y)
+	}
+	c, err := raster.NewCanvas(s2.RectFromLatLng(s2.LatLngFromDegrees(51.53510, -0.12470)).AddPoint(s2.LatLngFromDegrees(51.53550, -0.12400)), 400, 300, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := c.ToPixel(s2.PointFromLatLng(lighterman))
+	x, y := int(p.X), int(p.Y)
+	if found := color.RGBAModel.Convert(decoded.At(x, y)); found != rasterWhiteColour {
+		t.Errorf("Expected The Lighterman to be drawn as a building, found %v", found)
+	}
+
+	decoded = renderTestImage(t, h, "/render.png?bounds="+bounds+"&w=400&h=300&q=%28keyed+%22%23building%22%29")
+	if found := color.RGBAModel.Convert(decoded.At(x, y)); found != rasterQueryColour {
+		t.Errorf("Expected The Lighterman to be highlighted by the query, found %v", found)
+	}
+}
+
+func TestRasterHandlerRejectsBadArguments(t *testing.T) {
+	worlds := &ingest.MutableWorlds{Base: camden.BuildGranarySquareForTests(t)}
+	h := &RasterHandler{Basemap: &BasemapRenderer{RenderRules: BasemapRenderRules, Worlds: worlds}}
+	for _, url := range []string{
+		"/render.png",
+		"/render.png?bounds=51.5,-0.1",
+		"/render.png?bounds=51.53510,-0.12470,51.53550,-0.12400&w=100000",
+		"/render.png?bounds=51.53510,-0.12470,51.53550,-0.12400&h=tall",
+	} {
+		response := httptest.NewRecorder()
+		h.ServeHTTP(response, httptest.NewRequest("GET", url, nil))
+		if response.Code != http.StatusBadRequest {
+			t.Errorf("Expected status bad request for %q, found %d", url, response.Code)
+		}
+	}
+}
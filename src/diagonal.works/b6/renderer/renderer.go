@@ -1,9 +1,11 @@
 package renderer
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strconv"
+	"sync"
 
 	"diagonal.works/b6"
 	"diagonal.works/b6/api"
@@ -12,7 +14,9 @@ import (
 )
 
 type Renderer interface {
-	Render(tile b6.Tile, args *TileArgs) (*Tile, error)
+	// Render returns the content of the given tile. ctx carries the
+	// permissions of the request, see auth.FromContext.
+	Render(ctx context.Context, tile b6.Tile, args *TileArgs) (*Tile, error)
 }
 
 type byLayerThenID []b6.Feature
@@ -107,26 +111,81 @@ func NewLayers() *BasemapLayers {
 }
 
 type RenderRule struct {
-	Tag     b6.Tag
-	MinZoom uint `yaml:"min,omitempty"`
-	MaxZoom uint `yaml:"max,omitempty"`
+	Tag b6.Tag
+	// Query, if set, is an expression returning a query for the features
+	// rendered by the rule, used in place of Tag, though the value of
+	// Tag's key is still added to the rendered features. Rules that
+	// haven't been compiled are compiled when first used.
+	Query   string
+	MinZoom uint
+	MaxZoom uint
 	Layer   BasemapLayer
-	Label   bool `yaml:",omitempty"`
-	Icon    bool `yaml:",omitempty"`
+	// CustomLayer, if set, is the name of a layer to which features are
+	// added, in place of Layer, allowing rules to add layers beyond the
+	// built in basemap layers.
+	CustomLayer string
+	Label       bool
+	Icon        bool
+	// Attributes are the keys of tags whose values are added to rendered
+	// features.
+	Attributes []string
+	Simplify   []SimplifyRule
+
+	query b6.Query
 }
 
-func (r *RenderRule) ToQuery(zoom uint) (b6.Query, bool) {
+type compiledQuery struct {
+	q   b6.Query
+	err error
+}
+
+// compiledQueries holds the queries compiled from the expressions of rules
+// that weren't compiled before use, like those built in Go, by expression.
+var compiledQueries sync.Map
+
+// compiledQuery returns the query given by the rule's Query expression,
+// compiling it if necessary, or nil if the rule doesn't have one.
+func (r *RenderRule) compiledQuery() (b6.Query, error) {
+	if r.query != nil || r.Query == "" {
+		return r.query, nil
+	}
+	if c, ok := compiledQueries.Load(r.Query); ok {
+		return c.(compiledQuery).q, c.(compiledQuery).err
+	}
+	q, err := compileQuery(r.Query)
+	compiledQueries.Store(r.Query, compiledQuery{q: q, err: err})
+	return q, err
+}
+
+// ToQuery returns a query for the features rendered by the rule at the
+// given zoom level, or false if it doesn't render features at that level.
+func (r *RenderRule) ToQuery(zoom uint) (b6.Query, bool, error) {
 	if (r.MinZoom > 0 && zoom < r.MinZoom) || (r.MaxZoom > 0 && zoom > r.MaxZoom) {
-		return nil, false
+		return nil, false, nil
 	}
-	if r.Tag.IsValid() && r.Tag.Value.String() != "" {
-		return b6.Tagged(r.Tag), true
+	if q, err := r.compiledQuery(); err != nil {
+		return nil, false, err
+	} else if q != nil {
+		return q, true, nil
+	} else if r.Tag.IsValid() && r.Tag.Value.String() != "" {
+		return b6.Tagged(r.Tag), true, nil
 	} else {
-		return b6.Keyed{Key: r.Tag.Key}, true
+		return b6.Keyed{Key: r.Tag.Key}, true, nil
 	}
 }
 
-func (r *RenderRule) Matches(f b6.Taggable) (b6.Expression, bool) {
+// Matches returns true if the given feature is rendered by the rule,
+// together with the value of the rule's tag for the feature. Rules with
+// queries that fail to compile match nothing.
+func (r *RenderRule) Matches(f b6.Feature, w b6.World) (b6.Expression, bool) {
+	if q, err := r.compiledQuery(); err != nil {
+		return b6.Expression{}, false
+	} else if q != nil {
+		if !q.Matches(f, w) {
+			return b6.Expression{}, false
+		}
+		return f.Get(r.Tag.Key).Value, true
+	}
 	t := f.Get(r.Tag.Key)
 	if !t.IsValid() {
 		return b6.Expression{}, false
@@ -137,18 +196,39 @@ func (r *RenderRule) Matches(f b6.Taggable) (b6.Expression, bool) {
 
 type RenderRules []RenderRule
 
-func (rs RenderRules) ToQuery(zoom uint) b6.Query {
+func (rs RenderRules) ToQuery(zoom uint) (b6.Query, error) {
 	union := make(b6.Union, 0, len(rs))
 	for _, r := range rs {
-		if q, ok := r.ToQuery(zoom); ok {
+		if q, ok, err := r.ToQuery(zoom); err != nil {
+			return nil, err
+		} else if ok {
 			union = append(union, q)
 		}
 	}
-	return union
+	return union, nil
 }
 
+// IsRendered returns true if features with the given tag are rendered by
+// the rules. As it's not generally possible to determine the features
+// matched by a query, rules with queries are only considered if their
+// query is for a tag, or a key.
 func (rs RenderRules) IsRendered(tag b6.Tag) bool {
 	for _, r := range rs {
+		if q, err := r.compiledQuery(); err != nil {
+			continue
+		} else if q != nil {
+			switch q := q.(type) {
+			case b6.Keyed:
+				if q.Key == tag.Key {
+					return true
+				}
+			case b6.Tagged:
+				if q.Key == tag.Key && q.Value.String() == tag.Value.String() {
+					return true
+				}
+			}
+			continue
+		}
 		if r.Tag.Key == tag.Key {
 			if r.Tag.Value.AnyExpression == nil || r.Tag.Value.String() == "" || tag.Value.String() == "" || r.Tag.Value.String() == tag.Value.String() {
 				return true
@@ -158,10 +238,24 @@ func (rs RenderRules) IsRendered(tag b6.Tag) bool {
 	return false
 }
 
-func (rs RenderRules) AddTags(f b6.Feature, tags []b6.Tag) []b6.Tag {
+// AddTags adds the tag of the first rule matching the given feature to
+// tags, for renderers that don't use the rules to choose the features they
+// render.
+func (rs RenderRules) AddTags(f b6.Feature, w b6.World, tags []b6.Tag) []b6.Tag {
 	for _, rule := range rs {
+		if q, err := rule.compiledQuery(); err != nil {
+			continue
+		} else if q != nil {
+			if q.Matches(f, w) {
+				if t := f.Get(rule.Tag.Key); t.IsValid() {
+					tags = append(tags, b6.Tag{Key: tileTagKey(rule.Tag.Key), Value: t.Value})
+				}
+				break
+			}
+			continue
+		}
 		if t := f.Get(rule.Tag.Key); t.IsValid() && t.Value == rule.Tag.Value {
-			tags = append(tags, b6.Tag{Key: rule.Tag.Key[1:], Value: t.Value})
+			tags = append(tags, b6.Tag{Key: tileTagKey(rule.Tag.Key), Value: t.Value})
 			break
 		}
 	}
@@ -213,32 +307,49 @@ type BasemapRenderer struct {
 	Worlds      ingest.Worlds
 }
 
-func (b *BasemapRenderer) Render(tile b6.Tile, args *TileArgs) (*Tile, error) {
-	features := b.findFeatures(args.R, tile)
-	layers := NewLayers()
+func (b *BasemapRenderer) Render(ctx context.Context, tile b6.Tile, args *TileArgs) (*Tile, error) {
+	features, err := b.findFeatures(args.R, tile)
+	if err != nil {
+		return nil, err
+	}
+	layers := b.RenderRules.newLayers()
 	fs := make([]*Feature, 0, 2)
 	for _, feature := range features {
-		fs = b.renderFeature(args.R, feature, layers, fs[0:0])
+		fs = b.renderFeature(args.R, tile.Z, feature, layers, fs[0:0])
 	}
-	return &Tile{Layers: (*layers)[0:]}, nil
+	return &Tile{Layers: layers.all}, nil
 }
 
-func (b *BasemapRenderer) findFeatures(root b6.FeatureID, tile b6.Tile) []b6.Feature { // TODO: rename root to smth more sensible / like world
+func (b *BasemapRenderer) findFeatures(root b6.FeatureID, tile b6.Tile) ([]b6.Feature, error) { // TODO: rename root to smth more sensible / like world
 	bounds := tile.RectBound()
 	regionQuery := b6.MightIntersect{Region: bounds}
-	q := b6.Intersection{b.RenderRules.ToQuery(tile.Z), regionQuery}
+	rules, err := b.RenderRules.ToQuery(tile.Z)
+	if err != nil {
+		return nil, err
+	}
+	q := b6.Intersection{rules, regionQuery}
 	features := b6.AllFeatures(b.Worlds.FindOrCreateWorld(root).FindFeatures(q))
 	sort.Sort(byLayerThenID(features))
-	return features
+	return features, nil
 }
 
-func (b *BasemapRenderer) renderFeature(root b6.FeatureID, f b6.Feature, layers *BasemapLayers, fs []*Feature) []*Feature {
+func (b *BasemapRenderer) renderFeature(root b6.FeatureID, zoom uint, f b6.Feature, layers *basemapTileLayers, fs []*Feature) []*Feature {
 	var tags [1]b6.Tag
+	w := b.Worlds.FindOrCreateWorld(root)
 	for _, rule := range b.RenderRules {
-		if v, ok := rule.Matches(f); ok {
-			tags[0] = b6.Tag{Key: rule.Tag.Key[1:], Value: v}
-			fs = FillFeaturesFromFeature(f, tags[0:], fs, &rule, b.Worlds.FindOrCreateWorld(root))
-			layers[rule.Layer].AddFeatures(fs)
+		if v, ok := rule.Matches(f, w); ok {
+			n := 0
+			if rule.Tag.Key != "" && v.AnyExpression != nil {
+				tags[0] = b6.Tag{Key: tileTagKey(rule.Tag.Key), Value: v}
+				n = 1
+			}
+			fs = FillFeaturesFromFeature(f, tags[0:n], fs, &rule, w)
+			if tolerance := rule.simplifyTolerance(zoom); tolerance > 0 {
+				for _, tf := range fs {
+					tf.Simplify = tolerance
+				}
+			}
+			layers.find(&rule).AddFeatures(fs)
 			break
 		}
 	}
@@ -346,6 +457,15 @@ func fillTagsFromTags(tf *Feature, f b6.Feature, rule *RenderRule) {
 	}
 	fillColourFromFeature(tf, f)
 	fillIDFromFeature(tf, f)
+	fillAttributesFromFeature(tf, f, rule)
+}
+
+func fillAttributesFromFeature(tf *Feature, f b6.Feature, rule *RenderRule) {
+	for _, key := range rule.Attributes {
+		if t := f.Get(key); t.IsValid() {
+			tf.Tags[tileTagKey(key)] = t.Value.String()
+		}
+	}
 }
 
 func fillTagsFromIcon(tf *Feature, f b6.Feature, rule *RenderRule) {
@@ -1,6 +1,7 @@
 package renderer
 
 import (
+	"context"
 	"strconv"
 	"testing"
 
@@ -45,7 +46,7 @@ func TestFeaturesHaveTagsForNamespaceAndID(t *testing.T) {
 
 	projection := b6.NewTileMercatorProjection(16)
 	r := BasemapRenderer{RenderRules: BasemapRenderRules, Worlds: w}
-	tile, err := r.Render(projection.TileFromLatLng(s2.LatLngFromDegrees(51.53531, -0.12434)), &TileArgs{})
+	tile, err := r.Render(context.Background(), projection.TileFromLatLng(s2.LatLngFromDegrees(51.53531, -0.12434)), &TileArgs{})
 	if err != nil {
 		t.Fatalf("Expected no error, found: %s", err)
 	}
@@ -99,7 +100,7 @@ func TestFeaturesAreOrderedByLayerTag(t *testing.T) {
 
 	projection := b6.NewTileMercatorProjection(16)
 	r := BasemapRenderer{RenderRules: BasemapRenderRules, Worlds: w}
-	tile, err := r.Render(projection.TileFromLatLng(s2.LatLngFromDegrees(51.53531, -0.12434)), &TileArgs{})
+	tile, err := r.Render(context.Background(), projection.TileFromLatLng(s2.LatLngFromDegrees(51.53531, -0.12434)), &TileArgs{})
 	if err != nil {
 		t.Fatalf("Expected no error, found: %s", err)
 	}
@@ -0,0 +1,205 @@
+package renderer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/api"
+	"diagonal.works/b6/api/functions"
+	"diagonal.works/b6/ingest"
+
+	"gopkg.in/yaml.v2"
+)
+
+// SimplifyRule gives the tolerance used to simplify the geometry of
+// features at zoom levels up to MaxZoom, or at all zoom levels if MaxZoom
+// is 0. The tolerance is in tile units, of which there are 4096 across a
+// tile.
+type SimplifyRule struct {
+	MaxZoom   uint `yaml:"max,omitempty"`
+	Tolerance float64
+}
+
+// renderRuleYAML is the representation of a RenderRule in a rules file.
+// Tags are given as "#key" or "#key=value", and layers by name, with
+// names other than those of the built in basemap layers creating custom
+// layers.
+type renderRuleYAML struct {
+	Tag        string         `yaml:",omitempty"`
+	Query      string         `yaml:",omitempty"`
+	MinZoom    uint           `yaml:"min,omitempty"`
+	MaxZoom    uint           `yaml:"max,omitempty"`
+	Layer      string         `yaml:",omitempty"`
+	Label      bool           `yaml:",omitempty"`
+	Icon       bool           `yaml:",omitempty"`
+	Attributes []string       `yaml:",omitempty"`
+	Simplify   []SimplifyRule `yaml:",omitempty"`
+}
+
+func (r RenderRule) MarshalYAML() (interface{}, error) {
+	y := renderRuleYAML{
+		Query:      r.Query,
+		MinZoom:    r.MinZoom,
+		MaxZoom:    r.MaxZoom,
+		Layer:      r.LayerName(),
+		Label:      r.Label,
+		Icon:       r.Icon,
+		Attributes: r.Attributes,
+		Simplify:   r.Simplify,
+	}
+	if r.Tag.IsValid() && r.Tag.Value.String() != "" {
+		y.Tag = r.Tag.Key + "=" + r.Tag.Value.String()
+	} else {
+		y.Tag = r.Tag.Key
+	}
+	return y, nil
+}
+
+func (r *RenderRule) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var y renderRuleYAML
+	if err := unmarshal(&y); err != nil {
+		return err
+	}
+	*r = RenderRule{
+		Query:      y.Query,
+		MinZoom:    y.MinZoom,
+		MaxZoom:    y.MaxZoom,
+		Label:      y.Label,
+		Icon:       y.Icon,
+		Attributes: y.Attributes,
+		Simplify:   y.Simplify,
+	}
+	if key, value, ok := strings.Cut(y.Tag, "="); ok {
+		r.Tag = b6.Tag{Key: key, Value: b6.NewStringExpression(value)}
+	} else {
+		r.Tag = b6.Tag{Key: y.Tag}
+	}
+	if y.Tag == "" && y.Query == "" {
+		return fmt.Errorf("rule needs either a tag or a query")
+	}
+	if y.Layer == "" {
+		return fmt.Errorf("rule for %q needs a layer", y.Tag+y.Query)
+	}
+	r.Layer = BasemapLayerInvalid
+	for l := BasemapLayerBegin; l < BasemapLayerEnd; l++ {
+		if l.String() == y.Layer {
+			r.Layer = l
+			break
+		}
+	}
+	if r.Layer == BasemapLayerInvalid {
+		r.CustomLayer = y.Layer
+	}
+	return r.Compile()
+}
+
+// Compile evaluates the rule's query expression, if it has one, reporting
+// any errors. Rules read from YAML are compiled automatically, while
+// others are compiled when first used.
+func (r *RenderRule) Compile() error {
+	if r.Query == "" {
+		r.query = nil
+		return nil
+	}
+	q, err := compileQuery(r.Query)
+	if err != nil {
+		return err
+	}
+	r.query = q
+	return nil
+}
+
+func compileQuery(expression string) (b6.Query, error) {
+	context := api.Context{
+		World:           ingest.NewBasicMutableWorld(),
+		FunctionSymbols: functions.Functions(),
+		Adaptors:        functions.Adaptors(),
+		Context:         context.Background(),
+	}
+	v, err := api.EvaluateString(expression, &context)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", expression, err)
+	}
+	q, ok := v.(b6.Query)
+	if !ok {
+		return nil, fmt.Errorf("%q: expected a query, found %T", expression, v)
+	}
+	return q, nil
+}
+
+// LayerName returns the name of the layer to which the rule's features
+// are added.
+func (r *RenderRule) LayerName() string {
+	if r.CustomLayer != "" {
+		return r.CustomLayer
+	}
+	return r.Layer.String()
+}
+
+// simplifyTolerance returns the tolerance with which to simplify features
+// rendered at the given zoom level, or 0 if they shouldn't be simplified.
+func (r *RenderRule) simplifyTolerance(zoom uint) float64 {
+	for _, s := range r.Simplify {
+		if s.MaxZoom == 0 || zoom <= s.MaxZoom {
+			return s.Tolerance
+		}
+	}
+	return 0
+}
+
+// ReadRenderRules returns the rules in the given YAML file, which holds a
+// list of rules, in priority order.
+func ReadRenderRules(filename string) (RenderRules, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var rules RenderRules
+	if err := yaml.UnmarshalStrict(data, &rules); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return rules, nil
+}
+
+// tileTagKey returns the key used in tiles for the given tag key, without
+// the prefix indicating whether it's searchable.
+func tileTagKey(key string) string {
+	if strings.HasPrefix(key, "#") || strings.HasPrefix(key, "@") {
+		return key[1:]
+	}
+	return key
+}
+
+// basemapTileLayers holds the layers of a basemap tile, ordered with the
+// built in layers first, followed by any custom layers in the order in
+// which they're first used by the rules.
+type basemapTileLayers struct {
+	builtin *BasemapLayers
+	custom  map[string]*Layer
+	all     []*Layer
+}
+
+func (rs RenderRules) newLayers() *basemapTileLayers {
+	l := &basemapTileLayers{builtin: NewLayers(), custom: make(map[string]*Layer)}
+	l.all = append(l.all, (*l.builtin)[0:]...)
+	for _, rule := range rs {
+		if rule.CustomLayer != "" {
+			if _, ok := l.custom[rule.CustomLayer]; !ok {
+				layer := NewLayer(rule.CustomLayer)
+				l.custom[rule.CustomLayer] = layer
+				l.all = append(l.all, layer)
+			}
+		}
+	}
+	return l
+}
+
+func (l *basemapTileLayers) find(rule *RenderRule) *Layer {
+	if rule.CustomLayer != "" {
+		return l.custom[rule.CustomLayer]
+	}
+	return l.builtin[rule.Layer]
+}
@@ -0,0 +1,220 @@
+package renderer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"diagonal.works/b6"
+	"diagonal.works/b6/api"
+	"diagonal.works/b6/ingest"
+	"diagonal.works/b6/test/camden"
+
+	"github.com/golang/geo/s2"
+	"gopkg.in/yaml.v2"
+)
+
+const testRenderRules = `
+- tag: "#highway=primary"
+  min: 8
+  layer: road
+  label: true
+- query: or (keyed "#building") (keyed "#shop")
+  tag: "#building"
+  layer: named-building
+  attributes: [name, "#building:levels"]
+  simplify:
+    - {max: 14, tolerance: 16}
+    - {tolerance: 1}
+`
+
+func writeRenderRules(t *testing.T, rules string) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "rules.yaml")
+	if err := os.WriteFile(filename, []byte(rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestReadRenderRules(t *testing.T) {
+	rules, err := ReadRenderRules(writeRenderRules(t, testRenderRules))
+	if err != nil {
+		t.Fatalf("Expected no error, found: %s", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("Expected 2 rules, found %d", len(rules))
+	}
+	if rules[0].Tag.Key != "#highway" || rules[0].Tag.Value.String() != "primary" || rules[0].Layer != BasemapLayerRoad || !rules[0].Label {
+		t.Errorf("Unexpected first rule: %+v", rules[0])
+	}
+	if rules[1].LayerName() != "named-building" || rules[1].query == nil {
+		t.Errorf("Expected a compiled query for a custom layer, found %+v", rules[1])
+	}
+	for zoom, expected := range map[uint]float64{12: 16, 14: 16, 16: 1} {
+		if tolerance := rules[1].simplifyTolerance(zoom); tolerance != expected {
+			t.Errorf("Expected tolerance %f at zoom %d, found %f", expected, zoom, tolerance)
+		}
+	}
+
+	marshalled, err := yaml.Marshal(rules)
+	if err != nil {
+		t.Fatalf("Expected no error, found: %s", err)
+	}
+	var roundtripped RenderRules
+	if err := yaml.Unmarshal(marshalled, &roundtripped); err != nil {
+		t.Fatalf("Expected no error, found: %s", err)
+	}
+	if len(roundtripped) != 2 || roundtripped[0].Tag.String() != rules[0].Tag.String() || roundtripped[1].LayerName() != "named-building" {
+		t.Errorf("Expected rules to roundtrip, found %+v", roundtripped)
+	}
+}
+
+func TestReadInvalidRenderRules(t *testing.T) {
+	invalid := []string{
+		"- tag: \"#building\"\n",                               // No layer
+		"- layer: building\n",                                  // No tag or query
+		"- query: 'keyed \"#building\" 42'\n  layer: road\n",   // Bad query
+		"- query: 42\n  layer: road\n",                         // Not a query
+		"- tag: \"#building\"\n  layer: road\n  colour: red\n", // Unknown field
+	}
+	for _, rules := range invalid {
+		if _, err := ReadRenderRules(writeRenderRules(t, rules)); err == nil {
+			t.Errorf("Expected an error for %q", rules)
+		}
+	}
+}
+
+func TestBasemapRendererWithQueryAndCustomLayer(t *testing.T) {
+	rules, err := ReadRenderRules(writeRenderRules(t, testRenderRules))
+	if err != nil {
+		t.Fatalf("Expected no error, found: %s", err)
+	}
+	w := &ingest.MutableWorlds{Base: camden.BuildGranarySquareForTests(t)}
+	r := BasemapRenderer{RenderRules: rules, Worlds: w}
+	tile, err := r.Render(context.Background(), b6.NewTileMercatorProjection(16).TileFromLatLng(s2.LatLngFromDegrees(51.53531, -0.12434)), &TileArgs{})
+	if err != nil {
+		t.Fatalf("Expected no error, found: %s", err)
+	}
+	if tile.FindLayer("building") == nil || len(tile.FindLayer("building").Features) > 0 {
+		t.Error("Expected an empty building layer")
+	}
+	layer := tile.FindLayer("named-building")
+	if layer == nil {
+		t.Fatal("Expected to find custom layer")
+	}
+	if tile.Layers[len(tile.Layers)-1] != layer {
+		t.Error("Expected custom layer to follow built in layers")
+	}
+	found := false
+	for _, f := range layer.Features {
+		if f.Simplify != 1 {
+			t.Errorf("Expected simplify tolerance 1, found %f", f.Simplify)
+		}
+		if f.ID == api.TileFeatureIDForPolygon(camden.LightermanID.FeatureID(), 0) {
+			found = true
+			if f.Tags["name"] != "The Lighterman" || f.Tags["building"] == "" {
+				t.Errorf("Expected name and building tags, found %v", f.Tags)
+			}
+		}
+	}
+	if !found {
+		t.Error("Expected to find the Lighterman")
+	}
+
+	for _, layer := range rules.VectorLayers(8, 16) {
+		if layer.ID == "named-building" {
+			if _, ok := layer.Fields["building:levels"]; !ok {
+				t.Errorf("Expected attributes in fields, found %+v", layer.Fields)
+			}
+			return
+		}
+	}
+	t.Error("Expected custom layer in vector layers")
+}
+
+func TestEncodeTileWithSimplification(t *testing.T) {
+	projection := b6.NewTileMercatorProjection(16)
+	tile := projection.TileFromLatLng(s2.LatLngFromDegrees(51.53531, -0.12434))
+	bounds := tile.RectBound()
+	line := make(s2.Polyline, 0)
+	for i := 0; i <= 20; i++ {
+		// A slightly zigzagging line across the tile
+		f := float64(i) / 20.0
+		lat := bounds.Lo().Lat.Degrees() + (bounds.Hi().Lat.Degrees()-bounds.Lo().Lat.Degrees())*(0.5+float64(i%2)*0.0001)
+		lng := bounds.Lo().Lng.Degrees() + (bounds.Hi().Lng.Degrees()-bounds.Lo().Lng.Degrees())*f
+		line = append(line, s2.PointFromLatLng(s2.LatLngFromDegrees(lat, lng)))
+	}
+	encode := func(tolerance float64) int {
+		f := NewFeature(NewLineString(&line))
+		f.Simplify = tolerance
+		encoded := EncodeTile(tile, &Tile{Layers: []*Layer{{Name: "road", Features: []*Feature{f}}}})
+		return len(encoded.Layers[1].Features[0].Geometry)
+	}
+	if unsimplified, simplified := encode(0), encode(4); simplified >= unsimplified || simplified != 6 {
+		t.Errorf("Expected simplification to reduce the line to 2 points, found %d commands, from %d", simplified, unsimplified)
+	}
+}
+
+func TestUncompiledQueryRulesAreCompiledWhenUsed(t *testing.T) {
+	rules := RenderRules{
+		{Query: `keyed "#amenity"`, Tag: b6.Tag{Key: "#amenity"}, Layer: BasemapLayerAmenity},
+	}
+	w := &ingest.MutableWorlds{Base: camden.BuildGranarySquareForTests(t)}
+	r := BasemapRenderer{RenderRules: rules, Worlds: w}
+	tile, err := r.Render(context.Background(), b6.NewTileMercatorProjection(16).TileFromLatLng(s2.LatLngFromDegrees(51.53531, -0.12434)), &TileArgs{})
+	if err != nil {
+		t.Fatalf("Expected no error, found: %s", err)
+	}
+	layer := tile.FindLayer("amenity")
+	if layer == nil || len(layer.Features) == 0 {
+		t.Fatal("Expected features in amenity layer")
+	}
+	for _, f := range layer.Features {
+		if f.Tags["amenity"] == "" {
+			t.Errorf("Expected only amenities, found %v", f.Tags)
+		}
+	}
+
+	invalid := BasemapRenderer{RenderRules: RenderRules{{Query: "add-ints 1 2", Layer: BasemapLayerAmenity}}, Worlds: w}
+	if _, err := invalid.Render(context.Background(), b6.Tile{X: 32744, Y: 21784, Z: 16}, &TileArgs{}); err == nil {
+		t.Error("Expected an error for a rule whose query isn't a query")
+	}
+}
+
+func TestQueryRulesAreRespectedByIsRenderedAndAddTags(t *testing.T) {
+	rules := RenderRules{
+		{Query: `[#amenity=restaurant]`, Tag: b6.Tag{Key: "#amenity"}, Layer: BasemapLayerAmenity},
+		{Query: `keyed "#shop"`, Tag: b6.Tag{Key: "#building"}, Layer: BasemapLayerBuilding},
+	}
+	if !rules.IsRendered(b6.Tag{Key: "#amenity", Value: b6.NewStringExpression("restaurant")}) {
+		t.Error("Expected restaurants to be rendered")
+	}
+	if rules.IsRendered(b6.Tag{Key: "#amenity", Value: b6.NewStringExpression("cafe")}) {
+		t.Error("Expected cafes not to be rendered")
+	}
+	if !rules.IsRendered(b6.Tag{Key: "#shop", Value: b6.NewStringExpression("books")}) {
+		t.Error("Expected shops to be rendered")
+	}
+	if rules.IsRendered(b6.Tag{Key: "#building", Value: b6.NewStringExpression("yes")}) {
+		t.Error("Expected buildings not to be rendered, despite the rule's tag")
+	}
+
+	w := camden.BuildGranarySquareForTests(t)
+	restaurants := b6.AllFeatures(w.FindFeatures(b6.Tagged{Key: "#amenity", Value: b6.NewStringExpression("restaurant")}))
+	if len(restaurants) == 0 {
+		t.Fatal("Expected at least one restaurant")
+	}
+	if tags := rules.AddTags(restaurants[0], w, nil); len(tags) != 1 || tags[0].Key != "amenity" || tags[0].Value.String() != "restaurant" {
+		t.Errorf("Expected amenity tag, found %v", tags)
+	}
+	cafes := b6.AllFeatures(w.FindFeatures(b6.Tagged{Key: "#amenity", Value: b6.NewStringExpression("cafe")}))
+	for _, cafe := range cafes {
+		if _, ok := cafe.(b6.PhysicalFeature); ok && !cafe.Get("#shop").IsValid() {
+			if tags := rules.AddTags(cafe, w, nil); len(tags) != 0 {
+				t.Errorf("Expected no tags for a cafe, found %v", tags)
+			}
+		}
+	}
+}
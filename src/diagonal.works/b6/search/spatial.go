@@ -27,6 +27,12 @@ func ancestorCellIDToToken(cell s2.CellID) string {
 	return fmt.Sprintf("%s%s", s2AncestorCellIDTokenPrefix, cell.ToToken())
 }
 
+// IsSpatialToken returns true if the given token indexes features by their
+// location, rather than their tags.
+func IsSpatialToken(token string) bool {
+	return strings.HasPrefix(token, s2CellIDTokenPrefix) || strings.HasPrefix(token, s2AncestorCellIDTokenPrefix)
+}
+
 func MakeCoverer() s2.RegionCoverer {
 	return s2.RegionCoverer{MaxLevel: 16, MaxCells: 5}
 }
@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"diagonal.works/b6"
 	"diagonal.works/b6/api"
@@ -389,6 +390,25 @@ func fillSubstackFromError(substack *pb.SubstackProto, err error) {
 	})
 }
 
+func fillSubstackFromProfile(substack *pb.SubstackProto, profile *api.Profile, w b6.World) {
+	entries := profile.Entries()
+	substack.Lines = append(substack.Lines, leftRightValueLineFromValues("Profile", len(entries), w))
+	for i, e := range entries {
+		if i >= CollectionLineLimit {
+			break
+		}
+		call := e.Function
+		if unparsed, ok := api.UnparseExpression(e.Expression); ok {
+			call = unparsed
+		}
+		cost := fmt.Sprintf("%s, %d calls", e.Duration.Round(time.Microsecond), e.Calls)
+		if e.Items > 0 {
+			cost += fmt.Sprintf(", %d items", e.Items)
+		}
+		substack.Lines = append(substack.Lines, leftRightValueLineFromValues(call, cost, w))
+	}
+}
+
 func fillSubstacksFromFeature(response *UIResponseJSON, substacks []*pb.SubstackProto, f b6.Feature, w b6.World, closeable bool) []*pb.SubstackProto {
 	substack := &pb.SubstackProto{}
 
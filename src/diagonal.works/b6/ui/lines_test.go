@@ -7,7 +7,6 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"slices"
-	"sync"
 	"testing"
 
 	"diagonal.works/b6/api"
@@ -44,7 +43,7 @@ func sendExpressionToTestUI(e string, t *testing.T) *UIResponseJSON {
 		Base: camden.BuildGranarySquareForTests(t),
 	}
 
-	var lock sync.RWMutex
+	var locks ingest.WorldLocks
 	handler := StackHandler{
 		UI: &OpenSourceUI{
 			Worlds:          w,
@@ -56,9 +55,9 @@ func sendExpressionToTestUI(e string, t *testing.T) *UIResponseJSON {
 				Options: api.Options{
 					Cores: 2,
 				},
-				Lock: &lock,
+				Locks: &locks,
 			},
-			Lock: &lock,
+			Locks: &locks,
 		},
 	}
 
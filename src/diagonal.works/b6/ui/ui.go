@@ -3,6 +3,7 @@ package ui
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -16,7 +17,6 @@ import (
 	"sort"
 	"strconv"
 	"strings"
-	"sync"
 
 	"diagonal.works/b6"
 	"diagonal.works/b6/api"
@@ -42,11 +42,12 @@ type Options struct {
 	EnableVite        bool
 	EnableStorybook   bool
 	BasemapRules      renderer.RenderRules
+	TileCache         *renderer.TileCache
 	UI                UI
 	Worlds            ingest.Worlds
 	APIOptions        api.Options
 	InstrumentHandler func(handler http.Handler, name string) http.Handler
-	Lock              *sync.RWMutex
+	Locks             *ingest.WorldLocks
 }
 
 type DropPrefixFilesystem struct {
@@ -126,7 +127,12 @@ func RegisterWebInterface(root *http.ServeMux, options *Options) error {
 		Options:         options.APIOptions,
 		FunctionSymbols: functions.Functions(),
 		Adaptors:        functions.Adaptors(),
-		Lock:            options.Lock,
+		Locks:           options.Locks,
+	}
+
+	rules := renderer.BasemapRenderRules
+	if options.BasemapRules != nil {
+		rules = options.BasemapRules
 	}
 
 	var ui UI
@@ -136,9 +142,9 @@ func RegisterWebInterface(root *http.ServeMux, options *Options) error {
 		ui = &OpenSourceUI{
 			Worlds:          options.Worlds,
 			Evaluator:       evaluator,
-			BasemapRules:    renderer.BasemapRenderRules,
+			BasemapRules:    rules,
 			FunctionSymbols: functions.Functions(),
-			Lock:            options.Lock,
+			Locks:           options.Locks,
 		}
 	}
 	startup := http.Handler(&StartupHandler{UI: ui})
@@ -151,30 +157,53 @@ func RegisterWebInterface(root *http.ServeMux, options *Options) error {
 		stack = options.InstrumentHandler(stack, "ui")
 	}
 	root.Handle("/stack", stack)
-	root.Handle("/evaluate", &EvaluateHandler{
+	evaluate := http.Handler(&EvaluateHandler{
 		Evaluator: evaluator,
 	})
-	root.Handle("/compare", &CompareHandler{
+	if options.InstrumentHandler != nil {
+		evaluate = options.InstrumentHandler(evaluate, "evaluate")
+	}
+	root.Handle("/evaluate", evaluate)
+	complete := http.Handler(&CompleteHandler{
+		Worlds:    options.Worlds,
+		Completer: functions.NewCompleter(),
+		Locks:     options.Locks,
+	})
+	if options.InstrumentHandler != nil {
+		complete = options.InstrumentHandler(complete, "complete")
+	}
+	root.Handle("/complete", complete)
+	compare := http.Handler(&CompareHandler{
 		Evaluator: evaluator,
 		Worlds:    options.Worlds,
 	})
+	if options.InstrumentHandler != nil {
+		compare = options.InstrumentHandler(compare, "compare")
+	}
+	root.Handle("/compare", compare)
 
 	return nil
 }
 
+// lockedHandler holds a read lock on the world given by the request's r
+// parameter while serving it.
 type lockedHandler struct {
 	handler http.Handler
-	lock    *sync.RWMutex
+	locks   *ingest.WorldLocks
 }
 
 func (l *lockedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	l.lock.RLock()
-	defer l.lock.RUnlock()
+	unlock := l.locks.RLock(b6.FeatureIDFromString(r.URL.Query().Get("r")))
+	defer unlock()
 	l.handler.ServeHTTP(w, r)
 }
 
-func lockHandler(handler http.Handler, lock *sync.RWMutex) http.Handler {
-	return &lockedHandler{handler: handler, lock: lock}
+func lockHandler(handler http.Handler, locks *ingest.WorldLocks) http.Handler {
+	return &lockedHandler{handler: handler, locks: locks}
+}
+
+func newTileHandler(name string, r renderer.Renderer, options *Options) *renderer.TileHandler {
+	return &renderer.TileHandler{Renderer: r, Cache: options.TileCache, Name: name, Worlds: options.Worlds}
 }
 
 func RegisterTiles(root *http.ServeMux, options *Options) {
@@ -182,27 +211,37 @@ func RegisterTiles(root *http.ServeMux, options *Options) {
 	if options.BasemapRules != nil {
 		rules = options.BasemapRules
 	}
-	base := http.Handler(lockHandler(&renderer.TileHandler{Renderer: &renderer.BasemapRenderer{RenderRules: rules, Worlds: options.Worlds}}, options.Lock))
+	basemap := &renderer.BasemapRenderer{RenderRules: rules, Worlds: options.Worlds}
+	base := http.Handler(lockHandler(newTileHandler("base", basemap, options), options.Locks))
 	if options.InstrumentHandler != nil {
 		base = options.InstrumentHandler(base, "tiles_base")
 	}
 	root.Handle("/tiles/base/", base)
-	query := http.Handler(lockHandler(&renderer.TileHandler{Renderer: renderer.NewQueryRenderer(options.Worlds, options.APIOptions.Cores)}, options.Lock))
+	queryRenderer := renderer.NewQueryRenderer(options.Worlds, options.APIOptions)
+	queryHandler := newTileHandler("query", queryRenderer, options)
+	// Query expressions can depend on features anywhere in the world, for
+	// example via reachable.
+	queryHandler.Unbounded = true
+	query := http.Handler(lockHandler(queryHandler, options.Locks))
 	if options.InstrumentHandler != nil {
 		query = options.InstrumentHandler(query, "tiles_query")
 	}
 	root.Handle("/tiles/query/", query)
-	histogram := http.Handler(lockHandler(&renderer.TileHandler{Renderer: renderer.NewHistogramRenderer(rules, options.Worlds)}, options.Lock))
+	histogram := http.Handler(lockHandler(newTileHandler("histogram", renderer.NewHistogramRenderer(rules, options.Worlds), options), options.Locks))
 	if options.InstrumentHandler != nil {
 		histogram = options.InstrumentHandler(histogram, "tiles_histogram")
 	}
 	root.Handle("/tiles/histogram/", histogram)
-	collection := http.Handler(lockHandler(&renderer.TileHandler{Renderer: renderer.NewCollectionRenderer(rules, options.Worlds)}, options.Lock))
+	collection := http.Handler(lockHandler(newTileHandler("collection", renderer.NewCollectionRenderer(rules, options.Worlds), options), options.Locks))
 	if options.InstrumentHandler != nil {
-		histogram = options.InstrumentHandler(histogram, "tiles_collection")
+		collection = options.InstrumentHandler(collection, "tiles_collection")
 	}
 	root.Handle("/tiles/collection/", collection)
-
+	png := http.Handler(lockHandler(&renderer.RasterHandler{Basemap: basemap, Query: queryRenderer}, options.Locks))
+	if options.InstrumentHandler != nil {
+		png = options.InstrumentHandler(png, "render_png")
+	}
+	root.Handle("/render.png", png)
 }
 
 type StartupRequest struct {
@@ -293,7 +332,7 @@ func (u *UIResponseJSON) AddGeoJSON(g geojson.GeoJSON) {
 
 type UI interface {
 	ServeStartup(request *StartupRequest, response *StartupResponseJSON, ui UI) error
-	ServeStack(request *pb.UIRequestProto, response *UIResponseJSON, ui UI) error
+	ServeStack(ctx context.Context, request *pb.UIRequestProto, response *UIResponseJSON, ui UI) error
 	Render(response *UIResponseJSON, value interface{}, root b6.CollectionID, locked bool, ui UI, closeable bool) error
 }
 
@@ -342,7 +381,7 @@ func (s *StackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	response := NewUIResponseJSON()
 
-	if err := s.UI.ServeStack(request, response, s.UI); err == nil {
+	if err := s.UI.ServeStack(r.Context(), request, response, s.UI); err == nil {
 		SendJSON(response, w, r)
 	} else {
 		log.Println(err.Error())
@@ -408,12 +447,12 @@ type OpenSourceUI struct {
 	BasemapRules    renderer.RenderRules
 	FunctionSymbols api.FunctionSymbols // For function name completion
 	Evaluator       api.Evaluator
-	Lock            *sync.RWMutex
+	Locks           *ingest.WorldLocks
 }
 
 func (o *OpenSourceUI) ServeStartup(request *StartupRequest, response *StartupResponseJSON, ui UI) error {
-	o.Lock.RLock()
-	defer o.Lock.RUnlock()
+	unlock := o.Locks.RLock(request.Root.FeatureID())
+	defer unlock()
 	w := o.Worlds.FindOrCreateWorld(request.Root.FeatureID())
 	if root := b6.FindCollectionByID(request.Root, w); root != nil {
 		response.Locked = root.Get("locked").String() == "yes"
@@ -496,10 +535,10 @@ func (o *OpenSourceUI) ServeStartup(request *StartupRequest, response *StartupRe
 	return nil
 }
 
-func (o *OpenSourceUI) ServeStack(request *pb.UIRequestProto, response *UIResponseJSON, ui UI) error {
-	o.Lock.RLock()
-	defer o.Lock.RUnlock()
+func (o *OpenSourceUI) ServeStack(ctx context.Context, request *pb.UIRequestProto, response *UIResponseJSON, ui UI) error {
 	root := b6.NewFeatureIDFromProto(request.Root)
+	unlock := o.Locks.RLock(root)
+	defer unlock()
 
 	var expression b6.Expression
 	var err error
@@ -541,7 +580,7 @@ func (o *OpenSourceUI) ServeStack(request *pb.UIRequestProto, response *UIRespon
 	}
 
 	var result interface{}
-	result, err = o.Evaluator.EvaluateExpression(expression, root)
+	result, err = o.Evaluator.EvaluateExpression(ctx, expression, root)
 
 	if err == nil {
 		if a, ok := result.(*api.AppliedChange); ok {
@@ -699,6 +738,15 @@ func (o *OpenSourceUI) fillResponseFromResult(response *UIResponseJSON, result i
 				})
 			}
 		}
+	case *api.ProfiledResult:
+		// Render the value first, as costs incurred iterating over it
+		// are part of the profile.
+		if err := o.fillResponseFromResult(response, r.Value, w, closeable); err != nil {
+			return err
+		}
+		substack := &pb.SubstackProto{Collapsable: true}
+		fillSubstackFromProfile(substack, r.Profile, w)
+		p.Stack.Substacks = append(p.Stack.Substacks, substack)
 	case b6.UntypedCollection:
 		substack := &pb.SubstackProto{}
 		if err := fillSubstackFromCollection(substack, r, p, w); err == nil {
@@ -826,7 +874,9 @@ func (e *EvaluateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if r.Method == "GET" {
 		q := r.URL.Query()
 		root := b6.FeatureIDFromString(q.Get("r"))
-		if result, err = e.Evaluator.EvaluateString(q.Get("e"), root); err == nil {
+		unlock := e.Evaluator.Locks.RLock(root)
+		defer unlock()
+		if result, err = e.Evaluator.EvaluateString(r.Context(), q.Get("e"), root); err == nil {
 			if a, ok := result.(*api.AppliedChange); ok {
 				result = a.Modified
 			}
@@ -837,7 +887,9 @@ func (e *EvaluateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			r.Body.Close()
 			var request pb.EvaluateRequestProto
 			if err = protojson.Unmarshal(body, &request); err == nil {
-				result, err = e.Evaluator.EvaluateProto(&request)
+				unlock := e.Evaluator.Locks.RLock(b6.NewFeatureIDFromProto(request.Root))
+				defer unlock()
+				result, err = e.Evaluator.EvaluateProto(r.Context(), &request)
 			}
 		}
 	} else {
@@ -861,6 +913,41 @@ func (e *EvaluateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	SendJSON((*EvaluateResponseProtoJSON)(response), w, r)
 }
 
+type CompleteHandler struct {
+	Worlds    ingest.Worlds
+	Completer *api.Completer
+	Locks     *ingest.WorldLocks
+}
+
+func (c *CompleteHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	q := r.URL.Query()
+	expression := q.Get("e")
+	cursor := len(expression)
+	if c := q.Get("c"); c != "" {
+		var err error
+		if cursor, err = strconv.Atoi(c); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	// Completing against an unknown world uses the default world, rather
+	// than creating one.
+	root, unlock := c.Locks.RLockExisting(c.Worlds, b6.FeatureIDFromString(q.Get("r")))
+	defer unlock()
+	completions := c.Completer.Complete(expression, cursor, c.Worlds.FindOrCreateWorld(root))
+	response := &pb.CompleteResponseProto{
+		Completions: make([]*pb.CompletionProto, len(completions)),
+	}
+	for i, completion := range completions {
+		response.Completions[i] = completion.ToProto()
+	}
+	SendJSON(WrapProtoForJSON(response), w, r)
+}
+
 type ProtoJSON[Proto proto.Message] struct {
 	m Proto
 }
@@ -929,20 +1016,28 @@ func (c *CompareHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var response pb.ComparisonLineProto
+	unlock := c.Evaluator.Locks.RLock(b6.NewFeatureIDFromProto(request.Baseline))
 	response.Baseline = newComparisonHistogram(analysis, baseline)
-
-	c.Evaluator.Lock.RLock()
-	defer c.Evaluator.Lock.RUnlock()
-	for _, scenario := range scenarios {
-		if _, err = c.Evaluator.EvaluateExpression(expression.Get(b6.ExpressionTag).Value, scenario); err == nil {
-			w := c.Worlds.FindOrCreateWorld(scenario)
-			if comparison := b6.FindCollectionByID(analysis.CollectionID(), w); comparison != nil {
-				response.Scenarios = append(response.Scenarios, newComparisonHistogram(comparison, w))
-			} else {
-				err = fmt.Errorf("expression didn't produce required analysis")
-			}
+	unlock()
+
+	// Each scenario is locked in turn, rather than all at once, to allow
+	// the expression to be applied to one without waiting for readers of
+	// the others.
+	compare := func(scenario b6.FeatureID) error {
+		unlock := c.Evaluator.Locks.RLock(scenario)
+		defer unlock()
+		if _, err := c.Evaluator.EvaluateExpression(r.Context(), expression.Get(b6.ExpressionTag).Value, scenario); err != nil {
+			return err
 		}
-		if err != nil {
+		w := c.Worlds.FindOrCreateWorld(scenario)
+		if comparison := b6.FindCollectionByID(analysis.CollectionID(), w); comparison != nil {
+			response.Scenarios = append(response.Scenarios, newComparisonHistogram(comparison, w))
+			return nil
+		}
+		return fmt.Errorf("expression didn't produce required analysis")
+	}
+	for _, scenario := range scenarios {
+		if err = compare(scenario); err != nil {
 			break
 		}
 	}
@@ -2,12 +2,12 @@ package ui
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
-	"sync"
 	"testing"
 
 	"diagonal.works/b6"
@@ -22,13 +22,13 @@ import (
 )
 
 func TestStateFilledFromStartupQuery(t *testing.T) {
-	var lock sync.RWMutex
+	var locks ingest.WorldLocks
 	handler := StartupHandler{
 		UI: &OpenSourceUI{
 			Worlds: &ingest.MutableWorlds{
 				Base: b6.EmptyWorld{},
 			},
-			Lock: &lock,
+			Locks: &locks,
 		},
 	}
 
@@ -72,15 +72,15 @@ func TestEvaluateFunctionThatChangesWorld(t *testing.T) {
 	worlds := &ingest.MutableWorlds{
 		Base: camden.BuildGranarySquareForTests(t),
 	}
-	var lock sync.RWMutex
+	var locks ingest.WorldLocks
 	handler := StackHandler{
 		UI: &OpenSourceUI{
 			Worlds: worlds,
-			Lock:   &lock,
+			Locks:  &locks,
 			Evaluator: api.Evaluator{
 				Worlds:          worlds,
 				FunctionSymbols: functions.Functions(),
-				Lock:            &lock,
+				Locks:           &locks,
 			},
 		},
 	}
@@ -121,13 +121,13 @@ func TestEvaluateFunctionViaEvaluateEndpoint(t *testing.T) {
 	worlds := &ingest.MutableWorlds{
 		Base: camden.BuildGranarySquareForTests(t),
 	}
-	var lock sync.RWMutex
+	var locks ingest.WorldLocks
 	handler := EvaluateHandler{
 		Evaluator: api.Evaluator{
 			Worlds:          worlds,
 			FunctionSymbols: functions.Functions(),
 			Adaptors:        functions.Adaptors(),
-			Lock:            &lock,
+			Locks:           &locks,
 		},
 	}
 
@@ -190,13 +190,13 @@ func TestCompareScenarios(t *testing.T) {
 		Base: camden.BuildGranarySquareForTests(t),
 	}
 
-	var lock sync.RWMutex
+	var locks ingest.WorldLocks
 
 	evaluator := api.Evaluator{
 		Worlds:          worlds,
 		FunctionSymbols: functions.Functions(),
 		Adaptors:        functions.Adaptors(),
-		Lock:            &lock,
+		Locks:           &locks,
 	}
 
 	handler := CompareHandler{
@@ -208,12 +208,12 @@ func TestCompareScenarios(t *testing.T) {
 	baseline := b6.FeatureID{Type: b6.FeatureTypeCollection, Namespace: "diagonal.works/test/world", Value: 0}
 	scenario := b6.FeatureID{Type: b6.FeatureTypeCollection, Namespace: "diagonal.works/test/world", Value: 1}
 
-	lock.RLock()
+	unlock := locks.RLock(baseline)
 	e := "find [#amenity=restaurant] | map (get \"cuisine\") | histogram-with-id /" + analysis.String()
-	if _, err := evaluator.EvaluateString(e, baseline); err != nil {
+	if _, err := evaluator.EvaluateString(context.Background(), e, baseline); err != nil {
 		t.Fatalf("Failed to setup baseline analysis: %s", err)
 	}
-	lock.RUnlock()
+	unlock()
 
 	w := worlds.FindOrCreateWorld(scenario)
 	// The horror